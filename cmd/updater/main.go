@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -14,20 +17,33 @@ import (
 
 	_ "github.com/breml/rootcerts"
 	"github.com/qdm12/ddns-updater/internal/backup"
-	"github.com/qdm12/ddns-updater/internal/config"
+	configlib "github.com/qdm12/ddns-updater/internal/config"
 	"github.com/qdm12/ddns-updater/internal/data"
+	"github.com/qdm12/ddns-updater/internal/events"
 	"github.com/qdm12/ddns-updater/internal/health"
 	"github.com/qdm12/ddns-updater/internal/healthchecksio"
+	"github.com/qdm12/ddns-updater/internal/heartbeat"
+	"github.com/qdm12/ddns-updater/internal/hook"
+	"github.com/qdm12/ddns-updater/internal/httpclient"
+	"github.com/qdm12/ddns-updater/internal/killswitch"
+	"github.com/qdm12/ddns-updater/internal/metrics"
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/netgate"
 	jsonparams "github.com/qdm12/ddns-updater/internal/params"
 	persistence "github.com/qdm12/ddns-updater/internal/persistence/json"
+	"github.com/qdm12/ddns-updater/internal/probe"
+	providerlib "github.com/qdm12/ddns-updater/internal/provider"
 	recordslib "github.com/qdm12/ddns-updater/internal/records"
 	"github.com/qdm12/ddns-updater/internal/resolver"
+	"github.com/qdm12/ddns-updater/internal/secrets"
 	"github.com/qdm12/ddns-updater/internal/server"
 	"github.com/qdm12/ddns-updater/internal/shoutrrr"
 	"github.com/qdm12/ddns-updater/internal/update"
+	"github.com/qdm12/ddns-updater/internal/webhook"
 	"github.com/qdm12/ddns-updater/pkg/publicip"
+	"github.com/qdm12/ddns-updater/pkg/publicip/metadata"
 	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/reader/sources/flag"
 	"github.com/qdm12/goshutdown"
 	"github.com/qdm12/gosplash"
 	"github.com/qdm12/log"
@@ -48,7 +64,9 @@ func main() {
 	}
 	logger := log.New()
 
+	envSource := secrets.NewEnvSource(os.Environ())
 	reader := reader.New(reader.Settings{
+		Sources: []reader.Source{flag.New(os.Args), envSource},
 		HandleDeprecatedKey: func(source, oldKey, newKey string) {
 			logger.Warnf("%q key %s is deprecated, please use %q instead",
 				source, oldKey, newKey)
@@ -61,7 +79,7 @@ func main() {
 
 	errorCh := make(chan error)
 	go func() {
-		errorCh <- _main(ctx, reader, os.Args, logger, buildInfo, time.Now)
+		errorCh <- _main(ctx, reader, envSource, os.Args, logger, buildInfo, time.Now)
 	}()
 
 	select {
@@ -96,7 +114,8 @@ func main() {
 	os.Exit(1)
 }
 
-func _main(ctx context.Context, reader *reader.Reader, args []string, logger log.LoggerInterface,
+func _main(ctx context.Context, reader *reader.Reader, envSource *secrets.EnvSource,
+	args []string, logger log.LoggerInterface,
 	buildInfo models.BuildInformation, timeNow func() time.Time) (err error) {
 	if len(args) > 1 {
 		switch args[1] {
@@ -108,7 +127,7 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 			// built-in healthcheck, in an ephemeral fashion to query the
 			// long running instance of the program about its status
 
-			var healthSettings config.Health
+			var healthSettings configlib.Health
 			healthSettings.Read(reader)
 			healthSettings.SetDefaults()
 			err = healthSettings.Validate()
@@ -121,6 +140,23 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 		}
 	}
 
+	once, err := readOnceSettings(reader)
+	if err != nil {
+		return fmt.Errorf("reading once settings: %w", err)
+	}
+	verifyOnly, err := reader.BoolPtr("VERIFY")
+	if err != nil {
+		return fmt.Errorf("reading verify setting: %w", err)
+	}
+	splashWriter := io.Writer(os.Stdout)
+	logWriter := io.Writer(os.Stdout)
+	if once.enabled {
+		// Keep stdout for the results only, so they can be parsed.
+		splashWriter = os.Stderr
+		logWriter = os.Stderr
+		logger.Patch(log.SetWriters(logWriter))
+	}
+
 	announcementExp, err := time.Parse(time.RFC3339, "2023-07-15T00:00:00Z")
 	if err != nil {
 		return err
@@ -139,21 +175,25 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 		GithubSponsor: "qdm12",
 	}
 	for _, line := range gosplash.MakeLines(splashSettings) {
-		fmt.Println(line)
+		fmt.Fprintln(splashWriter, line)
 	}
 
-	var config config.Config
+	var config configlib.Config
 	err = config.Read(reader, logger)
 	if err != nil {
 		return fmt.Errorf("reading settings: %w", err)
 	}
+	err = envSource.Err()
+	if err != nil {
+		return fmt.Errorf("reading secret files: %w", err)
+	}
 	config.SetDefaults()
 	err = config.Validate()
 	if err != nil {
 		return fmt.Errorf("settings validation: %w", err)
 	}
 
-	logger.Patch(config.Logger.ToOptions()...)
+	logger.Patch(config.Logger.ToOptions(logWriter)...)
 
 	logger.Info(config.String())
 
@@ -173,51 +213,70 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 		return err
 	}
 
-	jsonReader := jsonparams.NewReader(logger)
-	jsonFilepath := filepath.Join(*config.Paths.DataDir, "config.json")
-	providers, warnings, err := jsonReader.JSONProviders(jsonFilepath)
-	for _, w := range warnings {
-		logger.Warn(w)
-		shoutrrrClient.Notify(w)
+	if *config.Paths.ImportSnapshot != "" {
+		logger.Info("Importing snapshot from " + *config.Paths.ImportSnapshot)
+		snapshot, err := persistence.ReadSnapshot(*config.Paths.ImportSnapshot)
+		if err != nil {
+			return fmt.Errorf("reading snapshot: %w", err)
+		}
+		err = persistentDB.Import(snapshot)
+		if err != nil {
+			return fmt.Errorf("importing snapshot: %w", err)
+		}
 	}
+
+	jsonReader := jsonparams.NewReader(logger)
+	configFilepath := jsonparams.ConfigFilepath(*config.Paths.DataDir)
+	strictDuplicates := config.Update.Duplicates == configlib.DuplicatesStrict
+	settings, err := readSettings(jsonReader, configFilepath, strictDuplicates,
+		logger, shoutrrrClient)
 	if err != nil {
 		shoutrrrClient.Notify(err.Error())
 		return err
 	}
 
-	L := len(providers)
+	L := len(settings)
 	switch L {
 	case 0:
 		logger.Warn("Found no setting to update record")
 	case 1:
 		logger.Info("Found single setting to update record")
 	default:
-		logger.Info("Found " + fmt.Sprint(len(providers)) + " settings to update records")
+		logger.Info("Found " + fmt.Sprint(len(settings)) + " settings to update records")
 	}
 
-	client := &http.Client{Timeout: config.Client.Timeout}
+	client := httpclient.New(httpclient.Settings{
+		Timeout:             config.Client.Timeout,
+		LocalAddress:        config.Client.LocalAddress,
+		MaxIdleConnsPerHost: *config.Client.MaxIdleConnsPerHost,
+		IdleConnTimeout:     *config.Client.IdleConnTimeout,
+		ForceAttemptHTTP2:   *config.Client.ForceAttemptHTTP2,
+	})
 
 	err = health.CheckHTTP(ctx, client)
 	if err != nil {
 		logger.Warn(err.Error())
 	}
 
-	records := make([]recordslib.Record, len(providers))
-	for i, provider := range providers {
-		logger.Info("Reading history from database: domain " +
-			provider.Domain() + " host " + provider.Host() +
-			" " + provider.IPVersion().String())
-		events, err := persistentDB.GetEvents(provider.Domain(),
-			provider.Host(), provider.IPVersion())
-		if err != nil {
-			shoutrrrClient.Notify(err.Error())
-			return err
-		}
-		records[i] = recordslib.New(provider, events)
+	err = verifyCredentials(ctx, client, settings, logger)
+	switch {
+	case verifyOnly != nil && *verifyOnly:
+		return err
+	case err != nil:
+		logger.Error(err.Error())
+		shoutrrrClient.Notify(err.Error())
+	}
+
+	sortRecords := config.Update.Order == configlib.UpdateOrderSorted
+	records, err := makeRecords(settings, persistentDB, sortRecords, logger)
+	if err != nil {
+		shoutrrrClient.Notify(err.Error())
+		return err
 	}
 
 	defer client.CloseIdleConnections()
-	db := data.NewDatabase(records, persistentDB)
+	db := data.NewDatabase(records, persistentDB,
+		logger.New(log.SetComponent("database")))
 	defer func() {
 		err := db.Close()
 		if err != nil {
@@ -235,7 +294,38 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 		Options: config.PubIP.ToDNSPOptions(),
 	}
 
-	ipGetter, err := publicip.NewFetcher(dnsSettings, httpSettings)
+	routeSettings := publicip.RouteSettings{
+		Enabled: *config.PubIP.RouteEnabled,
+	}
+
+	fileSettings := publicip.FileSettings{
+		Enabled: *config.PubIP.FileEnabled,
+		Path:    config.PubIP.FilePath,
+	}
+
+	metadataSettings := publicip.MetadataSettings{
+		Enabled:  *config.PubIP.MetadataEnabled,
+		Client:   client,
+		Provider: metadata.Provider(config.PubIP.MetadataProvider),
+	}
+
+	stunSettings := publicip.STUNSettings{
+		Enabled: *config.PubIP.STUNEnabled,
+		Options: config.PubIP.ToSTUNOptions(),
+	}
+
+	interfaceSettings := publicip.InterfaceSettings{
+		Enabled: *config.PubIP.InterfaceEnabled,
+		Name:    config.PubIP.InterfaceName,
+	}
+
+	upnpSettings := publicip.UPnPSettings{
+		Enabled: *config.PubIP.UPnPEnabled,
+		Client:  client,
+	}
+
+	ipGetter, err := publicip.NewFetcher(dnsSettings, httpSettings, routeSettings,
+		fileSettings, metadataSettings, stunSettings, interfaceSettings, upnpSettings)
 	if err != nil {
 		return err
 	}
@@ -249,11 +339,109 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 		return fmt.Errorf("creating resolver: %w", err)
 	}
 
-	hioClient := healthchecksio.New(client, *config.Health.HealthchecksioUUID)
+	hioBaseURL, err := url.Parse(*config.Health.HealthchecksioBaseURL)
+	if err != nil {
+		return fmt.Errorf("parsing healthchecks.io base URL: %w", err)
+	}
+	hioClient := healthchecksio.New(client, *hioBaseURL, *config.Health.HealthchecksioUUID)
+	heartbeatClient := heartbeat.New(client, heartbeat.Settings{
+		StartURL:   *config.Health.PingStartURL,
+		SuccessURL: *config.Health.PingSuccessURL,
+		FailureURL: *config.Health.PingFailureURL,
+		Method:     config.Health.PingMethod,
+	})
 
-	updater := update.NewUpdater(db, client, shoutrrrClient, logger, timeNow)
-	runner := update.NewRunner(db, updater, ipGetter, config.Update.Period,
-		config.Update.Cooldown, logger, resolver, timeNow, hioClient)
+	prober := probe.New(probe.Settings{
+		Port:     *config.Probe.Port,
+		HTTPPath: *config.Probe.HTTPPath,
+		Timeout:  config.Probe.Timeout,
+	})
+
+	networkGate := netgate.New(netgate.Settings{
+		Interfaces:  config.Network.Interfaces,
+		GatewayMACs: config.Network.GatewayMACs,
+	})
+	if networkGate.Enabled() && !netgate.Supported() {
+		logger.Warn("network gate is not supported on this platform, updating on all networks")
+		networkGate = netgate.New(netgate.Settings{})
+	}
+
+	killSwitch := killswitch.New(client, killswitch.Settings{
+		URL:          *config.KillSwitch.URL,
+		PauseOnError: config.KillSwitch.PauseOnError(),
+	})
+
+	eventsBroadcaster := events.NewBroadcaster()
+	metrics := metrics.New()
+	notificationTemplate, err := config.Shoutrrr.NotificationTemplate()
+	if err != nil {
+		return fmt.Errorf("creating notification template: %w", err)
+	}
+	hookRunner := hook.New(*config.Hook.Command, config.Hook.Timeout,
+		logger.New(log.SetComponent("hook")))
+	webhookTemplate, err := config.Webhook.PayloadTemplate()
+	if err != nil {
+		return fmt.Errorf("creating webhook template: %w", err)
+	}
+	webhookSender := webhook.New(config.Webhook.URLs, webhookTemplate, *config.Webhook.Retries,
+		client, logger.New(log.SetComponent("webhook")))
+	updater := update.NewUpdater(update.UpdaterSettings{
+		DB:                   db,
+		Client:               client,
+		ShoutrrrClient:       shoutrrrClient,
+		NotificationTemplate: notificationTemplate,
+		Events:               eventsBroadcaster,
+		Metrics:              metrics,
+		Hook:                 hookRunner,
+		Webhook:              webhookSender,
+		VerifyRetries:        *config.Update.VerifyRetries,
+		VerifyBackoff:        config.Update.VerifyBackoff,
+		AcceptLanguage:       *config.Client.AcceptLanguage,
+		AnonymizeIPs:         *config.Privacy.AnonymizeIPs,
+		Logger:               logger,
+		TimeNow:              timeNow,
+	})
+	runner := update.NewRunner(update.RunnerSettings{
+		DB:                  db,
+		Updater:             updater,
+		IPGetter:            metrics.WrapPublicIPFetcher(ipGetter),
+		Period:              config.Update.Period,
+		Cooldown:            config.Update.Cooldown,
+		CycleTimeout:        config.Update.CycleTimeout,
+		SettleDelay:         config.Update.SettleDelay,
+		Concurrency:         config.Update.Concurrency,
+		ZoneConcurrency:     config.Update.ZoneConcurrency,
+		Logger:              logger,
+		Resolver:            resolver,
+		TimeNow:             timeNow,
+		HIOClient:           hioClient,
+		Heartbeat:           heartbeatClient,
+		Prober:              prober,
+		NetworkGate:         networkGate,
+		KillSwitch:          killSwitch,
+		TransientRetries:    *config.Update.TransientRetries,
+		TransientRetryDelay: config.Update.TransientRetryDelay,
+		AnonymizeIPs:        *config.Privacy.AnonymizeIPs,
+		CGNATWarning:        *config.PubIP.CGNATWarning,
+		DryRun:              *config.Update.DryRun,
+	})
+
+	if once.enabled {
+		webhookDone := make(chan struct{})
+		go webhookSender.Run(ctx, webhookDone)
+		results, cycleErr := runner.RunOnce(ctx)
+		// Deliver the queued webhooks before exiting
+		webhookSender.Close()
+		<-webhookDone
+		err = update.WriteResults(os.Stdout, results, once.output)
+		if err != nil {
+			return fmt.Errorf("writing results: %w", err)
+		}
+		return cycleErr
+	}
+
+	webhookHandler, webhookCtx, webhookDone := goshutdown.NewGoRoutineHandler("webhook")
+	go webhookSender.Run(webhookCtx, webhookDone)
 
 	runnerHandler, runnerCtx, runnerDone := goshutdown.NewGoRoutineHandler("runner")
 	go runner.Run(runnerCtx, runnerDone)
@@ -271,18 +459,51 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 
 	serverLogger := logger.New(log.SetComponent("http server"))
 	server := server.New(ctx, config.Server.ListeningAddress, config.Server.RootURL,
-		db, serverLogger, runner)
+		db, serverLogger, runner, eventsBroadcaster, metrics, *config.Privacy.AnonymizeIPs,
+		config.Server.Auth())
 	serverHandler, serverCtx, serverDone := goshutdown.NewGoRoutineHandler("server")
 	go server.Run(serverCtx, serverDone)
 	shoutrrrClient.Notify("Launched with " + strconv.Itoa(len(records)) + " records to watch")
 
 	backupHandler, backupCtx, backupDone := goshutdown.NewGoRoutineHandler("backup")
 	backupLogger := logger.New(log.SetComponent("backup"))
-	go backupRunLoop(backupCtx, backupDone, *config.Backup.Period, *config.Paths.DataDir,
+	go backupRunLoop(backupCtx, backupDone, *config.Backup.Period, *config.Paths.DataDir, configFilepath,
 		*config.Backup.Directory, backupLogger, timeNow)
 
+	persistenceRetryHandler, persistenceRetryCtx, persistenceRetryDone :=
+		goshutdown.NewGoRoutineHandler("persistence retry")
+	go persistenceRetryLoop(persistenceRetryCtx, persistenceRetryDone, db)
+
+	historyPruneHandler, historyPruneCtx, historyPruneDone := goshutdown.NewGoRoutineHandler("history prune")
+	historyPruneLogger := logger.New(log.SetComponent("history"))
+	go historyPruneLoop(historyPruneCtx, historyPruneDone, *config.History.MaxAgeDays,
+		*config.History.MaxEvents, db, persistentDB, historyPruneLogger, timeNow)
+
+	fileWatchHandler, fileWatchCtx, fileWatchDone := goshutdown.NewGoRoutineHandler("public IP file watch")
+	fileWatchLogger := logger.New(log.SetComponent("public IP file"))
+	go fileWatchLoop(fileWatchCtx, fileWatchDone, *config.PubIP.FileEnabled,
+		ipGetter, runner, fileWatchLogger)
+
+	reloadHandler, reloadCtx, reloadDone := goshutdown.NewGoRoutineHandler("configuration reload")
+	reloadLogger := logger.New(log.SetComponent("configuration reload"))
+	reloadRecords := func(ctx context.Context) (err error) {
+		settings, err := readSettings(jsonReader, configFilepath, strictDuplicates,
+			reloadLogger, shoutrrrClient)
+		if err != nil {
+			return err
+		}
+		records, err := makeRecords(settings, persistentDB, sortRecords, reloadLogger)
+		if err != nil {
+			return err
+		}
+		return runner.Reload(ctx, records)
+	}
+	go reloadLoop(reloadCtx, reloadDone, reloadRecords, reloadLogger)
+
 	shutdownGroup := goshutdown.NewGroupHandler("")
-	shutdownGroup.Add(runnerHandler, healthServerHandler, serverHandler, backupHandler)
+	shutdownGroup.Add(runnerHandler, healthServerHandler, serverHandler,
+		backupHandler, persistenceRetryHandler, historyPruneHandler, fileWatchHandler,
+		reloadHandler, webhookHandler)
 
 	<-ctx.Done()
 
@@ -297,13 +518,121 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 	return nil
 }
 
+type onceSettings struct {
+	// enabled is whether to run a single update cycle and exit.
+	enabled bool
+	// output is the format of the results of the single update
+	// cycle, and can be update.OutputText or update.OutputJSON.
+	output string
+}
+
+var ErrOutputWithoutOnce = errors.New("output can only be set in once mode")
+
+// readOnceSettings reads the once mode settings, typically set
+// with the flags --once and --output json.
+func readOnceSettings(reader *reader.Reader) (settings onceSettings, err error) {
+	enabled, err := reader.BoolPtr("ONCE")
+	if err != nil {
+		return settings, err
+	}
+	settings.enabled = enabled != nil && *enabled
+
+	settings.output = reader.String("OUTPUT")
+	switch {
+	case settings.output == "":
+		settings.output = update.OutputText
+	case settings.output != update.OutputText && settings.output != update.OutputJSON:
+		return settings, fmt.Errorf("%w: %s", update.ErrOutputFormatNotValid, settings.output)
+	case !settings.enabled:
+		return settings, fmt.Errorf("%w", ErrOutputWithoutOnce)
+	}
+	return settings, nil
+}
+
+// verifyCredentials verifies the credentials of each provider implementing
+// the optional provider Verifier interface, and returns an error joining
+// the errors of the providers failing their verification.
+func verifyCredentials(ctx context.Context, client *http.Client,
+	settings []jsonparams.Settings, logger log.LoggerInterface) (err error) {
+	var errs []error
+	for _, setting := range settings {
+		verifier, ok := setting.Provider.(providerlib.Verifier)
+		if !ok {
+			continue
+		}
+		err := verifier.Verify(ctx, client)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("verifying credentials for %s: %w",
+				setting.Provider.BuildDomainName(), err))
+			continue
+		}
+		logger.Info("credentials verified for " + setting.Provider.BuildDomainName())
+	}
+	return errors.Join(errs...)
+}
+
+type Notifier interface {
+	Notify(message string)
+}
+
+// readSettings reads the records settings from the configuration
+// file, logging and notifying warnings, and removes duplicate settings.
+func readSettings(jsonReader *jsonparams.Reader, configFilepath string, strictDuplicates bool,
+	logger log.LoggerInterface, notifier Notifier) (settings []jsonparams.Settings, err error) {
+	settings, warnings, err := jsonReader.JSONSettings(configFilepath)
+	for _, w := range warnings {
+		logger.Warn(w)
+		notifier.Notify(w)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	settings, warnings, err = jsonparams.RemoveDuplicates(settings, strictDuplicates)
+	for _, w := range warnings {
+		logger.Warn(w)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// makeRecords creates the records from the settings given, reading
+// their history and managed record IDs from the persistent database.
+func makeRecords(settings []jsonparams.Settings, persistentDB *persistence.Database,
+	sorted bool, logger log.LoggerInterface) (records []recordslib.Record, err error) {
+	records = make([]recordslib.Record, len(settings))
+	for i, setting := range settings {
+		provider := setting.Provider
+		logger.Info("Reading history from database: domain " +
+			provider.Domain() + " host " + provider.Host() +
+			" " + provider.IPVersion().String())
+		events, err := persistentDB.GetEvents(provider.Domain(),
+			provider.Host(), provider.IPVersion())
+		if err != nil {
+			return nil, err
+		}
+		if manager, ok := provider.(providerlib.RecordManager); ok {
+			manager.SetManagedRecordIDs(persistentDB.GetManagedRecordIDs(
+				provider.Domain(), provider.Host()))
+		}
+		records[i] = recordslib.New(provider, setting.Options, events)
+	}
+
+	if sorted {
+		recordslib.Sort(records)
+	}
+	return records, nil
+}
+
 type InfoErroer interface {
 	Info(s string)
 	Error(s string)
 }
 
 func backupRunLoop(ctx context.Context, done chan<- struct{}, backupPeriod time.Duration,
-	dataDir, outputDir string, logger InfoErroer, timeNow func() time.Time) {
+	dataDir, configFilepath, outputDir string, logger InfoErroer, timeNow func() time.Time) {
 	defer close(done)
 	if backupPeriod == 0 {
 		logger.Info("disabled")
@@ -319,7 +648,7 @@ func backupRunLoop(ctx context.Context, done chan<- struct{}, backupPeriod time.
 		err := ziper.ZipFiles(
 			zipFilepath,
 			filepath.Join(dataDir, "updates.json"),
-			filepath.Join(dataDir, "config.json"),
+			configFilepath,
 		)
 		if err != nil {
 			logger.Error(err.Error())
@@ -334,6 +663,114 @@ func backupRunLoop(ctx context.Context, done chan<- struct{}, backupPeriod time.
 	}
 }
 
+// historyPruneLoop removes the IP address history events older than
+// the maximum age in days given, and beyond the maximum number of events
+// per record given, at startup and then every hour, to bound the size
+// of the database file and of the in memory records on long running
+// instances.
+func historyPruneLoop(ctx context.Context, done chan<- struct{}, maxAgeDays, maxEvents uint,
+	db *data.Database, persistentDB *persistence.Database, logger InfoErroer,
+	timeNow func() time.Time) {
+	defer close(done)
+	if maxAgeDays == 0 && maxEvents == 0 {
+		return
+	}
+	const period = time.Hour
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		var before time.Time
+		if maxAgeDays > 0 {
+			const day = 24 * time.Hour
+			before = timeNow().Add(-time.Duration(maxAgeDays) * day)
+		}
+		db.PruneHistory(before, maxEvents)
+		pruned, err := persistentDB.PruneEvents(before, maxEvents)
+		if err != nil {
+			logger.Error("pruning history: " + err.Error())
+		} else if pruned > 0 {
+			logger.Info("pruned " + strconv.Itoa(pruned) + " history events")
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// persistenceRetryLoop periodically retries writing to the persistent
+// database, if a previous write failed and data is only kept in memory.
+func persistenceRetryLoop(ctx context.Context, done chan<- struct{},
+	db *data.Database) {
+	defer close(done)
+	const period = time.Minute
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			db.RetryPersistence()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// fileWatchLoop forces an update of the records each time the public
+// IP addresses of the public IP file change, instead of waiting for
+// the next periodic update.
+func fileWatchLoop(ctx context.Context, done chan<- struct{}, enabled bool,
+	ipGetter *publicip.Fetcher, runner *update.Runner, logger InfoErroer) {
+	defer close(done)
+	if !enabled {
+		return
+	}
+	const period = 2 * time.Second
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			changed, err := ipGetter.FileChanged()
+			if err != nil {
+				logger.Error(err.Error())
+				continue
+			} else if !changed {
+				continue
+			}
+			logger.Info("public IP address changed, updating records")
+			// note: errors are logged by the runner
+			_ = runner.ForceUpdate(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reloadLoop reloads the records from the configuration file each time
+// the program receives a SIGHUP signal, without restarting the program.
+// The current records are kept if the configuration is not valid.
+func reloadLoop(ctx context.Context, done chan<- struct{},
+	reload func(ctx context.Context) error, logger InfoErroer) {
+	defer close(done)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	for {
+		select {
+		case <-signals:
+			logger.Info("reloading configuration")
+			err := reload(ctx)
+			if err != nil {
+				logger.Error("keeping current configuration: " + err.Error())
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func exitHealthchecksio(hioClient *healthchecksio.Client,
 	logger log.LoggerInterface, state healthchecksio.State) {
 	err := hioClient.Ping(context.Background(), state)
@@ -3,6 +3,7 @@ module github.com/qdm12/ddns-updater
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/breml/rootcerts v0.2.16
 	github.com/containrrr/shoutrrr v0.8.0
 	github.com/go-chi/chi/v5 v5.0.11
@@ -16,6 +17,7 @@ require (
 	github.com/stretchr/testify v1.8.4
 	golang.org/x/mod v0.15.0
 	google.golang.org/api v0.114.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -42,7 +44,6 @@ require (
 	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
 	google.golang.org/grpc v1.56.3 // indirect
 	google.golang.org/protobuf v1.30.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	kernel.org/pub/linux/libs/security/libcap/cap v1.2.69 // indirect
 	kernel.org/pub/linux/libs/security/libcap/psx v1.2.69 // indirect
 )
@@ -1,6 +1,10 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
 	"time"
 
 	"github.com/qdm12/gosettings"
@@ -10,15 +14,57 @@ import (
 
 type Client struct {
 	Timeout time.Duration
+	// LocalAddress is the local IP address to bind outgoing HTTP
+	// connections to. It is ignored if it is the zero value.
+	LocalAddress netip.Addr
+	// MaxIdleConnsPerHost is the maximum number of idle connections
+	// kept open for reuse per host.
+	MaxIdleConnsPerHost *uint
+	// IdleConnTimeout is the maximum duration an idle connection
+	// is kept open for reuse, and zero means no limit.
+	IdleConnTimeout *time.Duration
+	// ForceAttemptHTTP2 is whether to attempt HTTP/2 for all connections.
+	ForceAttemptHTTP2 *bool
+	// AcceptLanguage is the Accept-Language header value set on
+	// requests to DNS providers, and the empty string to not set it.
+	AcceptLanguage *string
 }
 
 func (c *Client) setDefaults() {
 	const defaultTimeout = 20 * time.Second
 	c.Timeout = gosettings.DefaultComparable(c.Timeout, defaultTimeout)
+	// Most requests of an update cycle go to the same few provider
+	// API hosts, so keep more idle connections than the default 2.
+	const defaultMaxIdleConnsPerHost = 16
+	c.MaxIdleConnsPerHost = gosettings.DefaultPointer(c.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost)
+	const defaultIdleConnTimeout = 2 * time.Minute
+	c.IdleConnTimeout = gosettings.DefaultPointer(c.IdleConnTimeout, defaultIdleConnTimeout)
+	c.ForceAttemptHTTP2 = gosettings.DefaultPointer(c.ForceAttemptHTTP2, true)
+	c.AcceptLanguage = gosettings.DefaultPointer(c.AcceptLanguage, "en")
 }
 
+var ErrLocalAddressNotFound = errors.New("local address not found on any network interface")
+
 func (c Client) Validate() (err error) {
-	return nil
+	if !c.LocalAddress.IsValid() {
+		return nil
+	}
+
+	interfaceAddresses, err := net.InterfaceAddrs()
+	if err != nil {
+		return fmt.Errorf("listing network interfaces addresses: %w", err)
+	}
+	for _, interfaceAddress := range interfaceAddresses {
+		ipNet, ok := interfaceAddress.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip, ok := netip.AddrFromSlice(ipNet.IP)
+		if ok && ip.Unmap() == c.LocalAddress.Unmap() {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrLocalAddressNotFound, c.LocalAddress)
 }
 
 func (c Client) String() string {
@@ -28,14 +74,45 @@ func (c Client) String() string {
 func (c Client) toLinesNode() *gotree.Node {
 	node := gotree.New("HTTP client")
 	node.Appendf("Timeout: %s", c.Timeout)
+	if c.LocalAddress.IsValid() {
+		node.Appendf("Local address: %s", c.LocalAddress)
+	}
+	node.Appendf("Max idle connections per host: %d", *c.MaxIdleConnsPerHost)
+	node.Appendf("Idle connection timeout: %s", *c.IdleConnTimeout)
+	node.Appendf("Force attempt HTTP/2: %s", gosettings.BoolToYesNo(c.ForceAttemptHTTP2))
+	if *c.AcceptLanguage != "" {
+		node.Appendf("Provider Accept-Language: %s", *c.AcceptLanguage)
+	}
 	return node
 }
 
-func (c *Client) read(reader *reader.Reader) (err error) {
-	c.Timeout, err = reader.Duration("HTTP_TIMEOUT")
+func (c *Client) read(r *reader.Reader) (err error) {
+	c.Timeout, err = r.Duration("HTTP_TIMEOUT")
+	if err != nil {
+		return err
+	}
+
+	c.LocalAddress, err = r.NetipAddr("HTTP_LOCAL_ADDRESS")
 	if err != nil {
 		return err
 	}
 
+	c.MaxIdleConnsPerHost, err = r.UintPtr("HTTP_MAX_IDLE_CONNS_PER_HOST")
+	if err != nil {
+		return err
+	}
+
+	c.IdleConnTimeout, err = r.DurationPtr("HTTP_IDLE_CONN_TIMEOUT")
+	if err != nil {
+		return err
+	}
+
+	c.ForceAttemptHTTP2, err = r.BoolPtr("HTTP_FORCE_ATTEMPT_HTTP2")
+	if err != nil {
+		return err
+	}
+
+	c.AcceptLanguage = r.Get("HTTP_ACCEPT_LANGUAGE", reader.AcceptEmpty(true))
+
 	return nil
 }
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
@@ -13,19 +17,60 @@ import (
 type Health struct {
 	ServerAddress      *string
 	HealthchecksioUUID *string
+	// HealthchecksioBaseURL is the base URL of the healthchecks.io
+	// ping server, to use a self-hosted or compatible instance.
+	HealthchecksioBaseURL *string
+	// PingStartURL, PingSuccessURL and PingFailureURL are URLs
+	// to ping respectively at the start of each update cycle, at
+	// the end of each successful update cycle and at the end of
+	// each failed update cycle, for external monitoring.
+	// They are disabled if empty.
+	PingStartURL   *string
+	PingSuccessURL *string
+	PingFailureURL *string
+	// PingMethod is the HTTP method to ping the URLs with,
+	// and can be GET or POST.
+	PingMethod string
 }
 
 func (h *Health) SetDefaults() {
 	h.ServerAddress = gosettings.DefaultPointer(h.ServerAddress, "127.0.0.1:9999")
 	h.HealthchecksioUUID = gosettings.DefaultPointer(h.HealthchecksioUUID, "")
+	h.HealthchecksioBaseURL = gosettings.DefaultPointer(h.HealthchecksioBaseURL, "https://hc-ping.com")
+	h.PingStartURL = gosettings.DefaultPointer(h.PingStartURL, "")
+	h.PingSuccessURL = gosettings.DefaultPointer(h.PingSuccessURL, "")
+	h.PingFailureURL = gosettings.DefaultPointer(h.PingFailureURL, "")
+	h.PingMethod = gosettings.DefaultComparable(h.PingMethod, http.MethodGet)
 }
 
+var ErrPingURLNotValid = errors.New("ping URL is not a valid HTTP(S) URL")
+
 func (h Health) Validate() (err error) {
 	err = validate.ListeningAddress(*h.ServerAddress, os.Getuid())
 	if err != nil {
 		return fmt.Errorf("server listening address: %w", err)
 	}
 
+	parsed, err := url.Parse(*h.HealthchecksioBaseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("healthchecks.io base URL: %w: %s", ErrPingURLNotValid, *h.HealthchecksioBaseURL)
+	}
+
+	for _, pingURL := range [...]string{*h.PingStartURL, *h.PingSuccessURL, *h.PingFailureURL} {
+		if pingURL == "" {
+			continue
+		}
+		parsed, err := url.Parse(pingURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%w: %s", ErrPingURLNotValid, pingURL)
+		}
+	}
+
+	err = validate.IsOneOf(h.PingMethod, http.MethodGet, http.MethodPost)
+	if err != nil {
+		return fmt.Errorf("ping method: %w", err)
+	}
+
 	return nil
 }
 
@@ -38,11 +83,30 @@ func (h Health) toLinesNode() *gotree.Node {
 	node.Appendf("Server listening address: %s", *h.ServerAddress)
 	if *h.HealthchecksioUUID != "" {
 		node.Appendf("Healthchecks.io UUID: %s", *h.HealthchecksioUUID)
+		node.Appendf("Healthchecks.io base URL: %s", *h.HealthchecksioBaseURL)
+	}
+	if *h.PingStartURL == "" && *h.PingSuccessURL == "" && *h.PingFailureURL == "" {
+		return node
+	}
+	pingNode := node.Appendf("Ping URLs with %s:", h.PingMethod)
+	if *h.PingStartURL != "" {
+		pingNode.Appendf("Start: %s", *h.PingStartURL)
+	}
+	if *h.PingSuccessURL != "" {
+		pingNode.Appendf("Success: %s", *h.PingSuccessURL)
+	}
+	if *h.PingFailureURL != "" {
+		pingNode.Appendf("Failure: %s", *h.PingFailureURL)
 	}
 	return node
 }
 
-func (h *Health) Read(reader *reader.Reader) {
-	h.ServerAddress = reader.Get("HEALTH_SERVER_ADDRESS")
-	h.HealthchecksioUUID = reader.Get("HEALTH_HEALTHCHECKSIO_UUID")
+func (h *Health) Read(r *reader.Reader) {
+	h.ServerAddress = r.Get("HEALTH_SERVER_ADDRESS")
+	h.HealthchecksioUUID = r.Get("HEALTH_HEALTHCHECKSIO_UUID")
+	h.HealthchecksioBaseURL = r.Get("HEALTH_HEALTHCHECKSIO_BASE_URL", reader.ForceLowercase(false))
+	h.PingStartURL = r.Get("HEALTH_PING_START_URL", reader.ForceLowercase(false))
+	h.PingSuccessURL = r.Get("HEALTH_PING_SUCCESS_URL", reader.ForceLowercase(false))
+	h.PingFailureURL = r.Get("HEALTH_PING_FAILURE_URL", reader.ForceLowercase(false))
+	h.PingMethod = strings.ToUpper(r.String("HEALTH_PING_METHOD"))
 }
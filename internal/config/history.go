@@ -0,0 +1,55 @@
+package config
+
+import (
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+type History struct {
+	// MaxAgeDays is the maximum age in days of the IP address
+	// history events kept on disk, and is zero to keep them
+	// all. The latest event of each record is always kept.
+	MaxAgeDays *uint
+	// MaxEvents is the maximum number of IP address history
+	// events kept on disk per record, and is zero to keep them all.
+	MaxEvents *uint
+}
+
+func (h *History) setDefaults() {
+	h.MaxAgeDays = gosettings.DefaultPointer(h.MaxAgeDays, 0)
+	h.MaxEvents = gosettings.DefaultPointer(h.MaxEvents, 0)
+}
+
+func (h History) Validate() (err error) {
+	return nil
+}
+
+func (h History) String() string {
+	return h.toLinesNode().String()
+}
+
+func (h History) toLinesNode() *gotree.Node {
+	if *h.MaxAgeDays == 0 && *h.MaxEvents == 0 {
+		return nil // history is kept forever
+	}
+
+	node := gotree.New("History retention")
+	if *h.MaxAgeDays > 0 {
+		node.Appendf("Maximum age: %d days", *h.MaxAgeDays)
+	}
+	if *h.MaxEvents > 0 {
+		node.Appendf("Maximum events per record: %d", *h.MaxEvents)
+	}
+	return node
+}
+
+func (h *History) read(r *reader.Reader) (err error) {
+	h.MaxAgeDays, err = r.UintPtr("HISTORY_MAX_AGE_DAYS")
+	if err != nil {
+		return err
+	}
+
+	h.MaxEvents, err = r.UintPtr("HISTORY_MAX_EVENTS")
+	return err
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+type Hook struct {
+	// Command is the command run after a record IP address changed,
+	// split on spaces into the program and its arguments. It is not
+	// run in a shell. It is the empty string to disable the hook.
+	Command *string
+	// Timeout is the maximum duration of the command,
+	// after which it is killed.
+	Timeout time.Duration
+}
+
+func (h *Hook) setDefaults() {
+	h.Command = gosettings.DefaultPointer(h.Command, "")
+	const defaultTimeout = time.Minute
+	h.Timeout = gosettings.DefaultComparable(h.Timeout, defaultTimeout)
+}
+
+func (h Hook) Validate() (err error) {
+	fields := strings.Fields(*h.Command)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	_, err = exec.LookPath(fields[0])
+	if err != nil {
+		return fmt.Errorf("command: %w", err)
+	}
+
+	const minTimeout = time.Second
+	if h.Timeout < minTimeout {
+		return fmt.Errorf("%w: %s is below the minimum %s",
+			ErrTimeoutTooLow, h.Timeout, minTimeout)
+	}
+
+	return nil
+}
+
+func (h Hook) String() string {
+	return h.toLinesNode().String()
+}
+
+func (h Hook) toLinesNode() *gotree.Node {
+	if *h.Command == "" {
+		return nil // no command means the hook is disabled
+	}
+
+	node := gotree.New("Post-update hook")
+	node.Appendf("Command: %s", *h.Command)
+	node.Appendf("Timeout: %s", h.Timeout)
+	return node
+}
+
+func (h *Hook) read(r *reader.Reader) (err error) {
+	h.Command = r.Get("HOOK_COMMAND", reader.ForceLowercase(false))
+	h.Timeout, err = r.Duration("HOOK_TIMEOUT")
+	return err
+}
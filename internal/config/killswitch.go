@@ -0,0 +1,75 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/validate"
+	"github.com/qdm12/gotree"
+)
+
+type KillSwitch struct {
+	// URL is the URL polled before each update cycle to check
+	// if updates are paused. It is disabled if empty.
+	URL *string
+	// OnError is the policy applied if the URL cannot be checked,
+	// and can be "update" to update anyway or "pause" to pause updates.
+	OnError string
+}
+
+const (
+	killSwitchOnErrorUpdate = "update"
+	killSwitchOnErrorPause  = "pause"
+)
+
+func (k *KillSwitch) setDefaults() {
+	k.URL = gosettings.DefaultPointer(k.URL, "")
+	k.OnError = gosettings.DefaultComparable(k.OnError, killSwitchOnErrorUpdate)
+}
+
+var ErrKillSwitchURLNotValid = errors.New("kill switch URL is not a valid HTTP(S) URL")
+
+func (k KillSwitch) Validate() (err error) {
+	if *k.URL != "" {
+		parsed, err := url.Parse(*k.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%w: %s", ErrKillSwitchURLNotValid, *k.URL)
+		}
+	}
+
+	err = validate.IsOneOf(k.OnError, killSwitchOnErrorUpdate, killSwitchOnErrorPause)
+	if err != nil {
+		return fmt.Errorf("on error policy: %w", err)
+	}
+
+	return nil
+}
+
+// PauseOnError returns true if updates should be paused
+// if the kill switch URL cannot be checked.
+func (k KillSwitch) PauseOnError() bool {
+	return k.OnError == killSwitchOnErrorPause
+}
+
+func (k KillSwitch) String() string {
+	return k.toLinesNode().String()
+}
+
+func (k KillSwitch) toLinesNode() *gotree.Node {
+	if *k.URL == "" {
+		return nil // kill switch disabled
+	}
+
+	node := gotree.New("Kill switch")
+	node.Appendf("URL: %s", *k.URL)
+	node.Appendf("On error: %s", k.OnError)
+	return node
+}
+
+func (k *KillSwitch) read(r *reader.Reader) {
+	k.URL = r.Get("KILL_SWITCH_URL", reader.ForceLowercase(false))
+	k.OnError = r.String("KILL_SWITCH_ON_ERROR")
+}
@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
+	"github.com/qdm12/ddns-updater/internal/logfmt"
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
 	"github.com/qdm12/gosettings/validate"
@@ -14,11 +17,15 @@ import (
 type Logger struct {
 	Level  string
 	Caller string
+	// Format is the format of log lines, and can be
+	// "text" or "logfmt" for key=value pairs.
+	Format string
 }
 
 func (l *Logger) setDefaults() {
 	l.Level = gosettings.DefaultComparable(l.Level, log.LevelInfo.String())
 	l.Caller = gosettings.DefaultComparable(l.Caller, "hidden")
+	l.Format = gosettings.DefaultComparable(l.Format, "text")
 }
 
 func (l Logger) Validate() (err error) {
@@ -32,15 +39,27 @@ func (l Logger) Validate() (err error) {
 		return fmt.Errorf("log caller: %w", err)
 	}
 
+	err = validate.IsOneOf(l.Format, "text", "logfmt")
+	if err != nil {
+		return fmt.Errorf("log format: %w", err)
+	}
+
 	return nil
 }
 
-func (l Logger) ToOptions() (options []log.Option) {
+// ToOptions returns the logger options, with log lines
+// written to the writer given.
+func (l Logger) ToOptions(writer io.Writer) (options []log.Option) {
 	level, _ := log.ParseLevel(l.Level)
 	options = append(options, log.SetLevel(level))
 	if l.Caller == "short" {
 		options = append(options, log.SetCallerFile(true), log.SetCallerLine(true))
 	}
+	if l.Format == "logfmt" {
+		writer = logfmt.NewWriter(writer)
+		options = append(options, log.SetTimeFormat(time.RFC3339))
+	}
+	options = append(options, log.SetWriters(writer))
 	return options
 }
 
@@ -52,6 +71,9 @@ func (l Logger) toLinesNode() *gotree.Node {
 	node := gotree.New("Logger")
 	node.Appendf("Level: %s", l.Level)
 	node.Appendf("Caller: %s", l.Caller)
+	if l.Format != "text" {
+		node.Appendf("Format: %s", l.Format)
+	}
 	return node
 }
 
@@ -62,4 +84,5 @@ func (l *Logger) read(reader *reader.Reader) {
 		l.Level = "warn"
 	}
 	l.Caller = reader.String("LOG_CALLER")
+	l.Format = reader.String("LOG_FORMAT")
 }
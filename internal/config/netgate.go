@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+type NetworkGate struct {
+	// Interfaces are the network interface names of the default route
+	// for which updates are allowed. Updates are allowed on all networks
+	// if both Interfaces and GatewayMACs are empty.
+	Interfaces []string
+	// GatewayMACs are the MAC addresses of the default route
+	// gateway for which updates are allowed.
+	GatewayMACs []string
+}
+
+func (n *NetworkGate) setDefaults() {
+	n.Interfaces = gosettings.DefaultSlice(n.Interfaces, []string{})
+	n.GatewayMACs = gosettings.DefaultSlice(n.GatewayMACs, []string{})
+}
+
+func (n NetworkGate) Validate() (err error) {
+	for _, mac := range n.GatewayMACs {
+		_, err = net.ParseMAC(mac)
+		if err != nil {
+			return fmt.Errorf("gateway MAC address: %w", err)
+		}
+	}
+	return nil
+}
+
+func (n NetworkGate) String() string {
+	return n.toLinesNode().String()
+}
+
+func (n NetworkGate) toLinesNode() *gotree.Node {
+	if len(n.Interfaces) == 0 && len(n.GatewayMACs) == 0 {
+		return nil // all networks are allowed
+	}
+
+	node := gotree.New("Network gate")
+	if len(n.Interfaces) > 0 {
+		childNode := node.Appendf("Allowed interfaces")
+		for _, name := range n.Interfaces {
+			childNode.Appendf(name)
+		}
+	}
+	if len(n.GatewayMACs) > 0 {
+		childNode := node.Appendf("Allowed gateway MAC addresses")
+		for _, mac := range n.GatewayMACs {
+			childNode.Appendf(mac)
+		}
+	}
+	return node
+}
+
+func (n *NetworkGate) read(r *reader.Reader) {
+	n.Interfaces = r.CSV("UPDATE_NETWORK_INTERFACES", reader.ForceLowercase(false))
+	n.GatewayMACs = r.CSV("UPDATE_NETWORK_GATEWAY_MACS")
+}
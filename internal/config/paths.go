@@ -8,10 +8,14 @@ import (
 
 type Paths struct {
 	DataDir *string
+	// ImportSnapshot is the path to a snapshot file to import
+	// on startup. It is the empty string to disable importing.
+	ImportSnapshot *string
 }
 
 func (p *Paths) setDefaults() {
 	p.DataDir = gosettings.DefaultPointer(p.DataDir, "./data")
+	p.ImportSnapshot = gosettings.DefaultPointer(p.ImportSnapshot, "")
 }
 
 func (p Paths) Validate() (err error) {
@@ -25,9 +29,13 @@ func (p Paths) String() string {
 func (p Paths) toLinesNode() *gotree.Node {
 	node := gotree.New("Paths")
 	node.Appendf("Data directory: %s", *p.DataDir)
+	if *p.ImportSnapshot != "" {
+		node.Appendf("Snapshot to import: %s", *p.ImportSnapshot)
+	}
 	return node
 }
 
 func (p *Paths) read(reader *reader.Reader) {
 	p.DataDir = reader.Get("DATADIR")
+	p.ImportSnapshot = reader.Get("IMPORT_SNAPSHOT_FILEPATH")
 }
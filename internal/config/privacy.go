@@ -0,0 +1,38 @@
+package config
+
+import (
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+type Privacy struct {
+	// AnonymizeIPs is whether to mask the last IPv4 octet and the
+	// IPv6 interface identifier of IP addresses shown in logs, in the
+	// web UI and in notifications. It is purely presentational and
+	// the full IP address is still used to update records.
+	AnonymizeIPs *bool
+}
+
+func (p *Privacy) setDefaults() {
+	p.AnonymizeIPs = gosettings.DefaultPointer(p.AnonymizeIPs, false)
+}
+
+func (p Privacy) Validate() (err error) {
+	return nil
+}
+
+func (p Privacy) String() string {
+	return p.toLinesNode().String()
+}
+
+func (p Privacy) toLinesNode() *gotree.Node {
+	node := gotree.New("Privacy")
+	node.Appendf("Anonymize IP addresses: %s", gosettings.BoolToYesNo(p.AnonymizeIPs))
+	return node
+}
+
+func (p *Privacy) read(reader *reader.Reader) (err error) {
+	p.AnonymizeIPs, err = reader.BoolPtr("ANONYMIZE_IPS")
+	return err
+}
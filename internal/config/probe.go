@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+type Probe struct {
+	// Port is the port to probe on the public IP address
+	// before updating records. It is zero to disable the probe.
+	Port *uint16
+	// HTTPPath is the path to send an HTTP GET request to, and
+	// is the empty string to only attempt a TCP connection.
+	HTTPPath *string
+	Timeout  time.Duration
+}
+
+func (p *Probe) setDefaults() {
+	p.Port = gosettings.DefaultPointer(p.Port, 0)
+	p.HTTPPath = gosettings.DefaultPointer(p.HTTPPath, "")
+	const defaultTimeout = 3 * time.Second
+	p.Timeout = gosettings.DefaultComparable(p.Timeout, defaultTimeout)
+}
+
+var ErrProbeHTTPPathNotValid = errors.New("HTTP path must start with /")
+
+func (p Probe) Validate() (err error) {
+	if *p.HTTPPath != "" && !strings.HasPrefix(*p.HTTPPath, "/") {
+		return fmt.Errorf("%w: %s", ErrProbeHTTPPathNotValid, *p.HTTPPath)
+	}
+	return nil
+}
+
+func (p Probe) String() string {
+	return p.toLinesNode().String()
+}
+
+func (p Probe) toLinesNode() *gotree.Node {
+	if *p.Port == 0 {
+		return gotree.New("Reachability probe: disabled")
+	}
+	node := gotree.New("Reachability probe")
+	node.Appendf("Port: %d", *p.Port)
+	if *p.HTTPPath != "" {
+		node.Appendf("HTTP path: %s", *p.HTTPPath)
+	}
+	node.Appendf("Timeout: %s", p.Timeout)
+	return node
+}
+
+func (p *Probe) read(r *reader.Reader) (err error) {
+	p.Port, err = r.Uint16Ptr("PROBE_PORT")
+	if err != nil {
+		return err
+	}
+
+	p.HTTPPath = r.Get("PROBE_HTTP_PATH", reader.ForceLowercase(false))
+
+	p.Timeout, err = r.Duration("PROBE_TIMEOUT")
+	return err
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/qdm12/ddns-updater/pkg/publicip/dns"
 	"github.com/qdm12/ddns-updater/pkg/publicip/http"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/qdm12/ddns-updater/pkg/publicip/metadata"
+	"github.com/qdm12/ddns-updater/pkg/publicip/stun"
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
 	"github.com/qdm12/gosettings/validate"
@@ -24,6 +26,32 @@ type PubIP struct {
 	DNSEnabled        *bool
 	DNSProviders      []string
 	DNSTimeout        time.Duration
+	// RouteEnabled is whether to use the local address of the
+	// default route as public IP address, for hosts with a public
+	// IP address assigned directly to a network interface.
+	RouteEnabled *bool
+	// FileEnabled is whether to read the public IP addresses from
+	// the file at FilePath, which is watched for changes.
+	FileEnabled *bool
+	FilePath    string
+	// MetadataEnabled is whether to obtain the public IP addresses
+	// from the instance metadata service of MetadataProvider.
+	MetadataEnabled  *bool
+	MetadataProvider string
+	// STUNEnabled is whether to obtain the public IP addresses
+	// with STUN binding requests to the STUNServers.
+	STUNEnabled *bool
+	STUNServers []string
+	// InterfaceEnabled is whether to read the public IP addresses
+	// from the network interface named InterfaceName.
+	InterfaceEnabled *bool
+	InterfaceName    string
+	// UPnPEnabled is whether to obtain the public IPv4 address
+	// from the local router using UPnP.
+	UPnPEnabled *bool
+	// CGNATWarning is whether to log a warning once per record
+	// if the public IP address is in the carrier-grade NAT range.
+	CGNATWarning *bool
 }
 
 func (p *PubIP) setDefaults() {
@@ -35,6 +63,15 @@ func (p *PubIP) setDefaults() {
 	p.DNSProviders = gosettings.DefaultSlice(p.DNSProviders, []string{all})
 	const defaultDNSTimeout = 3 * time.Second
 	p.DNSTimeout = gosettings.DefaultComparable(p.DNSTimeout, defaultDNSTimeout)
+	p.RouteEnabled = gosettings.DefaultPointer(p.RouteEnabled, false)
+	p.FileEnabled = gosettings.DefaultPointer(p.FileEnabled, false)
+	p.MetadataEnabled = gosettings.DefaultPointer(p.MetadataEnabled, false)
+	p.STUNEnabled = gosettings.DefaultPointer(p.STUNEnabled, false)
+	p.InterfaceEnabled = gosettings.DefaultPointer(p.InterfaceEnabled, false)
+	p.UPnPEnabled = gosettings.DefaultPointer(p.UPnPEnabled, false)
+	p.STUNServers = gosettings.DefaultSlice(p.STUNServers,
+		[]string{"stun.l.google.com:19302", "stun.cloudflare.com:3478"})
+	p.CGNATWarning = gosettings.DefaultPointer(p.CGNATWarning, true)
 }
 
 func (p PubIP) Validate() (err error) {
@@ -58,6 +95,33 @@ func (p PubIP) Validate() (err error) {
 		return fmt.Errorf("DNS providers: %w", err)
 	}
 
+	if *p.FileEnabled && p.FilePath == "" {
+		return fmt.Errorf("%w", ErrPublicIPFileNotSet)
+	}
+
+	if *p.InterfaceEnabled && p.InterfaceName == "" {
+		return fmt.Errorf("%w", ErrPublicIPInterfaceNotSet)
+	}
+
+	if *p.MetadataEnabled {
+		providers := metadata.ListProviders()
+		choices := make([]string, len(providers))
+		for i, provider := range providers {
+			choices[i] = string(provider)
+		}
+		err = validate.IsOneOf(p.MetadataProvider, choices...)
+		if err != nil {
+			return fmt.Errorf("metadata provider: %w", err)
+		}
+	}
+
+	if *p.STUNEnabled {
+		_, err = stun.New(p.ToSTUNOptions()...)
+		if err != nil {
+			return fmt.Errorf("STUN servers: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -95,6 +159,35 @@ func (p *PubIP) toLinesNode() (node *gotree.Node) {
 		}
 	}
 
+	if *p.RouteEnabled {
+		node.Appendf("Default route enabled: yes")
+	}
+
+	if *p.FileEnabled {
+		node.Appendf("File: %s", p.FilePath)
+	}
+
+	if *p.MetadataEnabled {
+		node.Appendf("Metadata provider: %s", p.MetadataProvider)
+	}
+
+	if *p.InterfaceEnabled {
+		node.Appendf("Network interface: %s", p.InterfaceName)
+	}
+
+	if *p.UPnPEnabled {
+		node.Appendf("UPnP enabled: yes")
+	}
+
+	if *p.STUNEnabled {
+		childNode := node.Appendf("STUN servers")
+		for _, server := range p.STUNServers {
+			childNode.Appendf(server)
+		}
+	}
+
+	node.Appendf("CGNAT warning: %s", gosettings.BoolToYesNo(p.CGNATWarning))
+
 	return node
 }
 
@@ -133,6 +226,16 @@ func stringsToHTTPProviders(providers []string, ipVersion ipversion.IPVersion) (
 	return updatedProviders
 }
 
+// ToSTUNOptions returns no option if no STUN server is set.
+func (p *PubIP) ToSTUNOptions() (options []stun.Option) {
+	if len(p.STUNServers) == 0 {
+		return nil
+	}
+	return []stun.Option{
+		stun.SetServers(p.STUNServers[0], p.STUNServers[1:]...),
+	}
+}
+
 // ToDNSPOptions assumes the settings have been validated.
 func (p *PubIP) ToDNSPOptions() (options []dns.Option) {
 	uniqueProviders := make(map[string]struct{}, len(p.DNSProviders))
@@ -159,7 +262,9 @@ func (p *PubIP) ToDNSPOptions() (options []dns.Option) {
 }
 
 var (
-	ErrNoPublicIPDNSProvider = errors.New("no public IP DNS provider specified")
+	ErrNoPublicIPDNSProvider   = errors.New("no public IP DNS provider specified")
+	ErrPublicIPFileNotSet      = errors.New("public IP file path is not set")
+	ErrPublicIPInterfaceNotSet = errors.New("public IP network interface is not set")
 )
 
 func (p PubIP) validateDNSProviders() (err error) {
@@ -234,11 +339,16 @@ func validateHTTPIPProviders(providerStrings []string,
 }
 
 func (p *PubIP) read(r *reader.Reader, warner Warner) (err error) {
-	p.HTTPEnabled, p.DNSEnabled, err = getFetchers(r)
+	err = p.readFetchers(r)
 	if err != nil {
 		return err
 	}
 
+	p.FilePath = r.String("PUBLICIP_FILE", reader.ForceLowercase(false))
+	p.MetadataProvider = r.String("PUBLICIP_METADATA_PROVIDER")
+	p.STUNServers = r.CSV("PUBLICIP_STUN_SERVERS")
+	p.InterfaceName = r.String("PUBLICIP_INTERFACE", reader.ForceLowercase(false))
+
 	p.HTTPIPProviders = r.CSV("PUBLICIP_HTTP_PROVIDERS",
 		reader.RetroKeys("IP_METHOD"))
 	p.HTTPIPv4Providers = r.CSV("PUBLICIPV4_HTTP_PROVIDERS",
@@ -287,37 +397,57 @@ func (p *PubIP) read(r *reader.Reader, warner Warner) (err error) {
 		return err
 	}
 
+	p.CGNATWarning, err = r.BoolPtr("PUBLICIP_CGNAT_WARNING")
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 var ErrFetcherNotValid = errors.New("fetcher is not valid")
 
-func getFetchers(reader *reader.Reader) (http, dns *bool, err error) {
+// readFetchers sets the enabled fetcher types from PUBLICIP_FETCHERS,
+// and leaves them unset if it is empty.
+func (p *PubIP) readFetchers(reader *reader.Reader) (err error) {
 	// TODO change to use reader.BoolPtr with retro-compatibility
 	s := reader.String("PUBLICIP_FETCHERS")
 	if s == "" {
-		return nil, nil, nil
+		return nil
 	}
 
-	http, dns = new(bool), new(bool)
+	p.HTTPEnabled, p.DNSEnabled, p.RouteEnabled = new(bool), new(bool), new(bool)
+	p.FileEnabled, p.MetadataEnabled, p.STUNEnabled = new(bool), new(bool), new(bool)
+	p.InterfaceEnabled, p.UPnPEnabled = new(bool), new(bool)
 	fields := strings.Split(s, ",")
 	for i, field := range fields {
 		switch strings.ToLower(field) {
 		case "all":
-			*http = true
-			*dns = true
+			*p.HTTPEnabled = true
+			*p.DNSEnabled = true
 		case "http":
-			*http = true
+			*p.HTTPEnabled = true
 		case "dns":
-			*dns = true
+			*p.DNSEnabled = true
+		case "route":
+			*p.RouteEnabled = true
+		case "file":
+			*p.FileEnabled = true
+		case "metadata":
+			*p.MetadataEnabled = true
+		case "stun":
+			*p.STUNEnabled = true
+		case "interface":
+			*p.InterfaceEnabled = true
+		case "upnp":
+			*p.UPnPEnabled = true
 		default:
-			return nil, nil, fmt.Errorf(
-				"%w: %q at position %d of %d",
+			return fmt.Errorf("%w: %q at position %d of %d",
 				ErrFetcherNotValid, field, i+1, len(fields))
 		}
 	}
 
-	return http, dns, nil
+	return nil
 }
 
 func handleRetroProvider(provider string) (updatedProvider string) {
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/qdm12/ddns-updater/internal/server"
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
 	"github.com/qdm12/gosettings/validate"
@@ -13,6 +16,14 @@ import (
 type Server struct {
 	ListeningAddress string
 	RootURL          string
+	// AuthUsername and AuthPassword are the HTTP basic authentication
+	// credentials required to access the server, and are both empty
+	// to disable basic authentication.
+	AuthUsername string
+	AuthPassword string
+	// AuthToken is the bearer token accepted to access the server,
+	// and is empty to disable token authentication.
+	AuthToken string
 }
 
 func (s *Server) setDefaults() {
@@ -28,9 +39,24 @@ func (s Server) Validate() (err error) {
 
 	// TODO validate RootURL
 
+	if (s.AuthUsername == "") != (s.AuthPassword == "") {
+		return fmt.Errorf("%w", ErrBasicAuthIncomplete)
+	}
+
 	return nil
 }
 
+var ErrBasicAuthIncomplete = errors.New("basic authentication username and password must be set together")
+
+// Auth returns the server authentication settings.
+func (s Server) Auth() server.Auth {
+	return server.Auth{
+		Username: s.AuthUsername,
+		Password: s.AuthPassword,
+		Token:    s.AuthToken,
+	}
+}
+
 func (s Server) String() string {
 	return s.toLinesNode().String()
 }
@@ -39,14 +65,24 @@ func (s Server) toLinesNode() *gotree.Node {
 	node := gotree.New("Server")
 	node.Appendf("Listening address: %s", s.ListeningAddress)
 	node.Appendf("Root URL: %s", s.RootURL)
+	var authMethods []string
+	if s.AuthUsername != "" {
+		authMethods = append(authMethods, "basic")
+	}
+	if s.AuthToken != "" {
+		authMethods = append(authMethods, "token")
+	}
+	if len(authMethods) > 0 {
+		node.Appendf("Authentication: %s", strings.Join(authMethods, ", "))
+	}
 	return node
 }
 
-func (s *Server) read(reader *reader.Reader, warner Warner) (err error) {
-	s.RootURL = reader.String("ROOT_URL")
+func (s *Server) read(r *reader.Reader, warner Warner) (err error) {
+	s.RootURL = r.String("ROOT_URL")
 
 	// Retro-compatibility
-	port, err := reader.Uint16Ptr("LISTENING_PORT") // TODO change to address
+	port, err := r.Uint16Ptr("LISTENING_PORT") // TODO change to address
 	if err != nil {
 		handleDeprecated(warner, "LISTENING_PORT", "LISTENING_ADDRESS")
 		return err
@@ -54,7 +90,10 @@ func (s *Server) read(reader *reader.Reader, warner Warner) (err error) {
 		s.ListeningAddress = fmt.Sprintf(":%d", *port)
 	}
 
-	s.ListeningAddress = reader.String("LISTENING_ADDRESS")
+	s.ListeningAddress = r.String("LISTENING_ADDRESS")
+	s.AuthUsername = r.String("SERVER_AUTH_USERNAME", reader.ForceLowercase(false))
+	s.AuthPassword = r.String("SERVER_AUTH_PASSWORD", reader.ForceLowercase(false))
+	s.AuthToken = r.String("SERVER_AUTH_TOKEN", reader.ForceLowercase(false))
 
 	return err
 }
@@ -8,29 +8,43 @@ import (
 )
 
 type Config struct {
-	Client   Client
-	Update   Update
-	PubIP    PubIP
-	Resolver Resolver
-	Server   Server
-	Health   Health
-	Paths    Paths
-	Backup   Backup
-	Logger   Logger
-	Shoutrrr Shoutrrr
+	Client     Client
+	Update     Update
+	Network    NetworkGate
+	KillSwitch KillSwitch
+	PubIP      PubIP
+	Probe      Probe
+	Resolver   Resolver
+	Server     Server
+	Health     Health
+	Paths      Paths
+	Backup     Backup
+	History    History
+	Logger     Logger
+	Privacy    Privacy
+	Shoutrrr   Shoutrrr
+	Hook       Hook
+	Webhook    Webhook
 }
 
 func (c *Config) SetDefaults() {
 	c.Client.setDefaults()
 	c.Update.setDefaults()
+	c.Network.setDefaults()
+	c.KillSwitch.setDefaults()
 	c.PubIP.setDefaults()
+	c.Probe.setDefaults()
 	c.Resolver.setDefaults()
 	c.Server.setDefaults()
 	c.Health.SetDefaults()
 	c.Paths.setDefaults()
 	c.Backup.setDefaults()
+	c.History.setDefaults()
 	c.Logger.setDefaults()
+	c.Privacy.setDefaults()
 	c.Shoutrrr.setDefaults()
+	c.Hook.setDefaults()
+	c.Webhook.setDefaults()
 }
 
 func (c Config) Validate() (err error) {
@@ -38,16 +52,23 @@ func (c Config) Validate() (err error) {
 		Validate() (err error)
 	}
 	toValidate := map[string]validator{
-		"client":    &c.Client,
-		"update":    &c.Update,
-		"public ip": &c.PubIP,
-		"resolver":  &c.Resolver,
-		"server":    &c.Server,
-		"health":    &c.Health,
-		"paths":     &c.Paths,
-		"backup":    &c.Backup,
-		"logger":    &c.Logger,
-		"shoutrrr":  &c.Shoutrrr,
+		"client":      &c.Client,
+		"update":      &c.Update,
+		"network":     &c.Network,
+		"kill switch": &c.KillSwitch,
+		"public ip":   &c.PubIP,
+		"probe":       &c.Probe,
+		"resolver":    &c.Resolver,
+		"server":      &c.Server,
+		"health":      &c.Health,
+		"paths":       &c.Paths,
+		"backup":      &c.Backup,
+		"history":     &c.History,
+		"logger":      &c.Logger,
+		"privacy":     &c.Privacy,
+		"shoutrrr":    &c.Shoutrrr,
+		"hook":        &c.Hook,
+		"webhook":     &c.Webhook,
 	}
 
 	for name, v := range toValidate {
@@ -68,14 +89,21 @@ func (c Config) toLinesNode() *gotree.Node {
 	node := gotree.New("Settings summary:")
 	node.AppendNode(c.Client.toLinesNode())
 	node.AppendNode(c.Update.toLinesNode())
+	node.AppendNode(c.Network.toLinesNode())
+	node.AppendNode(c.KillSwitch.toLinesNode())
 	node.AppendNode(c.PubIP.toLinesNode())
+	node.AppendNode(c.Probe.toLinesNode())
 	node.AppendNode(c.Resolver.ToLinesNode())
 	node.AppendNode(c.Server.toLinesNode())
 	node.AppendNode(c.Health.toLinesNode())
 	node.AppendNode(c.Paths.toLinesNode())
 	node.AppendNode(c.Backup.toLinesNode())
+	node.AppendNode(c.History.toLinesNode())
 	node.AppendNode(c.Logger.toLinesNode())
+	node.AppendNode(c.Privacy.toLinesNode())
 	node.AppendNode(c.Shoutrrr.ToLinesNode())
+	node.AppendNode(c.Hook.toLinesNode())
+	node.AppendNode(c.Webhook.toLinesNode())
 	return node
 }
 
@@ -91,11 +119,19 @@ func (c *Config) Read(reader *reader.Reader,
 		return fmt.Errorf("reading update settings: %w", err)
 	}
 
+	c.Network.read(reader)
+	c.KillSwitch.read(reader)
+
 	err = c.PubIP.read(reader, warner)
 	if err != nil {
 		return fmt.Errorf("reading public IP settings: %w", err)
 	}
 
+	err = c.Probe.read(reader)
+	if err != nil {
+		return fmt.Errorf("reading probe settings: %w", err)
+	}
+
 	err = c.Resolver.read(reader)
 	if err != nil {
 		return fmt.Errorf("reading resolver settings: %w", err)
@@ -114,12 +150,32 @@ func (c *Config) Read(reader *reader.Reader,
 		return fmt.Errorf("reading backup settings: %w", err)
 	}
 
+	err = c.History.read(reader)
+	if err != nil {
+		return fmt.Errorf("reading history settings: %w", err)
+	}
+
 	c.Logger.read(reader)
 
+	err = c.Privacy.read(reader)
+	if err != nil {
+		return fmt.Errorf("reading privacy settings: %w", err)
+	}
+
 	err = c.Shoutrrr.read(reader, warner)
 	if err != nil {
 		return fmt.Errorf("reading shoutrrr settings: %w", err)
 	}
 
+	err = c.Hook.read(reader)
+	if err != nil {
+		return fmt.Errorf("reading hook settings: %w", err)
+	}
+
+	err = c.Webhook.read(reader)
+	if err != nil {
+		return fmt.Errorf("reading webhook settings: %w", err)
+	}
+
 	return nil
 }
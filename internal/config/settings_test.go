@@ -16,10 +16,19 @@ func Test_Settings_String(t *testing.T) {
 
 	const expected = `Settings summary:
 ├── HTTP client
-|   └── Timeout: 20s
+|   ├── Timeout: 20s
+|   ├── Max idle connections per host: 16
+|   ├── Idle connection timeout: 2m0s
+|   ├── Force attempt HTTP/2: yes
+|   └── Provider Accept-Language: en
 ├── Update
 |   ├── Period: 10m0s
-|   └── Cooldown: 5m0s
+|   ├── Cooldown: 5m0s
+|   ├── Cycle timeout: 10m0s
+|   ├── Order: sorted
+|   ├── Duplicates: lenient
+|   ├── Transient error retries: 2
+|   └── Transient error retry delay: 15s
 ├── Public IP fetching
 |   ├── HTTP enabled: yes
 |   ├── HTTP IP providers
@@ -30,8 +39,10 @@ func Test_Settings_String(t *testing.T) {
 |   |   └── all
 |   ├── DNS enabled: yes
 |   ├── DNS timeout: 3s
-|   └── DNS over TLS providers
-|       └── all
+|   ├── DNS over TLS providers
+|   |   └── all
+|   └── CGNAT warning: yes
+├── Reachability probe: disabled
 ├── Resolver: use Go default resolver
 ├── Server
 |   ├── Listening address: :8000
@@ -41,8 +52,10 @@ func Test_Settings_String(t *testing.T) {
 ├── Paths
 |   └── Data directory: ./data
 ├── Backup: disabled
-└── Logger
-    ├── Level: INFO
-    └── Caller: hidden`
+├── Logger
+|   ├── Level: INFO
+|   └── Caller: hidden
+└── Privacy
+    └── Anonymize IP addresses: no`
 	assert.Equal(t, expected, s)
 }
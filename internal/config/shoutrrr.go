@@ -2,10 +2,14 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"net/url"
 	"path"
+	"text/template"
+	"time"
 
 	"github.com/containrrr/shoutrrr"
+	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
 	"github.com/qdm12/gotree"
@@ -14,11 +18,18 @@ import (
 type Shoutrrr struct {
 	Addresses    []string
 	DefaultTitle string
+	// Template is the Go text/template used to build the notification
+	// message when a record IP address changes. Its data is of type
+	// models.NotificationData.
+	Template string
 }
 
+const defaultShoutrrrTemplate = "{{.FQDN}} changed to {{.NewIP}}"
+
 func (s *Shoutrrr) setDefaults() {
 	s.Addresses = gosettings.DefaultSlice(s.Addresses, []string{})
 	s.DefaultTitle = gosettings.DefaultComparable(s.DefaultTitle, "DDNS Updater")
+	s.Template = gosettings.DefaultComparable(s.Template, defaultShoutrrrTemplate)
 }
 
 func (s Shoutrrr) Validate() (err error) {
@@ -26,9 +37,40 @@ func (s Shoutrrr) Validate() (err error) {
 	if err != nil {
 		return fmt.Errorf("shoutrrr addresses: %w", err)
 	}
+
+	notificationTemplate, err := s.NotificationTemplate()
+	if err != nil {
+		return err
+	}
+
+	// Execute the template once to catch errors such as unknown fields.
+	sampleData := models.NotificationData{
+		Host:     "sub",
+		Domain:   "example.com",
+		FQDN:     "sub.example.com",
+		Provider: "cloudflare",
+		OldIP:    "1.2.3.4",
+		NewIP:    "5.6.7.8",
+		Time:     time.Unix(0, 0),
+		Tags:     []string{"tag"},
+	}
+	err = notificationTemplate.Execute(io.Discard, sampleData)
+	if err != nil {
+		return fmt.Errorf("executing shoutrrr template: %w", err)
+	}
 	return nil
 }
 
+// NotificationTemplate parses and returns the notification template.
+func (s Shoutrrr) NotificationTemplate() (notificationTemplate *template.Template, err error) {
+	notificationTemplate, err = template.New("notification").
+		Option("missingkey=error").Parse(s.Template)
+	if err != nil {
+		return nil, fmt.Errorf("parsing shoutrrr template: %w", err)
+	}
+	return notificationTemplate, nil
+}
+
 func (s Shoutrrr) String() string {
 	return s.ToLinesNode().String()
 }
@@ -40,6 +82,7 @@ func (s Shoutrrr) ToLinesNode() *gotree.Node {
 
 	node := gotree.New("Shoutrrr")
 	node.Appendf("Default title: %s", s.DefaultTitle)
+	node.Appendf("Template: %s", s.Template)
 
 	childNode := node.Appendf("Addresses")
 	for _, address := range s.Addresses {
@@ -74,6 +117,7 @@ func (s *Shoutrrr) read(r *reader.Reader, warner Warner) (err error) {
 	}
 
 	s.DefaultTitle = r.String("SHOUTRRR_DEFAULT_TITLE", reader.ForceLowercase(false))
+	s.Template = r.String("SHOUTRRR_TEMPLATE", reader.ForceLowercase(false))
 	return nil
 }
 
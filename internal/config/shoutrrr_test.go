@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Shoutrrr_Validate_template(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		template   string
+		errMessage string
+	}{
+		"default": {
+			template: defaultShoutrrrTemplate,
+		},
+		"custom": {
+			template: "{{.Provider}}: {{.FQDN}} {{.OldIP}} -> {{.NewIP}} {{.Tags}}",
+		},
+		"parse_error": {
+			template:   "{{.FQDN",
+			errMessage: `parsing shoutrrr template: template: notification:1: unclosed action`,
+		},
+		"unknown_field": {
+			template: "{{.Unknown}}",
+			errMessage: `executing shoutrrr template: template: notification:1:2: ` +
+				`executing "notification" at <.Unknown>: can't evaluate field Unknown ` +
+				`in type models.NotificationData`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := Shoutrrr{Template: testCase.template}
+
+			err := settings.Validate()
+
+			if testCase.errMessage == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -1,17 +1,68 @@
 package config
 
 import (
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/validate"
 	"github.com/qdm12/gotree"
 )
 
+const (
+	// UpdateOrderSorted processes records sorted by domain and host.
+	UpdateOrderSorted = "sorted"
+	// UpdateOrderConfig processes records in the configuration order.
+	UpdateOrderConfig = "config"
+)
+
+const (
+	// DuplicatesStrict fails at startup on duplicate records.
+	DuplicatesStrict = "strict"
+	// DuplicatesLenient keeps only the first of duplicate records.
+	DuplicatesLenient = "lenient"
+)
+
 type Update struct {
 	Period   time.Duration
 	Cooldown time.Duration
+	// CycleTimeout is the maximum duration of an update cycle,
+	// after which remaining records are skipped until the next cycle.
+	CycleTimeout time.Duration
+	// SettleDelay is the duration to wait after startup before
+	// the first update, to avoid pushing a transient IP address.
+	// It is zero to disable the delay.
+	SettleDelay   time.Duration
+	VerifyRetries *uint
+	VerifyBackoff time.Duration
+	// TransientRetries is the maximum number of times a record failing
+	// with a transient error, such as a network error or an HTTP status
+	// 429 or 5xx, is retried within the cycle. It is zero to disable
+	// retries until the next cycle.
+	TransientRetries *uint
+	// TransientRetryDelay is the duration to wait before each retry.
+	TransientRetryDelay time.Duration
+	// Order is the order records are processed and displayed in,
+	// and can be UpdateOrderSorted or UpdateOrderConfig.
+	Order string
+	// Duplicates is how records with the same domain, host and
+	// IP version are handled, and can be DuplicatesStrict or
+	// DuplicatesLenient.
+	Duplicates string
+	// Concurrency is the maximum number of records updated
+	// at the same time. It defaults to 1 to update records
+	// one after the other.
+	Concurrency uint
+	// ZoneConcurrency is the maximum number of records of the
+	// same provider and domain updated at the same time, since
+	// some provider APIs penalize or mishandle concurrent edits
+	// of the same zone. It defaults to 1.
+	ZoneConcurrency uint
+	// DryRun is whether to only log the record changes that
+	// would be made, without updating any record.
+	DryRun *bool
 }
 
 func (u *Update) setDefaults() {
@@ -19,9 +70,33 @@ func (u *Update) setDefaults() {
 	u.Period = gosettings.DefaultComparable(u.Period, defaultPeriod)
 	const defaultCooldown = 5 * time.Minute
 	u.Cooldown = gosettings.DefaultComparable(u.Cooldown, defaultCooldown)
+	const defaultCycleTimeout = 10 * time.Minute
+	u.CycleTimeout = gosettings.DefaultComparable(u.CycleTimeout, defaultCycleTimeout)
+	u.SettleDelay = gosettings.DefaultComparable(u.SettleDelay, 0)
+	u.VerifyRetries = gosettings.DefaultPointer(u.VerifyRetries, 0)
+	const defaultVerifyBackoff = 2 * time.Second
+	u.VerifyBackoff = gosettings.DefaultComparable(u.VerifyBackoff, defaultVerifyBackoff)
+	const defaultTransientRetries = 2
+	u.TransientRetries = gosettings.DefaultPointer(u.TransientRetries, defaultTransientRetries)
+	const defaultTransientRetryDelay = 15 * time.Second
+	u.TransientRetryDelay = gosettings.DefaultComparable(u.TransientRetryDelay, defaultTransientRetryDelay)
+	u.Order = gosettings.DefaultComparable(u.Order, UpdateOrderSorted)
+	u.Duplicates = gosettings.DefaultComparable(u.Duplicates, DuplicatesLenient)
+	u.Concurrency = gosettings.DefaultComparable(u.Concurrency, 1)
+	u.ZoneConcurrency = gosettings.DefaultComparable(u.ZoneConcurrency, 1)
+	u.DryRun = gosettings.DefaultPointer(u.DryRun, false)
 }
 
 func (u Update) Validate() (err error) {
+	err = validate.IsOneOf(u.Order, UpdateOrderSorted, UpdateOrderConfig)
+	if err != nil {
+		return fmt.Errorf("order: %w", err)
+	}
+
+	err = validate.IsOneOf(u.Duplicates, DuplicatesStrict, DuplicatesLenient)
+	if err != nil {
+		return fmt.Errorf("duplicates: %w", err)
+	}
 	return nil
 }
 
@@ -33,6 +108,27 @@ func (u Update) toLinesNode() *gotree.Node {
 	node := gotree.New("Update")
 	node.Appendf("Period: %s", u.Period)
 	node.Appendf("Cooldown: %s", u.Cooldown)
+	node.Appendf("Cycle timeout: %s", u.CycleTimeout)
+	node.Appendf("Order: %s", u.Order)
+	node.Appendf("Duplicates: %s", u.Duplicates)
+	if u.SettleDelay > 0 {
+		node.Appendf("Settle delay: %s", u.SettleDelay)
+	}
+	if *u.VerifyRetries > 0 {
+		node.Appendf("IP verification retries: %d", *u.VerifyRetries)
+		node.Appendf("IP verification backoff: %s", u.VerifyBackoff)
+	}
+	if *u.TransientRetries > 0 {
+		node.Appendf("Transient error retries: %d", *u.TransientRetries)
+		node.Appendf("Transient error retry delay: %s", u.TransientRetryDelay)
+	}
+	if u.Concurrency > 1 {
+		node.Appendf("Concurrency: %d", u.Concurrency)
+		node.Appendf("Concurrency per zone: %d", u.ZoneConcurrency)
+	}
+	if *u.DryRun {
+		node.Appendf("Dry run: enabled")
+	}
 	return node
 }
 
@@ -43,7 +139,58 @@ func (u *Update) read(reader *reader.Reader, warner Warner) (err error) {
 	}
 
 	u.Cooldown, err = reader.Duration("UPDATE_COOLDOWN_PERIOD")
-	return err
+	if err != nil {
+		return err
+	}
+
+	u.CycleTimeout, err = reader.Duration("UPDATE_CYCLE_TIMEOUT")
+	if err != nil {
+		return err
+	}
+
+	u.SettleDelay, err = reader.Duration("UPDATE_SETTLE_DELAY")
+	if err != nil {
+		return err
+	}
+
+	u.VerifyRetries, err = reader.UintPtr("UPDATE_VERIFY_RETRIES")
+	if err != nil {
+		return err
+	}
+
+	u.VerifyBackoff, err = reader.Duration("UPDATE_VERIFY_BACKOFF")
+	if err != nil {
+		return err
+	}
+
+	u.TransientRetries, err = reader.UintPtr("UPDATE_TRANSIENT_RETRIES")
+	if err != nil {
+		return err
+	}
+
+	u.TransientRetryDelay, err = reader.Duration("UPDATE_TRANSIENT_RETRY_DELAY")
+	if err != nil {
+		return err
+	}
+
+	u.Order = reader.String("UPDATE_ORDER")
+	u.Duplicates = reader.String("UPDATE_DUPLICATES")
+
+	u.Concurrency, err = reader.Uint("UPDATE_CONCURRENCY")
+	if err != nil {
+		return err
+	}
+
+	u.ZoneConcurrency, err = reader.Uint("UPDATE_ZONE_CONCURRENCY")
+	if err != nil {
+		return err
+	}
+
+	u.DryRun, err = reader.BoolPtr("UPDATE_DRY_RUN")
+	if err != nil {
+		return err
+	}
+	return nil
 }
 
 func readUpdatePeriod(r *reader.Reader, warner Warner) (period time.Duration, err error) {
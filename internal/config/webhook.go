@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"text/template"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/webhook"
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+type Webhook struct {
+	// URLs are the URLs a JSON payload is posted to when a
+	// record update succeeds or fails. Webhooks are disabled
+	// if it is empty.
+	URLs []string
+	// Template is the Go text/template used to build the payload,
+	// with data of type webhook.Event. It is the empty string to
+	// post the JSON encoded event.
+	Template string
+	// Retries is the number of times a failed request is retried.
+	Retries *uint
+}
+
+func (w *Webhook) setDefaults() {
+	w.URLs = gosettings.DefaultSlice(w.URLs, []string{})
+	const defaultRetries = 2
+	w.Retries = gosettings.DefaultPointer(w.Retries, defaultRetries)
+}
+
+var ErrWebhookURLNotValid = errors.New("webhook URL is not a valid HTTP(S) URL")
+
+func (w Webhook) Validate() (err error) {
+	for i, rawURL := range w.URLs {
+		parsed, err := url.Parse(rawURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			// Do not show the URL since it often contains a secret token.
+			return fmt.Errorf("%w: URL %d of %d", ErrWebhookURLNotValid, i+1, len(w.URLs))
+		}
+	}
+
+	payloadTemplate, err := w.PayloadTemplate()
+	if err != nil || payloadTemplate == nil {
+		return err
+	}
+
+	// Execute the template once to catch errors such as unknown fields.
+	sampleEvent := webhook.Event{
+		Record:   "sub.example.com",
+		Domain:   "example.com",
+		Host:     "sub",
+		Provider: "cloudflare",
+		Status:   webhook.StatusSuccess,
+		OldIP:    "1.2.3.4",
+		NewIP:    "5.6.7.8",
+		Time:     time.Unix(0, 0),
+	}
+	err = payloadTemplate.Execute(io.Discard, sampleEvent)
+	if err != nil {
+		return fmt.Errorf("executing webhook template: %w", err)
+	}
+	return nil
+}
+
+// PayloadTemplate parses and returns the payload template,
+// or returns nil if no template is set.
+func (w Webhook) PayloadTemplate() (payloadTemplate *template.Template, err error) {
+	if w.Template == "" {
+		return nil, nil //nolint:nilnil
+	}
+	payloadTemplate, err = webhook.ParseTemplate(w.Template)
+	if err != nil {
+		return nil, fmt.Errorf("parsing webhook template: %w", err)
+	}
+	return payloadTemplate, nil
+}
+
+func (w Webhook) String() string {
+	return w.toLinesNode().String()
+}
+
+func (w Webhook) toLinesNode() *gotree.Node {
+	if len(w.URLs) == 0 {
+		return nil // no URL means webhooks are disabled
+	}
+
+	node := gotree.New("Webhooks")
+	node.Appendf("URLs: %d [redacted]", len(w.URLs))
+	template := w.Template
+	if template == "" {
+		template = "JSON encoded event"
+	}
+	node.Appendf("Template: %s", template)
+	node.Appendf("Retries: %d", *w.Retries)
+	return node
+}
+
+func (w *Webhook) read(r *reader.Reader) (err error) {
+	w.URLs = r.CSV("WEBHOOK_URLS", reader.ForceLowercase(false))
+	w.Template = r.String("WEBHOOK_TEMPLATE", reader.ForceLowercase(false))
+	w.Retries, err = r.UintPtr("WEBHOOK_RETRIES")
+	return err
+}
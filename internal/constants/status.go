@@ -8,4 +8,5 @@ const (
 	UPTODATE models.Status = "up to date"
 	UPDATING models.Status = "updating"
 	UNSET    models.Status = "unset"
+	SKIPPED  models.Status = "skipped"
 )
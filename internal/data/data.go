@@ -10,12 +10,18 @@ type Database struct {
 	data []records.Record
 	sync.RWMutex
 	persistentDB PersistentDatabase
+	// persistenceErr is the last error writing to the persistent
+	// database, and is nil if the persistent database is healthy.
+	persistenceErr error
+	logger         Logger
 }
 
 // NewDatabase creates a new in memory database.
-func NewDatabase(data []records.Record, persistentDB PersistentDatabase) *Database {
+func NewDatabase(data []records.Record, persistentDB PersistentDatabase,
+	logger Logger) *Database {
 	return &Database{
 		data:         data,
 		persistentDB: persistentDB,
+		logger:       logger,
 	}
 }
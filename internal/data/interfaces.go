@@ -8,4 +8,11 @@ import (
 type PersistentDatabase interface {
 	Close() error
 	StoreNewIP(domain, host string, ip netip.Addr, t time.Time) (err error)
+	StoreManagedRecordIDs(domain, host string, ids []string) (err error)
+	Flush() (err error)
+}
+
+type Logger interface {
+	Info(s string)
+	Warn(s string)
 }
@@ -3,6 +3,7 @@ package data
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/qdm12/ddns-updater/internal/records"
 )
@@ -23,3 +24,26 @@ func (db *Database) SelectAll() (records []records.Record) {
 	defer db.RUnlock()
 	return db.data
 }
+
+// Replace replaces all the records, for example
+// when the configuration is reloaded.
+func (db *Database) Replace(records []records.Record) {
+	db.Lock()
+	defer db.Unlock()
+	db.data = records
+}
+
+// PruneHistory removes the IP address history events of the records
+// older than the time given, if it is not zero, and beyond the maximum
+// number of events per record given, if it is not zero, keeping the
+// in memory history in line with the pruned persistent database.
+func (db *Database) PruneHistory(before time.Time, maxEvents uint) (pruned int) {
+	db.Lock()
+	defer db.Unlock()
+	for i, record := range db.data {
+		history, removed := record.History.Prune(before, maxEvents)
+		db.data[i].History = history
+		pruned += removed
+	}
+	return pruned
+}
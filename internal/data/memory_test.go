@@ -0,0 +1,37 @@
+package data
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Database_PruneHistory(t *testing.T) {
+	t.Parallel()
+
+	day := func(n int64) time.Time { return time.Unix(n*24*60*60, 0).UTC() }
+	event := func(ip string, n int64) models.HistoryEvent {
+		return models.HistoryEvent{IP: netip.MustParseAddr(ip), Time: day(n)}
+	}
+
+	db := NewDatabase([]records.Record{
+		records.New(&snapshotTestProvider{domain: "a.com", host: "@"}, records.Options{},
+			models.History{event("1.1.1.1", 1), event("1.1.1.2", 5), event("1.1.1.3", 9)}),
+		records.New(&snapshotTestProvider{domain: "b.com", host: "@"}, records.Options{},
+			models.History{event("2.2.2.1", 2)}),
+		records.New(&snapshotTestProvider{domain: "c.com", host: "@"}, records.Options{}, nil),
+	}, nil, noopLogger{})
+
+	pruned := db.PruneHistory(day(3), 1)
+
+	assert.Equal(t, 2, pruned)
+	records := db.SelectAll()
+	assert.Equal(t, models.History{event("1.1.1.3", 9)}, records[0].History)
+	// the latest event is kept even if it is too old
+	assert.Equal(t, models.History{event("2.2.2.1", 2)}, records[1].History)
+	assert.Empty(t, records[2].History)
+}
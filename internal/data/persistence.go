@@ -3,6 +3,7 @@ package data
 import (
 	"fmt"
 
+	"github.com/qdm12/ddns-updater/internal/provider"
 	"github.com/qdm12/ddns-updater/internal/records"
 )
 
@@ -15,18 +16,83 @@ func (db *Database) Update(id uint, record records.Record) (err error) {
 	currentCount := len(db.data[id].History)
 	newCount := len(record.History)
 	db.data[id] = record
-	// new IP address added
-	if newCount > currentCount {
-		if err := db.persistentDB.StoreNewIP(
+	// Persistence errors do not fail the update, which is kept
+	// in memory, and the persistent database is written again
+	// by RetryPersistence. Updates not writing to the persistent
+	// database, such as status changes, leave the persistence
+	// error as it is.
+	written, err := db.persist(record, newCount > currentCount)
+	if written || err != nil {
+		db.setPersistenceErr(err)
+	}
+	return nil
+}
+
+// persist writes the new IP address and managed record IDs of the
+// record to the persistent database. It returns written as true if
+// a write was attempted and succeeded, and false if there was nothing
+// to write.
+func (db *Database) persist(record records.Record, newIP bool) (
+	written bool, err error) {
+	if newIP {
+		err = db.persistentDB.StoreNewIP(
 			record.Provider.Domain(),
 			record.Provider.Host(),
 			record.History.GetCurrentIP(),
 			record.History.GetSuccessTime(),
-		); err != nil {
-			return err
+		)
+		if err != nil {
+			return false, fmt.Errorf("storing new IP address: %w", err)
 		}
+		written = true
 	}
-	return nil
+
+	if manager, ok := record.Provider.(provider.RecordManager); ok {
+		err = db.persistentDB.StoreManagedRecordIDs(
+			record.Provider.Domain(),
+			record.Provider.Host(),
+			manager.ManagedRecordIDs(),
+		)
+		if err != nil {
+			return false, fmt.Errorf("storing managed record ids: %w", err)
+		}
+		written = true
+	}
+	return written, nil
+}
+
+// RetryPersistence writes the in memory data of the persistent
+// database to its storage if a previous write failed.
+// It is a no-op if persistence is not degraded.
+func (db *Database) RetryPersistence() {
+	db.Lock()
+	defer db.Unlock()
+	if db.persistenceErr == nil {
+		return
+	}
+	db.setPersistenceErr(db.persistentDB.Flush())
+}
+
+// PersistenceDegraded returns true if the last write to the
+// persistent database failed, in which case data is only kept
+// in memory until a write succeeds.
+func (db *Database) PersistenceDegraded() bool {
+	db.RLock()
+	defer db.RUnlock()
+	return db.persistenceErr != nil
+}
+
+// setPersistenceErr sets the persistence error and logs a warning
+// when persistence becomes degraded, and an information message
+// when it recovers. It must be called with the lock held.
+func (db *Database) setPersistenceErr(err error) {
+	switch {
+	case err != nil && db.persistenceErr == nil:
+		db.logger.Warn("persistence degraded, keeping data in memory only: " + err.Error())
+	case err == nil && db.persistenceErr != nil:
+		db.logger.Info("persistence recovered")
+	}
+	db.persistenceErr = err
 }
 
 func (db *Database) Close() (err error) {
@@ -0,0 +1,119 @@
+package data
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Info(string) {}
+func (noopLogger) Warn(string) {}
+
+var errTestWrite = errors.New("no space left on device")
+
+type failingPersistentDB struct {
+	PersistentDatabase
+	writeErr error
+	flushes  int
+}
+
+func (db *failingPersistentDB) StoreNewIP(string, string, netip.Addr, time.Time) error {
+	return db.writeErr
+}
+
+func (db *failingPersistentDB) StoreManagedRecordIDs(string, string, []string) error {
+	return db.writeErr
+}
+
+func (db *failingPersistentDB) Flush() error {
+	db.flushes++
+	return db.writeErr
+}
+
+func Test_Database_Update_persistenceDegraded(t *testing.T) {
+	t.Parallel()
+
+	persistentDB := &failingPersistentDB{writeErr: errTestWrite}
+	record := records.New(&snapshotTestProvider{domain: "example.com", host: "@"},
+		records.Options{}, nil)
+	db := NewDatabase([]records.Record{record}, persistentDB, noopLogger{})
+
+	record.History = models.History{
+		{IP: netip.MustParseAddr("1.2.3.4"), Time: time.Unix(1, 0)},
+	}
+	err := db.Update(0, record)
+	require.NoError(t, err)
+	assert.True(t, db.PersistenceDegraded())
+
+	selected, err := db.Select(0)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("1.2.3.4"), selected.History.GetCurrentIP())
+
+	// Updates keep working while persistence is degraded
+	record.History = append(record.History, models.HistoryEvent{
+		IP: netip.MustParseAddr("5.6.7.8"), Time: time.Unix(2, 0),
+	})
+	err = db.Update(0, record)
+	require.NoError(t, err)
+	selected, err = db.Select(0)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("5.6.7.8"), selected.History.GetCurrentIP())
+
+	db.RetryPersistence()
+	assert.True(t, db.PersistenceDegraded())
+
+	persistentDB.writeErr = nil
+	db.RetryPersistence()
+	assert.False(t, db.PersistenceDegraded())
+	assert.Equal(t, 2, persistentDB.flushes)
+
+	// No flush is done when persistence is not degraded
+	db.RetryPersistence()
+	assert.Equal(t, 2, persistentDB.flushes)
+}
+
+// unmanagedTestProvider is a provider which is not a record manager,
+// such that only new IP addresses are written to the persistent database.
+type unmanagedTestProvider struct {
+	provider.Provider
+}
+
+func (p *unmanagedTestProvider) Domain() string { return "example.com" }
+func (p *unmanagedTestProvider) Host() string   { return "@" }
+
+func Test_Database_Update_statusOnlyKeepsDegraded(t *testing.T) {
+	t.Parallel()
+
+	persistentDB := &failingPersistentDB{writeErr: errTestWrite}
+	record := records.New(&unmanagedTestProvider{}, records.Options{}, nil)
+	db := NewDatabase([]records.Record{record}, persistentDB, noopLogger{})
+
+	record.History = models.History{
+		{IP: netip.MustParseAddr("1.2.3.4"), Time: time.Unix(1, 0)},
+	}
+	err := db.Update(0, record)
+	require.NoError(t, err)
+	require.True(t, db.PersistenceDegraded())
+
+	// A status only update writes nothing to the persistent
+	// database, so it must not clear the persistence error.
+	persistentDB.writeErr = nil
+	record.Status = constants.UPDATING
+	err = db.Update(0, record)
+	require.NoError(t, err)
+	assert.True(t, db.PersistenceDegraded())
+
+	db.RetryPersistence()
+	assert.False(t, db.PersistenceDegraded())
+	assert.Equal(t, 1, persistentDB.flushes)
+}
@@ -0,0 +1,28 @@
+package data
+
+import (
+	"slices"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+)
+
+// Snapshot returns the current state of all records.
+func (db *Database) Snapshot() (snapshot models.Snapshot) {
+	db.RLock()
+	defer db.RUnlock()
+	snapshot.Records = make([]models.SnapshotRecord, len(db.data))
+	for i, record := range db.data {
+		snapshot.Records[i] = models.SnapshotRecord{
+			Domain:    record.Provider.Domain(),
+			Host:      record.Provider.Host(),
+			IPVersion: record.Provider.IPVersion().String(),
+			IP:        record.History.GetCurrentIP(),
+			Time:      record.History.GetSuccessTime(),
+		}
+		if manager, ok := record.Provider.(provider.RecordManager); ok {
+			snapshot.Records[i].ManagedIDs = slices.Clone(manager.ManagedRecordIDs())
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,109 @@
+package data
+
+import (
+	"encoding/json"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	persistence "github.com/qdm12/ddns-updater/internal/persistence/json"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type snapshotTestProvider struct {
+	provider.Provider
+	domain     string
+	host       string
+	managedIDs []string
+}
+
+func (p *snapshotTestProvider) Domain() string                 { return p.domain }
+func (p *snapshotTestProvider) Host() string                   { return p.host }
+func (p *snapshotTestProvider) IPVersion() ipversion.IPVersion { return ipversion.IP4or6 }
+func (p *snapshotTestProvider) ManagedRecordIDs() []string     { return p.managedIDs }
+func (p *snapshotTestProvider) SetManagedRecordIDs(ids []string) {
+	p.managedIDs = ids
+}
+
+func Test_Database_Snapshot_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	firstTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	secondTime := firstTime.Add(time.Hour)
+	history := models.History{
+		{IP: netip.MustParseAddr("1.1.1.1"), Time: firstTime},
+		{IP: netip.MustParseAddr("2.2.2.2"), Time: secondTime},
+	}
+
+	sourcePersistentDB, err := persistence.NewDatabase(t.TempDir())
+	require.NoError(t, err)
+	sourceDB := NewDatabase([]records.Record{
+		records.New(&snapshotTestProvider{domain: "example.com", host: "@"},
+			records.Options{}, history),
+		records.New(&snapshotTestProvider{domain: "example.com", host: "www", managedIDs: []string{"id"}},
+			records.Options{}, nil),
+	}, sourcePersistentDB, noopLogger{})
+
+	snapshot := sourceDB.Snapshot()
+	expectedSnapshot := models.Snapshot{
+		Records: []models.SnapshotRecord{
+			{
+				Domain:    "example.com",
+				Host:      "@",
+				IPVersion: "ipv4 or ipv6",
+				IP:        netip.MustParseAddr("2.2.2.2"),
+				Time:      secondTime,
+			},
+			{
+				Domain:     "example.com",
+				Host:       "www",
+				IPVersion:  "ipv4 or ipv6",
+				ManagedIDs: []string{"id"},
+			},
+		},
+	}
+	assert.Equal(t, expectedSnapshot, snapshot)
+
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	const perm os.FileMode = 0600
+	err = os.WriteFile(snapshotPath, data, perm)
+	require.NoError(t, err)
+
+	readSnapshot, err := persistence.ReadSnapshot(snapshotPath)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, readSnapshot)
+
+	destinationDir := t.TempDir()
+	destinationDB, err := persistence.NewDatabase(destinationDir)
+	require.NoError(t, err)
+	err = destinationDB.Import(readSnapshot)
+	require.NoError(t, err)
+	// Importing twice must not duplicate history events.
+	err = destinationDB.Import(readSnapshot)
+	require.NoError(t, err)
+
+	// Re-open the database to check the import was persisted.
+	destinationDB, err = persistence.NewDatabase(destinationDir)
+	require.NoError(t, err)
+
+	events, err := destinationDB.GetEvents("example.com", "@", ipversion.IP4or6)
+	require.NoError(t, err)
+	assert.Equal(t, []models.HistoryEvent{
+		{IP: netip.MustParseAddr("2.2.2.2"), Time: secondTime},
+	}, events)
+	assert.Empty(t, destinationDB.GetManagedRecordIDs("example.com", "@"))
+
+	events, err = destinationDB.GetEvents("example.com", "www", ipversion.IP4or6)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	assert.Equal(t, []string{"id"}, destinationDB.GetManagedRecordIDs("example.com", "www"))
+}
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+)
+
+// Event is emitted each time a record update completes.
+type Event struct {
+	Domain  string        `json:"domain"`
+	Host    string        `json:"host"`
+	Status  models.Status `json:"status"`
+	IP      string        `json:"ip,omitempty"`
+	Message string        `json:"message,omitempty"`
+	Time    time.Time     `json:"time"`
+	// CGNAT is true if the public IP address is in
+	// the carrier-grade NAT range.
+	CGNAT bool `json:"cgnat,omitempty"`
+}
+
+// Broadcaster fans out events to all its subscribers.
+// Publishing never blocks: events are dropped for subscribers
+// whose buffer is full.
+type Broadcaster struct {
+	subscribers map[chan Event]struct{}
+	mutex       sync.RWMutex
+}
+
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber and returns its events channel
+// together with an unsubscribe function which must be called once the
+// subscriber is done, to release its resources.
+func (b *Broadcaster) Subscribe() (events <-chan Event, unsubscribe func()) {
+	const bufferSize = 16
+	ch := make(chan Event, bufferSize)
+
+	b.mutex.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mutex.Unlock()
+
+	var once sync.Once
+	unsubscribe = func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			delete(b.subscribers, ch)
+			b.mutex.Unlock()
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Publish sends the event to all subscribers without blocking.
+func (b *Broadcaster) Publish(event Event) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default: // slow subscriber, drop the event
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Broadcaster(t *testing.T) {
+	t.Parallel()
+
+	broadcaster := NewBroadcaster()
+
+	events, unsubscribe := broadcaster.Subscribe()
+
+	event := Event{
+		Domain: "example.com",
+		Host:   "@",
+		Status: constants.SUCCESS,
+		IP:     "1.2.3.4",
+		Time:   time.Unix(1, 0),
+	}
+	broadcaster.Publish(event)
+
+	select {
+	case received := <-events:
+		assert.Equal(t, event, received)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	unsubscribe()
+	_, ok := <-events
+	assert.False(t, ok)
+
+	// Publishing without subscribers must not block
+	broadcaster.Publish(event)
+}
+
+func Test_Broadcaster_slowSubscriber(t *testing.T) {
+	t.Parallel()
+
+	broadcaster := NewBroadcaster()
+
+	_, unsubscribe := broadcaster.Subscribe()
+	defer unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		const eventsCount = 100
+		for i := 0; i < eventsCount; i++ {
+			broadcaster.Publish(Event{})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publishing blocked on slow subscriber")
+	}
+}
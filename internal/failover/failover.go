@@ -0,0 +1,95 @@
+// Package failover implements a DNS failover controller choosing between
+// a primary and a backup IP address depending on the primary health.
+package failover
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/probe"
+)
+
+type Settings struct {
+	// PrimaryIP is the IP address to use when it is healthy.
+	PrimaryIP netip.Addr
+	// BackupIP is the IP address to use when the primary
+	// IP address is unhealthy.
+	BackupIP netip.Addr
+	// FailureThreshold is the number of consecutive failed health
+	// checks of the primary IP address before failing over.
+	FailureThreshold uint
+	// RecoveryThreshold is the number of consecutive successful health
+	// checks of the primary IP address before failing back to it.
+	RecoveryThreshold uint
+	// HealthCheck contains the settings of the probe used
+	// to health check the primary IP address.
+	HealthCheck probe.Settings
+}
+
+type Prober interface {
+	Probe(ctx context.Context, ip netip.Addr) (err error)
+}
+
+// New creates a new failover controller, starting on the primary IP address.
+func New(settings Settings, prober Prober) *Controller {
+	return &Controller{
+		settings: settings,
+		prober:   prober,
+	}
+}
+
+type Controller struct {
+	settings Settings
+	prober   Prober
+	// onBackup is true if the backup IP address is in use.
+	onBackup bool
+	// streak is the number of consecutive health check results
+	// going against the current state: failures when on the primary
+	// IP address, and successes when on the backup IP address.
+	streak uint
+}
+
+// Check health checks the primary IP address and returns the IP address
+// to use, which is the backup IP address once the primary IP address failed
+// the failure threshold number of consecutive checks, and back to the
+// primary IP address once it passed the recovery threshold number of
+// consecutive checks. The changed boolean is true if the IP address to use
+// changed with this check, and checkErr is the health check error, if any.
+func (c *Controller) Check(ctx context.Context) (ip netip.Addr, changed bool, checkErr error) {
+	checkErr = c.prober.Probe(ctx, c.settings.PrimaryIP)
+	healthy := checkErr == nil
+
+	switch {
+	case !c.onBackup && healthy, c.onBackup && !healthy:
+		c.streak = 0
+	case !c.onBackup: // unhealthy primary
+		c.streak++
+		if c.streak >= c.settings.FailureThreshold {
+			c.onBackup = true
+			c.streak = 0
+			changed = true
+		}
+	default: // healthy primary while on backup
+		c.streak++
+		if c.streak >= c.settings.RecoveryThreshold {
+			c.onBackup = false
+			c.streak = 0
+			changed = true
+		}
+	}
+
+	return c.IP(), changed, checkErr
+}
+
+// IP returns the IP address currently in use.
+func (c *Controller) IP() netip.Addr {
+	if c.onBackup {
+		return c.settings.BackupIP
+	}
+	return c.settings.PrimaryIP
+}
+
+// OnBackup returns true if the backup IP address is in use.
+func (c *Controller) OnBackup() bool {
+	return c.onBackup
+}
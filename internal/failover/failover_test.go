@@ -0,0 +1,80 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sequenceProber returns the errors of its results one
+// after the other, for each probe done.
+type sequenceProber struct {
+	results []error
+	calls   int
+}
+
+func (p *sequenceProber) Probe(context.Context, netip.Addr) (err error) {
+	err = p.results[p.calls]
+	p.calls++
+	return err
+}
+
+func Test_Controller_Check(t *testing.T) {
+	t.Parallel()
+
+	errDown := errors.New("connection refused")
+	primary := netip.MustParseAddr("1.1.1.1")
+	backup := netip.MustParseAddr("2.2.2.2")
+
+	testCases := map[string]struct {
+		results []error
+		ips     []netip.Addr
+		changes []bool
+	}{
+		"primary_healthy": {
+			results: []error{nil, nil},
+			ips:     []netip.Addr{primary, primary},
+			changes: []bool{false, false},
+		},
+		"primary_transient_failure": {
+			results: []error{errDown, nil, errDown, nil},
+			ips:     []netip.Addr{primary, primary, primary, primary},
+			changes: []bool{false, false, false, false},
+		},
+		"failover_then_recovery": {
+			results: []error{errDown, errDown, nil, errDown, nil, nil},
+			ips:     []netip.Addr{primary, backup, backup, backup, backup, primary},
+			changes: []bool{false, true, false, false, false, true},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := Settings{
+				PrimaryIP:         primary,
+				BackupIP:          backup,
+				FailureThreshold:  2,
+				RecoveryThreshold: 2,
+			}
+			prober := &sequenceProber{results: testCase.results}
+			controller := New(settings, prober)
+
+			ips := make([]netip.Addr, len(testCase.results))
+			changes := make([]bool, len(testCase.results))
+			for i, expectedErr := range testCase.results {
+				var err error
+				ips[i], changes[i], err = controller.Check(context.Background())
+				assert.ErrorIs(t, err, expectedErr)
+			}
+
+			assert.Equal(t, testCase.ips, ips)
+			assert.Equal(t, testCase.changes, changes)
+		})
+	}
+}
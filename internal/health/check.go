@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/provider"
 )
 
 func MakeIsHealthy(db AllSelecter, resolver LookupIPer) func() error {
@@ -28,7 +29,7 @@ func isHealthy(db AllSelecter, resolver LookupIPer) (err error) {
 	for _, record := range records {
 		if record.Status == constants.FAIL {
 			return fmt.Errorf("%w: %s", ErrRecordUpdateFailed, record.String())
-		} else if record.Provider.Proxied() {
+		} else if provider.SkipLookup(record.Provider) {
 			continue
 		}
 
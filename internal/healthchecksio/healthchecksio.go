@@ -6,19 +6,24 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
-// New creates a new healthchecks.io client.
+// New creates a new healthchecks.io client pinging the check with
+// the uuid given on the ping server at baseURL, which is typically
+// https://hc-ping.com or the ping URL of a self-hosted instance.
 // If passed an empty uuid string, it acts as no-op implementation.
-func New(httpClient *http.Client, uuid string) *Client {
+func New(httpClient *http.Client, baseURL url.URL, uuid string) *Client {
 	return &Client{
 		httpClient: httpClient,
+		baseURL:    baseURL,
 		uuid:       uuid,
 	}
 }
 
 type Client struct {
 	httpClient *http.Client
+	baseURL    url.URL
 	uuid       string
 }
 
@@ -41,11 +46,8 @@ func (c *Client) Ping(ctx context.Context, state State) (err error) {
 		return nil
 	}
 
-	url := url.URL{
-		Scheme: "https",
-		Host:   "hc-ping.com",
-		Path:   "/" + c.uuid,
-	}
+	url := c.baseURL
+	url.Path = strings.TrimSuffix(url.Path, "/") + "/" + c.uuid
 	if state != Ok {
 		url.Path += "/" + string(state)
 	}
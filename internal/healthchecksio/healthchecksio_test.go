@@ -0,0 +1,57 @@
+package healthchecksio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Client_Ping(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		basePath string
+		state    State
+		path     string
+	}{
+		"ok": {
+			state: Ok,
+			path:  "/uuid",
+		},
+		"fail": {
+			state: Fail,
+			path:  "/uuid/fail",
+		},
+		"base_path": {
+			basePath: "/ping/",
+			state:    Exit1,
+			path:     "/ping/uuid/1",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var path string
+			server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+			}))
+			t.Cleanup(server.Close)
+			baseURL, err := url.Parse(server.URL + testCase.basePath)
+			require.NoError(t, err)
+			client := New(server.Client(), *baseURL, "uuid")
+
+			err = client.Ping(context.Background(), testCase.state)
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.path, path)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Package heartbeat pings user configured URLs at the start and at the
+// end of each update cycle, such that an external monitor, for example
+// healthchecks.io or an Uptime Kuma push monitor, can alert if the
+// program stops running or stops updating successfully.
+package heartbeat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type Event string
+
+const (
+	Start   Event = "start"
+	Success Event = "success"
+	Failure Event = "failure"
+)
+
+type Settings struct {
+	// StartURL is pinged at the start of each update cycle.
+	StartURL string
+	// SuccessURL is pinged at the end of each update cycle
+	// without any error.
+	SuccessURL string
+	// FailureURL is pinged at the end of each update cycle
+	// with at least one error.
+	FailureURL string
+	// Method is the HTTP method to ping with,
+	// and is either GET or POST.
+	Method string
+}
+
+// New creates a new heartbeat client. Events with an
+// empty URL configured are not pinged.
+func New(httpClient *http.Client, settings Settings) *Client {
+	return &Client{
+		httpClient: httpClient,
+		urls: map[Event]string{
+			Start:   settings.StartURL,
+			Success: settings.SuccessURL,
+			Failure: settings.FailureURL,
+		},
+		method: settings.Method,
+	}
+}
+
+type Client struct {
+	httpClient *http.Client
+	urls       map[Event]string
+	method     string
+}
+
+var ErrStatusCode = errors.New("bad status code")
+
+// Ping pings the URL configured for the event given,
+// and is a no-op if no URL is configured for the event.
+func (c *Client) Ping(ctx context.Context, event Event) (err error) {
+	url := c.urls[event]
+	if url == "" {
+		return nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, c.method, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("doing http request: %w", err)
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		_ = response.Body.Close()
+		return fmt.Errorf("%w: %d %s", ErrStatusCode, response.StatusCode, response.Status)
+	}
+
+	err = response.Body.Close()
+	if err != nil {
+		return fmt.Errorf("closing response body: %w", err)
+	}
+
+	return nil
+}
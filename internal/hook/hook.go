@@ -0,0 +1,99 @@
+// Package hook runs a user configured command after a record IP
+// address changed, for example to update firewall rules or to
+// restart a service bound to the IP address.
+package hook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/netip"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Event contains the details of a record IP address change,
+// given to the command as environment variables.
+type Event struct {
+	Domain   string
+	Host     string
+	FQDN     string
+	Provider string
+	// OldIP is the previous IP address of the record,
+	// and is invalid if it is unknown.
+	OldIP netip.Addr
+	NewIP netip.Addr
+}
+
+type Logger interface {
+	Info(s string)
+	Error(s string)
+}
+
+type Runner struct {
+	args    []string
+	timeout time.Duration
+	logger  Logger
+}
+
+// New creates a hook runner for the command given, which is split on
+// spaces into the program and its arguments, since the Docker image has
+// no shell. An empty command disables the hook.
+func New(command string, timeout time.Duration, logger Logger) *Runner {
+	return &Runner{
+		args:    strings.Fields(command),
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// Run runs the command for the event given, with the environment
+// variables DDNS_DOMAIN, DDNS_HOST, DDNS_FQDN, DDNS_PROVIDER, DDNS_OLD_IP
+// and DDNS_NEW_IP set, and waits for it to complete or time out. Each line
+// of its combined standard output and error is logged. Failures are logged
+// and not returned, so they do not affect the record update.
+// It is a no-op if no command is configured.
+func (r *Runner) Run(ctx context.Context, event Event) {
+	if len(r.args) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, r.args[0], r.args[1:]...) //nolint:gosec
+	cmd.Env = append(os.Environ(), makeEnv(event)...)
+	// Do not wait indefinitely for child processes keeping the output open
+	const waitDelay = time.Second
+	cmd.WaitDelay = waitDelay
+
+	output, err := cmd.CombinedOutput()
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		r.logger.Info(event.FQDN + ": " + scanner.Text())
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("%w: %w", err, ctx.Err())
+		}
+		r.logger.Error(fmt.Sprintf("%s: running hook command: %s", event.FQDN, err))
+	}
+}
+
+func makeEnv(event Event) (env []string) {
+	oldIP := ""
+	if event.OldIP.IsValid() {
+		oldIP = event.OldIP.String()
+	}
+	return []string{
+		"DDNS_DOMAIN=" + event.Domain,
+		"DDNS_HOST=" + event.Host,
+		"DDNS_FQDN=" + event.FQDN,
+		"DDNS_PROVIDER=" + event.Provider,
+		"DDNS_OLD_IP=" + oldIP,
+		"DDNS_NEW_IP=" + event.NewIP.String(),
+	}
+}
@@ -0,0 +1,156 @@
+package hook
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain runs the test binary as the hook command if the
+// HOOK_TEST_HELPER environment variable is set, printing the
+// hook environment variables and exiting with the code given.
+func TestMain(m *testing.M) {
+	switch os.Getenv("HOOK_TEST_HELPER") {
+	case "":
+		os.Exit(m.Run())
+	case "sleep":
+		time.Sleep(time.Minute)
+	}
+	for _, variable := range os.Environ() {
+		if strings.HasPrefix(variable, "DDNS_") {
+			fmt.Println(variable)
+		}
+	}
+	fmt.Fprintln(os.Stderr, "arguments:", strings.Join(os.Args[1:], " "))
+	if os.Getenv("HOOK_TEST_HELPER") == "fail" {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+type recordingLogger struct {
+	mutex  sync.Mutex
+	infos  []string
+	errors []string
+}
+
+func (l *recordingLogger) Info(s string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.infos = append(l.infos, s)
+}
+
+func (l *recordingLogger) Error(s string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.errors = append(l.errors, s)
+}
+
+func Test_Runner_Run(t *testing.T) {
+	// Not parallel since the environment is modified.
+	event := Event{
+		Domain:   "example.com",
+		Host:     "www",
+		FQDN:     "www.example.com",
+		Provider: "cloudflare",
+		NewIP:    netip.MustParseAddr("5.6.7.8"),
+	}
+
+	testCases := map[string]struct {
+		helper  string
+		timeout time.Duration
+		infos   []string
+		errors  []string
+	}{
+		"success": {
+			helper:  "print",
+			timeout: time.Minute,
+			infos: []string{
+				"www.example.com: DDNS_DOMAIN=example.com",
+				"www.example.com: DDNS_HOST=www",
+				"www.example.com: DDNS_FQDN=www.example.com",
+				"www.example.com: DDNS_PROVIDER=cloudflare",
+				"www.example.com: DDNS_OLD_IP=",
+				"www.example.com: DDNS_NEW_IP=5.6.7.8",
+				"www.example.com: arguments: first second",
+			},
+		},
+		"failure": {
+			helper:  "fail",
+			timeout: time.Minute,
+			infos: []string{
+				"www.example.com: DDNS_DOMAIN=example.com",
+				"www.example.com: DDNS_HOST=www",
+				"www.example.com: DDNS_FQDN=www.example.com",
+				"www.example.com: DDNS_PROVIDER=cloudflare",
+				"www.example.com: DDNS_OLD_IP=",
+				"www.example.com: DDNS_NEW_IP=5.6.7.8",
+				"www.example.com: arguments: first second",
+			},
+			errors: []string{"www.example.com: running hook command: exit status 1"},
+		},
+		"timeout": {
+			helper:  "sleep",
+			timeout: 100 * time.Millisecond,
+			errors: []string{"www.example.com: running hook command: " +
+				"signal: killed: context deadline exceeded"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("HOOK_TEST_HELPER", testCase.helper)
+			logger := &recordingLogger{}
+			command := os.Args[0] + " first second"
+			runner := New(command, testCase.timeout, logger)
+
+			runner.Run(context.Background(), event)
+
+			assert.ElementsMatch(t, testCase.infos, logger.infos)
+			assert.Equal(t, testCase.errors, logger.errors)
+		})
+	}
+}
+
+func Test_Runner_Run_noCommand(t *testing.T) {
+	t.Parallel()
+
+	logger := &recordingLogger{}
+	runner := New("  ", time.Minute, logger)
+
+	runner.Run(context.Background(), Event{})
+
+	require.Empty(t, logger.infos)
+	require.Empty(t, logger.errors)
+}
+
+func Test_makeEnv(t *testing.T) {
+	t.Parallel()
+
+	env := makeEnv(Event{
+		Domain:   "example.com",
+		Host:     "@",
+		FQDN:     "example.com",
+		Provider: "ovh",
+		OldIP:    netip.MustParseAddr("::1"),
+		NewIP:    netip.MustParseAddr("::2"),
+	})
+
+	expected := []string{
+		"DDNS_DOMAIN=example.com",
+		"DDNS_HOST=@",
+		"DDNS_FQDN=example.com",
+		"DDNS_PROVIDER=ovh",
+		"DDNS_OLD_IP=::1",
+		"DDNS_NEW_IP=::2",
+	}
+	assert.Equal(t, expected, env)
+}
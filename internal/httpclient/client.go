@@ -0,0 +1,52 @@
+// Package httpclient creates the HTTP client used for provider API
+// calls and public IP address fetching over HTTP.
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"time"
+)
+
+// Settings are the settings to create the HTTP client.
+type Settings struct {
+	// Timeout is the timeout for each HTTP request.
+	Timeout time.Duration
+	// LocalAddress is the local address to bind outgoing connections to,
+	// which is useful on multi-homed hosts to choose the network interface
+	// used. It is ignored if it is the zero value.
+	LocalAddress netip.Addr
+	// MaxIdleConnsPerHost is the maximum number of idle connections
+	// kept open for reuse per host. If zero, the standard library
+	// default of 2 is used.
+	MaxIdleConnsPerHost uint
+	// IdleConnTimeout is the maximum duration an idle connection is
+	// kept open for reuse. If zero, there is no limit.
+	IdleConnTimeout time.Duration
+	// ForceAttemptHTTP2 is whether to attempt HTTP/2 for all connections.
+	ForceAttemptHTTP2 bool
+}
+
+// New creates an HTTP client with the given settings, sharing a single
+// transport so connections are reused across requests to the same host.
+func New(settings Settings) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.MaxIdleConnsPerHost = int(settings.MaxIdleConnsPerHost)
+	transport.IdleConnTimeout = settings.IdleConnTimeout
+	transport.ForceAttemptHTTP2 = settings.ForceAttemptHTTP2
+	if settings.LocalAddress.IsValid() {
+		const dialTimeout, keepAlive = 30 * time.Second, 30 * time.Second
+		dialer := &net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: keepAlive,
+			LocalAddr: &net.TCPAddr{IP: settings.LocalAddress.AsSlice()},
+		}
+		transport.DialContext = dialer.DialContext
+	}
+
+	return &http.Client{
+		Timeout:   settings.Timeout,
+		Transport: transport,
+	}
+}
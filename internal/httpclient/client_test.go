@@ -0,0 +1,138 @@
+package httpclient
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS != "linux" {
+		t.Skip("binding to a loopback address other than 127.0.0.1 requires Linux")
+	}
+
+	remoteAddresses := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		remoteAddresses <- r.RemoteAddr
+	}))
+	t.Cleanup(server.Close)
+
+	localAddress := netip.MustParseAddr("127.0.0.2")
+	client := New(Settings{
+		Timeout:             time.Second,
+		LocalAddress:        localAddress,
+		MaxIdleConnsPerHost: 4,
+		IdleConnTimeout:     time.Minute,
+		ForceAttemptHTTP2:   true,
+	})
+
+	assert.Equal(t, time.Second, client.Timeout)
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 4, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.True(t, transport.ForceAttemptHTTP2)
+
+	response, err := client.Get(server.URL)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+
+	remoteHost, _, err := net.SplitHostPort(<-remoteAddresses)
+	require.NoError(t, err)
+	assert.Equal(t, localAddress.String(), remoteHost)
+}
+
+func Test_New_connectionReuse(t *testing.T) {
+	t.Parallel()
+
+	const burstSize = 4
+
+	testCases := map[string]struct {
+		maxIdleConnsPerHost uint
+		newConnections      int32
+	}{
+		"stdlib_default": {
+			// Only 2 connections of the first burst are kept idle
+			// and reused by the second burst.
+			newConnections: 2*burstSize - 2,
+		},
+		"tuned": {
+			maxIdleConnsPerHost: burstSize,
+			newConnections:      burstSize,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// The barrier blocks the handler until all the requests of a
+			// burst are received, so each burst uses burstSize connections.
+			var barrier atomic.Pointer[sync.WaitGroup]
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+				wg := barrier.Load()
+				wg.Done()
+				wg.Wait()
+			}))
+			var newConnections atomic.Int32
+			server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					newConnections.Add(1)
+				}
+			}
+			server.Start()
+			t.Cleanup(server.Close)
+
+			client := New(Settings{
+				Timeout:             time.Second,
+				MaxIdleConnsPerHost: testCase.maxIdleConnsPerHost,
+			})
+			t.Cleanup(client.CloseIdleConnections)
+
+			const bursts = 2
+			for i := 0; i < bursts; i++ {
+				wg := new(sync.WaitGroup)
+				wg.Add(burstSize)
+				barrier.Store(wg)
+				doBurst(t, client, server.URL, burstSize)
+			}
+
+			assert.Equal(t, testCase.newConnections, newConnections.Load())
+		})
+	}
+}
+
+func doBurst(t *testing.T, client *http.Client, url string, size int) {
+	t.Helper()
+	errs := make(chan error)
+	for i := 0; i < size; i++ {
+		go func() {
+			response, err := client.Get(url)
+			if err != nil {
+				errs <- err
+				return
+			}
+			// Reading the body until EOF blocks until the connection
+			// is put back in the idle connections pool.
+			_, err = io.Copy(io.Discard, response.Body)
+			_ = response.Body.Close()
+			errs <- err
+		}()
+	}
+	for i := 0; i < size; i++ {
+		assert.NoError(t, <-errs)
+	}
+}
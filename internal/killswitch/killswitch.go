@@ -0,0 +1,104 @@
+// Package killswitch lets operators pause the updates of a fleet of
+// updaters from a central URL, for example during a DNS provider incident.
+package killswitch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type Settings struct {
+	// URL is the URL polled before each update cycle. It must respond
+	// with the body "paused" to pause updates, or "running" to let
+	// updates run. The kill switch is disabled if the URL is empty.
+	URL string
+	// PauseOnError is whether to pause updates if the URL cannot be
+	// reached or responds with an invalid response. Updates run in
+	// this case if it is false.
+	PauseOnError bool
+}
+
+// New creates a new kill switch client.
+func New(httpClient *http.Client, settings Settings) *KillSwitch {
+	return &KillSwitch{
+		httpClient:   httpClient,
+		url:          settings.URL,
+		pauseOnError: settings.PauseOnError,
+	}
+}
+
+type KillSwitch struct {
+	httpClient   *http.Client
+	url          string
+	pauseOnError bool
+}
+
+const (
+	signalPaused  = "paused"
+	signalRunning = "running"
+)
+
+var (
+	ErrStatusCode     = errors.New("bad status code")
+	ErrSignalNotValid = errors.New("signal is not valid")
+)
+
+// Paused returns true if updates are paused by the kill switch.
+// If the kill switch URL cannot be checked, the error is returned
+// with paused set according to the pause on error policy.
+// It always returns false and a nil error if no URL is set.
+func (k *KillSwitch) Paused(ctx context.Context) (paused bool, err error) {
+	if k.url == "" {
+		return false, nil
+	}
+
+	signal, err := k.getSignal(ctx)
+	if err != nil {
+		return k.pauseOnError, err
+	}
+
+	switch signal {
+	case signalPaused:
+		return true, nil
+	case signalRunning:
+		return false, nil
+	default:
+		return k.pauseOnError, fmt.Errorf("%w: %q must be %q or %q",
+			ErrSignalNotValid, signal, signalPaused, signalRunning)
+	}
+}
+
+func (k *KillSwitch) getSignal(ctx context.Context) (signal string, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := k.httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("doing http request: %w", err)
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		_ = response.Body.Close()
+		return "", fmt.Errorf("%w: %s", ErrStatusCode, response.Status)
+	}
+
+	const maxBodySize = 1024
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+	if err != nil {
+		_ = response.Body.Close()
+		return "", fmt.Errorf("reading response body: %w", err)
+	}
+
+	err = response.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("closing response body: %w", err)
+	}
+
+	return strings.ToLower(strings.TrimSpace(string(body))), nil
+}
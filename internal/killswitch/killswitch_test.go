@@ -0,0 +1,88 @@
+package killswitch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_KillSwitch_Paused(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		status       int
+		body         string
+		pauseOnError bool
+		paused       bool
+		errWrapped   error
+		errMessage   string
+	}{
+		"paused": {
+			status: http.StatusOK,
+			body:   "Paused\n",
+			paused: true,
+		},
+		"running": {
+			status: http.StatusOK,
+			body:   "running",
+		},
+		"bad_status_update_anyway": {
+			status:     http.StatusServiceUnavailable,
+			errWrapped: ErrStatusCode,
+			errMessage: "bad status code: 503 Service Unavailable",
+		},
+		"bad_status_pause": {
+			status:       http.StatusServiceUnavailable,
+			pauseOnError: true,
+			paused:       true,
+			errWrapped:   ErrStatusCode,
+			errMessage:   "bad status code: 503 Service Unavailable",
+		},
+		"signal_not_valid": {
+			status:     http.StatusOK,
+			body:       "<html></html>",
+			errWrapped: ErrSignalNotValid,
+			errMessage: `signal is not valid: "<html></html>" must be "paused" or "running"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(testCase.status)
+				_, _ = w.Write([]byte(testCase.body))
+			}))
+			t.Cleanup(server.Close)
+
+			killSwitch := New(server.Client(), Settings{
+				URL:          server.URL,
+				PauseOnError: testCase.pauseOnError,
+			})
+
+			paused, err := killSwitch.Paused(context.Background())
+
+			assert.Equal(t, testCase.paused, paused)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_KillSwitch_Paused_disabled(t *testing.T) {
+	t.Parallel()
+
+	killSwitch := New(http.DefaultClient, Settings{PauseOnError: true})
+
+	paused, err := killSwitch.Paused(context.Background())
+
+	assert.False(t, paused)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,111 @@
+// Package logfmt converts the text log lines of the logger
+// to logfmt lines of key=value pairs, for easy grepping.
+package logfmt
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Writer converts each text log line written to it to a logfmt
+// line with the keys time, level, component, msg and caller, and
+// writes it to the underlying writer. The logger must log the time
+// in the RFC3339 format for the time to be parsed.
+type Writer struct {
+	writer  io.Writer
+	buffer  []byte
+	mutex   sync.Mutex
+	colorRe *regexp.Regexp
+}
+
+// NewWriter returns a logfmt writer writing to the writer given.
+func NewWriter(writer io.Writer) *Writer {
+	return &Writer{
+		writer:  writer,
+		colorRe: regexp.MustCompile("\x1b\\[[0-9;]*m"),
+	}
+}
+
+// Write converts the complete log lines of b to logfmt and writes
+// them to the underlying writer. Incomplete lines are kept until
+// their end is written.
+func (w *Writer) Write(b []byte) (n int, err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buffer = append(w.buffer, b...)
+	for {
+		i := bytes.IndexByte(w.buffer, '\n')
+		if i == -1 {
+			return len(b), nil
+		}
+		line := string(w.buffer[:i])
+		w.buffer = w.buffer[i+1:]
+		_, err = io.WriteString(w.writer, w.convert(line)+"\n")
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+var levels = map[string]string{ //nolint:gochecknoglobals
+	"DEBUG": "debug",
+	"INFO":  "info",
+	"WARN":  "warn",
+	"ERROR": "error",
+}
+
+// convert converts a text log line in the format
+// "[time ]LEVEL [[component] ]message[\tcaller]" to logfmt.
+func (w *Writer) convert(line string) string {
+	line = w.colorRe.ReplaceAllString(line, "")
+
+	var pairs []string
+	first, rest, _ := strings.Cut(line, " ")
+	if _, err := time.Parse(time.RFC3339, first); err == nil {
+		pairs = append(pairs, "time="+first)
+		first, rest, _ = strings.Cut(rest, " ")
+	}
+
+	level, ok := levels[first]
+	if !ok { // not a line from the logger
+		return strings.Join(append(pairs, "msg="+quote(line)), " ")
+	}
+	pairs = append(pairs, "level="+level)
+
+	if strings.HasPrefix(rest, "[") {
+		component, message, found := strings.Cut(rest[1:], "] ")
+		if found {
+			pairs = append(pairs, "component="+quote(component))
+			rest = message
+		}
+	}
+
+	message, caller := rest, ""
+	if i := strings.LastIndexByte(rest, '\t'); i != -1 &&
+		!strings.ContainsAny(rest[i+1:], " \t") && strings.Contains(rest[i+1:], ":") {
+		message, caller = rest[:i], rest[i+1:]
+	}
+	pairs = append(pairs, "msg="+quote(message))
+	if caller != "" {
+		pairs = append(pairs, "caller="+quote(caller))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// quote quotes the value given if it is empty or contains
+// spaces, equal signs, quotes or non printable characters.
+func quote(value string) string {
+	needsQuoting := value == "" || strings.ContainsFunc(value, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || !strconv.IsPrint(r)
+	})
+	if needsQuoting {
+		return strconv.Quote(value)
+	}
+	return value
+}
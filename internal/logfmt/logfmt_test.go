@@ -0,0 +1,70 @@
+package logfmt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Writer(t *testing.T) {
+	t.Parallel()
+
+	buffer := new(strings.Builder)
+	logger := log.New(log.SetWriters(NewWriter(buffer)),
+		log.SetTimeFormat(time.RFC3339), log.SetComponent("updater"))
+
+	logger.Info("Updating record [domain: example.com | host: www | " +
+		"provider: cloudflare | ip: ipv4] to use 1.2.3.4")
+	logger.Warn("key=value")
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.Regexp(t, `^time=\d{4}-\d{2}-\d{2}T\S+ `, lines[0])
+	assert.Contains(t, lines[0], ` level=info component=updater msg="Updating record `+
+		`[domain: example.com | host: www | provider: cloudflare | ip: ipv4] to use 1.2.3.4"`)
+	assert.True(t, strings.HasSuffix(lines[1], ` level=warn component=updater msg="key=value"`))
+}
+
+func Test_Writer_convert(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		line      string
+		converted string
+	}{
+		"colored_level": {
+			line:      "2024-01-02T03:04:05Z \x1b[36mINFO\x1b[0m hello",
+			converted: "time=2024-01-02T03:04:05Z level=info msg=hello",
+		},
+		"no_time": {
+			line:      "ERROR [http server] listening failed",
+			converted: `level=error component="http server" msg="listening failed"`,
+		},
+		"caller": {
+			line:      "DEBUG message with spaces\tmain.go:10",
+			converted: `level=debug msg="message with spaces" caller=main.go:10`,
+		},
+		"empty_message": {
+			line:      "INFO ",
+			converted: `level=info msg=""`,
+		},
+		"unknown_format": {
+			line:      "some \"other\" line",
+			converted: `msg="some \"other\" line"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			writer := NewWriter(nil)
+			converted := writer.convert(testCase.line)
+			assert.Equal(t, testCase.converted, converted)
+		})
+	}
+}
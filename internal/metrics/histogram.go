@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+)
+
+// requestDurationBuckets are the upper bounds in seconds
+// of the provider HTTP request duration histogram buckets.
+var requestDurationBuckets = []float64{ //nolint:gochecknoglobals
+	0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+type histogram struct {
+	upperBounds []float64
+	// counts contains the number of observations for each
+	// bucket, not including the observations of lower buckets.
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(upperBounds []float64) *histogram {
+	return &histogram{
+		upperBounds: upperBounds,
+		counts:      make([]uint64, len(upperBounds)),
+	}
+}
+
+func (h *histogram) observe(value float64) {
+	h.count++
+	h.sum += value
+	for i, upperBound := range h.upperBounds {
+		if value <= upperBound {
+			h.counts[i]++
+			return
+		}
+	}
+}
+
+func writeHistograms(sb *strings.Builder, name, help string,
+	histograms map[models.Provider]*histogram) {
+	writeHeader(sb, name, help, "histogram")
+	for _, provider := range sortedKeys(histograms) {
+		h := histograms[provider]
+		var cumulative uint64
+		for i, upperBound := range h.upperBounds {
+			cumulative += h.counts[i]
+			fmt.Fprintf(sb, "%s_bucket{provider=%q,le=%q} %d\n",
+				name, string(provider), formatFloat(upperBound), cumulative)
+		}
+		fmt.Fprintf(sb, "%s_bucket{provider=%q,le=\"+Inf\"} %d\n", name, string(provider), h.count)
+		fmt.Fprintf(sb, "%s_sum{provider=%q} %s\n", name, string(provider), formatFloat(h.sum))
+		fmt.Fprintf(sb, "%s_count{provider=%q} %d\n", name, string(provider), h.count)
+	}
+}
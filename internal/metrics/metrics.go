@@ -0,0 +1,202 @@
+// Package metrics holds counters and gauges about record updates, public
+// IP address fetches and provider HTTP requests, exposed in the Prometheus
+// text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+)
+
+// Metrics counts records created and updated, labeled by provider,
+// record update results and times, public IP fetch failures and
+// provider HTTP request durations. It is safe for concurrent use.
+type Metrics struct {
+	created         map[models.Provider]uint64
+	updated         map[models.Provider]uint64
+	records         map[Record]*recordStats
+	fetchFailures   map[string]uint64
+	requestDuration map[models.Provider]*histogram
+	mutex           sync.RWMutex
+}
+
+func New() *Metrics {
+	return &Metrics{
+		created:         make(map[models.Provider]uint64),
+		updated:         make(map[models.Provider]uint64),
+		records:         make(map[Record]*recordStats),
+		fetchFailures:   make(map[string]uint64),
+		requestDuration: make(map[models.Provider]*histogram),
+	}
+}
+
+// Record identifies a record in the metrics labels.
+type Record struct {
+	Provider  models.Provider
+	FQDN      string
+	IPVersion string
+}
+
+func (r Record) labels() string {
+	return fmt.Sprintf("provider=%q,record=%q,ip_version=%q",
+		string(r.Provider), r.FQDN, r.IPVersion)
+}
+
+type recordStats struct {
+	successes   uint64
+	failures    uint64
+	lastAttempt time.Time
+	lastSuccess time.Time
+}
+
+// RecordCreated increments the records created counter for the provider.
+// It should be called when a missing record got created by the provider.
+func (m *Metrics) RecordCreated(provider models.Provider) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.created[provider]++
+}
+
+// RecordUpdated increments the records updated counter for the provider.
+// It should be called when an existing record got updated by the provider.
+func (m *Metrics) RecordUpdated(provider models.Provider) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.updated[provider]++
+}
+
+// UpdateFinished counts an update attempt of the record finished at the
+// time given, as a success or a failure, and sets the record last attempt
+// time and, on success, its last success time.
+func (m *Metrics) UpdateFinished(record Record, success bool, at time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	stats, ok := m.records[record]
+	if !ok {
+		stats = new(recordStats)
+		m.records[record] = stats
+	}
+	stats.lastAttempt = at
+	if success {
+		stats.successes++
+		stats.lastSuccess = at
+	} else {
+		stats.failures++
+	}
+}
+
+// PublicIPFetchFailed increments the public IP address fetch
+// failures counter for the IP version given.
+func (m *Metrics) PublicIPFetchFailed(ipVersion string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.fetchFailures[ipVersion]++
+}
+
+// ProviderRequestDone observes the duration of an HTTP request
+// sent to the API of the provider given.
+func (m *Metrics) ProviderRequestDone(provider models.Provider, duration time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	h, ok := m.requestDuration[provider]
+	if !ok {
+		h = newHistogram(requestDurationBuckets)
+		m.requestDuration[provider] = h
+	}
+	h.observe(duration.Seconds())
+}
+
+// WriteTo writes all the metrics to w in the Prometheus text
+// exposition format, with label values sorted alphabetically.
+func (m *Metrics) WriteTo(w io.Writer) (n int64, err error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	sb := new(strings.Builder)
+	writeCounter(sb, "ddns_records_created_total",
+		"Total number of DNS records created because they were missing.", m.created)
+	writeCounter(sb, "ddns_records_updated_total",
+		"Total number of existing DNS records updated.", m.updated)
+	m.writeRecords(sb)
+	writeFetchFailures(sb, m.fetchFailures)
+	writeHistograms(sb, "ddns_provider_http_request_duration_seconds",
+		"Duration of HTTP requests sent to provider APIs.", m.requestDuration)
+
+	written, err := io.WriteString(w, sb.String())
+	return int64(written), err
+}
+
+func writeCounter(sb *strings.Builder, name, help string,
+	values map[models.Provider]uint64) {
+	writeHeader(sb, name, help, "counter")
+	for _, provider := range sortedKeys(values) {
+		fmt.Fprintf(sb, "%s{provider=%q} %d\n", name, string(provider), values[provider])
+	}
+}
+
+func (m *Metrics) writeRecords(sb *strings.Builder) {
+	records := make([]Record, 0, len(m.records))
+	for record := range m.records {
+		records = append(records, record)
+	}
+	slices.SortFunc(records, func(a, b Record) int {
+		return strings.Compare(a.labels(), b.labels())
+	})
+
+	const updatesName = "ddns_record_updates_total"
+	writeHeader(sb, updatesName, "Total number of record update attempts, by result.", "counter")
+	for _, record := range records {
+		stats := m.records[record]
+		fmt.Fprintf(sb, "%s{%s,result=\"success\"} %d\n", updatesName, record.labels(), stats.successes)
+		fmt.Fprintf(sb, "%s{%s,result=\"failure\"} %d\n", updatesName, record.labels(), stats.failures)
+	}
+
+	const lastAttemptName = "ddns_record_last_update_attempt_timestamp_seconds"
+	writeHeader(sb, lastAttemptName, "Unix time of the last record update attempt.", "gauge")
+	for _, record := range records {
+		fmt.Fprintf(sb, "%s{%s} %d\n", lastAttemptName, record.labels(), m.records[record].lastAttempt.Unix())
+	}
+
+	const lastSuccessName = "ddns_record_last_update_success_timestamp_seconds"
+	writeHeader(sb, lastSuccessName, "Unix time of the last successful record update.", "gauge")
+	for _, record := range records {
+		lastSuccess := m.records[record].lastSuccess
+		if lastSuccess.IsZero() {
+			continue
+		}
+		fmt.Fprintf(sb, "%s{%s} %d\n", lastSuccessName, record.labels(), lastSuccess.Unix())
+	}
+}
+
+func writeFetchFailures(sb *strings.Builder, values map[string]uint64) {
+	const name = "ddns_public_ip_fetch_failures_total"
+	writeHeader(sb, name, "Total number of failed public IP address fetches.", "counter")
+	for _, ipVersion := range sortedKeys(values) {
+		fmt.Fprintf(sb, "%s{ip_version=%q} %d\n", name, ipVersion, values[ipVersion])
+	}
+}
+
+func writeHeader(sb *strings.Builder, name, help, metricType string) {
+	fmt.Fprintf(sb, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(sb, "# TYPE %s %s\n", name, metricType)
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) (keys []K) {
+	keys = make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64) //nolint:gomnd
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Metrics_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	metrics := New()
+	metrics.RecordCreated(constants.Hetzner)
+	metrics.RecordUpdated(constants.Hetzner)
+	metrics.RecordUpdated(constants.Cloudflare)
+	metrics.RecordUpdated(constants.Hetzner)
+	record := Record{Provider: constants.Hetzner, FQDN: "example.com", IPVersion: "ipv4"}
+	metrics.UpdateFinished(record, true, time.Unix(1000, 0))
+	metrics.UpdateFinished(record, false, time.Unix(2000, 0))
+	failingRecord := Record{Provider: constants.Cloudflare, FQDN: "example.org", IPVersion: "ipv6"}
+	metrics.UpdateFinished(failingRecord, false, time.Unix(3000, 0))
+	metrics.PublicIPFetchFailed("ipv6")
+	metrics.ProviderRequestDone(constants.Hetzner, 200*time.Millisecond)
+	metrics.ProviderRequestDone(constants.Hetzner, 3*time.Second)
+
+	sb := new(strings.Builder)
+	n, err := metrics.WriteTo(sb)
+
+	require.NoError(t, err)
+	const expected = `# HELP ddns_records_created_total Total number of DNS records created because they were missing.
+# TYPE ddns_records_created_total counter
+ddns_records_created_total{provider="hetzner"} 1
+# HELP ddns_records_updated_total Total number of existing DNS records updated.
+# TYPE ddns_records_updated_total counter
+ddns_records_updated_total{provider="cloudflare"} 1
+ddns_records_updated_total{provider="hetzner"} 2
+# HELP ddns_record_updates_total Total number of record update attempts, by result.
+# TYPE ddns_record_updates_total counter
+ddns_record_updates_total{provider="cloudflare",record="example.org",ip_version="ipv6",result="success"} 0
+ddns_record_updates_total{provider="cloudflare",record="example.org",ip_version="ipv6",result="failure"} 1
+ddns_record_updates_total{provider="hetzner",record="example.com",ip_version="ipv4",result="success"} 1
+ddns_record_updates_total{provider="hetzner",record="example.com",ip_version="ipv4",result="failure"} 1
+# HELP ddns_record_last_update_attempt_timestamp_seconds Unix time of the last record update attempt.
+# TYPE ddns_record_last_update_attempt_timestamp_seconds gauge
+ddns_record_last_update_attempt_timestamp_seconds{provider="cloudflare",record="example.org",ip_version="ipv6"} 3000
+ddns_record_last_update_attempt_timestamp_seconds{provider="hetzner",record="example.com",ip_version="ipv4"} 2000
+# HELP ddns_record_last_update_success_timestamp_seconds Unix time of the last successful record update.
+# TYPE ddns_record_last_update_success_timestamp_seconds gauge
+ddns_record_last_update_success_timestamp_seconds{provider="hetzner",record="example.com",ip_version="ipv4"} 1000
+# HELP ddns_public_ip_fetch_failures_total Total number of failed public IP address fetches.
+# TYPE ddns_public_ip_fetch_failures_total counter
+ddns_public_ip_fetch_failures_total{ip_version="ipv6"} 1
+# HELP ddns_provider_http_request_duration_seconds Duration of HTTP requests sent to provider APIs.
+# TYPE ddns_provider_http_request_duration_seconds histogram
+ddns_provider_http_request_duration_seconds_bucket{provider="hetzner",le="0.05"} 0
+ddns_provider_http_request_duration_seconds_bucket{provider="hetzner",le="0.1"} 0
+ddns_provider_http_request_duration_seconds_bucket{provider="hetzner",le="0.25"} 1
+ddns_provider_http_request_duration_seconds_bucket{provider="hetzner",le="0.5"} 1
+ddns_provider_http_request_duration_seconds_bucket{provider="hetzner",le="1"} 1
+ddns_provider_http_request_duration_seconds_bucket{provider="hetzner",le="2.5"} 1
+ddns_provider_http_request_duration_seconds_bucket{provider="hetzner",le="5"} 2
+ddns_provider_http_request_duration_seconds_bucket{provider="hetzner",le="10"} 2
+ddns_provider_http_request_duration_seconds_bucket{provider="hetzner",le="+Inf"} 2
+ddns_provider_http_request_duration_seconds_sum{provider="hetzner"} 3.2
+ddns_provider_http_request_duration_seconds_count{provider="hetzner"} 2
+`
+	assert.Equal(t, expected, sb.String())
+	assert.Equal(t, int64(len(expected)), n)
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type PublicIPFetcher interface {
+	IP(ctx context.Context) (netip.Addr, error)
+	IP4(ctx context.Context) (netip.Addr, error)
+	IP6(ctx context.Context) (netip.Addr, error)
+}
+
+// WrapPublicIPFetcher returns a public IP fetcher using the fetcher given
+// and counting its failures in the public IP fetch failures counter.
+func (m *Metrics) WrapPublicIPFetcher(fetcher PublicIPFetcher) PublicIPFetcher {
+	return &countingFetcher{fetcher: fetcher, metrics: m}
+}
+
+type countingFetcher struct {
+	fetcher PublicIPFetcher
+	metrics *Metrics
+}
+
+func (f *countingFetcher) IP(ctx context.Context) (netip.Addr, error) {
+	ip, err := f.fetcher.IP(ctx)
+	if err != nil {
+		f.metrics.PublicIPFetchFailed(ipversion.IP4or6.String())
+	}
+	return ip, err
+}
+
+func (f *countingFetcher) IP4(ctx context.Context) (netip.Addr, error) {
+	ip, err := f.fetcher.IP4(ctx)
+	if err != nil {
+		f.metrics.PublicIPFetchFailed(ipversion.IP4.String())
+	}
+	return ip, err
+}
+
+func (f *countingFetcher) IP6(ctx context.Context) (netip.Addr, error) {
+	ip, err := f.fetcher.IP6(ctx)
+	if err != nil {
+		f.metrics.PublicIPFetchFailed(ipversion.IP6.String())
+	}
+	return ip, err
+}
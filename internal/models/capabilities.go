@@ -0,0 +1,49 @@
+package models
+
+import "strings"
+
+// Capabilities describes the features supported by a DNS provider.
+type Capabilities struct {
+	// IPv4 is true if the provider can update A records.
+	IPv4 bool
+	// IPv6 is true if the provider can update AAAA records.
+	IPv6 bool
+	// CreateMissing is true if the provider creates a record
+	// which does not exist, which can be disabled with the
+	// "missing_record" setting.
+	CreateMissing bool
+	// Proxied is true if the provider can proxy traffic to the
+	// record, set with the "proxied" setting.
+	Proxied bool
+	// TTL is true if the provider supports a custom record TTL,
+	// set with the "ttl" setting.
+	TTL bool
+	// View is true if the provider supports split-horizon views,
+	// set with the "view" setting or its "line" alias.
+	View bool
+	// StaticIPs is true if the provider supports record sets with
+	// multiple values, set with the "static_ips" setting.
+	StaticIPs bool
+}
+
+// String returns a comma separated list of the features supported.
+func (c Capabilities) String() string {
+	features := make([]string, 0, 7) //nolint:gomnd
+	for _, feature := range []struct {
+		supported bool
+		name      string
+	}{
+		{c.IPv4, "IPv4"},
+		{c.IPv6, "IPv6"},
+		{c.CreateMissing, "create missing record"},
+		{c.Proxied, "proxied"},
+		{c.TTL, "TTL"},
+		{c.View, "view"},
+		{c.StaticIPs, "static IPs"},
+	} {
+		if feature.supported {
+			features = append(features, feature.name)
+		}
+	}
+	return strings.Join(features, ", ")
+}
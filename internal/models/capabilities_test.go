@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Capabilities_String(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		capabilities Capabilities
+		s            string
+	}{
+		"none": {},
+		"ipv4_only": {
+			capabilities: Capabilities{IPv4: true},
+			s:            "IPv4",
+		},
+		"all": {
+			capabilities: Capabilities{
+				IPv4:          true,
+				IPv6:          true,
+				CreateMissing: true,
+				Proxied:       true,
+				TTL:           true,
+				View:          true,
+				StaticIPs:     true,
+			},
+			s: "IPv4, IPv6, create missing record, proxied, TTL, view, static IPs",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := testCase.capabilities.String()
+
+			assert.Equal(t, testCase.s, s)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"net/netip"
+	"slices"
 	"strings"
 	"time"
 )
@@ -64,6 +65,29 @@ func (h History) GetDurationSinceSuccess(now time.Time) string {
 	}
 }
 
+// Prune returns the history without the events older than the time given,
+// if it is not zero, and without the oldest events beyond the maximum
+// number of events given, if it is not zero. The latest event is always
+// kept since it is the current IP address. The history returned does
+// not share its memory with h if events were removed.
+func (h History) Prune(before time.Time, maxEvents uint) (pruned History, removed int) {
+	if len(h) == 0 {
+		return h, 0
+	}
+
+	start := len(h) - 1 // always keep the latest event
+	for start > 0 && (before.IsZero() || !h[start-1].Time.Before(before)) {
+		start--
+	}
+	if maxEvents > 0 && uint(len(h)-start) > maxEvents {
+		start = len(h) - int(maxEvents)
+	}
+	if start == 0 {
+		return h, 0
+	}
+	return slices.Clone(h[start:]), start
+}
+
 func (h History) String() (s string) {
 	currentIP := h.GetCurrentIP()
 	if !currentIP.IsValid() {
@@ -4,6 +4,9 @@ package models
 // It is exported so that the HTML template engine can render it.
 type HTMLData struct {
 	Rows []HTMLRow
+	// PersistenceDegraded is true if data is only kept in memory
+	// because the persistent database cannot be written.
+	PersistenceDegraded bool
 }
 
 // HTMLRow contains HTML fields to be rendered
@@ -16,4 +19,11 @@ type HTMLRow struct {
 	Status      string
 	CurrentIP   string
 	PreviousIPs string
+	// Preview shows the IP address at the provider
+	// alongside the public IP address detected.
+	Preview string
+	// LastError is the truncated error of the last failed update.
+	LastError string
+	// Capabilities lists the features supported by the provider.
+	Capabilities string
 }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// NotificationData contains the fields available to the notification
+// template when the IP address of a record changes.
+// It is exported so that the template engine can render it.
+type NotificationData struct {
+	Host   string
+	Domain string
+	// FQDN is the fully qualified domain name built from
+	// the host and domain, for example sub.example.com.
+	FQDN     string
+	Provider string
+	// OldIP is the previous IP address of the record,
+	// and is the empty string if there is none.
+	OldIP string
+	NewIP string
+	Time  time.Time
+	Tags  []string
+}
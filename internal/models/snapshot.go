@@ -0,0 +1,27 @@
+package models
+
+import (
+	"net/netip"
+	"time"
+)
+
+// Snapshot contains the current state of all records,
+// and is used to backup and restore the program state.
+type Snapshot struct {
+	Records []SnapshotRecord `json:"records"`
+}
+
+// SnapshotRecord contains the current state of a record.
+type SnapshotRecord struct {
+	Domain    string `json:"domain"`
+	Host      string `json:"host"`
+	IPVersion string `json:"ip_version"`
+	// IP is the current IP address of the record,
+	// and is the zero value if the record was never updated.
+	IP netip.Addr `json:"ip"`
+	// Time is the time of the last successful update.
+	Time time.Time `json:"time"`
+	// ManagedIDs are the provider record IDs managed by the program,
+	// for providers supporting the managed records only safeguard.
+	ManagedIDs []string `json:"managed_ids,omitempty"`
+}
@@ -0,0 +1,98 @@
+// Package netgate restricts updates to allowed networks, identified by
+// the network interface or the gateway MAC address of the default route,
+// for example to not publish the IP address of a tethered mobile connection.
+package netgate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+type Settings struct {
+	// Interfaces are the names of the network interfaces of
+	// the default route for which updates are allowed.
+	Interfaces []string
+	// GatewayMACs are the MAC addresses of the default route
+	// gateway for which updates are allowed.
+	GatewayMACs []string
+}
+
+type Gate struct {
+	interfaces  []string
+	gatewayMACs []string
+	readFile    func(name string) ([]byte, error)
+}
+
+// New creates a network gate allowing updates if the default route goes
+// through one of the interfaces given or through a gateway with one of
+// the MAC addresses given. If both are empty, all networks are allowed.
+func New(settings Settings) *Gate {
+	gatewayMACs := make([]string, len(settings.GatewayMACs))
+	for i, mac := range settings.GatewayMACs {
+		gatewayMACs[i] = strings.ToLower(mac)
+	}
+	return &Gate{
+		interfaces:  settings.Interfaces,
+		gatewayMACs: gatewayMACs,
+		readFile:    os.ReadFile,
+	}
+}
+
+// Enabled returns true if the gate restricts updates to some networks.
+func (g *Gate) Enabled() bool {
+	return len(g.interfaces) > 0 || len(g.gatewayMACs) > 0
+}
+
+// Supported returns true if the default route can be
+// inspected on this platform, which is only Linux for now.
+func Supported() bool {
+	return runtime.GOOS == "linux"
+}
+
+var ErrNotAllowed = errors.New("network is not allowed")
+
+// Check returns an error wrapping ErrNotAllowed if the current
+// network is not allowed, or another error if the default route
+// cannot be inspected. It returns nil if the gate is not enabled.
+func (g *Gate) Check() (err error) {
+	if !g.Enabled() {
+		return nil
+	}
+
+	routeData, err := g.readFile("/proc/net/route")
+	if err != nil {
+		return fmt.Errorf("reading routes: %w", err)
+	}
+	route, err := parseDefaultRoute(routeData)
+	if errors.Is(err, errDefaultRouteNotFound) {
+		return fmt.Errorf("%w: %w", ErrNotAllowed, err)
+	} else if err != nil {
+		return fmt.Errorf("parsing routes: %w", err)
+	}
+
+	if slices.Contains(g.interfaces, route.iface) {
+		return nil
+	}
+
+	gatewayMAC := "unknown"
+	if len(g.gatewayMACs) > 0 {
+		arpData, err := g.readFile("/proc/net/arp")
+		if err != nil {
+			return fmt.Errorf("reading ARP table: %w", err)
+		}
+		mac, found := findMAC(arpData, route.iface, route.gateway)
+		if found {
+			gatewayMAC = mac
+			if slices.Contains(g.gatewayMACs, mac) {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: default route through interface %s and gateway %s with MAC address %s",
+		ErrNotAllowed, route.iface, route.gateway, gatewayMAC)
+}
@@ -0,0 +1,102 @@
+package netgate
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"net/netip"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// routeHex returns the IPv4 address given as written
+// in /proc/net/route, in the host byte order.
+func routeHex(s string) string {
+	ip := netip.MustParseAddr(s).As4()
+	b := make([]byte, 4) //nolint:gomnd
+	binary.NativeEndian.PutUint32(b, binary.BigEndian.Uint32(ip[:]))
+	return hex.EncodeToString(b)
+}
+
+func Test_Gate_Check(t *testing.T) {
+	t.Parallel()
+
+	routes := "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+		"wwan0\t00000000\t" + routeHex("10.64.0.1") + "\t0003\t0\t0\t700\t00000000\t0\t0\t0\n" +
+		"wlan0\t00000000\t" + routeHex("192.168.1.1") + "\t0003\t0\t0\t600\t00000000\t0\t0\t0\n" +
+		"eth0\t00000000\t" + routeHex("192.168.2.1") + "\t0002\t0\t0\t100\t00000000\t0\t0\t0\n" +
+		"wlan0\t0001A8C0\t00000000\t0001\t0\t0\t600\t00FFFFFF\t0\t0\t0\n"
+	const arp = "IP address       HW type     Flags       HW address            Mask     Device\n" +
+		"192.168.1.1      0x1         0x2         AA:BB:CC:DD:EE:FF     *        wlan0\n" +
+		"10.64.0.1        0x1         0x2         11:22:33:44:55:66     *        wwan0\n"
+	const noDefaultRoute = "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+		"wlan0\t0001A8C0\t00000000\t0001\t0\t0\t600\t00FFFFFF\t0\t0\t0\n"
+
+	testCases := map[string]struct {
+		settings   Settings
+		routes     string
+		errWrapped error
+		errMessage string
+	}{
+		"disabled": {},
+		"interface_allowed": {
+			settings: Settings{Interfaces: []string{"eth0", "wlan0"}},
+			routes:   routes,
+		},
+		"interface_not_allowed": {
+			settings:   Settings{Interfaces: []string{"eth0"}},
+			routes:     routes,
+			errWrapped: ErrNotAllowed,
+			errMessage: "network is not allowed: default route through interface wlan0 " +
+				"and gateway 192.168.1.1 with MAC address unknown",
+		},
+		"gateway_mac_allowed": {
+			settings: Settings{GatewayMACs: []string{"aa:bb:cc:dd:ee:ff"}},
+			routes:   routes,
+		},
+		"gateway_mac_not_allowed": {
+			settings:   Settings{GatewayMACs: []string{"11:22:33:44:55:66"}},
+			routes:     routes,
+			errWrapped: ErrNotAllowed,
+			errMessage: "network is not allowed: default route through interface wlan0 " +
+				"and gateway 192.168.1.1 with MAC address aa:bb:cc:dd:ee:ff",
+		},
+		"no_default_route": {
+			settings:   Settings{Interfaces: []string{"wlan0"}},
+			routes:     noDefaultRoute,
+			errWrapped: ErrNotAllowed,
+			errMessage: "network is not allowed: default route not found",
+		},
+		"routes_not_readable": {
+			settings:   Settings{Interfaces: []string{"wlan0"}},
+			errWrapped: os.ErrNotExist,
+			errMessage: "reading routes: file does not exist",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			gate := New(testCase.settings)
+			gate.readFile = func(name string) ([]byte, error) {
+				switch {
+				case name == "/proc/net/route" && testCase.routes != "":
+					return []byte(testCase.routes), nil
+				case name == "/proc/net/arp":
+					return []byte(arp), nil
+				}
+				return nil, os.ErrNotExist
+			}
+
+			err := gate.Check()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
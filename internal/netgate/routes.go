@@ -0,0 +1,115 @@
+package netgate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+type defaultRoute struct {
+	iface   string
+	gateway netip.Addr
+	metric  uint64
+}
+
+var (
+	errDefaultRouteNotFound = errors.New("default route not found")
+	errRouteLineMalformed   = errors.New("route line is malformed")
+)
+
+// parseDefaultRoute parses the content of /proc/net/route and returns
+// the IPv4 default route which is up and has the lowest metric.
+func parseDefaultRoute(data []byte) (route defaultRoute, err error) {
+	const (
+		ifaceField = iota
+		destinationField
+		gatewayField
+		flagsField
+		_ // refcnt
+		_ // use
+		metricField
+		maskField
+		minFields
+	)
+	const routeFlagUp = 0x1
+
+	found := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Scan() // skip header line
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < minFields {
+			continue
+		}
+		if fields[destinationField] != "00000000" || fields[maskField] != "00000000" {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(fields[flagsField], 16, 16) //nolint:gomnd
+		if err != nil {
+			return defaultRoute{}, fmt.Errorf("%w: flags: %w", errRouteLineMalformed, err)
+		} else if flags&routeFlagUp == 0 {
+			continue
+		}
+
+		metric, err := strconv.ParseUint(fields[metricField], 10, 64) //nolint:gomnd
+		if err != nil {
+			return defaultRoute{}, fmt.Errorf("%w: metric: %w", errRouteLineMalformed, err)
+		} else if found && metric >= route.metric {
+			continue
+		}
+
+		gatewayBytes, err := hex.DecodeString(fields[gatewayField])
+		if err != nil || len(gatewayBytes) != 4 { //nolint:gomnd
+			return defaultRoute{}, fmt.Errorf("%w: gateway: %s",
+				errRouteLineMalformed, fields[gatewayField])
+		}
+		// The gateway address is written in the host byte order.
+		var gateway [4]byte
+		binary.BigEndian.PutUint32(gateway[:], binary.NativeEndian.Uint32(gatewayBytes))
+
+		route = defaultRoute{
+			iface:   fields[ifaceField],
+			gateway: netip.AddrFrom4(gateway),
+			metric:  metric,
+		}
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return defaultRoute{}, err
+	}
+
+	if !found {
+		return defaultRoute{}, fmt.Errorf("%w", errDefaultRouteNotFound)
+	}
+	return route, nil
+}
+
+// findMAC finds the MAC address of the IP address given on the interface
+// given, from the content of /proc/net/arp.
+func findMAC(data []byte, iface string, ip netip.Addr) (mac string, found bool) {
+	const (
+		ipField     = 0
+		macField    = 3
+		deviceField = 5
+		minFields   = 6
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Scan() // skip header line
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < minFields || fields[deviceField] != iface ||
+			fields[ipField] != ip.String() || fields[macField] == "00:00:00:00:00:00" {
+			continue
+		}
+		return strings.ToLower(fields[macField]), true
+	}
+	return "", false
+}
@@ -0,0 +1,84 @@
+// Package nscheck verifies a record update by querying a nameserver
+// for the record, instead of trusting the value echoed by the provider API.
+package nscheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+)
+
+type Settings struct {
+	// Address is the address of the nameserver to query, in
+	// the form host:port, usually an authoritative nameserver
+	// of the domain to avoid caching resolvers.
+	Address string
+	// Timeout is the maximum duration to wait for the
+	// nameserver to return the IP address expected.
+	Timeout time.Duration
+	// Interval is the duration to wait between queries.
+	Interval time.Duration
+}
+
+type LookupIPer interface {
+	LookupIP(ctx context.Context, network, host string) (ips []net.IP, err error)
+}
+
+// NewResolver returns a resolver querying the nameserver
+// at the address given directly, over UDP.
+func NewResolver(address string) *net.Resolver {
+	dialer := net.Dialer{}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			const protocol = "udp"
+			return dialer.DialContext(ctx, protocol, address)
+		},
+	}
+}
+
+var ErrIPNotServed = errors.New("nameserver does not serve the IP address expected")
+
+// Check queries the resolver for the hostname until the IP address
+// given is part of the addresses returned. It returns an error if
+// this is not the case within the settings timeout, mentioning the
+// last IP addresses received or the last lookup error.
+func Check(ctx context.Context, resolver LookupIPer, settings Settings,
+	hostname string, ip netip.Addr) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, settings.Timeout)
+	defer cancel()
+
+	network := "ip4"
+	if ip.Is6() {
+		network = "ip6"
+	}
+
+	var lastIPs []net.IP
+	var lookupErr error
+	timer := time.NewTimer(0)
+	for {
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			if lookupErr != nil {
+				return fmt.Errorf("%w: %s at %s for %s: %w",
+					ErrIPNotServed, ip, settings.Address, hostname, lookupErr)
+			}
+			return fmt.Errorf("%w: %s at %s for %s, received %v",
+				ErrIPNotServed, ip, settings.Address, hostname, lastIPs)
+		}
+
+		lastIPs, lookupErr = resolver.LookupIP(ctx, network, hostname)
+		for _, netIP := range lastIPs {
+			servedIP, ok := netip.AddrFromSlice(netIP)
+			if ok && servedIP.Unmap() == ip.Unmap() {
+				timer.Stop()
+				return nil
+			}
+		}
+		timer.Reset(settings.Interval)
+	}
+}
@@ -0,0 +1,118 @@
+package nscheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockResolver struct {
+	network string
+	host    string
+	// responses are returned in order, and the last
+	// response is returned once all others were returned.
+	responses []mockResponse
+	calls     int
+}
+
+type mockResponse struct {
+	ips []net.IP
+	err error
+}
+
+func (m *mockResolver) LookupIP(_ context.Context, network, host string) (
+	ips []net.IP, err error) {
+	if network != m.network || host != m.host {
+		return nil, errors.New("unexpected lookup")
+	}
+	response := m.responses[min(m.calls, len(m.responses)-1)]
+	m.calls++
+	return response.ips, response.err
+}
+
+func Test_Check(t *testing.T) {
+	t.Parallel()
+
+	errTimeout := errors.New("i/o timeout")
+
+	testCases := map[string]struct {
+		ip         netip.Addr
+		network    string
+		responses  []mockResponse
+		calls      int
+		errWrapped error
+		errMessage string
+	}{
+		"served_immediately": {
+			ip:      netip.MustParseAddr("1.2.3.4"),
+			network: "ip4",
+			responses: []mockResponse{
+				{ips: []net.IP{net.ParseIP("5.6.7.8"), net.ParseIP("1.2.3.4")}},
+			},
+			calls: 1,
+		},
+		"served_after_propagation": {
+			ip:      netip.MustParseAddr("::1"),
+			network: "ip6",
+			responses: []mockResponse{
+				{err: errTimeout},
+				{ips: []net.IP{net.ParseIP("::2")}},
+				{ips: []net.IP{net.ParseIP("::1")}},
+			},
+			calls: 3,
+		},
+		"old_ip_served": {
+			ip:      netip.MustParseAddr("1.2.3.4"),
+			network: "ip4",
+			responses: []mockResponse{
+				{ips: []net.IP{net.ParseIP("5.6.7.8")}},
+			},
+			errWrapped: ErrIPNotServed,
+			errMessage: "nameserver does not serve the IP address expected: " +
+				"1.2.3.4 at ns1.example.com:53 for host.example.com, received [5.6.7.8]",
+		},
+		"lookup_failing": {
+			ip:      netip.MustParseAddr("1.2.3.4"),
+			network: "ip4",
+			responses: []mockResponse{
+				{err: errTimeout},
+			},
+			errWrapped: ErrIPNotServed,
+			errMessage: "nameserver does not serve the IP address expected: " +
+				"1.2.3.4 at ns1.example.com:53 for host.example.com: i/o timeout",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resolver := &mockResolver{
+				network:   testCase.network,
+				host:      "host.example.com",
+				responses: testCase.responses,
+			}
+			settings := Settings{
+				Address:  "ns1.example.com:53",
+				Timeout:  50 * time.Millisecond,
+				Interval: time.Millisecond,
+			}
+
+			err := Check(context.Background(), resolver, settings,
+				"host.example.com", testCase.ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.calls, resolver.calls)
+		})
+	}
+}
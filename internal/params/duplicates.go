@@ -0,0 +1,47 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+var ErrDuplicateRecord = errors.New("duplicate record")
+
+// RemoveDuplicates removes settings updating the same record as
+// previous settings, that is with the same domain, host and IP version,
+// since each would otherwise be updated once per update cycle.
+// If strict is true, an error is returned on the first duplicate found.
+// Otherwise, only the first settings are kept and a warning is returned
+// for each duplicate removed.
+func RemoveDuplicates(settings []Settings, strict bool) (
+	deduplicated []Settings, warnings []string, err error) {
+	type target struct {
+		domain    string
+		host      string
+		ipVersion string
+	}
+	seen := make(map[target]struct{}, len(settings))
+	deduplicated = make([]Settings, 0, len(settings))
+	for _, setting := range settings {
+		provider := setting.Provider
+		key := target{
+			domain:    utils.NormalizeRecordName(provider.Domain(), false),
+			host:      utils.NormalizeRecordName(provider.Host(), false),
+			ipVersion: provider.IPVersion().String(),
+		}
+		_, duplicate := seen[key]
+		if !duplicate {
+			seen[key] = struct{}{}
+			deduplicated = append(deduplicated, setting)
+			continue
+		}
+
+		if strict {
+			return nil, warnings, fmt.Errorf("%w: %s", ErrDuplicateRecord, provider)
+		}
+		warnings = append(warnings, fmt.Sprintf("ignoring %s, already configured", provider))
+	}
+	return deduplicated, warnings, nil
+}
@@ -0,0 +1,65 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RemoveDuplicates(t *testing.T) {
+	t.Parallel()
+
+	const config = `{"settings": [
+		{"provider":"gandi","domain":"example.com","host":"@,www",
+			"ip_version":"ipv4","personal_access_token":"token"},
+		{"provider":"gandi","domain":"example.com","host":"@",
+			"ip_version":"ipv6","personal_access_token":"token"},
+		{"provider":"gandi","domain":"Example.com","host":"WWW",
+			"ip_version":"ipv4","personal_access_token":"other"}
+	]}`
+
+	testCases := map[string]struct {
+		strict     bool
+		domains    []string
+		warnings   []string
+		errWrapped error
+		errMessage string
+	}{
+		"lenient": {
+			domains: []string{"example.com", "www.example.com", "example.com"},
+			warnings: []string{"ignoring [domain: Example.com | host: WWW | " +
+				"provider: gandi | ip: ipv4], already configured"},
+		},
+		"strict": {
+			strict:     true,
+			errWrapped: ErrDuplicateRecord,
+			errMessage: "duplicate record: [domain: Example.com | host: WWW | " +
+				"provider: gandi | ip: ipv4]",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings, _, err := extractAllSettings([]byte(config))
+			require.NoError(t, err)
+			require.Len(t, settings, 4)
+
+			settings, warnings, err := RemoveDuplicates(settings, testCase.strict)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.warnings, warnings)
+			var domains []string
+			for _, setting := range settings {
+				domains = append(domains, setting.Provider.BuildDomainName())
+			}
+			assert.Equal(t, testCase.domains, domains)
+		})
+	}
+}
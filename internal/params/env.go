@@ -0,0 +1,93 @@
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const recordEnvPrefix = "RECORD_"
+
+// getSettingsFromRecordEnv obtains the update settings from environment
+// variables defining records without a configuration file. The first
+// record settings are set with variables prefixed with RECORD_, such as
+// RECORD_PROVIDER or RECORD_TOKEN, and further records with variables
+// prefixed with RECORD_2_, RECORD_3_ and so on. The rest of each variable
+// name is the lowercased settings key, such as IP_VERSION for ip_version.
+func (r *Reader) getSettingsFromRecordEnv() (
+	settings []Settings, warnings []string, err error) {
+	recordsKeyValues := make(map[int]map[string]json.RawMessage)
+	for _, keyValue := range r.environ() {
+		name, value, _ := strings.Cut(keyValue, "=")
+		rest, ok := strings.CutPrefix(name, recordEnvPrefix)
+		if !ok || value == "" {
+			continue
+		}
+
+		index := 1
+		indexString, key, hasIndex := strings.Cut(rest, "_")
+		if hasIndex {
+			n, err := strconv.Atoi(indexString)
+			if err == nil && n > 0 {
+				index = n
+				rest = key
+			}
+		}
+
+		keyValues, ok := recordsKeyValues[index]
+		if !ok {
+			keyValues = make(map[string]json.RawMessage)
+			recordsKeyValues[index] = keyValues
+		}
+		keyValues[strings.ToLower(rest)] = envValueToJSON(value)
+	}
+
+	if len(recordsKeyValues) == 0 {
+		return nil, nil, nil
+	}
+	r.logger.Info("reading config from " + recordEnvPrefix + " environment variables")
+
+	indexes := make([]int, 0, len(recordsKeyValues))
+	for index := range recordsKeyValues {
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+
+	rawConfig := struct {
+		Settings []map[string]json.RawMessage `json:"settings"`
+	}{
+		Settings: make([]map[string]json.RawMessage, len(indexes)),
+	}
+	for i, index := range indexes {
+		rawConfig.Settings[i] = recordsKeyValues[index]
+	}
+	jsonBytes, err := json.Marshal(rawConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings, warnings, err = extractAllSettings(jsonBytes)
+	if err != nil {
+		return nil, warnings, fmt.Errorf("%s environment variables: %w", recordEnvPrefix, err)
+	}
+	return settings, warnings, nil
+}
+
+// envValueToJSON converts an environment variable value to JSON.
+// The values true and false are booleans, valid JSON arrays and
+// objects are kept as is, and other values are strings, since
+// numeric settings also accept strings.
+func envValueToJSON(value string) json.RawMessage {
+	switch {
+	case value == "true" || value == "false":
+		return json.RawMessage(value)
+	case strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{"):
+		if json.Valid([]byte(value)) {
+			return json.RawMessage(value)
+		}
+	}
+	b, _ := json.Marshal(value) // cannot fail for a string
+	return b
+}
@@ -0,0 +1,116 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Info(string)  {}
+func (noopLogger) Debug(string) {}
+
+func Test_Reader_getSettingsFromRecordEnv(t *testing.T) {
+	t.Parallel()
+
+	reader := &Reader{
+		logger: noopLogger{},
+		environ: func() []string {
+			return []string{
+				"PATH=/usr/bin",
+				"RECORD_2_PROVIDER=cloudflare",
+				"RECORD_2_DOMAIN=example.org",
+				"RECORD_2_ZONE_IDENTIFIER=zone",
+				"RECORD_2_TOKEN=token",
+				"RECORD_2_PROXIED=true",
+				"RECORD_2_TTL=300",
+				"RECORD_PROVIDER=namecheap",
+				"RECORD_DOMAIN=example.com",
+				"RECORD_HOST=@",
+				"RECORD_PASSWORD=e5322165c1d74692bfa6d807100c0310",
+				"RECORD_IP_VERSION=ipv4",
+				"RECORD_TAGS=[\"home\"]",
+				"RECORD_VIEW=",
+			}
+		},
+	}
+
+	settings, warnings, err := reader.getSettingsFromRecordEnv()
+
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	require.Len(t, settings, 2)
+	assert.Equal(t, constants.Namecheap, settings[0].Options.ProviderName)
+	assert.Equal(t, "example.com", settings[0].Provider.BuildDomainName())
+	assert.Equal(t, ipversion.IP4, settings[0].Provider.IPVersion())
+	assert.Equal(t, []string{"home"}, settings[0].Options.Tags)
+	assert.Equal(t, constants.Cloudflare, settings[1].Options.ProviderName)
+	assert.True(t, settings[1].Provider.Proxied())
+}
+
+func Test_Reader_getSettingsFromRecordEnv_none(t *testing.T) {
+	t.Parallel()
+
+	reader := &Reader{
+		logger:  noopLogger{},
+		environ: func() []string { return []string{"PATH=/usr/bin"} },
+	}
+
+	settings, warnings, err := reader.getSettingsFromRecordEnv()
+
+	assert.NoError(t, err)
+	assert.Nil(t, warnings)
+	assert.Nil(t, settings)
+}
+
+func Test_Reader_getSettingsFromRecordEnv_missingVariable(t *testing.T) {
+	t.Parallel()
+
+	reader := &Reader{
+		logger: noopLogger{},
+		environ: func() []string {
+			return []string{
+				"RECORD_PROVIDER=namecheap",
+				"RECORD_DOMAIN=example.com",
+				"RECORD_HOST=@",
+			}
+		},
+	}
+
+	settings, _, err := reader.getSettingsFromRecordEnv()
+
+	assert.ErrorIs(t, err, errors.ErrPasswordNotValid)
+	assert.EqualError(t, err, "RECORD_ environment variables: password is not valid: "+
+		`password "" does not match regex "^[a-f0-9]{32}$"`)
+	assert.Nil(t, settings)
+}
+
+func Test_envValueToJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		value string
+		json  string
+	}{
+		"string":        {value: "token", json: `"token"`},
+		"number":        {value: "300", json: `"300"`},
+		"boolean":       {value: "true", json: `true`},
+		"array":         {value: `["a","b"]`, json: `["a","b"]`},
+		"object":        {value: `{"port":80}`, json: `{"port":80}`},
+		"malformed":     {value: `[a`, json: `"[a"`},
+		"special_chars": {value: `p"ss`, json: `"p\"ss"`},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.json, string(envValueToJSON(testCase.value)))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/failover"
+	"github.com/qdm12/ddns-updater/internal/probe"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type failoverSettings struct {
+	PrimaryIP         netip.Addr `json:"primary_ip"`
+	BackupIP          netip.Addr `json:"backup_ip"`
+	Port              uint16     `json:"port"`
+	HTTPPath          string     `json:"http_path,omitempty"`
+	Timeout           string     `json:"timeout,omitempty"`
+	FailureThreshold  uint       `json:"failure_threshold,omitempty"`
+	RecoveryThreshold uint       `json:"recovery_threshold,omitempty"`
+}
+
+var (
+	ErrFailoverIPNotSet          = errors.New("failover IP address is not set")
+	ErrFailoverIPVersionMismatch = errors.New("failover IP address does not match the IP version")
+	ErrFailoverPortNotSet        = errors.New("failover health check port is not set")
+	ErrFailoverHTTPPathNotValid  = errors.New("failover health check HTTP path must start with /")
+	ErrFailoverTimeoutNotValid   = errors.New("failover health check timeout is not valid")
+)
+
+func makeFailoverSettings(settings failoverSettings, ipVersion ipversion.IPVersion) (
+	result *failover.Settings, err error) {
+	names := [...]string{"primary", "backup"}
+	for i, ip := range [...]netip.Addr{settings.PrimaryIP, settings.BackupIP} {
+		name := names[i]
+		switch {
+		case !ip.IsValid():
+			return nil, fmt.Errorf("%w: %s", ErrFailoverIPNotSet, name)
+		case ipVersion == ipversion.IP4 && !ip.Is4(),
+			ipVersion == ipversion.IP6 && !ip.Is6():
+			return nil, fmt.Errorf("%w: %s IP address %s for IP version %s",
+				ErrFailoverIPVersionMismatch, name, ip, ipVersion)
+		}
+	}
+
+	if settings.Port == 0 {
+		return nil, fmt.Errorf("%w", ErrFailoverPortNotSet)
+	} else if settings.HTTPPath != "" && !strings.HasPrefix(settings.HTTPPath, "/") {
+		return nil, fmt.Errorf("%w: %s", ErrFailoverHTTPPathNotValid, settings.HTTPPath)
+	}
+
+	const defaultTimeout = 3 * time.Second
+	timeout := defaultTimeout
+	if settings.Timeout != "" {
+		timeout, err = time.ParseDuration(settings.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailoverTimeoutNotValid, err)
+		}
+	}
+
+	const defaultThreshold = 3
+	result = &failover.Settings{
+		PrimaryIP:         settings.PrimaryIP,
+		BackupIP:          settings.BackupIP,
+		FailureThreshold:  settings.FailureThreshold,
+		RecoveryThreshold: settings.RecoveryThreshold,
+		HealthCheck: probe.Settings{
+			Port:     settings.Port,
+			HTTPPath: settings.HTTPPath,
+			Timeout:  timeout,
+		},
+	}
+	if result.FailureThreshold == 0 {
+		result.FailureThreshold = defaultThreshold
+	}
+	if result.RecoveryThreshold == 0 {
+		result.RecoveryThreshold = defaultThreshold
+	}
+	return result, nil
+}
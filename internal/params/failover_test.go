@@ -0,0 +1,85 @@
+package params
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/failover"
+	"github.com/qdm12/ddns-updater/internal/probe"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_makeFailoverSettings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings   failoverSettings
+		ipVersion  ipversion.IPVersion
+		result     *failover.Settings
+		errWrapped error
+		errMessage string
+	}{
+		"defaults": {
+			settings: failoverSettings{
+				PrimaryIP: netip.MustParseAddr("1.1.1.1"),
+				BackupIP:  netip.MustParseAddr("2.2.2.2"),
+				Port:      443,
+			},
+			ipVersion: ipversion.IP4,
+			result: &failover.Settings{
+				PrimaryIP:         netip.MustParseAddr("1.1.1.1"),
+				BackupIP:          netip.MustParseAddr("2.2.2.2"),
+				FailureThreshold:  3,
+				RecoveryThreshold: 3,
+				HealthCheck: probe.Settings{
+					Port:    443,
+					Timeout: 3 * time.Second,
+				},
+			},
+		},
+		"backup_not_set": {
+			settings: failoverSettings{
+				PrimaryIP: netip.MustParseAddr("1.1.1.1"),
+				Port:      443,
+			},
+			errWrapped: ErrFailoverIPNotSet,
+			errMessage: "failover IP address is not set: backup",
+		},
+		"ip_version_mismatch": {
+			settings: failoverSettings{
+				PrimaryIP: netip.MustParseAddr("1.1.1.1"),
+				BackupIP:  netip.MustParseAddr("::2"),
+				Port:      443,
+			},
+			ipVersion:  ipversion.IP4,
+			errWrapped: ErrFailoverIPVersionMismatch,
+			errMessage: "failover IP address does not match the IP version: " +
+				"backup IP address ::2 for IP version ipv4",
+		},
+		"port_not_set": {
+			settings: failoverSettings{
+				PrimaryIP: netip.MustParseAddr("1.1.1.1"),
+				BackupIP:  netip.MustParseAddr("2.2.2.2"),
+			},
+			errWrapped: ErrFailoverPortNotSet,
+			errMessage: "failover health check port is not set",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := makeFailoverSettings(testCase.settings, testCase.ipVersion)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.result, result)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package params
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+var (
+	ErrFallbackProviderNotSet = errors.New("fallback provider is not set")
+	ErrFallbackFallbackNested = errors.New("fallback provider cannot have a fallback provider")
+)
+
+// makeFallback creates the fallback provider for the host, using the
+// provider name and the provider specific settings found in rawSettings.
+// The provider settings are validated here, such that invalid fallback
+// credentials are detected at startup and not only on a primary failure.
+func makeFallback(rawSettings json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	fallback *records.Fallback, err error) {
+	var common struct {
+		Provider string          `json:"provider"`
+		Fallback json.RawMessage `json:"fallback"`
+	}
+	err = json.Unmarshal(rawSettings, &common)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case common.Provider == "":
+		return nil, fmt.Errorf("%w", ErrFallbackProviderNotSet)
+	case common.Fallback != nil:
+		return nil, fmt.Errorf("%w", ErrFallbackFallbackNested)
+	}
+
+	providerName := models.Provider(common.Provider)
+	err = provider.CheckIPVersion(providerName, domain, host, ipVersion)
+	if err != nil {
+		return nil, err
+	}
+	fallbackProvider, err := provider.New(providerName, rawSettings, domain,
+		host, ipVersion, ipv6Suffix)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", providerName, err)
+	}
+
+	return &records.Fallback{
+		Name:     providerName,
+		Provider: fallbackProvider,
+	}, nil
+}
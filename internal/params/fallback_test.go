@@ -0,0 +1,60 @@
+package params
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_makeFallback(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		rawJSON    string
+		errWrapped error
+		errMessage string
+	}{
+		"valid": {
+			rawJSON: `{"provider":"gandi","personal_access_token":"token"}`,
+		},
+		"provider_not_set": {
+			rawJSON:    `{"personal_access_token":"token"}`,
+			errWrapped: ErrFallbackProviderNotSet,
+			errMessage: "fallback provider is not set",
+		},
+		"nested_fallback": {
+			rawJSON:    `{"provider":"gandi","personal_access_token":"token","fallback":{}}`,
+			errWrapped: ErrFallbackFallbackNested,
+			errMessage: "fallback provider cannot have a fallback provider",
+		},
+		"credentials_not_valid": {
+			rawJSON:    `{"provider":"gandi"}`,
+			errWrapped: errors.ErrKeyNotSet,
+			errMessage: "gandi: key is not set: API key and personal access token not set",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			fallback, err := makeFallback(json.RawMessage(testCase.rawJSON),
+				"example.com", "@", ipversion.IP4, netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				assert.Nil(t, fallback)
+				return
+			}
+			assert.Equal(t, constants.Gandi, fallback.Name)
+			assert.Equal(t, "example.com", fallback.Provider.BuildDomainName())
+		})
+	}
+}
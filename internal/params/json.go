@@ -7,44 +7,79 @@ import (
 	"fmt"
 	"io/fs"
 	"net/netip"
+	"net/url"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/provider"
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/secrets"
+	"github.com/qdm12/ddns-updater/internal/transform"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
 
 type commonSettings struct {
-	Provider   string       `json:"provider"`
-	Domain     string       `json:"domain"`
-	Host       string       `json:"host"`
-	IPVersion  string       `json:"ip_version"`
-	IPv6Suffix netip.Prefix `json:"ipv6_suffix,omitempty"`
-	// Retro values for warnings
-	IPMethod *string `json:"ip_method,omitempty"`
-	Delay    *uint64 `json:"delay,omitempty"`
+	Provider        string                   `json:"provider"`
+	Domain          string                   `json:"domain"`
+	Host            string                   `json:"host"`
+	IPVersion       string                   `json:"ip_version"`
+	IPv6Suffix      netip.Prefix             `json:"ipv6_suffix,omitempty"`
+	View            string                   `json:"view,omitempty"`
+	Line            string                   `json:"line,omitempty"` // alias for view
+	StaticIPs       []netip.Addr             `json:"static_ips,omitempty"`
+	SkipVerify      bool                     `json:"skip_verify,omitempty"`
+	Tags            []string                 `json:"tags,omitempty"`
+	IgnoreErrors    []string                 `json:"ignore_errors,omitempty"`
+	ExtraHeaders    map[string]string        `json:"extra_headers,omitempty"`
+	APIURL          string                   `json:"api_url,omitempty"`
+	Failover        *failoverSettings        `json:"failover,omitempty"`
+	Fallback        json.RawMessage          `json:"fallback,omitempty"`
+	AllowedIPRanges []netip.Prefix           `json:"allowed_ip_ranges,omitempty"`
+	NameserverCheck *nameserverCheckSettings `json:"nameserver_check,omitempty"`
+	MissingRecord   string                   `json:"missing_record,omitempty"`
+	Transforms      []string                 `json:"transforms,omitempty"`
+	Period          string                   `json:"period,omitempty"`
+	// TTL is only parsed here to report a malformed value early,
+	// and each provider reads and validates it from its settings.
+	TTL *utils.TTL `json:"ttl,omitempty"`
 }
 
-// JSONProviders obtain the update settings from the JSON content,
-// first trying from the environment variable CONFIG and then from
-// the file config.json.
-func (r *Reader) JSONProviders(filePath string) (
-	providers []provider.Provider, warnings []string, err error) {
-	providers, warnings, err = r.getProvidersFromEnv(filePath)
-	if providers != nil || warnings != nil || err != nil {
-		return providers, warnings, err
+// Settings contains a provider and its record options.
+type Settings struct {
+	Provider provider.Provider
+	Options  records.Options
+}
+
+// JSONSettings obtain the update settings from the JSON content,
+// first trying from the environment variable CONFIG, then from the
+// RECORD_ prefixed environment variables and finally from the
+// configuration file, which can be in JSON, YAML or TOML depending
+// on its extension.
+func (r *Reader) JSONSettings(filePath string) (
+	settings []Settings, warnings []string, err error) {
+	settings, warnings, err = r.getSettingsFromEnv(filePath)
+	if settings != nil || warnings != nil || err != nil {
+		return settings, warnings, err
+	}
+	settings, warnings, err = r.getSettingsFromRecordEnv()
+	if settings != nil || warnings != nil || err != nil {
+		return settings, warnings, err
 	}
-	return r.getProvidersFromFile(filePath)
+	return r.getSettingsFromFile(filePath)
 }
 
 var errWriteConfigToFile = errors.New("cannot write configuration to file")
 
-// getProvidersFromFile obtain the update settings from config.json.
-func (r *Reader) getProvidersFromFile(filePath string) (
-	providers []provider.Provider, warnings []string, err error) {
-	r.logger.Info("reading JSON config from file " + filePath)
+// getSettingsFromFile obtain the update settings from the configuration
+// file, converting it to JSON first if it is a YAML or TOML file.
+func (r *Reader) getSettingsFromFile(filePath string) (
+	settings []Settings, warnings []string, err error) {
+	r.logger.Info("reading config from file " + filePath)
 	bytes, err := r.readFile(filePath)
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
@@ -63,13 +98,24 @@ func (r *Reader) getProvidersFromFile(filePath string) (
 	}
 	r.logger.Debug("config read: " + string(bytes))
 
+	switch {
+	case isYAML(filePath):
+		bytes, err = yamlToJSON(bytes)
+	case isTOML(filePath):
+		bytes, err = tomlToJSON(bytes)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
 	return extractAllSettings(bytes)
 }
 
-// getProvidersFromEnv obtain the update settings from the environment variable CONFIG.
-// If the settings are valid, they are written to the filePath.
-func (r *Reader) getProvidersFromEnv(filePath string) (
-	providers []provider.Provider, warnings []string, err error) {
+// getSettingsFromEnv obtain the update settings from the environment variable CONFIG.
+// If the settings are valid, they are written to the filePath,
+// converted to TOML if it is a TOML file.
+func (r *Reader) getSettingsFromEnv(filePath string) (
+	settings []Settings, warnings []string, err error) {
 	s := os.Getenv("CONFIG")
 	if s == "" {
 		return nil, nil, nil
@@ -79,23 +125,30 @@ func (r *Reader) getProvidersFromEnv(filePath string) (
 
 	b := []byte(s)
 
-	providers, warnings, err = extractAllSettings(b)
+	settings, warnings, err = extractAllSettings(b)
 	if err != nil {
-		return providers, warnings, fmt.Errorf("configuration given: %w", err)
+		return settings, warnings, fmt.Errorf("configuration given: %w", err)
 	}
 
 	buffer := bytes.NewBuffer(nil)
 	err = json.Indent(buffer, b, "", "  ")
 	if err != nil {
-		return providers, warnings, fmt.Errorf("%w: %w", errWriteConfigToFile, err)
+		return settings, warnings, fmt.Errorf("%w: %w", errWriteConfigToFile, err)
+	}
+	content := buffer.Bytes()
+	if isTOML(filePath) {
+		content, err = jsonToTOML(b)
+		if err != nil {
+			return settings, warnings, fmt.Errorf("%w: %w", errWriteConfigToFile, err)
+		}
 	}
 	const mode = fs.FileMode(0600)
-	err = r.writeFile(filePath, buffer.Bytes(), mode)
+	err = r.writeFile(filePath, content, mode)
 	if err != nil {
-		return providers, warnings, fmt.Errorf("%w: %w", errWriteConfigToFile, err)
+		return settings, warnings, fmt.Errorf("%w: %w", errWriteConfigToFile, err)
 	}
 
-	return providers, warnings, nil
+	return settings, warnings, nil
 }
 
 var (
@@ -104,17 +157,10 @@ var (
 )
 
 func extractAllSettings(jsonBytes []byte) (
-	allProviders []provider.Provider, warnings []string, err error) {
-	config := struct {
-		CommonSettings []commonSettings `json:"settings"`
-	}{}
+	allSettings []Settings, warnings []string, err error) {
 	rawConfig := struct {
 		Settings []json.RawMessage `json:"settings"`
 	}{}
-	err = json.Unmarshal(jsonBytes, &config)
-	if err != nil {
-		return nil, nil, fmt.Errorf("%w: %w", errUnmarshalCommon, err)
-	}
 	err = json.Unmarshal(jsonBytes, &rawConfig)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%w: %w", errUnmarshalRaw, err)
@@ -125,31 +171,92 @@ func extractAllSettings(jsonBytes []byte) (
 		return nil, nil, fmt.Errorf("getting retro-compatible global IPV6 suffix: %w", err)
 	}
 
-	for i, common := range config.CommonSettings {
-		newProvider, newWarnings, err := makeSettingsFromObject(common, rawConfig.Settings[i],
+	for _, rawSettings := range rawConfig.Settings {
+		rawSettings, err := resolveSecretFiles(rawSettings, secrets.ReadFile)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("record settings: %w", err)
+		}
+
+		rawSettings, newWarnings, err := migrateSettings(rawSettings)
+		warnings = append(warnings, newWarnings...)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("%w: %w", errUnmarshalCommon, err)
+		}
+
+		var common commonSettings
+		err = json.Unmarshal(rawSettings, &common)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("%w: %w", errUnmarshalCommon, err)
+		}
+
+		newSettings, newWarnings, err := makeSettingsFromObject(common, rawSettings,
 			retroIPv6Suffix)
 		warnings = append(warnings, newWarnings...)
 		if err != nil {
 			return nil, warnings, err
 		}
-		allProviders = append(allProviders, newProvider...)
+		allSettings = append(allSettings, newSettings...)
 	}
 
-	return allProviders, warnings, nil
+	return allSettings, warnings, nil
 }
 
+// dualStackIPVersion is the IP version value to update
+// both an A record and an AAAA record for each host.
+const dualStackIPVersion = "ipv4 and ipv6"
+
 var (
 	ErrProviderNoLongerSupported = errors.New("provider no longer supported")
+	ErrViewNotSupported          = errors.New("view is not supported by provider")
+	ErrStaticIPsNotSupported     = errors.New("static IP addresses are not supported by provider")
+	ErrIgnoreErrorEmpty          = errors.New("ignored error cannot be empty")
+	ErrAPIURLNotValid            = errors.New("API URL is not valid")
+	ErrAPIURLNotSupported        = errors.New("API URL is not supported by provider")
+	ErrMissingRecordNotValid     = errors.New("missing record policy is not valid")
+	ErrCreateNotSupported        = errors.New("creating missing records is not supported by provider")
+	ErrPeriodNotValid            = errors.New("period is not valid")
 )
 
 func makeSettingsFromObject(common commonSettings, rawSettings json.RawMessage,
 	retroGlobalIPv6Suffix netip.Prefix) (
-	providers []provider.Provider, warnings []string, err error) {
+	settings []Settings, warnings []string, err error) {
 	if common.Provider == "google" {
 		return nil, nil, fmt.Errorf("%w: %s", ErrProviderNoLongerSupported, common.Provider)
 	}
 
 	providerName := models.Provider(common.Provider)
+	capabilities, err := provider.Capabilities(providerName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if (common.View != "" || common.Line != "") && !capabilities.View {
+		return nil, nil, fmt.Errorf("%w: %s", ErrViewNotSupported, providerName)
+	}
+	if len(common.StaticIPs) > 0 && !capabilities.StaticIPs {
+		return nil, nil, fmt.Errorf("%w: %s", ErrStaticIPsNotSupported, providerName)
+	}
+	common.MissingRecord = strings.ToLower(common.MissingRecord)
+	switch common.MissingRecord {
+	case "", records.MissingRecordError, records.MissingRecordSkip:
+	case records.MissingRecordCreate:
+		if !capabilities.CreateMissing {
+			return nil, nil, fmt.Errorf("%w: %s", ErrCreateNotSupported, providerName)
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: %q must be one of %q, %q or %q",
+			ErrMissingRecordNotValid, common.MissingRecord, records.MissingRecordError,
+			records.MissingRecordSkip, records.MissingRecordCreate)
+	}
+	if slices.Contains(common.IgnoreErrors, "") {
+		// an empty substring would match and ignore every error
+		return nil, nil, ErrIgnoreErrorEmpty
+	}
+	if common.TTL != nil && !capabilities.TTL {
+		warnings = append(warnings,
+			fmt.Sprintf("ignoring ttl %d because it is not supported by provider %s",
+				*common.TTL, providerName))
+	}
+
 	if providerName == constants.DuckDNS { // only hosts, no domain
 		if common.Domain != "" { // retro compatibility
 			if common.Host == "" {
@@ -169,9 +276,17 @@ func makeSettingsFromObject(common commonSettings, rawSettings json.RawMessage,
 	if common.IPVersion == "" {
 		common.IPVersion = ipversion.IP4or6.String()
 	}
-	ipVersion, err := ipversion.Parse(common.IPVersion)
-	if err != nil {
-		return nil, nil, err
+	var ipVersions []ipversion.IPVersion
+	if strings.EqualFold(common.IPVersion, dualStackIPVersion) {
+		// One record is made per IP family, such that the A and the AAAA
+		// records are each created if missing and updated independently.
+		ipVersions = []ipversion.IPVersion{ipversion.IP4, ipversion.IP6}
+	} else {
+		ipVersion, err := ipversion.Parse(common.IPVersion)
+		if err != nil {
+			return nil, nil, err
+		}
+		ipVersions = []ipversion.IPVersion{ipVersion}
 	}
 
 	ipv6Suffix := common.IPv6Suffix
@@ -179,19 +294,127 @@ func makeSettingsFromObject(common commonSettings, rawSettings json.RawMessage,
 		ipv6Suffix = retroGlobalIPv6Suffix
 	}
 
-	if ipVersion == ipversion.IP4 && ipv6Suffix.IsValid() {
+	if len(ipVersions) == 1 && ipVersions[0] == ipversion.IP4 && ipv6Suffix.IsValid() {
 		warnings = append(warnings,
 			fmt.Sprintf("IPv6 suffix specified as %s but IP version is %s",
-				ipv6Suffix, ipVersion))
+				ipv6Suffix, ipVersions[0]))
 	}
 
-	providers = make([]provider.Provider, len(hosts))
-	for i, host := range hosts {
-		providers[i], err = provider.New(providerName, rawSettings, common.Domain,
-			host, ipVersion, ipv6Suffix)
+	options := records.Options{
+		SkipVerify:      common.SkipVerify,
+		ProviderName:    providerName,
+		Tags:            common.Tags,
+		IgnoreErrors:    common.IgnoreErrors,
+		ExtraHeaders:    common.ExtraHeaders,
+		AllowedIPRanges: common.AllowedIPRanges,
+		MissingRecord:   common.MissingRecord,
+	}
+	if common.APIURL != "" {
+		options.APIURL, err = parseAPIURL(common.APIURL, providerName)
 		if err != nil {
 			return nil, warnings, err
 		}
 	}
-	return providers, warnings, nil
+	if len(common.Transforms) > 0 {
+		options.Transforms = make([]transform.Transform, len(common.Transforms))
+		for i, spec := range common.Transforms {
+			options.Transforms[i], err = transform.Parse(spec)
+			if err != nil {
+				return nil, warnings, fmt.Errorf("transform %d: %w", i+1, err)
+			}
+		}
+	}
+	if common.Period != "" {
+		options.Period, err = time.ParseDuration(common.Period)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("%w: %w", ErrPeriodNotValid, err)
+		} else if options.Period <= 0 {
+			return nil, warnings, fmt.Errorf("%w: %s must be positive",
+				ErrPeriodNotValid, options.Period)
+		}
+	}
+	if common.NameserverCheck != nil {
+		options.NameserverCheck, err = makeNameserverCheckSettings(*common.NameserverCheck)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("nameserver check settings: %w", err)
+		}
+	}
+
+	settings = make([]Settings, 0, len(hosts)*len(ipVersions))
+	for _, host := range hosts {
+		for _, ipVersion := range ipVersions {
+			err = provider.CheckIPVersion(providerName, common.Domain, host, ipVersion)
+			if err != nil {
+				return nil, warnings, err
+			}
+			var hostProvider provider.Provider
+			hostProvider, err = provider.New(providerName, rawSettings, common.Domain,
+				host, ipVersion, ipv6Suffix)
+			if err != nil {
+				return nil, warnings, err
+			}
+			hostOptions := options
+			if common.Failover != nil {
+				hostOptions.Failover, err = makeFailoverSettings(*common.Failover, ipVersion)
+				if err != nil {
+					return nil, warnings, fmt.Errorf("failover settings: %w", err)
+				}
+			}
+			if common.Fallback != nil {
+				hostOptions.Fallback, err = makeFallback(common.Fallback, common.Domain,
+					host, ipVersion, ipv6Suffix)
+				if err != nil {
+					return nil, warnings, fmt.Errorf("fallback provider settings: %w", err)
+				}
+			}
+			settings = append(settings, Settings{
+				Provider: hostProvider,
+				Options:  hostOptions,
+			})
+		}
+	}
+	return settings, warnings, nil
+}
+
+// parseAPIURL parses the API URL given, which must be an
+// absolute http or https URL without query or fragment, for a
+// provider supporting it.
+func parseAPIURL(s string, providerName models.Provider) (apiURL *url.URL, err error) {
+	if slices.Contains(apiURLUnsupportedProviders(), providerName) {
+		return nil, fmt.Errorf("%w: %s", ErrAPIURLNotSupported, providerName)
+	}
+
+	apiURL, err = url.Parse(s)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("%w: %w", ErrAPIURLNotValid, err)
+	case apiURL.Scheme != "http" && apiURL.Scheme != "https":
+		return nil, fmt.Errorf("%w: scheme must be http or https: %s", ErrAPIURLNotValid, s)
+	case apiURL.Host == "":
+		return nil, fmt.Errorf("%w: host is empty: %s", ErrAPIURLNotValid, s)
+	case apiURL.RawQuery != "" || apiURL.Fragment != "":
+		return nil, fmt.Errorf("%w: query and fragment are not allowed: %s", ErrAPIURLNotValid, s)
+	}
+	return apiURL, nil
+}
+
+// apiURLUnsupportedProviders returns the providers for which the API URL
+// cannot be replaced in their requests. These providers either sign their
+// requests including the request host, which the API URL replacement
+// would invalidate, or send requests to several hosts such as a token
+// or instance metadata endpoint, or do not send HTTP requests at all.
+// Their regional endpoints are set with provider specific settings.
+func apiURLUnsupportedProviders() []models.Provider {
+	return []models.Provider{
+		constants.Aliyun,
+		constants.Azure,
+		constants.ClouDNS,
+		constants.DeSEC,
+		constants.Exec,
+		constants.GCP,
+		constants.OCI,
+		constants.OVH,
+		constants.RFC2136,
+		constants.Route53,
+	}
 }
@@ -0,0 +1,431 @@
+package params
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_makeSettingsFromObject_view(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		common     commonSettings
+		rawJSON    string
+		errWrapped error
+		errMessage string
+	}{
+		"view_supported": {
+			common: commonSettings{
+				Provider: "aliyun",
+				Domain:   "example.com",
+				Host:     "@",
+				View:     "telecom",
+			},
+			rawJSON: `{"access_key_id":"id","access_secret":"secret","view":"telecom"}`,
+		},
+		"line_alias_supported": {
+			common: commonSettings{
+				Provider: "dnspod",
+				Domain:   "example.com",
+				Host:     "@",
+				Line:     "电信",
+			},
+			rawJSON: `{"token":"token","line":"电信"}`,
+		},
+		"line_alias_not_supported": {
+			common: commonSettings{
+				Provider: "duckdns",
+				Host:     "host",
+				Line:     "internal",
+			},
+			rawJSON:    `{"token":"token","line":"internal"}`,
+			errWrapped: ErrViewNotSupported,
+			errMessage: "view is not supported by provider: duckdns",
+		},
+		"view_not_supported": {
+			common: commonSettings{
+				Provider: "duckdns",
+				Host:     "host",
+				View:     "internal",
+			},
+			rawJSON:    `{"token":"token","view":"internal"}`,
+			errWrapped: ErrViewNotSupported,
+			errMessage: "view is not supported by provider: duckdns",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := makeSettingsFromObject(testCase.common,
+				json.RawMessage(testCase.rawJSON), netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_makeSettingsFromObject_staticIPs(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		common     commonSettings
+		rawJSON    string
+		errWrapped error
+		errMessage string
+	}{
+		"static_ips_supported": {
+			common: commonSettings{
+				Provider:  "gandi",
+				Domain:    "example.com",
+				Host:      "@",
+				StaticIPs: []netip.Addr{netip.MustParseAddr("1.2.3.4")},
+			},
+			rawJSON: `{"personal_access_token":"token","static_ips":["1.2.3.4"]}`,
+		},
+		"static_ips_not_supported": {
+			common: commonSettings{
+				Provider:  "duckdns",
+				Host:      "host",
+				StaticIPs: []netip.Addr{netip.MustParseAddr("1.2.3.4")},
+			},
+			rawJSON:    `{"token":"token","static_ips":["1.2.3.4"]}`,
+			errWrapped: ErrStaticIPsNotSupported,
+			errMessage: "static IP addresses are not supported by provider: duckdns",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := makeSettingsFromObject(testCase.common,
+				json.RawMessage(testCase.rawJSON), netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_makeSettingsFromObject_dualStack(t *testing.T) {
+	t.Parallel()
+
+	common := commonSettings{
+		Provider:  "cloudflare",
+		Domain:    "example.com",
+		Host:      "@,www",
+		IPVersion: "IPv4 and IPv6",
+	}
+	rawJSON := `{"token":"token","zone_identifier":"zone","ttl":1}`
+
+	settings, _, err := makeSettingsFromObject(common,
+		json.RawMessage(rawJSON), netip.Prefix{})
+
+	require.NoError(t, err)
+	records := make([]string, len(settings))
+	for i, setting := range settings {
+		records[i] = setting.Provider.BuildDomainName() + " " +
+			setting.Provider.IPVersion().String()
+	}
+	expectedRecords := []string{
+		"example.com ipv4", "example.com ipv6",
+		"www.example.com ipv4", "www.example.com ipv6",
+	}
+	assert.Equal(t, expectedRecords, records)
+}
+
+func Test_makeSettingsFromObject_ipVersionNotSupported(t *testing.T) {
+	t.Parallel()
+
+	common := commonSettings{
+		Provider:  "namecheap",
+		Domain:    "example.com",
+		Host:      "@,www",
+		IPVersion: "IPv4 and IPv6",
+	}
+	rawJSON := `{"password":"0123456789abcdef0123456789abcdef"}`
+
+	settings, _, err := makeSettingsFromObject(common,
+		json.RawMessage(rawJSON), netip.Prefix{})
+
+	assert.Nil(t, settings)
+	assert.ErrorIs(t, err, provider.ErrIPVersionNotSupported)
+	assert.EqualError(t, err, "IP version not supported: "+
+		"namecheap does not support IPv6 for host example.com")
+}
+
+func Test_makeSettingsFromObject_apiURL(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		provider   string
+		apiURL     string
+		expected   string
+		errWrapped error
+		errMessage string
+	}{
+		"default": {},
+		"valid": {
+			apiURL:   "https://api.eu.example.com/base",
+			expected: "https://api.eu.example.com/base",
+		},
+		"bad_scheme": {
+			apiURL:     "ftp://api.example.com",
+			errWrapped: ErrAPIURLNotValid,
+			errMessage: "API URL is not valid: scheme must be http or https: ftp://api.example.com",
+		},
+		"no_host": {
+			apiURL:     "https:///v2",
+			errWrapped: ErrAPIURLNotValid,
+			errMessage: "API URL is not valid: host is empty: https:///v2",
+		},
+		"query": {
+			apiURL:     "http://localhost:8000/?key=value",
+			errWrapped: ErrAPIURLNotValid,
+			errMessage: "API URL is not valid: query and fragment are not allowed: " +
+				"http://localhost:8000/?key=value",
+		},
+		"signed_provider": {
+			provider:   "route53",
+			apiURL:     "https://route53.example.com",
+			errWrapped: ErrAPIURLNotSupported,
+			errMessage: "API URL is not supported by provider: route53",
+		},
+		"multi_host_provider": {
+			provider:   "desec",
+			apiURL:     "https://desec.example.com",
+			errWrapped: ErrAPIURLNotSupported,
+			errMessage: "API URL is not supported by provider: desec",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := testCase.provider
+			if provider == "" {
+				provider = "digitalocean"
+			}
+			common := commonSettings{
+				Provider: provider,
+				Domain:   "example.com",
+				Host:     "@",
+				APIURL:   testCase.apiURL,
+			}
+			rawJSON := `{"token":"token"}`
+
+			settings, _, err := makeSettingsFromObject(common,
+				json.RawMessage(rawJSON), netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.Len(t, settings, 1)
+			apiURL := settings[0].Options.APIURL
+			if testCase.expected == "" {
+				assert.Nil(t, apiURL)
+				return
+			}
+			require.NotNil(t, apiURL)
+			assert.Equal(t, testCase.expected, apiURL.String())
+		})
+	}
+}
+
+func Test_makeSettingsFromObject_missingRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		provider      string
+		missingRecord string
+		expected      string
+		errWrapped    error
+		errMessage    string
+	}{
+		"default": {
+			provider: "cloudflare",
+		},
+		"error": {
+			provider:      "cloudflare",
+			missingRecord: "error",
+			expected:      "error",
+		},
+		"skip": {
+			provider:      "namecheap",
+			missingRecord: "Skip",
+			expected:      "skip",
+		},
+		"create": {
+			provider:      "cloudflare",
+			missingRecord: "create",
+			expected:      "create",
+		},
+		"create_not_supported": {
+			provider:      "namecheap",
+			missingRecord: "create",
+			errWrapped:    ErrCreateNotSupported,
+			errMessage:    "creating missing records is not supported by provider: namecheap",
+		},
+		"invalid": {
+			provider:      "cloudflare",
+			missingRecord: "ignore",
+			errWrapped:    ErrMissingRecordNotValid,
+			errMessage: `missing record policy is not valid: "ignore" must be one of ` +
+				`"error", "skip" or "create"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			common := commonSettings{
+				Provider:      testCase.provider,
+				Domain:        "example.com",
+				Host:          "@",
+				IPVersion:     "ipv4",
+				MissingRecord: testCase.missingRecord,
+			}
+			rawJSON := `{"token":"token","zone_identifier":"zone","ttl":1,` +
+				`"password":"0123456789abcdef0123456789abcdef"}`
+
+			settings, _, err := makeSettingsFromObject(common,
+				json.RawMessage(rawJSON), netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.Len(t, settings, 1)
+			assert.Equal(t, testCase.expected, settings[0].Options.MissingRecord)
+		})
+	}
+}
+
+func Test_makeSettingsFromObject_transforms(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		transforms []string
+		expected   []string
+		errWrapped error
+		errMessage string
+	}{
+		"default": {},
+		"valid": {
+			transforms: []string{"strip_to_v4", "add_offset:1"},
+			expected:   []string{"strip_to_v4", "add_offset:1"},
+		},
+		"unknown": {
+			transforms: []string{"strip_to_v4", "to_upper"},
+			errWrapped: transform.ErrTransformUnknown,
+			errMessage: `transform 2: transform is unknown: "to_upper" must be one of ` +
+				"strip_to_v4, add_offset or nat",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			common := commonSettings{
+				Provider:   "digitalocean",
+				Domain:     "example.com",
+				Host:       "@",
+				Transforms: testCase.transforms,
+			}
+			rawJSON := `{"token":"token"}`
+
+			settings, _, err := makeSettingsFromObject(common,
+				json.RawMessage(rawJSON), netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.Len(t, settings, 1)
+			var specs []string
+			for _, recordTransform := range settings[0].Options.Transforms {
+				specs = append(specs, recordTransform.String())
+			}
+			assert.Equal(t, testCase.expected, specs)
+		})
+	}
+}
+
+func Test_makeSettingsFromObject_period(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		period     string
+		expected   time.Duration
+		errWrapped error
+		errMessage string
+	}{
+		"default": {},
+		"valid": {
+			period:   "2m",
+			expected: 2 * time.Minute,
+		},
+		"malformed": {
+			period:     "2",
+			errWrapped: ErrPeriodNotValid,
+			errMessage: `period is not valid: time: missing unit in duration "2"`,
+		},
+		"negative": {
+			period:     "-1h",
+			errWrapped: ErrPeriodNotValid,
+			errMessage: "period is not valid: -1h0m0s must be positive",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			common := commonSettings{
+				Provider: "digitalocean",
+				Domain:   "example.com",
+				Host:     "@",
+				Period:   testCase.period,
+			}
+			rawJSON := `{"token":"token"}`
+
+			settings, _, err := makeSettingsFromObject(common,
+				json.RawMessage(rawJSON), netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.Len(t, settings, 1)
+			assert.Equal(t, testCase.expected, settings[0].Options.Period)
+		})
+	}
+}
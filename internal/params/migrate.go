@@ -0,0 +1,83 @@
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+)
+
+// legacyKey is a record settings key of a previous configuration
+// format, migrated on startup such that users do not have to edit
+// their configuration when upgrading.
+type legacyKey struct {
+	// provider is the provider the key applies to,
+	// and is empty if it applies to all providers.
+	provider models.Provider
+	key      string
+	// newKey is the key replacing the legacy key, and
+	// is empty if the key is removed without replacement.
+	newKey string
+	// hint is the action to take for a removed key.
+	hint string
+}
+
+func legacyKeys() []legacyKey {
+	return []legacyKey{
+		{key: "ip_method", hint: "set PUBLICIP_HTTP_PROVIDERS instead"},
+		{key: "delay", hint: "set UPDATE_PERIOD instead"},
+		{provider: constants.DonDominio, key: "password", newKey: "key"},
+		{provider: constants.Dyn, key: "password", newKey: "client_key"},
+	}
+}
+
+// migrateSettings migrates the legacy keys of the JSON object of a
+// record settings to the current configuration format, and returns a
+// deprecation warning for each legacy key found. The value of a legacy
+// key is discarded if its new key is already set.
+func migrateSettings(rawSettings json.RawMessage) (
+	migrated json.RawMessage, warnings []string, err error) {
+	var object map[string]json.RawMessage
+	err = json.Unmarshal(rawSettings, &object)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var providerName models.Provider
+	_ = json.Unmarshal(object["provider"], &providerName) // validated later
+
+	for _, legacy := range legacyKeys() {
+		value, ok := object[legacy.key]
+		if !ok || (legacy.provider != "" && legacy.provider != providerName) {
+			continue
+		}
+		delete(object, legacy.key)
+
+		var warning string
+		switch _, newKeySet := object[legacy.newKey]; {
+		case legacy.newKey == "":
+			warning = fmt.Sprintf("%q is no longer supported and is ignored, %s",
+				legacy.key, legacy.hint)
+		case newKeySet:
+			warning = fmt.Sprintf("%q is deprecated and is ignored since %q is set",
+				legacy.key, legacy.newKey)
+		default:
+			object[legacy.newKey] = value
+			warning = fmt.Sprintf("%q is deprecated, please rename it to %q",
+				legacy.key, legacy.newKey)
+		}
+		warnings = append(warnings, fmt.Sprintf("%s record settings: %s",
+			providerName, warning))
+	}
+
+	if len(warnings) == 0 {
+		return rawSettings, nil, nil
+	}
+
+	migrated, err = json.Marshal(object)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding migrated settings: %w", err)
+	}
+	return migrated, warnings, nil
+}
@@ -0,0 +1,106 @@
+package params
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_migrateSettings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		rawSettings string
+		migrated    string
+		warnings    []string
+		errMessage  string
+	}{
+		"current_format": {
+			rawSettings: `{"provider":"dyn","username":"user","client_key":"key"}`,
+			migrated:    `{"provider":"dyn","username":"user","client_key":"key"}`,
+		},
+		"renamed_key": {
+			rawSettings: `{"provider":"dyn","username":"user","password":"key"}`,
+			migrated:    `{"client_key":"key","provider":"dyn","username":"user"}`,
+			warnings: []string{
+				`dyn record settings: "password" is deprecated, please rename it to "client_key"`,
+			},
+		},
+		"renamed_key_other_provider": {
+			rawSettings: `{"provider":"dynu","username":"user","password":"key"}`,
+			migrated:    `{"provider":"dynu","username":"user","password":"key"}`,
+		},
+		"renamed_key_and_new_key_set": {
+			rawSettings: `{"provider":"dondominio","password":"old","key":"new"}`,
+			migrated:    `{"key":"new","provider":"dondominio"}`,
+			warnings: []string{
+				`dondominio record settings: "password" is deprecated and is ignored since "key" is set`,
+			},
+		},
+		"removed_keys": {
+			rawSettings: `{"provider":"duckdns","ip_method":"cycle","delay":300}`,
+			migrated:    `{"provider":"duckdns"}`,
+			warnings: []string{
+				`duckdns record settings: "ip_method" is no longer supported ` +
+					`and is ignored, set PUBLICIP_HTTP_PROVIDERS instead`,
+				`duckdns record settings: "delay" is no longer supported ` +
+					`and is ignored, set UPDATE_PERIOD instead`,
+			},
+		},
+		"not_an_object": {
+			rawSettings: `[]`,
+			errMessage:  "json: cannot unmarshal array",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			migrated, warnings, err := migrateSettings(json.RawMessage(testCase.rawSettings))
+
+			if testCase.errMessage != "" {
+				assert.ErrorContains(t, err, testCase.errMessage)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.migrated, string(migrated))
+			assert.Equal(t, testCase.warnings, warnings)
+		})
+	}
+}
+
+func Test_extractAllSettings_legacyFormat(t *testing.T) {
+	t.Parallel()
+
+	jsonBytes := []byte(`{"settings": [
+		{
+			"provider": "dondominio",
+			"domain": "example.com",
+			"host": "@",
+			"name": "service",
+			"username": "user",
+			"password": "legacy-key",
+			"ip_method": "cycle",
+			"delay": 300
+		}
+	]}`)
+
+	settings, warnings, err := extractAllSettings(jsonBytes)
+
+	require.NoError(t, err)
+	require.Len(t, settings, 1)
+	assert.Equal(t, constants.DonDominio, settings[0].Options.ProviderName)
+	assert.Equal(t, "example.com", settings[0].Provider.Domain())
+	assert.Equal(t, []string{
+		`dondominio record settings: "ip_method" is no longer supported ` +
+			`and is ignored, set PUBLICIP_HTTP_PROVIDERS instead`,
+		`dondominio record settings: "delay" is no longer supported ` +
+			`and is ignored, set UPDATE_PERIOD instead`,
+		`dondominio record settings: "password" is deprecated, please rename it to "key"`,
+	}, warnings)
+}
@@ -0,0 +1,53 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/nscheck"
+)
+
+type nameserverCheckSettings struct {
+	Address string `json:"address"`
+	Timeout string `json:"timeout,omitempty"`
+}
+
+var (
+	ErrNameserverAddressNotSet   = errors.New("nameserver check address is not set")
+	ErrNameserverTimeoutNotValid = errors.New("nameserver check timeout is not valid")
+)
+
+func makeNameserverCheckSettings(settings nameserverCheckSettings) (
+	result *nscheck.Settings, err error) {
+	if settings.Address == "" {
+		return nil, fmt.Errorf("%w", ErrNameserverAddressNotSet)
+	}
+
+	address := settings.Address
+	_, _, err = net.SplitHostPort(address)
+	if err != nil {
+		const defaultPort = "53"
+		address = net.JoinHostPort(address, defaultPort)
+	}
+
+	const defaultTimeout = time.Minute
+	timeout := defaultTimeout
+	if settings.Timeout != "" {
+		timeout, err = time.ParseDuration(settings.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNameserverTimeoutNotValid, err)
+		} else if timeout <= 0 {
+			return nil, fmt.Errorf("%w: %s must be positive",
+				ErrNameserverTimeoutNotValid, timeout)
+		}
+	}
+
+	const interval = 2 * time.Second
+	return &nscheck.Settings{
+		Address:  address,
+		Timeout:  timeout,
+		Interval: interval,
+	}, nil
+}
@@ -0,0 +1,75 @@
+package params
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/nscheck"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_makeNameserverCheckSettings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings   nameserverCheckSettings
+		result     *nscheck.Settings
+		errWrapped error
+		errMessage string
+	}{
+		"address_not_set": {
+			errWrapped: ErrNameserverAddressNotSet,
+			errMessage: "nameserver check address is not set",
+		},
+		"defaults": {
+			settings: nameserverCheckSettings{Address: "ns1.example.com"},
+			result: &nscheck.Settings{
+				Address:  "ns1.example.com:53",
+				Timeout:  time.Minute,
+				Interval: 2 * time.Second,
+			},
+		},
+		"ipv6_address_with_port": {
+			settings: nameserverCheckSettings{
+				Address: "[2001:db8::1]:5353",
+				Timeout: "10s",
+			},
+			result: &nscheck.Settings{
+				Address:  "[2001:db8::1]:5353",
+				Timeout:  10 * time.Second,
+				Interval: 2 * time.Second,
+			},
+		},
+		"malformed_timeout": {
+			settings: nameserverCheckSettings{
+				Address: "ns1.example.com",
+				Timeout: "soon",
+			},
+			errWrapped: ErrNameserverTimeoutNotValid,
+			errMessage: `nameserver check timeout is not valid: time: invalid duration "soon"`,
+		},
+		"negative_timeout": {
+			settings: nameserverCheckSettings{
+				Address: "ns1.example.com",
+				Timeout: "-1s",
+			},
+			errWrapped: ErrNameserverTimeoutNotValid,
+			errMessage: "nameserver check timeout is not valid: -1s must be positive",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := makeNameserverCheckSettings(testCase.settings)
+
+			assert.Equal(t, testCase.result, result)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
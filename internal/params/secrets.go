@@ -0,0 +1,72 @@
+package params
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrSecretFileKeyConflict  = errors.New("key and its _file key cannot be both set")
+	ErrSecretFilePathNotValid = errors.New("secret file path is not valid")
+)
+
+// resolveSecretFiles replaces each key suffixed with _file of the record
+// settings, such as "token_file", with the key without the suffix set to
+// the content of the file at the path given, for example for Docker or
+// Kubernetes secrets. Nested objects such as the fallback settings are
+// resolved as well.
+func resolveSecretFiles(rawSettings json.RawMessage,
+	readFile func(path string) (value string, err error)) (
+	resolved json.RawMessage, err error) {
+	var object map[string]json.RawMessage
+	err = json.Unmarshal(rawSettings, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for key, value := range object {
+		if strings.HasPrefix(string(value), "{") {
+			resolvedValue, err := resolveSecretFiles(value, readFile)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			if !bytes.Equal(resolvedValue, value) {
+				object[key] = resolvedValue
+				changed = true
+			}
+			continue
+		}
+
+		secretKey, ok := strings.CutSuffix(key, "_file")
+		if !ok {
+			continue
+		} else if _, set := object[secretKey]; set {
+			return nil, fmt.Errorf("%w: %s and %s", ErrSecretFileKeyConflict, secretKey, key)
+		}
+
+		var path string
+		err = json.Unmarshal(value, &path)
+		if err != nil || path == "" {
+			return nil, fmt.Errorf("%w: %s: %s", ErrSecretFilePathNotValid, key, value)
+		}
+		secret, err := readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", key, err)
+		}
+		delete(object, key)
+		object[secretKey], err = json.Marshal(secret)
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", secretKey, err)
+		}
+		changed = true
+	}
+
+	if !changed {
+		return rawSettings, nil
+	}
+	return json.Marshal(object)
+}
@@ -0,0 +1,71 @@
+package params
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resolveSecretFiles(t *testing.T) {
+	t.Parallel()
+
+	readFile := func(path string) (string, error) {
+		if path == "/run/secrets/token" {
+			return "secret", nil
+		}
+		return "", os.ErrNotExist
+	}
+
+	testCases := map[string]struct {
+		rawSettings string
+		resolved    string
+		errWrapped  error
+		errMessage  string
+	}{
+		"no_secret_file": {
+			rawSettings: `{"provider":"duckdns","token":"token"}`,
+			resolved:    `{"provider":"duckdns","token":"token"}`,
+		},
+		"secret_file": {
+			rawSettings: `{"provider":"duckdns","token_file":"/run/secrets/token"}`,
+			resolved:    `{"provider":"duckdns","token":"secret"}`,
+		},
+		"nested_secret_file": {
+			rawSettings: `{"fallback":{"provider":"hetzner","token_file":"/run/secrets/token"}}`,
+			resolved:    `{"fallback":{"provider":"hetzner","token":"secret"}}`,
+		},
+		"key_conflict": {
+			rawSettings: `{"token":"token","token_file":"/run/secrets/token"}`,
+			errWrapped:  ErrSecretFileKeyConflict,
+			errMessage:  "key and its _file key cannot be both set: token and token_file",
+		},
+		"path_not_valid": {
+			rawSettings: `{"token_file":1}`,
+			errWrapped:  ErrSecretFilePathNotValid,
+			errMessage:  "secret file path is not valid: token_file: 1",
+		},
+		"file_not_found": {
+			rawSettings: `{"token_file":"/run/secrets/other"}`,
+			errWrapped:  os.ErrNotExist,
+			errMessage:  "reading token_file: file does not exist",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resolved, err := resolveSecretFiles(json.RawMessage(testCase.rawSettings), readFile)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.resolved, string(resolved))
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package params
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+func isTOML(filePath string) bool {
+	return strings.ToLower(filepath.Ext(filePath)) == ".toml"
+}
+
+var errTOMLNotValid = errors.New("TOML configuration is not valid")
+
+// tomlToJSON converts the TOML content given to JSON, such that TOML
+// configuration files are parsed the same way as JSON ones.
+func tomlToJSON(tomlBytes []byte) (jsonBytes []byte, err error) {
+	value := make(map[string]any)
+	err = toml.Unmarshal(tomlBytes, &value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errTOMLNotValid, err)
+	}
+
+	jsonBytes, err = json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errTOMLNotValid, err)
+	}
+	return jsonBytes, nil
+}
+
+// jsonToTOML converts the JSON content given to TOML, to write
+// configuration given as JSON to a TOML configuration file.
+func jsonToTOML(jsonBytes []byte) (tomlBytes []byte, err error) {
+	value := make(map[string]any)
+	err = json.Unmarshal(jsonBytes, &value)
+	if err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	err = toml.NewEncoder(buffer).Encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("encoding TOML: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+package params
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConfigFilepath_toml(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+
+	const perm = 0600
+	err := os.WriteFile(filepath.Join(dataDir, "config.toml"), nil, perm)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dataDir, "config.toml"), ConfigFilepath(dataDir))
+
+	err = os.WriteFile(filepath.Join(dataDir, "config.yaml"), nil, perm)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dataDir, "config.yaml"), ConfigFilepath(dataDir))
+}
+
+func Test_tomlToJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		toml       string
+		json       string
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {
+			json: `{}`,
+		},
+		"settings": {
+			toml: `# home records
+[[settings]]
+provider = "duckdns"
+domain = "example.duckdns.org"
+token = "token" # comment
+ttl = 300
+tags = ["home", "nas"]
+`,
+			json: `{"settings":[{"domain":"example.duckdns.org","provider":"duckdns",` +
+				`"tags":["home","nas"],"token":"token","ttl":300}]}`,
+		},
+		"malformed": {
+			toml:       "provider duckdns",
+			errWrapped: errTOMLNotValid,
+			errMessage: "TOML configuration is not valid: toml: line 1: expected '.' or '=', but got 'd' instead",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			jsonBytes, err := tomlToJSON([]byte(testCase.toml))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.json, string(jsonBytes))
+		})
+	}
+}
+
+func Test_jsonToTOML(t *testing.T) {
+	t.Parallel()
+
+	const jsonContent = `{"settings":[{"provider":"duckdns","domain":"example.duckdns.org",` +
+		`"ttl":300,"tags":["home","nas"]}]}`
+
+	tomlBytes, err := jsonToTOML([]byte(jsonContent))
+	require.NoError(t, err)
+
+	jsonBytes, err := tomlToJSON(tomlBytes)
+	require.NoError(t, err)
+	assert.JSONEq(t, jsonContent, string(jsonBytes))
+}
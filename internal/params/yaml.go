@@ -0,0 +1,55 @@
+package params
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFilepath returns the path of the configuration file in the data
+// directory given, which is config.yaml, config.yml or config.toml if one
+// of them exists, and config.json otherwise.
+func ConfigFilepath(dataDir string) (path string) {
+	for _, name := range [...]string{"config.yaml", "config.yml", "config.toml"} {
+		path = filepath.Join(dataDir, name)
+		_, err := os.Stat(path)
+		if err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dataDir, "config.json")
+}
+
+func isYAML(filePath string) bool {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+var errYAMLNotValid = errors.New("YAML configuration is not valid")
+
+// yamlToJSON converts the YAML content given to JSON, such that YAML
+// configuration files are parsed the same way as JSON ones.
+func yamlToJSON(yamlBytes []byte) (jsonBytes []byte, err error) {
+	var value any
+	err = yaml.Unmarshal(yamlBytes, &value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errYAMLNotValid, err)
+	} else if value == nil { // empty file
+		return []byte(`{}`), nil
+	}
+
+	jsonBytes, err = json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errYAMLNotValid, err)
+	}
+	return jsonBytes, nil
+}
@@ -0,0 +1,69 @@
+package params
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConfigFilepath(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	assert.Equal(t, filepath.Join(dataDir, "config.json"), ConfigFilepath(dataDir))
+
+	const perm = 0600
+	err := os.WriteFile(filepath.Join(dataDir, "config.yml"), nil, perm)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dataDir, "config.yml"), ConfigFilepath(dataDir))
+}
+
+func Test_yamlToJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		yaml       string
+		json       string
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {
+			json: `{}`,
+		},
+		"settings": {
+			yaml: `# home records
+settings:
+  - provider: duckdns
+    domain: example.duckdns.org
+    token: token # comment
+    ttl: 300
+    tags: [home, nas]
+`,
+			json: `{"settings":[{"domain":"example.duckdns.org","provider":"duckdns",` +
+				`"tags":["home","nas"],"token":"token","ttl":300}]}`,
+		},
+		"malformed": {
+			yaml:       "settings: [",
+			errWrapped: errYAMLNotValid,
+			errMessage: "YAML configuration is not valid: yaml: line 1: did not find expected node content",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			jsonBytes, err := yamlToJSON([]byte(testCase.yaml))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.json, string(jsonBytes))
+		})
+	}
+}
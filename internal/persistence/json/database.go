@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -125,19 +126,50 @@ func checkHistoryEvents(events []models.HistoryEvent) (err error) {
 	return nil
 }
 
+// write writes the data to a temporary file and renames it to the
+// database file, such that the database file is never left partially
+// written if the program is stopped or the disk is full during a write.
 func (db *Database) write() error {
+	file, err := os.CreateTemp(filepath.Dir(db.filepath), "."+filepath.Base(db.filepath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	tempPath := file.Name()
+
+	err = encodeData(file, db.data)
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err == nil && closeErr != nil {
+		err = fmt.Errorf("closing temporary file: %w", closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+
+	err = os.Rename(tempPath, db.filepath)
+	if err != nil {
+		_ = os.Remove(tempPath)
+		// The database file itself may be bind mounted in a container,
+		// in which case it cannot be replaced and is written in place.
+		return db.writeInPlace()
+	}
+	return nil
+}
+
+func (db *Database) writeInPlace() error {
 	const createPerms fs.FileMode = 0600
 	file, err := os.OpenFile(db.filepath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, createPerms)
 	if err != nil {
 		return fmt.Errorf("opening file: %w", err)
 	}
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	err = encoder.Encode(db.data)
+	err = encodeData(file, db.data)
 	if err != nil {
 		_ = file.Close()
-		return fmt.Errorf("encoding data to file: %w", err)
+		return err
 	}
 
 	err = file.Close()
@@ -146,3 +178,21 @@ func (db *Database) write() error {
 	}
 	return nil
 }
+
+func encodeData(w io.Writer, data dataModel) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(data)
+	if err != nil {
+		return fmt.Errorf("encoding data to file: %w", err)
+	}
+	return nil
+}
+
+// Flush writes the data held in memory to the database file.
+// It is used to retry persisting data after a write failure.
+func (db *Database) Flush() error {
+	db.Lock()
+	defer db.Unlock()
+	return db.write()
+}
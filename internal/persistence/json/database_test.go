@@ -0,0 +1,41 @@
+package json
+
+import (
+	"net/netip"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Database_write(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	db, err := NewDatabase(dataDir)
+	require.NoError(t, err)
+
+	ip := netip.MustParseAddr("1.2.3.4")
+	now := time.Unix(1000, 0).UTC()
+	err = db.StoreNewIP("example.com", "@", ip, now)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dataDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "updates.json", entries[0].Name())
+	info, err := entries[0].Info()
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	reopened, err := NewDatabase(dataDir)
+	require.NoError(t, err)
+	events, err := reopened.GetEvents("example.com", "@", ipversion.IP4or6)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, ip, events[0].IP)
+	assert.True(t, now.Equal(events[0].Time))
+}
@@ -0,0 +1,119 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+)
+
+// ReadSnapshot reads and decodes the snapshot file at the given path.
+func ReadSnapshot(path string) (snapshot models.Snapshot, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return snapshot, fmt.Errorf("opening file: %w", err)
+	}
+
+	decoder := json.NewDecoder(file)
+	err = decoder.Decode(&snapshot)
+	if err != nil {
+		_ = file.Close()
+		return snapshot, fmt.Errorf("decoding snapshot: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return snapshot, fmt.Errorf("closing file: %w", err)
+	}
+	return snapshot, nil
+}
+
+// Import restores the current IP addresses and managed record IDs
+// from the snapshot given. Information already in the database
+// takes precedence, so an IP address is only imported if it is
+// more recent than the last IP address stored for the record, and
+// managed record IDs are only imported if none are stored.
+// The database file is only written if data is imported.
+func (db *Database) Import(snapshot models.Snapshot) (err error) {
+	db.Lock()
+	defer db.Unlock()
+
+	changed := false
+	for _, snapshotRecord := range snapshot.Records {
+		switch {
+		case snapshotRecord.Domain == "":
+			return fmt.Errorf("%w: in snapshot", ErrDomainEmpty)
+		case snapshotRecord.Host == "":
+			return fmt.Errorf("%w: in snapshot for domain %s",
+				ErrHostIsEmpty, snapshotRecord.Domain)
+		}
+
+		targetIndex := -1
+		for i, record := range db.data.Records {
+			if record.Domain == snapshotRecord.Domain && record.Host == snapshotRecord.Host {
+				targetIndex = i
+				break
+			}
+		}
+
+		if targetIndex == -1 {
+			db.data.Records = append(db.data.Records, record{
+				Domain: snapshotRecord.Domain,
+				Host:   snapshotRecord.Host,
+			})
+			targetIndex = len(db.data.Records) - 1
+			changed = true
+		}
+		target := &db.data.Records[targetIndex]
+
+		if importEvent(target, snapshotRecord) {
+			changed = true
+		}
+
+		if len(target.ManagedIDs) == 0 && len(snapshotRecord.ManagedIDs) > 0 {
+			target.ManagedIDs = slices.Clone(snapshotRecord.ManagedIDs)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return db.write()
+}
+
+// importEvent appends the snapshot record IP address and time as a new
+// history event to the record, if it is valid, more recent than the last
+// event of the record and different from the last IP address of the same
+// IP version stored. It returns true if the event was appended.
+func importEvent(target *record, snapshotRecord models.SnapshotRecord) (imported bool) {
+	if !snapshotRecord.IP.IsValid() || snapshotRecord.Time.IsZero() {
+		return false
+	}
+
+	if len(target.Events) > 0 {
+		lastEvent := target.Events[len(target.Events)-1]
+		if !snapshotRecord.Time.After(lastEvent.Time) {
+			return false
+		}
+	}
+
+	for i := len(target.Events) - 1; i >= 0; i-- {
+		event := target.Events[i]
+		if event.IP.Is4() != snapshotRecord.IP.Is4() {
+			continue
+		}
+		if event.IP == snapshotRecord.IP {
+			return false
+		}
+		break
+	}
+
+	target.Events = append(target.Events, models.HistoryEvent{
+		IP:   snapshotRecord.IP,
+		Time: snapshotRecord.Time,
+	})
+	return true
+}
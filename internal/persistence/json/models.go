@@ -14,6 +14,9 @@ type record struct {
 	Domain string                `json:"domain"`
 	Host   string                `json:"host"`
 	Events []models.HistoryEvent `json:"ips"`
+	// ManagedIDs are the provider record IDs managed by the program,
+	// for providers supporting the managed records only safeguard.
+	ManagedIDs []string `json:"managed_ids,omitempty"`
 }
 
 func (r record) String() string {
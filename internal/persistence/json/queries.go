@@ -3,6 +3,7 @@ package json
 import (
 	"fmt"
 	"net/netip"
+	"slices"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/models"
@@ -39,6 +40,49 @@ func (db *Database) StoreNewIP(domain, host string, ip netip.Addr, t time.Time)
 	return db.write()
 }
 
+// StoreManagedRecordIDs stores the managed record IDs for a certain
+// domain and host. The database file is only written if the IDs changed.
+func (db *Database) StoreManagedRecordIDs(domain, host string, ids []string) (err error) {
+	db.Lock()
+	defer db.Unlock()
+
+	targetIndex := -1
+	for i, record := range db.data.Records {
+		if record.Domain == domain && record.Host == host {
+			targetIndex = i
+			break
+		}
+	}
+
+	if targetIndex == -1 {
+		if len(ids) == 0 {
+			return nil
+		}
+		db.data.Records = append(db.data.Records, record{
+			Domain: domain,
+			Host:   host,
+		})
+		targetIndex = len(db.data.Records) - 1
+	} else if slices.Equal(db.data.Records[targetIndex].ManagedIDs, ids) {
+		return nil
+	}
+
+	db.data.Records[targetIndex].ManagedIDs = slices.Clone(ids)
+	return db.write()
+}
+
+// GetManagedRecordIDs gets the managed record IDs for a certain domain and host.
+func (db *Database) GetManagedRecordIDs(domain, host string) (ids []string) {
+	db.RLock()
+	defer db.RUnlock()
+	for _, record := range db.data.Records {
+		if record.Domain == domain && record.Host == host {
+			return slices.Clone(record.ManagedIDs)
+		}
+	}
+	return nil
+}
+
 // GetEvents gets all the IP addresses history for a certain domain, host and
 // IP version, in the order from oldest to newest.
 func (db *Database) GetEvents(domain, host string,
@@ -73,3 +117,27 @@ func filterEvents(events []models.HistoryEvent, ipVersion ipversion.IPVersion) (
 	}
 	return filteredEvents
 }
+
+// PruneEvents removes the IP address history events older than the time
+// given, if it is not zero, and the oldest events beyond the maximum number
+// of events per record given, if it is not zero. The latest event of each
+// record is always kept since it is the current IP address of the record.
+// The database file is only written if events were removed.
+func (db *Database) PruneEvents(before time.Time, maxEvents uint) (pruned int, err error) {
+	db.Lock()
+	defer db.Unlock()
+
+	for i, record := range db.data.Records {
+		events, removed := models.History(record.Events).Prune(before, maxEvents)
+		if removed == 0 {
+			continue
+		}
+		pruned += removed
+		db.data.Records[i].Events = events
+	}
+
+	if pruned == 0 {
+		return 0, nil
+	}
+	return pruned, db.write()
+}
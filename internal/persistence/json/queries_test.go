@@ -0,0 +1,86 @@
+package json
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Database_PruneEvents(t *testing.T) {
+	t.Parallel()
+
+	day := func(n int64) time.Time { return time.Unix(n*24*60*60, 0).UTC() }
+	event := func(ip string, n int64) models.HistoryEvent {
+		return models.HistoryEvent{IP: netip.MustParseAddr(ip), Time: day(n)}
+	}
+
+	testCases := map[string]struct {
+		before    time.Time
+		maxEvents uint
+		pruned    int
+		a         []models.HistoryEvent
+		b         []models.HistoryEvent
+	}{
+		"disabled": {
+			a: []models.HistoryEvent{event("1.1.1.1", 1), event("1.1.1.2", 5), event("1.1.1.3", 9)},
+			b: []models.HistoryEvent{event("2.2.2.1", 2)},
+		},
+		"max_age": {
+			before: day(5),
+			pruned: 1,
+			a:      []models.HistoryEvent{event("1.1.1.2", 5), event("1.1.1.3", 9)},
+			// the latest event is kept even if it is too old
+			b: []models.HistoryEvent{event("2.2.2.1", 2)},
+		},
+		"max_events": {
+			maxEvents: 1,
+			pruned:    2,
+			a:         []models.HistoryEvent{event("1.1.1.3", 9)},
+			b:         []models.HistoryEvent{event("2.2.2.1", 2)},
+		},
+		"max_age_and_max_events": {
+			before:    day(2),
+			maxEvents: 2,
+			pruned:    1,
+			a:         []models.HistoryEvent{event("1.1.1.2", 5), event("1.1.1.3", 9)},
+			b:         []models.HistoryEvent{event("2.2.2.1", 2)},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dataDir := t.TempDir()
+			db, err := NewDatabase(dataDir)
+			require.NoError(t, err)
+			for _, e := range []models.HistoryEvent{event("1.1.1.1", 1), event("1.1.1.2", 5), event("1.1.1.3", 9)} {
+				err = db.StoreNewIP("a.com", "@", e.IP, e.Time)
+				require.NoError(t, err)
+			}
+			err = db.StoreNewIP("b.com", "@", netip.MustParseAddr("2.2.2.1"), day(2))
+			require.NoError(t, err)
+
+			pruned, err := db.PruneEvents(testCase.before, testCase.maxEvents)
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.pruned, pruned)
+
+			// Reopen the database to check the pruned events are written
+			db, err = NewDatabase(dataDir)
+			require.NoError(t, err)
+			events, err := db.GetEvents("a.com", "@", ipversion.IP4or6)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.a, events)
+			events, err = db.GetEvents("b.com", "@", ipversion.IP4or6)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.b, events)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"time"
+)
+
+type Settings struct {
+	// Port is the port to probe on the public IP address.
+	// It is zero to disable the probe.
+	Port uint16
+	// HTTPPath is the path to send an HTTP GET request to.
+	// It is empty to only attempt a TCP connection.
+	HTTPPath string
+	// Timeout is the maximum duration of a probe.
+	Timeout time.Duration
+}
+
+// New creates a new reachability prober.
+// If the port is zero, it acts as no-op implementation.
+func New(settings Settings) *Prober {
+	return &Prober{
+		settings: settings,
+		dialer:   &net.Dialer{},
+		client: &http.Client{
+			// Do not follow redirects, any HTTP response
+			// means the IP address is reachable.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+type Prober struct {
+	settings Settings
+	dialer   *net.Dialer
+	client   *http.Client
+}
+
+var ErrUnreachable = errors.New("IP address is not reachable")
+
+// Probe returns an error if the IP address given does not accept
+// a TCP connection on the configured port, or does not respond to
+// an HTTP GET request if an HTTP path is configured.
+func (p *Prober) Probe(ctx context.Context, ip netip.Addr) (err error) {
+	if p.settings.Port == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.settings.Timeout)
+	defer cancel()
+
+	address := netip.AddrPortFrom(ip, p.settings.Port).String()
+	if p.settings.HTTPPath == "" {
+		connection, err := p.dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrUnreachable, err)
+		}
+		_ = connection.Close()
+		return nil
+	}
+
+	url := "http://" + address + p.settings.HTTPPath
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+	_ = response.Body.Close()
+	return nil
+}
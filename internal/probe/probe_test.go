@@ -0,0 +1,76 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Prober_Probe(t *testing.T) {
+	t.Parallel()
+
+	reachableListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reachableListener.Close() })
+	reachablePort := netip.MustParseAddrPort(reachableListener.Addr().String()).Port()
+
+	// Find a free port and close its listener, such that
+	// connections to it are refused.
+	unreachableListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachablePort := netip.MustParseAddrPort(unreachableListener.Addr().String()).Port()
+	err = unreachableListener.Close()
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/health", r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+		}))
+	t.Cleanup(httpServer.Close)
+	httpPort := netip.MustParseAddrPort(httpServer.Listener.Addr().String()).Port()
+
+	testCases := map[string]struct {
+		settings   Settings
+		errWrapped error
+	}{
+		"disabled": {
+			settings: Settings{Port: 0},
+		},
+		"tcp_reachable": {
+			settings: Settings{Port: reachablePort},
+		},
+		"tcp_unreachable": {
+			settings:   Settings{Port: unreachablePort},
+			errWrapped: ErrUnreachable,
+		},
+		"http_reachable": {
+			settings: Settings{Port: httpPort, HTTPPath: "/health"},
+		},
+		"http_unreachable": {
+			settings:   Settings{Port: unreachablePort, HTTPPath: "/health"},
+			errWrapped: ErrUnreachable,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			testCase.settings.Timeout = time.Second
+			prober := New(testCase.settings)
+
+			err := prober.Probe(context.Background(), netip.MustParseAddr("127.0.0.1"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Package batch defines the interface implemented by providers able to
+// update several records with a single API call.
+package batch
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+)
+
+// Updater is optionally implemented by providers which can update
+// several records with a single API call, for example all the records
+// of the same zone and credentials.
+type Updater interface {
+	// BatchKey returns a key such that records with the same key can be
+	// updated in the same batch. It returns the empty string if the
+	// record cannot be updated in a batch.
+	BatchKey() string
+	// BatchUpdate updates the records of all the updaters given, which
+	// all have the same batch key as the receiver and usually include it,
+	// to the IP address given. It returns one result per updater, in the
+	// same order as the updaters given.
+	BatchUpdate(ctx context.Context, client *http.Client, ip netip.Addr,
+		updaters []Updater) (results []Result)
+}
+
+// Result is the result of a batch update for a single record.
+type Result struct {
+	// Created is true if the record was missing and got created.
+	Created bool
+	// Err is the error updating the record, and is nil on success.
+	Err error
+}
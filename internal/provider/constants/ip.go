@@ -1,6 +1,13 @@
 package constants
 
 const (
-	A    = "A"
-	AAAA = "AAAA"
+	A     = "A"
+	AAAA  = "AAAA"
+	CAA   = "CAA"
+	CNAME = "CNAME"
+	MX    = "MX"
+	PTR   = "PTR"
+	SRV   = "SRV"
+	TLSA  = "TLSA"
+	TXT   = "TXT"
 )
@@ -6,7 +6,9 @@ import "github.com/qdm12/ddns-updater/internal/models"
 const (
 	Aliyun       models.Provider = "aliyun"
 	AllInkl      models.Provider = "allinkl"
+	Azure        models.Provider = "azure"
 	Cloudflare   models.Provider = "cloudflare"
+	ClouDNS      models.Provider = "cloudns"
 	Custom       models.Provider = "custom"
 	Dd24         models.Provider = "dd24"
 	DdnssDe      models.Provider = "ddnss"
@@ -22,6 +24,7 @@ const (
 	DynV6        models.Provider = "dynv6"
 	EasyDNS      models.Provider = "easydns"
 	Example      models.Provider = "example"
+	Exec         models.Provider = "exec"
 	FreeDNS      models.Provider = "freedns"
 	Gandi        models.Provider = "gandi"
 	GCP          models.Provider = "gcp"
@@ -34,20 +37,27 @@ const (
 	Ionos        models.Provider = "ionos"
 	Linode       models.Provider = "linode"
 	LuaDNS       models.Provider = "luadns"
+	Mock         models.Provider = "mock"
 	Namecheap    models.Provider = "namecheap"
 	NameCom      models.Provider = "name.com"
 	Netcup       models.Provider = "netcup"
+	Netlify      models.Provider = "netlify"
 	Njalla       models.Provider = "njalla"
 	NoIP         models.Provider = "noip"
 	NowDNS       models.Provider = "nowdns"
+	OCI          models.Provider = "oci"
 	OpenDNS      models.Provider = "opendns"
 	OVH          models.Provider = "ovh"
 	Porkbun      models.Provider = "porkbun"
+	RFC2136      models.Provider = "rfc2136"
+	Route53      models.Provider = "route53"
+	Scaleway     models.Provider = "scaleway"
 	SelfhostDe   models.Provider = "selfhost.de"
 	Servercow    models.Provider = "servercow"
 	Spdyn        models.Provider = "spdyn"
 	Strato       models.Provider = "strato"
 	Variomedia   models.Provider = "variomedia"
+	Vultr        models.Provider = "vultr"
 	Zoneedit     models.Provider = "zoneedit"
 )
 
@@ -55,7 +65,9 @@ func ProviderChoices() []models.Provider {
 	return []models.Provider{
 		Aliyun,
 		AllInkl,
+		Azure,
 		Cloudflare,
+		ClouDNS,
 		Dd24,
 		DdnssDe,
 		DeSEC,
@@ -84,16 +96,22 @@ func ProviderChoices() []models.Provider {
 		LuaDNS,
 		Namecheap,
 		NameCom,
+		Netlify,
 		Njalla,
 		NoIP,
 		NowDNS,
+		OCI,
 		OpenDNS,
 		OVH,
 		Porkbun,
+		RFC2136,
+		Route53,
+		Scaleway,
 		SelfhostDe,
 		Spdyn,
 		Strato,
 		Variomedia,
+		Vultr,
 		Zoneedit,
 	}
 }
@@ -0,0 +1,33 @@
+package errors
+
+import "strconv"
+
+// HTTPStatusError is the error for an HTTP response with a status code
+// which is not valid, and wraps ErrHTTPStatusNotValid.
+type HTTPStatusError struct {
+	StatusCode int
+	// Details is usually the response body on a single line,
+	// and can be empty.
+	Details string
+}
+
+// NewHTTPStatusError returns an error for the HTTP response status code
+// and details given.
+func NewHTTPStatusError(statusCode int, details string) error {
+	return &HTTPStatusError{
+		StatusCode: statusCode,
+		Details:    details,
+	}
+}
+
+func (e *HTTPStatusError) Error() string {
+	message := ErrHTTPStatusNotValid.Error() + ": " + strconv.Itoa(e.StatusCode)
+	if e.Details != "" {
+		message += ": " + e.Details
+	}
+	return message
+}
+
+func (e *HTTPStatusError) Unwrap() error {
+	return ErrHTTPStatusNotValid
+}
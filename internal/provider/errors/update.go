@@ -9,6 +9,8 @@ var (
 	ErrBannedAbuse               = errors.New("banned due to abuse")
 	ErrBannedUserAgent           = errors.New("user agend is banned")
 	ErrConflictingRecord         = errors.New("conflicting record")
+	ErrDataReceivedMalformed     = errors.New("malformed record data received")
+	ErrDataReceivedMismatch      = errors.New("mismatching record data received")
 	ErrDNSServerSide             = errors.New("server side DNS error")
 	ErrDomainDisabled            = errors.New("record disabled")
 	ErrDomainIDNotFound          = errors.New("ID not found in domain record")
@@ -25,11 +27,14 @@ var (
 	ErrReceivedNoResult          = errors.New("received no result in response")
 	ErrRecordNotEditable         = errors.New("record is not editable")
 	ErrRecordNotFound            = errors.New("record not found")
+	ErrRecordNotManaged          = errors.New("record is not managed by this program")
 	ErrRecordResourceSetNotFound = errors.New("record resource set not found")
 	ErrResponseTooShort          = errors.New("response is too short")
+	ErrReverseNameNotInZone      = errors.New("reverse name is not in zone")
 	ErrResultsCountReceived      = errors.New("wrong number of results received")
 	ErrSessionIsEmpty            = errors.New("session received is empty")
 	ErrSystemParamNotValid       = errors.New("system parameter is not valid")
+	ErrTargetReceivedMismatch    = errors.New("mismatching target received")
 	ErrUnknownResponse           = errors.New("unknown response received")
 	ErrUnsuccessful              = errors.New("unsuccessful result")
 	ErrZoneNotFound              = errors.New("zone not found")
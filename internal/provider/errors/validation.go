@@ -8,32 +8,56 @@ var (
 	ErrAPIKeyNotSet           = errors.New("API key is not set")
 	ErrAPISecretNotSet        = errors.New("API secret is not set")
 	ErrAppKeyNotSet           = errors.New("app key is not set")
+	ErrAuthIDNotSet           = errors.New("auth ID is not set")
+	ErrClientIDNotSet         = errors.New("client ID is not set")
+	ErrCommandNotSet          = errors.New("command is not set")
+	ErrCAANotValid            = errors.New("CAA record data is not valid")
 	ErrConsumerKeyNotSet      = errors.New("consumer key is not set")
 	ErrCredentialsNotSet      = errors.New("credentials are not set")
 	ErrCustomerNumberNotSet   = errors.New("customer number is not set")
 	ErrDomainNotSet           = errors.New("domain is not set")
 	ErrEmailNotSet            = errors.New("email is not set")
 	ErrEmailNotValid          = errors.New("email address is not valid")
+	ErrFingerprintNotSet      = errors.New("fingerprint is not set")
 	ErrGCPProjectNotSet       = errors.New("GCP project is not set")
 	ErrDomainNotValid         = errors.New("domain is not valid")
 	ErrHostNotSet             = errors.New("host is not set")
 	ErrHostOnlySubdomain      = errors.New("host can only be a subdomain")
 	ErrHostWildcard           = errors.New(`host cannot be a "*"`)
+	ErrInputNotValid          = errors.New("input is not valid")
 	ErrIPv4KeyNotSet          = errors.New("IPv4 key is not set")
 	ErrIPv6KeyNotSet          = errors.New("IPv6 key is not set")
+	ErrKeyAlgorithmNotValid   = errors.New("key algorithm is not valid")
+	ErrKeyNameNotSet          = errors.New("key name is not set")
 	ErrKeyNotSet              = errors.New("key is not set")
 	ErrKeyNotValid            = errors.New("key is not valid")
+	ErrLineNotValid           = errors.New("line is not valid")
 	ErrNameNotSet             = errors.New("name is not set")
+	ErrNameserverNotSet       = errors.New("nameserver is not set")
 	ErrPasswordNotSet         = errors.New("password is not set")
 	ErrPasswordNotValid       = errors.New("password is not valid")
+	ErrRecordTypeNotValid     = errors.New("record type is not valid")
+	ErrRecordValueNotValid    = errors.New("record value is not valid")
+	ErrRegionNotSet           = errors.New("region is not set")
+	ErrResourceGroupNotSet    = errors.New("resource group is not set")
 	ErrSecretNotSet           = errors.New("secret is not set")
+	ErrSecretNotValid         = errors.New("secret is not valid")
+	ErrSubscriptionIDNotSet   = errors.New("subscription ID is not set")
 	ErrSuccessRegexNotSet     = errors.New("success regex is not set")
+	ErrTargetNotSet           = errors.New("target is not set")
+	ErrTargetNotValid         = errors.New("target is not valid")
+	ErrTenancyOCIDNotSet      = errors.New("tenancy OCID is not set")
+	ErrTenantIDNotSet         = errors.New("tenant ID is not set")
+	ErrTLSANotValid           = errors.New("TLSA record data is not valid")
 	ErrTokenNotSet            = errors.New("token is not set")
 	ErrTokenNotValid          = errors.New("token is not valid")
 	ErrTTLNotSet              = errors.New("TTL is not set")
+	ErrTTLNotValid            = errors.New("TTL is not valid")
+	ErrTTLTooHigh             = errors.New("TTL is too high")
 	ErrTTLTooLow              = errors.New("TTL is too low")
 	ErrURLNotHTTPS            = errors.New("url is not https")
 	ErrURLNotSet              = errors.New("url is not set")
+	ErrUserOCIDNotSet         = errors.New("user OCID is not set")
 	ErrUsernameNotSet         = errors.New("username is not set")
 	ErrUsernameNotValid       = errors.New("username is not valid")
 	ErrUserServiceKeyNotValid = errors.New("user service key is not valid")
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+var ErrIPVersionNotSupported = errors.New("IP version not supported")
+
+// CheckIPVersion returns an error if the provider does not support the
+// IP version given, such that unsupported combinations are reported for
+// each host when parsing the configuration. The IP version `ipv4 or ipv6`
+// is supported by providers supporting at least one of the two families.
+func CheckIPVersion(providerName models.Provider, domain, host string,
+	ipVersion ipversion.IPVersion) (err error) {
+	registration, ok := registry[providerName]
+	if !ok {
+		return nil
+	}
+	capabilities := registration.capabilities
+
+	var unsupported string
+	switch {
+	case ipVersion == ipversion.IP4 && !capabilities.IPv4:
+		unsupported = "IPv4"
+	case ipVersion == ipversion.IP6 && !capabilities.IPv6:
+		unsupported = "IPv6"
+	case ipVersion == ipversion.IP4or6 && !capabilities.IPv4 && !capabilities.IPv6:
+		unsupported = "IPv4 or IPv6"
+	default:
+		return nil
+	}
+	return fmt.Errorf("%w: %s does not support %s for host %s",
+		ErrIPVersionNotSupported, providerName, unsupported,
+		utils.BuildURLQueryHostname(host, domain))
+}
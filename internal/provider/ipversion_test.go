@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CheckIPVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		providerName models.Provider
+		host         string
+		ipVersion    ipversion.IPVersion
+		errWrapped   error
+		errMessage   string
+	}{
+		"dual_family_provider_ipv6": {
+			providerName: constants.Cloudflare,
+			host:         "@",
+			ipVersion:    ipversion.IP6,
+		},
+		"ipv4_only_provider_ipv4": {
+			providerName: constants.Namecheap,
+			host:         "@",
+			ipVersion:    ipversion.IP4,
+		},
+		"ipv4_only_provider_ipv4_or_ipv6": {
+			providerName: constants.Namecheap,
+			host:         "www",
+			ipVersion:    ipversion.IP4or6,
+		},
+		"ipv4_only_provider_ipv6": {
+			providerName: constants.Namecheap,
+			host:         "www",
+			ipVersion:    ipversion.IP6,
+			errWrapped:   ErrIPVersionNotSupported,
+			errMessage: "IP version not supported: " +
+				"namecheap does not support IPv6 for host www.example.com",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := CheckIPVersion(testCase.providerName, "example.com",
+				testCase.host, testCase.ipVersion)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testProvider struct {
+	Provider
+	proxied bool
+}
+
+func (p *testProvider) Proxied() bool { return p.proxied }
+
+type testLookupSkipper struct {
+	testProvider
+	skipLookup bool
+}
+
+func (p *testLookupSkipper) SkipLookup() bool { return p.skipLookup }
+
+func Test_SkipLookup(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		provider   Provider
+		skipLookup bool
+	}{
+		"not_proxied": {
+			provider: &testProvider{},
+		},
+		"proxied": {
+			provider:   &testProvider{proxied: true},
+			skipLookup: true,
+		},
+		"lookup_skipper_not_skipping": {
+			provider: &testLookupSkipper{},
+		},
+		"lookup_skipper_skipping": {
+			provider:   &testLookupSkipper{skipLookup: true},
+			skipLookup: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			skipLookup := SkipLookup(testCase.provider)
+
+			assert.Equal(t, testCase.skipLookup, skipLookup)
+		})
+	}
+}
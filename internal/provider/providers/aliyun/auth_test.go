@@ -0,0 +1,29 @@
+package aliyun
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sign(t *testing.T) {
+	t.Parallel()
+
+	provider := Provider{
+		accessKeyID: "testid",
+		timeNow: func() time.Time {
+			return time.Date(2016, time.February, 23, 12, 46, 24, 0, time.UTC)
+		},
+	}
+	values := provider.newURLValues()
+	values.Set("SignatureNonce", "3ee8c1b8-83d3-44af-a94f-4e0ad82fd6cf")
+	values.Set("Action", "DescribeDomainRecords")
+	values.Set("DomainName", "example.com")
+
+	sign(http.MethodGet, values, "testsecret")
+
+	assert.Equal(t, "2016-02-23T12:46:24Z", values.Get("Timestamp"))
+	assert.Equal(t, "gJWgpyG3jEOKSdvVhn8+L0gQXZU=", values.Get("Signature"))
+}
@@ -24,12 +24,15 @@ func (p *Provider) createRecord(ctx context.Context,
 		Scheme: "https",
 		Host:   "alidns.aliyuncs.com",
 	}
-	values := newURLValues(p.accessKeyID)
+	values := p.newURLValues()
 	values.Set("Action", "AddDomainRecord")
 	values.Set("DomainName", p.domain)
 	values.Set("RR", p.host)
 	values.Set("Type", recordType)
 	values.Set("Value", ip.String())
+	if p.view != "" {
+		values.Set("Line", p.view)
+	}
 
 	sign(http.MethodGet, values, p.accessSecret)
 
@@ -48,9 +51,7 @@ func (p *Provider) createRecord(ctx context.Context,
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode,
-			utils.BodyToSingleLine(response.Body))
+		return "", errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
 	}
 
 	var data struct {
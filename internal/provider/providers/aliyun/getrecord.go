@@ -18,11 +18,14 @@ func (p *Provider) getRecordID(ctx context.Context, client *http.Client,
 		Scheme: "https",
 		Host:   "dns.aliyuncs.com",
 	}
-	values := newURLValues(p.accessKeyID)
+	values := p.newURLValues()
 	values.Set("Action", "DescribeDomainRecords")
 	values.Set("DomainName", p.domain)
 	values.Set("RRKeyWord", p.host)
 	values.Set("Type", recordType)
+	if p.view != "" {
+		values.Set("Line", p.view)
+	}
 
 	sign(http.MethodGet, values, p.accessSecret)
 
@@ -55,16 +58,12 @@ func (p *Provider) getRecordID(ctx context.Context, client *http.Client,
 		}
 		err = json.Unmarshal(bodyBytes, &data)
 		if err != nil || data.Code != "InvalidDomainName.NoExist" {
-			return "", fmt.Errorf("%w: %d: %s",
-				errors.ErrHTTPStatusNotValid, response.StatusCode,
-				utils.BodyToSingleLine(response.Body))
+			return "", errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
 		}
 
 		return "", fmt.Errorf("%w", errors.ErrRecordNotFound)
 	default:
-		return "", fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode,
-			utils.BodyToSingleLine(response.Body))
+		return "", errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
 	}
 
 	decoder := json.NewDecoder(response.Body)
@@ -0,0 +1,67 @@
+package aliyun
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Provider_getRecordID_view(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		view string
+		line string
+	}{
+		"no_view": {},
+		"view": {
+			view: "telecom",
+			line: "telecom",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, testCase.line, r.URL.Query().Get("Line"))
+					const body = `{"DomainRecords":{"Record":[{"RecordId":"123"}]}}`
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}, nil
+				}),
+			}
+
+			provider := Provider{
+				domain:       "example.com",
+				host:         "@",
+				accessKeyID:  "id",
+				accessSecret: "secret",
+				view:         testCase.view,
+				timeNow:      time.Now,
+			}
+
+			recordID, err := provider.getRecordID(context.Background(), client, constants.A)
+
+			require.NoError(t, err)
+			assert.Equal(t, "123", recordID)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/netip"
+	"time"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
@@ -23,6 +24,8 @@ type Provider struct {
 	accessKeyID  string
 	accessSecret string
 	region       string
+	view         string
+	timeNow      func() time.Time
 }
 
 func New(data json.RawMessage, domain, host string,
@@ -32,6 +35,8 @@ func New(data json.RawMessage, domain, host string,
 		AccessKeyID  string `json:"access_key_id"`
 		AccessSecret string `json:"access_secret"`
 		Region       string `json:"region"`
+		View         string `json:"view"`
+		Line         string `json:"line"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
@@ -45,6 +50,11 @@ func New(data json.RawMessage, domain, host string,
 		accessKeyID:  extraSettings.AccessKeyID,
 		accessSecret: extraSettings.AccessSecret,
 		region:       "cn-hangzhou",
+		view:         extraSettings.View,
+		timeNow:      time.Now,
+	}
+	if p.view == "" {
+		p.view = extraSettings.Line
 	}
 	if extraSettings.Region != "" {
 		p.region = extraSettings.Region
@@ -90,6 +100,20 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{
+		IPv4:          true,
+		IPv6:          true,
+		CreateMissing: true,
+		View:          true,
+	}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -112,10 +136,14 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 
 	recordID, err := p.getRecordID(ctx, client, recordType)
 	if stderrors.Is(err, errors.ErrRecordNotFound) {
+		if !utils.CreationAllowed(ctx) {
+			return netip.Addr{}, err
+		}
 		recordID, err = p.createRecord(ctx, client, ip)
 		if err != nil {
 			return newIP, fmt.Errorf("creating record: %w", err)
 		}
+		utils.SignalCreated(ctx)
 	} else if err != nil {
 		return newIP, fmt.Errorf("getting record id: %w", err)
 	}
@@ -23,12 +23,15 @@ func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
 		Scheme: "https",
 		Host:   "alidns.aliyuncs.com",
 	}
-	values := newURLValues(p.accessKeyID)
+	values := p.newURLValues()
 	values.Set("Action", "UpdateDomainRecord")
 	values.Set("RecordId", recordID)
 	values.Set("RR", p.host)
 	values.Set("Type", recordType)
 	values.Set("Value", ip.String())
+	if p.view != "" {
+		values.Set("Line", p.view)
+	}
 
 	sign(http.MethodGet, values, p.accessSecret)
 
@@ -49,9 +52,7 @@ func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
 	switch response.StatusCode {
 	case http.StatusOK:
 	default:
-		return fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode,
-			utils.BodyToSingleLine(response.Body))
+		return errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
 	}
 
 	return nil
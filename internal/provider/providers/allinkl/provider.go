@@ -93,6 +93,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -144,8 +153,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	s := string(b)
 
 	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
-			response.StatusCode, utils.ToSingleLine(s))
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.ToSingleLine(s))
 	}
 
 	switch s {
@@ -0,0 +1,159 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain         string
+	host           string
+	ipVersion      ipversion.IPVersion
+	ipv6Suffix     netip.Prefix
+	tenantID       string
+	clientID       string
+	clientSecret   string
+	subscriptionID string
+	resourceGroup  string
+	ttl            uint32
+	// token is the access token last obtained, and tokenExpiry is
+	// its expiry time, both protected by the tokenMutex.
+	token       string
+	tokenExpiry time.Time
+	tokenMutex  sync.Mutex
+	// loginURL and managementURL are the URLs of the Microsoft
+	// identity platform and of the Azure Resource Manager API.
+	loginURL      string
+	managementURL string
+	timeNow       func() time.Time
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	p *Provider, err error) {
+	extraSettings := struct {
+		TenantID       string    `json:"tenant_id"`
+		ClientID       string    `json:"client_id"`
+		ClientSecret   string    `json:"client_secret"`
+		SubscriptionID string    `json:"subscription_id"`
+		ResourceGroup  string    `json:"resource_group"`
+		TTL            utils.TTL `json:"ttl"`
+	}{}
+	err = json.Unmarshal(data, &extraSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	const defaultTTL = 300
+	if extraSettings.TTL == 0 {
+		extraSettings.TTL = defaultTTL
+	}
+
+	p = &Provider{
+		domain:         domain,
+		host:           host,
+		ipVersion:      ipVersion,
+		ipv6Suffix:     ipv6Suffix,
+		tenantID:       extraSettings.TenantID,
+		clientID:       extraSettings.ClientID,
+		clientSecret:   extraSettings.ClientSecret,
+		subscriptionID: extraSettings.SubscriptionID,
+		resourceGroup:  extraSettings.ResourceGroup,
+		ttl:            uint32(extraSettings.TTL),
+		loginURL:       "https://login.microsoftonline.com",
+		managementURL:  "https://management.azure.com",
+		timeNow:        time.Now,
+	}
+	err = p.isValid()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	const minTTL, maxTTL = 1, 2147483647
+	switch {
+	case p.tenantID == "":
+		return fmt.Errorf("%w", errors.ErrTenantIDNotSet)
+	case p.clientID == "":
+		return fmt.Errorf("%w", errors.ErrClientIDNotSet)
+	case p.clientSecret == "":
+		return fmt.Errorf("%w", errors.ErrSecretNotSet)
+	case p.subscriptionID == "":
+		return fmt.Errorf("%w", errors.ErrSubscriptionIDNotSet)
+	case p.resourceGroup == "":
+		return fmt.Errorf("%w", errors.ErrResourceGroupNotSet)
+	}
+	return utils.CheckTTL(utils.TTL(p.ttl), minTTL, maxTTL)
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Azure, p.ipVersion)
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) IPv6Suffix() netip.Prefix {
+	return p.ipv6Suffix
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true, TTL: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName()),
+		Host:      p.Host(),
+		Provider:  "<a href=\"https://azure.microsoft.com/products/dns\">Azure DNS</a>",
+		IPVersion: p.ipVersion.String(),
+	}
+}
+
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	token, err := p.getToken(ctx, client)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting access token: %w", err)
+	}
+
+	newIP, err = p.putRecordSet(ctx, client, token, ip)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("putting record set: %w", err)
+	}
+	return newIP, nil
+}
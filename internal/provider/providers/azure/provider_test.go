@@ -0,0 +1,119 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func newResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	const settings = `{"tenant_id":"tenant","client_id":"client","client_secret":"secret",` +
+		`"subscription_id":"sub","resource_group":"group"}`
+	const recordSetURL = "https://management.azure.com/subscriptions/sub/resourceGroups/group/" +
+		"providers/Microsoft.Network/dnsZones/example.com/A/home?api-version=2018-05-01"
+
+	testCases := map[string]struct {
+		tokenStatus int
+		putStatus   int
+		putBody     string
+		newIP       netip.Addr
+		errWrapped  error
+		errMessage  string
+	}{
+		"success": {
+			tokenStatus: http.StatusOK,
+			putStatus:   http.StatusOK,
+			putBody:     `{"name":"home","properties":{"TTL":300,"ARecords":[{"ipv4Address":"1.2.3.4"}]}}`,
+			newIP:       netip.MustParseAddr("1.2.3.4"),
+		},
+		"bad_credentials": {
+			tokenStatus: http.StatusUnauthorized,
+			errWrapped:  errors.ErrAuth,
+			errMessage:  "getting access token: bad authentication: invalid_client",
+		},
+		"zone_not_found": {
+			tokenStatus: http.StatusOK,
+			putStatus:   http.StatusNotFound,
+			putBody:     `{"error":{"code":"ParentResourceNotFound"}}`,
+			errWrapped:  errors.ErrZoneNotFound,
+			errMessage:  `putting record set: zone not found: {"error":{"code":"ParentResourceNotFound"}}`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(settings), "example.com", "home",
+				ipversion.IP4, netip.Prefix{})
+			require.NoError(t, err)
+			provider.timeNow = func() time.Time { return time.Unix(0, 0) }
+
+			tokenRequests := 0
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					if r.URL.String() == "https://login.microsoftonline.com/tenant/oauth2/v2.0/token" {
+						tokenRequests++
+						require.NoError(t, r.ParseForm())
+						assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+						assert.Equal(t, "https://management.azure.com/.default", r.PostForm.Get("scope"))
+						if testCase.tokenStatus != http.StatusOK {
+							return newResponse(testCase.tokenStatus, `invalid_client`), nil
+						}
+						return newResponse(http.StatusOK, `{"access_token":"token","expires_in":3599}`), nil
+					}
+
+					assert.Equal(t, http.MethodPut, r.Method)
+					assert.Equal(t, recordSetURL, r.URL.String())
+					assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+					body, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					assert.Equal(t, `{"properties":{"TTL":300,"ARecords":[{"ipv4Address":"1.2.3.4"}]}}`,
+						string(body))
+					return newResponse(testCase.putStatus, testCase.putBody), nil
+				}),
+			}
+
+			ip := netip.MustParseAddr("1.2.3.4")
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.newIP, newIP)
+
+			// The access token is reused until it expires.
+			_, err = provider.Update(context.Background(), client, ip)
+			require.NoError(t, err)
+			assert.Equal(t, 1, tokenRequests)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// getToken returns the access token of the service principal for the
+// Azure Resource Manager API, cached until shortly before it expires.
+// See https://learn.microsoft.com/en-us/entra/identity-platform/v2-oauth2-client-creds-grant-flow
+func (p *Provider) getToken(ctx context.Context, client *http.Client) (token string, err error) {
+	p.tokenMutex.Lock()
+	defer p.tokenMutex.Unlock()
+
+	const expiryMargin = 5 * time.Minute
+	if p.token != "" && p.timeNow().Add(expiryMargin).Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	values := url.Values{}
+	values.Set("grant_type", "client_credentials")
+	values.Set("client_id", p.clientID)
+	values.Set("client_secret", p.clientSecret)
+	values.Set("scope", p.managementURL+"/.default")
+	tokenURL := p.loginURL + "/" + url.PathEscape(p.tenantID) + "/oauth2/v2.0/token"
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL,
+		strings.NewReader(values.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("creating http request: %w", err)
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/x-www-form-urlencoded")
+	headers.SetAccept(request, "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusUnauthorized:
+		return "", fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	default:
+		return "", errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	var data struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   uint   `json:"expires_in"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&data)
+	if err != nil {
+		return "", fmt.Errorf("json decoding response body: %w", err)
+	} else if data.AccessToken == "" {
+		return "", fmt.Errorf("%w", errors.ErrReceivedNoResult)
+	}
+
+	p.token = data.AccessToken
+	p.tokenExpiry = p.timeNow().Add(time.Duration(data.ExpiresIn) * time.Second)
+	return p.token, nil
+}
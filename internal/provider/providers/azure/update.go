@@ -0,0 +1,107 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+type recordSetProperties struct {
+	TTL         uint32       `json:"TTL"`
+	ARecords    []aRecord    `json:"ARecords,omitempty"`
+	AAAARecords []aaaaRecord `json:"AAAARecords,omitempty"`
+}
+
+type aRecord struct {
+	IPv4Address netip.Addr `json:"ipv4Address"`
+}
+
+type aaaaRecord struct {
+	IPv6Address netip.Addr `json:"ipv6Address"`
+}
+
+// putRecordSet creates or replaces the record set of the host
+// with a single record with the IP address given.
+// See https://learn.microsoft.com/en-us/rest/api/dns/record-sets/create-or-update
+func (p *Provider) putRecordSet(ctx context.Context, client *http.Client,
+	token string, ip netip.Addr) (newIP netip.Addr, err error) {
+	recordType := constants.A
+	properties := recordSetProperties{TTL: p.ttl}
+	if ip.Is6() {
+		recordType = constants.AAAA
+		properties.AAAARecords = []aaaaRecord{{IPv6Address: ip}}
+	} else {
+		properties.ARecords = []aRecord{{IPv4Address: ip}}
+	}
+
+	u, err := url.Parse(p.managementURL)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("parsing management URL: %w", err)
+	}
+	u = u.JoinPath("subscriptions", p.subscriptionID, "resourceGroups", p.resourceGroup,
+		"providers/Microsoft.Network/dnsZones", p.domain, recordType, p.host)
+	u.RawQuery = url.Values{"api-version": []string{"2018-05-01"}}.Encode()
+
+	requestBody, err := json.Marshal(struct {
+		Properties recordSetProperties `json:"properties"`
+	}{Properties: properties})
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(requestBody))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	headers.SetAuthBearer(request, token)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrZoneNotFound, utils.BodyToSingleLine(response.Body))
+	default:
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	var data struct {
+		Properties recordSetProperties `json:"properties"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&data)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	switch {
+	case ip.Is6() && len(data.Properties.AAAARecords) == 1:
+		newIP = data.Properties.AAAARecords[0].IPv6Address
+	case ip.Is4() && len(data.Properties.ARecords) == 1:
+		newIP = data.Properties.ARecords[0].IPv4Address
+	default:
+		return netip.Addr{}, fmt.Errorf("%w", errors.ErrReceivedNoIP)
+	}
+	if newIP.Compare(ip) != 0 {
+		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
+			errors.ErrIPReceivedMismatch, ip, newIP)
+	}
+	return newIP, nil
+}
@@ -0,0 +1,300 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/batch"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// BatchKey returns the zone identifier and credentials of the record,
+// since records of the same zone and credentials can be updated with
+// a single batch request. It returns the empty string if the zone
+// identifier is not set, since it then has to be looked up per record,
+// or if a record type is set, since only A and AAAA records are batched.
+func (p *Provider) BatchKey() string {
+	if p.zoneIdentifier == "" || p.recordType != "" {
+		return ""
+	}
+	return strings.Join([]string{p.zoneIdentifier, p.token,
+		p.userServiceKey, p.email, p.key}, "\x00")
+}
+
+// BatchUpdate updates the records of the updaters given using a single
+// list request and a single batch request, instead of one list request and
+// one update or create request per record. Missing records are created and
+// records already up to date are left untouched.
+// See https://developers.cloudflare.com/api/resources/dns/subresources/records/methods/batch/
+func (p *Provider) BatchUpdate(ctx context.Context, client *http.Client, ip netip.Addr,
+	updaters []batch.Updater) (results []batch.Result) {
+	results = make([]batch.Result, len(updaters))
+	setAllErrors := func(err error) []batch.Result {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = err
+			}
+		}
+		return results
+	}
+
+	providers := make([]*Provider, len(updaters))
+	for i, updater := range updaters {
+		provider, ok := updater.(*Provider)
+		if !ok {
+			return setAllErrors(fmt.Errorf("%w: batch updater %T is not a Cloudflare provider",
+				errors.ErrUnsuccessful, updater))
+		}
+		providers[i] = provider
+	}
+
+	existing, err := p.listRecords(ctx, client, ip)
+	if err != nil {
+		return setAllErrors(fmt.Errorf("listing records: %w", err))
+	}
+
+	var requestData batchRequest
+	patchIndexes := make([]int, 0, len(providers))
+	postIndexes := make([]int, 0, len(providers))
+	for i, provider := range providers {
+		name := utils.BuildURLQueryHostname(provider.host, provider.domain)
+		records := existing[utils.NormalizeRecordName(name, false)]
+		if len(records) == 0 {
+			if !utils.CreationAllowed(ctx) {
+				results[i].Err = fmt.Errorf("%w", errors.ErrRecordNotFound)
+				continue
+			}
+			requestData.Posts = append(requestData.Posts, recordData{
+				Type:    recordTypeFromIP(ip),
+				Name:    name,
+				Content: ip.String(),
+				Proxied: provider.proxied,
+				TTL:     provider.ttl,
+			})
+			postIndexes = append(postIndexes, i)
+			continue
+		} else if len(records) > 1 {
+			results[i].Err = fmt.Errorf("%w: %d instead of 1",
+				errors.ErrResultsCountReceived, len(records))
+			continue
+		}
+
+		record := records[0]
+		err = provider.checkManaged(record.ID)
+		if err != nil {
+			results[i].Err = err
+			continue
+		} else if record.Content == ip.String() && record.Proxied == provider.proxied {
+			continue // already up to date
+		}
+		requestData.Patches = append(requestData.Patches, batchPatch{
+			ID:         record.ID,
+			recordData: recordData{Content: ip.String(), Proxied: provider.proxied, TTL: provider.ttl},
+		})
+		patchIndexes = append(patchIndexes, i)
+	}
+
+	if len(requestData.Patches) == 0 && len(requestData.Posts) == 0 {
+		return results
+	}
+
+	response, err := p.sendBatch(ctx, client, requestData)
+	if err != nil {
+		for _, i := range append(patchIndexes, postIndexes...) {
+			results[i].Err = err
+		}
+		return results
+	}
+
+	// Records are checked individually, such that a mismatching
+	// record does not fail the other records of the batch.
+	for j, i := range patchIndexes {
+		results[i].Err = response.patchResult(j, requestData.Patches[j].ID, ip, providers[i].proxied)
+	}
+	for j, i := range postIndexes {
+		provider := providers[i]
+		id, err := response.postResult(j, ip, provider.proxied)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Created = true
+		if provider.managedOnly {
+			provider.managedIDs = append(provider.managedIDs, id)
+		}
+	}
+	return results
+}
+
+func recordTypeFromIP(ip netip.Addr) string {
+	if ip.Is6() {
+		return constants.AAAA
+	}
+	return constants.A
+}
+
+type listedRecord struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	Proxied bool   `json:"proxied"`
+}
+
+// listRecords lists all the records of the zone matching the IP address
+// type, and returns them keyed by their normalized name.
+// See https://developers.cloudflare.com/api/resources/dns/subresources/records/methods/list/
+func (p *Provider) listRecords(ctx context.Context, client *http.Client, ip netip.Addr) (
+	records map[string][]listedRecord, err error) {
+	records = make(map[string][]listedRecord)
+	for page := 1; ; page++ {
+		u := url.URL{
+			Scheme: "https",
+			Host:   "api.cloudflare.com",
+			Path:   fmt.Sprintf("/client/v4/zones/%s/dns_records", p.zoneIdentifier),
+		}
+		values := url.Values{}
+		values.Set("type", recordTypeFromIP(ip))
+		values.Set("page", strconv.Itoa(page))
+		values.Set("per_page", "5000")
+		u.RawQuery = values.Encode()
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating http request: %w", err)
+		}
+		p.setHeaders(request)
+
+		response, err := client.Do(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode != http.StatusOK {
+			err = errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+			_ = response.Body.Close()
+			return nil, err
+		}
+
+		var listRecordsResponse struct {
+			Success    bool           `json:"success"`
+			Errors     apiErrors      `json:"errors"`
+			Result     []listedRecord `json:"result"`
+			ResultInfo struct {
+				TotalPages int `json:"total_pages"`
+			} `json:"result_info"`
+		}
+		err = json.NewDecoder(response.Body).Decode(&listRecordsResponse)
+		_ = response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("json decoding response body: %w", err)
+		} else if !listRecordsResponse.Success {
+			return nil, fmt.Errorf("%w: %s", errors.ErrUnsuccessful, listRecordsResponse.Errors)
+		}
+
+		for _, record := range listRecordsResponse.Result {
+			name := utils.NormalizeRecordName(record.Name, false)
+			records[name] = append(records[name], record)
+		}
+
+		if page >= listRecordsResponse.ResultInfo.TotalPages {
+			return records, nil
+		}
+	}
+}
+
+type batchPatch struct {
+	ID string `json:"id"`
+	recordData
+}
+
+type batchRequest struct {
+	Patches []batchPatch `json:"patches,omitempty"`
+	Posts   []recordData `json:"posts,omitempty"`
+}
+
+type batchResponse struct {
+	Success bool      `json:"success"`
+	Errors  apiErrors `json:"errors"`
+	Result  struct {
+		Patches []recordResult `json:"patches"`
+		Posts   []recordResult `json:"posts"`
+	} `json:"result"`
+}
+
+func (p *Provider) sendBatch(ctx context.Context, client *http.Client,
+	requestData batchRequest) (parsedJSON batchResponse, err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.cloudflare.com",
+		Path:   fmt.Sprintf("/client/v4/zones/%s/dns_records/batch", p.zoneIdentifier),
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	err = encoder.Encode(requestData)
+	if err != nil {
+		return parsedJSON, fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), buffer)
+	if err != nil {
+		return parsedJSON, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return parsedJSON, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode > http.StatusUnsupportedMediaType {
+		return parsedJSON, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	err = decoder.Decode(&parsedJSON)
+	if err != nil {
+		return parsedJSON, fmt.Errorf("json decoding response body: %w", err)
+	} else if !parsedJSON.Success {
+		return parsedJSON, fmt.Errorf("%w: %s", errors.ErrUnsuccessful, parsedJSON.Errors)
+	}
+	return parsedJSON, nil
+}
+
+// patchResult checks the patched record at the index given
+// has the identifier, IP address and proxied status given.
+func (r batchResponse) patchResult(index int, id string, ip netip.Addr,
+	proxied bool) (err error) {
+	if index >= len(r.Result.Patches) {
+		return fmt.Errorf("%w: %d patched records for patch %d",
+			errors.ErrResultsCountReceived, len(r.Result.Patches), index+1)
+	}
+	record := r.Result.Patches[index]
+	if record.ID != id {
+		return fmt.Errorf("%w: sent record id %s but received %s",
+			errors.ErrUnsuccessful, id, record.ID)
+	}
+	return record.check(ip, proxied)
+}
+
+// postResult checks the created record at the index given has the IP
+// address and proxied status given, and returns its identifier.
+func (r batchResponse) postResult(index int, ip netip.Addr,
+	proxied bool) (id string, err error) {
+	if index >= len(r.Result.Posts) {
+		return "", fmt.Errorf("%w: %d created records for post %d",
+			errors.ErrResultsCountReceived, len(r.Result.Posts), index+1)
+	}
+	record := r.Result.Posts[index]
+	return record.ID, record.check(ip, proxied)
+}
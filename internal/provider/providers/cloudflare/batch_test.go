@@ -0,0 +1,91 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/batch"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_BatchUpdate(t *testing.T) {
+	t.Parallel()
+
+	var batchCalls int
+	var sent batchRequest
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			var body string
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/client/v4/zones/zone/dns_records":
+				assert.Equal(t, "A", r.URL.Query().Get("type"))
+				body = `{"success":true,"result_info":{"total_pages":1},"result":[
+					{"id":"1","name":"a.example.com","content":"5.6.7.8"},
+					{"id":"2","name":"b.example.com","content":"5.6.7.8"},
+					{"id":"3","name":"c.example.com","content":"1.2.3.4"}
+				]}`
+			case r.Method == http.MethodPost && r.URL.Path == "/client/v4/zones/zone/dns_records/batch":
+				batchCalls++
+				err := json.NewDecoder(r.Body).Decode(&sent)
+				if err != nil {
+					return nil, err
+				}
+				// Record b is echoed with a mismatching content
+				// to check partial failures are handled per record.
+				body = `{"success":true,"result":{
+					"patches":[{"id":"1","content":"1.2.3.4"},{"id":"2","content":"5.6.7.8"}],
+					"posts":[{"id":"4","content":"1.2.3.4"}]
+				}}`
+			default:
+				return nil, fmt.Errorf("unexpected request %s %s", r.Method, r.URL)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	hosts := []string{"a", "b", "c", "d"}
+	updaters := make([]batch.Updater, len(hosts))
+	for i, host := range hosts {
+		updaters[i] = &Provider{
+			domain:         "example.com",
+			host:           host,
+			token:          "token",
+			zoneIdentifier: "zone",
+			ttl:            1,
+		}
+	}
+	ip := netip.MustParseAddr("1.2.3.4")
+
+	results := updaters[0].BatchUpdate(context.Background(), client, ip, updaters)
+
+	assert.Equal(t, 1, batchCalls)
+	expectedSent := batchRequest{
+		Patches: []batchPatch{
+			{ID: "1", recordData: recordData{Content: "1.2.3.4", TTL: 1}},
+			{ID: "2", recordData: recordData{Content: "1.2.3.4", TTL: 1}},
+		},
+		Posts: []recordData{
+			{Type: "A", Name: "d.example.com", Content: "1.2.3.4", TTL: 1},
+		},
+	}
+	assert.Equal(t, expectedSent, sent)
+
+	require.Len(t, results, len(hosts))
+	assert.Equal(t, batch.Result{}, results[0])
+	assert.ErrorIs(t, results[1].Err, errors.ErrIPReceivedMismatch)
+	assert.EqualError(t, results[1].Err, "mismatching IP address received: "+
+		"sent ip 1.2.3.4 to update but received 5.6.7.8")
+	assert.Equal(t, batch.Result{}, results[2]) // already up to date
+	assert.Equal(t, batch.Result{Created: true}, results[3])
+}
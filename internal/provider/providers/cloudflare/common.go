@@ -0,0 +1,107 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// recordData is the record data sent when creating or updating a record.
+type recordData struct {
+	Type    string `json:"type,omitempty"`    // A or AAAA depending on ip address given
+	Name    string `json:"name,omitempty"`    // DNS record name i.e. example.com
+	Content string `json:"content,omitempty"` // ip address
+	// Priority is the priority of MX records.
+	Priority *uint16 `json:"priority,omitempty"`
+	// Data is the structured data of SRV and CAA records,
+	// which is a srvData or a caaData value.
+	Data any `json:"data,omitempty"`
+	// Proxied is whether the record is receiving the performance
+	// and security benefits of Cloudflare.
+	Proxied bool `json:"proxied"`
+	TTL     uint `json:"ttl"`
+}
+
+// recordResponse is the response received when creating or updating a record.
+type recordResponse struct {
+	Success bool         `json:"success"`
+	Errors  apiErrors    `json:"errors"`
+	Result  recordResult `json:"result"`
+}
+
+// recordResult is the record received after creating or updating it.
+type recordResult struct {
+	ID       string          `json:"id"`
+	Content  string          `json:"content"`
+	Priority *uint16         `json:"priority"`
+	Data     json.RawMessage `json:"data"`
+	Proxied  bool            `json:"proxied"`
+}
+
+// check verifies the record content and proxied status
+// match the IP address and proxied status sent.
+func (r recordResult) check(ip netip.Addr, proxied bool) (err error) {
+	newIP, err := netip.ParseAddr(r.Content)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+	} else if newIP.Compare(ip) != 0 {
+		return fmt.Errorf("%w: sent ip %s to update but received %s",
+			errors.ErrIPReceivedMismatch, ip, newIP)
+	} else if r.Proxied != proxied {
+		return fmt.Errorf("%w: sent proxied %t but received %t",
+			errors.ErrUnsuccessful, proxied, r.Proxied)
+	}
+	return nil
+}
+
+// sendRecord sends the record data given with the HTTP method given
+// to the URL path given, to create or update a record, and returns
+// the record received.
+func (p *Provider) sendRecord(ctx context.Context, client *http.Client,
+	method, path string, requestData recordData) (record recordResult, err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.cloudflare.com",
+		Path:   path,
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	err = encoder.Encode(requestData)
+	if err != nil {
+		return record, fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), buffer)
+	if err != nil {
+		return record, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return record, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode > http.StatusUnsupportedMediaType {
+		return record, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var parsedJSON recordResponse
+	err = decoder.Decode(&parsedJSON)
+	if err != nil {
+		return record, fmt.Errorf("json decoding response body: %w", err)
+	} else if !parsedJSON.Success {
+		return record, fmt.Errorf("%w: %s", errors.ErrUnsuccessful, parsedJSON.Errors)
+	}
+	return parsedJSON.Result, nil
+}
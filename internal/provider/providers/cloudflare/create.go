@@ -0,0 +1,34 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// See https://developers.cloudflare.com/api/operations/dns-records-for-a-zone-create-dns-record
+func (p *Provider) createRecord(ctx context.Context, client *http.Client, ip netip.Addr) (recordID string, err error) {
+	requestData := recordData{
+		Type:    recordTypeFromIP(ip),
+		Name:    utils.BuildURLQueryHostname(p.host, p.domain),
+		Content: ip.String(),
+		Proxied: p.proxied,
+		TTL:     p.ttl,
+	}
+
+	path := fmt.Sprintf("/client/v4/zones/%s/dns_records", p.zoneIdentifier)
+	record, err := p.sendRecord(ctx, client, http.MethodPost, path, requestData)
+	if err != nil {
+		return "", err
+	}
+
+	err = record.check(ip, p.proxied)
+	if err != nil {
+		return "", err
+	}
+
+	return record.ID, nil
+}
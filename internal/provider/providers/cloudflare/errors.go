@@ -0,0 +1,20 @@
+package cloudflare
+
+import (
+	"fmt"
+	"strings"
+)
+
+// apiErrors is the errors array present in all Cloudflare API responses.
+type apiErrors []struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (a apiErrors) String() string {
+	errStrings := make([]string, len(a))
+	for i, apiErr := range a {
+		errStrings[i] = fmt.Sprintf("error %d: %s", apiErr.Code, apiErr.Message)
+	}
+	return strings.Join(errStrings, "; ")
+}
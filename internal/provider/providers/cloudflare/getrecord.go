@@ -0,0 +1,82 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// Obtain domain ID.
+// See https://api.cloudflare.com/#dns-records-for-a-zone-list-dns-records.
+func (p *Provider) getRecordID(ctx context.Context, client *http.Client, newIP netip.Addr) (
+	identifier string, upToDate bool, err error) {
+	record, err := p.getRecord(ctx, client, recordTypeFromIP(newIP))
+	if err != nil {
+		return "", false, err
+	}
+	upToDate = record.Content == newIP.String() && record.Proxied == p.proxied
+	return record.ID, upToDate, nil
+}
+
+// getRecord obtains the single record of the record type given for the host.
+// It returns an error wrapping errors.ErrReceivedNoResult if no record exists.
+func (p *Provider) getRecord(ctx context.Context, client *http.Client, recordType string) (
+	record recordResult, err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.cloudflare.com",
+		Path:   fmt.Sprintf("/client/v4/zones/%s/dns_records", p.zoneIdentifier),
+	}
+
+	values := url.Values{}
+	values.Set("type", recordType)
+	values.Set("name", utils.BuildURLQueryHostname(p.host, p.domain))
+	values.Set("page", "1")
+	values.Set("per_page", "1")
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return record, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return record, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return record, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	listRecordsResponse := struct {
+		Success bool           `json:"success"`
+		Errors  apiErrors      `json:"errors"`
+		Result  []recordResult `json:"result"`
+	}{}
+	err = decoder.Decode(&listRecordsResponse)
+	if err != nil {
+		return record, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	switch {
+	case !listRecordsResponse.Success:
+		return record, fmt.Errorf("%w: %s",
+			errors.ErrUnsuccessful, listRecordsResponse.Errors)
+	case len(listRecordsResponse.Result) == 0:
+		return record, fmt.Errorf("%w", errors.ErrReceivedNoResult)
+	case len(listRecordsResponse.Result) > 1:
+		return record, fmt.Errorf("%w: %d instead of 1",
+			errors.ErrResultsCountReceived, len(listRecordsResponse.Result))
+	}
+	return listRecordsResponse.Result[0], nil
+}
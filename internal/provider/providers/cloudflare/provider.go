@@ -1,15 +1,14 @@
 package cloudflare
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	stderrors "errors"
 	"fmt"
 	"net/http"
 	"net/netip"
-	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/qdm12/ddns-updater/internal/models"
@@ -32,24 +31,45 @@ type Provider struct {
 	zoneIdentifier string
 	proxied        bool
 	ttl            uint
+	// managedOnly is whether to refuse updating records which
+	// were not created or previously managed by this program.
+	managedOnly bool
+	// adoptExisting is whether to start managing an existing record
+	// not yet managed, and is only used if managedOnly is true.
+	adoptExisting bool
+	// managedIDs are the IDs of records managed by this program.
+	managedIDs []string
+	// recordType is the empty string to update A and AAAA records,
+	// or TXT, MX, SRV or CAA to set the record value rendered from
+	// the value template.
+	recordType string
+	value      string
 }
 
 func New(data json.RawMessage, domain, host string,
 	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
 	p *Provider, err error) {
 	extraSettings := struct {
-		Key            string `json:"key"`
-		Token          string `json:"token"`
-		Email          string `json:"email"`
-		UserServiceKey string `json:"user_service_key"`
-		ZoneIdentifier string `json:"zone_identifier"`
-		Proxied        bool   `json:"proxied"`
-		TTL            uint   `json:"ttl"`
+		Key            string    `json:"key"`
+		Token          string    `json:"token"`
+		APIToken       string    `json:"api_token"`
+		Email          string    `json:"email"`
+		UserServiceKey string    `json:"user_service_key"`
+		ZoneIdentifier string    `json:"zone_identifier"`
+		Proxied        bool      `json:"proxied"`
+		TTL            utils.TTL `json:"ttl"`
+		ManagedOnly    bool      `json:"managed_only"`
+		AdoptExisting  bool      `json:"adopt_existing"`
+		RecordType     string    `json:"record_type"`
+		Value          string    `json:"value"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
 		return nil, err
 	}
+	if extraSettings.Token == "" {
+		extraSettings.Token = extraSettings.APIToken
+	}
 	p = &Provider{
 		domain:         domain,
 		host:           host,
@@ -61,7 +81,11 @@ func New(data json.RawMessage, domain, host string,
 		userServiceKey: extraSettings.UserServiceKey,
 		zoneIdentifier: extraSettings.ZoneIdentifier,
 		proxied:        extraSettings.Proxied,
-		ttl:            extraSettings.TTL,
+		ttl:            uint(extraSettings.TTL),
+		managedOnly:    extraSettings.ManagedOnly,
+		adoptExisting:  extraSettings.AdoptExisting,
+		recordType:     strings.ToUpper(extraSettings.RecordType),
+		value:          extraSettings.Value,
 	}
 	err = p.isValid()
 	if err != nil {
@@ -95,12 +119,28 @@ func (p *Provider) isValid() error {
 	default: // constants.API token only
 	}
 	switch {
-	case p.zoneIdentifier == "":
-		return fmt.Errorf("%w", errors.ErrZoneIdentifierNotSet)
+	case p.recordType == "" && p.value != "":
+		return fmt.Errorf("%w: value can only be set with a record type",
+			errors.ErrRecordValueNotValid)
+	case p.recordType != "" && p.proxied:
+		return fmt.Errorf("%w: %s records cannot be proxied",
+			errors.ErrRecordTypeNotValid, p.recordType)
+	case p.recordType != "":
+		err := utils.CheckRecordValue(p.recordType, p.value)
+		if err != nil {
+			return err
+		}
+	}
+	// A TTL of 1 is for an automatic TTL, and
+	// 30 seconds is the minimum for enterprise zones.
+	const automaticTTL, minTTL, maxTTL = 1, 30, 86400
+	switch {
 	case p.ttl == 0:
 		return fmt.Errorf("%w", errors.ErrTTLNotSet)
+	case p.ttl == automaticTTL:
+		return nil
 	}
-	return nil
+	return utils.CheckTTL(utils.TTL(p.ttl), minTTL, maxTTL)
 }
 
 func (p *Provider) String() string {
@@ -127,6 +167,28 @@ func (p *Provider) Proxied() bool {
 	return p.proxied
 }
 
+// SkipLookup returns true for TXT, MX, SRV and CAA records, since
+// the IP address cannot be resolved from their name to check if
+// an update is needed.
+func (p *Provider) SkipLookup() bool {
+	return p.recordType != ""
+}
+
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{
+		IPv4:          true,
+		IPv6:          true,
+		CreateMissing: true,
+		Proxied:       true,
+		TTL:           true,
+	}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -155,250 +217,71 @@ func (p *Provider) setHeaders(request *http.Request) {
 	}
 }
 
-// Obtain domain ID.
-// See https://api.cloudflare.com/#dns-records-for-a-zone-list-dns-records.
-func (p *Provider) getRecordID(ctx context.Context, client *http.Client, newIP netip.Addr) (
-	identifier string, upToDate bool, err error) {
-	recordType := constants.A
-	if newIP.Is6() {
-		recordType = constants.AAAA
-	}
-
-	u := url.URL{
-		Scheme: "https",
-		Host:   "api.cloudflare.com",
-		Path:   fmt.Sprintf("/client/v4/zones/%s/dns_records", p.zoneIdentifier),
-	}
-
-	values := url.Values{}
-	values.Set("type", recordType)
-	values.Set("name", utils.BuildURLQueryHostname(p.host, p.domain))
-	values.Set("page", "1")
-	values.Set("per_page", "1")
-	u.RawQuery = values.Encode()
-
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		return "", false, fmt.Errorf("creating http request: %w", err)
-	}
-	p.setHeaders(request)
-
-	response, err := client.Do(request)
-	if err != nil {
-		return "", false, err
-	}
-	defer response.Body.Close()
-
-	if response.StatusCode != http.StatusOK {
-		return "", false, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
-	}
-
-	decoder := json.NewDecoder(response.Body)
-	listRecordsResponse := struct {
-		Success bool     `json:"success"`
-		Errors  []string `json:"errors"`
-		Result  []struct {
-			ID      string `json:"id"`
-			Content string `json:"content"`
-		} `json:"result"`
-	}{}
-	err = decoder.Decode(&listRecordsResponse)
-	if err != nil {
-		return "", false, fmt.Errorf("json decoding response body: %w", err)
-	}
-
-	switch {
-	case len(listRecordsResponse.Errors) > 0:
-		return "", false, fmt.Errorf("%w: %s",
-			errors.ErrUnsuccessful, strings.Join(listRecordsResponse.Errors, ","))
-	case !listRecordsResponse.Success:
-		return "", false, fmt.Errorf("%w", errors.ErrUnsuccessful)
-	case len(listRecordsResponse.Result) == 0:
-		return "", false, fmt.Errorf("%w", errors.ErrReceivedNoResult)
-	case len(listRecordsResponse.Result) > 1:
-		return "", false, fmt.Errorf("%w: %d instead of 1",
-			errors.ErrResultsCountReceived, len(listRecordsResponse.Result))
-	case listRecordsResponse.Result[0].Content == newIP.String(): // up to date
-		return "", true, nil
-	}
-	return listRecordsResponse.Result[0].ID, false, nil
-}
-
-func (p *Provider) createRecord(ctx context.Context, client *http.Client, ip netip.Addr) (recordID string, err error) {
-	recordType := constants.A
-
-	if ip.Is6() {
-		recordType = constants.AAAA
-	}
-
-	u := url.URL{
-		Scheme: "https",
-		Host:   "api.cloudflare.com",
-		Path:   fmt.Sprintf("/client/v4/zones/%s/dns_records", p.zoneIdentifier),
-	}
-
-	requestData := struct {
-		Type    string `json:"type"`    // constants.A or constants.AAAA depending on ip address given
-		Name    string `json:"name"`    // DNS record name i.e. example.com
-		Content string `json:"content"` // ip address
-		Proxied bool   `json:"proxied"` // whether the record is receiving the performance and security benefits of Cloudflare
-		TTL     uint   `json:"ttl"`
-	}{
-		Type:    recordType,
-		Name:    utils.BuildURLQueryHostname(p.host, p.domain),
-		Content: ip.String(),
-		Proxied: p.proxied,
-		TTL:     p.ttl,
-	}
-
-	buffer := bytes.NewBuffer(nil)
-	encoder := json.NewEncoder(buffer)
-	err = encoder.Encode(requestData)
-	if err != nil {
-		return "", fmt.Errorf("JSON encoding request data: %w", err)
-	}
-
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), buffer)
-	if err != nil {
-		return "", fmt.Errorf("creating http request: %w", err)
-	}
-
-	p.setHeaders(request)
-
-	response, err := client.Do(request)
-	if err != nil {
-		return "", err
-	}
-	defer response.Body.Close()
-
-	if response.StatusCode > http.StatusUnsupportedMediaType {
-		return "", fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
-	}
-
-	decoder := json.NewDecoder(response.Body)
-	var parsedJSON struct {
-		Success bool `json:"success"`
-		Errors  []struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-		} `json:"errors"`
-		Result struct {
-			ID string `json:"id"`
-		} `json:"result"`
-	}
-	err = decoder.Decode(&parsedJSON)
-	if err != nil {
-		return "", fmt.Errorf("json decoding response body: %w", err)
-	}
-
-	if !parsedJSON.Success {
-		var errStr string
-		for _, e := range parsedJSON.Errors {
-			errStr += fmt.Sprintf("error %d: %s; ", e.Code, e.Message)
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	if p.zoneIdentifier == "" {
+		p.zoneIdentifier, err = p.getZoneID(ctx, client)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("getting zone id: %w", err)
 		}
-		return "", fmt.Errorf("%w: %s", errors.ErrUnsuccessful, errStr)
 	}
 
-	return parsedJSON.Result.ID, nil
-}
-
-func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
-	recordType := constants.A
-	if ip.Is6() {
-		recordType = constants.AAAA
+	if p.recordType != "" {
+		return p.updateRecordValue(ctx, client, ip)
 	}
 
 	identifier, upToDate, err := p.getRecordID(ctx, client, ip)
-
 	switch {
 	case stderrors.Is(err, errors.ErrReceivedNoResult):
+		if !utils.CreationAllowed(ctx) {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
 		identifier, err = p.createRecord(ctx, client, ip)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
 		}
+		utils.SignalCreated(ctx)
+		if p.managedOnly {
+			p.managedIDs = append(p.managedIDs, identifier)
+		}
+		return ip, nil
 	case err != nil:
 		return netip.Addr{}, fmt.Errorf("getting record id: %w", err)
-	case upToDate:
-		return ip, nil
 	}
 
-	u := url.URL{
-		Scheme: "https",
-		Host:   "api.cloudflare.com",
-		Path:   fmt.Sprintf("/client/v4/zones/%s/dns_records/%s", p.zoneIdentifier, identifier),
-	}
-
-	requestData := struct {
-		Type    string `json:"type"`    // constants.A or constants.AAAA depending on ip address given
-		Name    string `json:"name"`    // DNS record name i.e. example.com
-		Content string `json:"content"` // ip address
-		Proxied bool   `json:"proxied"` // whether the record is receiving the performance and security benefits of Cloudflare
-		TTL     uint   `json:"ttl"`
-	}{
-		Type:    recordType,
-		Name:    utils.BuildURLQueryHostname(p.host, p.domain),
-		Content: ip.String(),
-		Proxied: p.proxied,
-		TTL:     p.ttl,
-	}
-
-	buffer := bytes.NewBuffer(nil)
-	encoder := json.NewEncoder(buffer)
-	err = encoder.Encode(requestData)
-	if err != nil {
-		return netip.Addr{}, fmt.Errorf("json encoding request data: %w", err)
-	}
-
-	request, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), buffer)
-	if err != nil {
-		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
-	}
-
-	p.setHeaders(request)
-
-	response, err := client.Do(request)
+	err = p.checkManaged(identifier)
 	if err != nil {
 		return netip.Addr{}, err
-	}
-	defer response.Body.Close()
-
-	if response.StatusCode > http.StatusUnsupportedMediaType {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	} else if upToDate {
+		return ip, nil
 	}
 
-	decoder := json.NewDecoder(response.Body)
-	var parsedJSON struct {
-		Success bool `json:"success"`
-		Errors  []struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-		} `json:"errors"`
-		Result struct {
-			Content string `json:"content"`
-		} `json:"result"`
-	}
-	err = decoder.Decode(&parsedJSON)
+	newIP, err = p.updateRecord(ctx, client, identifier, ip)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("json decoding response body: %w", err)
+		return netip.Addr{}, fmt.Errorf("updating record: %w", err)
 	}
+	return newIP, nil
+}
 
-	if !parsedJSON.Success {
-		var errStr string
-		for _, e := range parsedJSON.Errors {
-			errStr += fmt.Sprintf("error %d: %s; ", e.Code, e.Message)
-		}
-		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrUnsuccessful, errStr)
-	}
+func (p *Provider) ManagedRecordIDs() (ids []string) {
+	return p.managedIDs
+}
 
-	newIP, err = netip.ParseAddr(parsedJSON.Result.Content)
-	if err != nil {
-		return netip.Addr{}, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
-	} else if newIP.Compare(ip) != 0 {
-		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
-			errors.ErrIPReceivedMismatch, ip, newIP)
+func (p *Provider) SetManagedRecordIDs(ids []string) {
+	p.managedIDs = ids
+}
+
+// checkManaged returns an error if the managed records only safeguard
+// is enabled and the record identifier given is not managed, unless
+// existing records can be adopted, in which case the record becomes managed.
+func (p *Provider) checkManaged(identifier string) (err error) {
+	switch {
+	case !p.managedOnly, slices.Contains(p.managedIDs, identifier):
+		return nil
+	case p.adoptExisting:
+		p.managedIDs = append(p.managedIDs, identifier)
+		return nil
+	default:
+		return fmt.Errorf("%w: record id %s, set adopt_existing to true to manage it",
+			errors.ErrRecordNotManaged, identifier)
 	}
-	return newIP, nil
 }
@@ -0,0 +1,145 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_checkManaged(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		provider   Provider
+		identifier string
+		managedIDs []string
+		errWrapped error
+		errMessage string
+	}{
+		"safeguard_disabled": {
+			identifier: "id",
+		},
+		"managed": {
+			provider: Provider{
+				managedOnly: true,
+				managedIDs:  []string{"other", "id"},
+			},
+			identifier: "id",
+			managedIDs: []string{"other", "id"},
+		},
+		"not_managed_refused": {
+			provider: Provider{
+				managedOnly: true,
+				managedIDs:  []string{"other"},
+			},
+			identifier: "id",
+			managedIDs: []string{"other"},
+			errWrapped: errors.ErrRecordNotManaged,
+			errMessage: "record is not managed by this program: " +
+				"record id id, set adopt_existing to true to manage it",
+		},
+		"not_managed_adopted": {
+			provider: Provider{
+				managedOnly:   true,
+				adoptExisting: true,
+				managedIDs:    []string{"other"},
+			},
+			identifier: "id",
+			managedIDs: []string{"other", "id"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.provider.checkManaged(testCase.identifier)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.managedIDs, testCase.provider.ManagedRecordIDs())
+		})
+	}
+}
+
+func Test_Provider_Update_dualStackFirstRun(t *testing.T) {
+	t.Parallel()
+
+	// zoneRecords maps record types to their content,
+	// and starts empty to simulate a first run.
+	zoneRecords := map[string]string{}
+	var created []string
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			recordType := r.URL.Query().Get("type")
+			var body string
+			switch r.Method {
+			case http.MethodGet:
+				content, ok := zoneRecords[recordType]
+				if !ok {
+					body = `{"success":true,"result":[]}`
+					break
+				}
+				body = fmt.Sprintf(`{"success":true,"result":[{"id":%q,"content":%q}]}`,
+					recordType, content)
+			case http.MethodPost:
+				var data recordData
+				err := json.NewDecoder(r.Body).Decode(&data)
+				if err != nil {
+					return nil, err
+				}
+				zoneRecords[data.Type] = data.Content
+				created = append(created, data.Type)
+				body = fmt.Sprintf(`{"success":true,"result":{"id":%q,"content":%q}}`,
+					data.Type, data.Content)
+			default:
+				return nil, fmt.Errorf("unexpected method %s", r.Method)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	familyIPs := map[ipversion.IPVersion]netip.Addr{
+		ipversion.IP4: netip.MustParseAddr("1.2.3.4"),
+		ipversion.IP6: netip.MustParseAddr("::1"),
+	}
+	for run := 0; run < 2; run++ {
+		for _, version := range []ipversion.IPVersion{ipversion.IP4, ipversion.IP6} {
+			provider := &Provider{
+				domain:         "example.com",
+				host:           "@",
+				ipVersion:      version,
+				token:          "token",
+				zoneIdentifier: "zone",
+				ttl:            1,
+			}
+			ip := familyIPs[version]
+
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			require.NoError(t, err, "run %d for %s", run, version)
+			assert.Equal(t, ip, newIP)
+		}
+	}
+
+	// Both record types are created on the first run only.
+	assert.Equal(t, []string{"A", "AAAA"}, created)
+	expectedZoneRecords := map[string]string{"A": "1.2.3.4", "AAAA": "::1"}
+	assert.Equal(t, expectedZoneRecords, zoneRecords)
+}
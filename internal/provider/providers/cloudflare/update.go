@@ -0,0 +1,31 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+)
+
+// See https://developers.cloudflare.com/api/operations/dns-records-for-a-zone-patch-dns-record
+func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
+	identifier string, ip netip.Addr) (newIP netip.Addr, err error) {
+	requestData := recordData{
+		Content: ip.String(),
+		Proxied: p.proxied,
+		TTL:     p.ttl,
+	}
+
+	path := fmt.Sprintf("/client/v4/zones/%s/dns_records/%s", p.zoneIdentifier, identifier)
+	record, err := p.sendRecord(ctx, client, http.MethodPatch, path, requestData)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	err = record.check(ip, p.proxied)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	return ip, nil
+}
@@ -0,0 +1,139 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// srvData is the structured data of SRV records.
+type srvData struct {
+	Priority uint16 `json:"priority"`
+	Weight   uint16 `json:"weight"`
+	Port     uint16 `json:"port"`
+	Target   string `json:"target"`
+}
+
+// caaData is the structured data of CAA records.
+type caaData struct {
+	Flags uint8  `json:"flags"`
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+// makeValueRecordData returns the record data to send for the record value given.
+func (p *Provider) makeValueRecordData(value utils.RecordValue) (data recordData) {
+	data = recordData{
+		Type: value.Type,
+		Name: utils.BuildURLQueryHostname(p.host, p.domain),
+		TTL:  p.ttl,
+	}
+	switch value.Type {
+	case constants.TXT:
+		data.Content = value.Text
+	case constants.MX:
+		data.Content = value.Target
+		data.Priority = &value.Priority
+	case constants.SRV:
+		data.Data = srvData{
+			Priority: value.Priority,
+			Weight:   value.Weight,
+			Port:     value.Port,
+			Target:   value.Target,
+		}
+	case constants.CAA:
+		data.Data = caaData{
+			Flags: value.CAA.Flags,
+			Tag:   value.CAA.Tag,
+			Value: value.CAA.Value,
+		}
+	}
+	return data
+}
+
+// matchesValue returns true if the record has the value of the record data
+// given, comparing the content of TXT and MX records, the priority of MX
+// records, and the structured data of SRV and CAA records.
+func (r recordResult) matchesValue(sent recordData) bool {
+	switch sent.Type {
+	case constants.TXT:
+		return r.Content == sent.Content
+	case constants.MX:
+		return utils.RecordNamesEqual(r.Content, sent.Content) &&
+			r.Priority != nil && *r.Priority == *sent.Priority
+	case constants.SRV:
+		var received srvData
+		err := json.Unmarshal(r.Data, &received)
+		if err != nil {
+			return false
+		}
+		expected, _ := sent.Data.(srvData)
+		received.Target = utils.NormalizeRecordName(received.Target, false)
+		return received == expected
+	case constants.CAA:
+		var received caaData
+		err := json.Unmarshal(r.Data, &received)
+		return err == nil && received == sent.Data
+	default:
+		return false
+	}
+}
+
+// updateRecordValue sets the record of the record type configured to
+// the value rendered for the IP address given, creating the record if
+// it does not exist. The IP address given is returned as is, since it
+// is not the record content.
+func (p *Provider) updateRecordValue(ctx context.Context, client *http.Client,
+	ip netip.Addr) (newIP netip.Addr, err error) {
+	value, err := utils.RenderRecordValue(p.recordType, p.value, ip)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("rendering value: %w", err)
+	}
+	requestData := p.makeValueRecordData(value)
+
+	method := http.MethodPatch
+	record, err := p.getRecord(ctx, client, p.recordType)
+	switch {
+	case stderrors.Is(err, errors.ErrReceivedNoResult):
+		if !utils.CreationAllowed(ctx) {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
+		method = http.MethodPost
+	case err != nil:
+		return netip.Addr{}, fmt.Errorf("getting record: %w", err)
+	default:
+		err = p.checkManaged(record.ID)
+		if err != nil {
+			return netip.Addr{}, err
+		} else if record.matchesValue(requestData) {
+			return ip, nil
+		}
+	}
+
+	path := fmt.Sprintf("/client/v4/zones/%s/dns_records", p.zoneIdentifier)
+	if method == http.MethodPatch {
+		path += "/" + record.ID
+	}
+	record, err = p.sendRecord(ctx, client, method, path, requestData)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("sending record: %w", err)
+	} else if !record.matchesValue(requestData) {
+		return netip.Addr{}, fmt.Errorf("%w: sent %s record value %q but received %q",
+			errors.ErrDataReceivedMismatch, value.Type, value, record.Content)
+	}
+
+	if method == http.MethodPost {
+		utils.SignalCreated(ctx)
+		if p.managedOnly {
+			p.managedIDs = append(p.managedIDs, record.ID)
+		}
+	}
+	return ip, nil
+}
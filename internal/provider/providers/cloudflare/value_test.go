@@ -0,0 +1,167 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update_value(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		recordType string
+		value      string
+		listResult string
+		method     string
+		sent       string
+		sendResult string
+		errWrapped error
+		errMessage string
+	}{
+		"create_txt": {
+			recordType: "TXT",
+			value:      "v=spf1 ip4:{ip} -all",
+			listResult: `[]`,
+			method:     http.MethodPost,
+			sent: `{"type":"TXT","name":"www.example.com",` +
+				`"content":"v=spf1 ip4:1.2.3.4 -all","proxied":false,"ttl":1}`,
+			sendResult: `{"id":"new","content":"v=spf1 ip4:1.2.3.4 -all"}`,
+		},
+		"update_mx": {
+			recordType: "MX",
+			value:      "10 mail.example.com",
+			listResult: `[{"id":"id","content":"old.example.com","priority":10}]`,
+			method:     http.MethodPatch,
+			sent: `{"type":"MX","name":"www.example.com",` +
+				`"content":"mail.example.com","priority":10,"proxied":false,"ttl":1}`,
+			sendResult: `{"id":"id","content":"mail.example.com","priority":10}`,
+		},
+		"srv_up_to_date": {
+			recordType: "SRV",
+			value:      "10 5 5060 sip.example.com",
+			listResult: `[{"id":"id","data":{"priority":10,"weight":5,"port":5060,"target":"sip.example.com."}}]`,
+		},
+		"caa_mismatch": {
+			recordType: "CAA",
+			value:      `0 issue "letsencrypt.org"`,
+			listResult: `[]`,
+			method:     http.MethodPost,
+			sent: `{"type":"CAA","name":"www.example.com",` +
+				`"data":{"flags":0,"tag":"issue","value":"letsencrypt.org"},"proxied":false,"ttl":1}`,
+			sendResult: `{"id":"new","content":"0 issue \"other.org\"",` +
+				`"data":{"flags":0,"tag":"issue","value":"other.org"}}`,
+			errWrapped: errors.ErrDataReceivedMismatch,
+			errMessage: "mismatching record data received: sent CAA record value " +
+				`"0 issue \"letsencrypt.org\"" but received "0 issue \"other.org\""`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					var result string
+					switch r.Method {
+					case http.MethodGet:
+						assert.Equal(t, testCase.recordType, r.URL.Query().Get("type"))
+						result = testCase.listResult
+					case testCase.method:
+						body, err := io.ReadAll(r.Body)
+						require.NoError(t, err)
+						assert.JSONEq(t, testCase.sent, string(body))
+						result = testCase.sendResult
+					default:
+						t.Errorf("unexpected method %s", r.Method)
+					}
+					responseData, err := json.Marshal(map[string]any{
+						"success": true,
+						"result":  json.RawMessage(result),
+					})
+					require.NoError(t, err)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(string(responseData))),
+					}, nil
+				}),
+			}
+
+			provider := &Provider{
+				domain:         "example.com",
+				host:           "www",
+				token:          "token",
+				zoneIdentifier: "zone",
+				ttl:            1,
+				recordType:     testCase.recordType,
+				value:          testCase.value,
+			}
+			ip := netip.MustParseAddr("1.2.3.4")
+
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, ip, newIP)
+		})
+	}
+}
+
+func Test_New_value(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+		errMessage string
+	}{
+		"txt": {
+			data: `{"token":"token","ttl":1,"record_type":"txt","value":"token-value"}`,
+		},
+		"value_without_record_type": {
+			data:       `{"token":"token","ttl":1,"value":"token-value"}`,
+			errWrapped: errors.ErrRecordValueNotValid,
+			errMessage: "record value is not valid: value can only be set with a record type",
+		},
+		"proxied_record_type": {
+			data:       `{"token":"token","ttl":1,"proxied":true,"record_type":"TXT","value":"token-value"}`,
+			errWrapped: errors.ErrRecordTypeNotValid,
+			errMessage: "record type is not valid: TXT records cannot be proxied",
+		},
+		"srv_port_not_valid": {
+			data:       `{"token":"token","ttl":1,"record_type":"SRV","value":"10 5 70000 sip.example.com"}`,
+			errWrapped: errors.ErrRecordValueNotValid,
+			errMessage: `record value is not valid: SRV field 3: strconv.ParseUint: ` +
+				`parsing "70000": value out of range`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "example.com", "www",
+				ipversion.IP4or6, netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// getZoneID discovers the zone identifier from the domain name.
+// See https://developers.cloudflare.com/api/operations/zones-get
+func (p *Provider) getZoneID(ctx context.Context, client *http.Client) (
+	identifier string, err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.cloudflare.com",
+		Path:   "/client/v4/zones",
+	}
+	values := url.Values{}
+	values.Set("name", p.domain)
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	default:
+		return "", errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var data struct {
+		Success bool      `json:"success"`
+		Errors  apiErrors `json:"errors"`
+		Result  []struct {
+			ID string `json:"id"`
+		} `json:"result"`
+	}
+	err = decoder.Decode(&data)
+	if err != nil {
+		return "", fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	switch {
+	case !data.Success:
+		return "", fmt.Errorf("%w: %s", errors.ErrUnsuccessful, data.Errors)
+	case len(data.Result) == 0:
+		return "", fmt.Errorf("%w: for domain %s", errors.ErrZoneNotFound, p.domain)
+	case len(data.Result) > 1:
+		return "", fmt.Errorf("%w: %d instead of 1",
+			errors.ErrResultsCountReceived, len(data.Result))
+	}
+	return data.Result[0].ID, nil
+}
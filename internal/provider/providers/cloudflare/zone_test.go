@@ -0,0 +1,88 @@
+package cloudflare
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Provider_getZoneID(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		statusCode int
+		body       string
+		identifier string
+		errWrapped error
+		errMessage string
+	}{
+		"success": {
+			statusCode: http.StatusOK,
+			body:       `{"success":true,"result":[{"id":"zone1"}]}`,
+			identifier: "zone1",
+		},
+		"unauthorized": {
+			statusCode: http.StatusForbidden,
+			body:       `{"success":false}`,
+			errWrapped: errors.ErrAuth,
+			errMessage: `bad authentication: {"success":false}`,
+		},
+		"unsuccessful": {
+			statusCode: http.StatusOK,
+			body:       `{"success":false,"errors":[{"code":1000,"message":"bad"}]}`,
+			errWrapped: errors.ErrUnsuccessful,
+			errMessage: "unsuccessful result: error 1000: bad",
+		},
+		"no_zone": {
+			statusCode: http.StatusOK,
+			body:       `{"success":true,"result":[]}`,
+			errWrapped: errors.ErrZoneNotFound,
+			errMessage: "zone not found: for domain example.com",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, "/client/v4/zones", r.URL.Path)
+					assert.Equal(t, "example.com", r.URL.Query().Get("name"))
+					assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+					return &http.Response{
+						StatusCode: testCase.statusCode,
+						Body:       io.NopCloser(strings.NewReader(testCase.body)),
+					}, nil
+				}),
+			}
+			provider := &Provider{
+				domain: "example.com",
+				token:  "token",
+			}
+
+			identifier, err := provider.getZoneID(context.Background(), client)
+
+			assert.Equal(t, testCase.identifier, identifier)
+			if testCase.errWrapped != nil {
+				require.ErrorIs(t, err, testCase.errWrapped)
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package cloudns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+type record struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Host   string `json:"host"`
+	Record string `json:"record"`
+	TTL    string `json:"ttl"`
+}
+
+func (p *Provider) updateWithAPI(ctx context.Context, client *http.Client,
+	ip netip.Addr) (newIP netip.Addr, err error) {
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+
+	records, err := p.getRecords(ctx, client, recordType)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting records: %w", err)
+	}
+
+	if len(records) == 0 {
+		if !utils.CreationAllowed(ctx) {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
+		values := p.recordValues(ip)
+		values.Set("record-type", recordType)
+		err = p.post(ctx, client, "add-record.json", values)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("adding record: %w", err)
+		}
+		utils.SignalCreated(ctx)
+		return ip, nil
+	}
+
+	ttl := strconv.FormatUint(uint64(p.ttl), 10)
+	for _, record := range records {
+		if record.Record == ip.String() && record.TTL == ttl {
+			continue
+		}
+		values := p.recordValues(ip)
+		values.Set("record-id", record.ID)
+		err = p.post(ctx, client, "mod-record.json", values)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("modifying record: %w", err)
+		}
+	}
+	return ip, nil
+}
+
+// recordName returns the record host as used by the ClouDNS
+// API, which is empty for the root domain.
+func (p *Provider) recordName() string {
+	if p.host == "@" {
+		return ""
+	}
+	return p.host
+}
+
+func (p *Provider) authValues() url.Values {
+	values := url.Values{}
+	if p.subAuthID != "" {
+		values.Set("sub-auth-id", p.subAuthID)
+	} else {
+		values.Set("auth-id", p.authID)
+	}
+	values.Set("auth-password", p.authPassword)
+	values.Set("domain-name", p.domain)
+	return values
+}
+
+func (p *Provider) recordValues(ip netip.Addr) url.Values {
+	values := p.authValues()
+	values.Set("host", p.recordName())
+	values.Set("record", ip.String())
+	values.Set("ttl", strconv.FormatUint(uint64(p.ttl), 10))
+	return values
+}
+
+func makeURL(endpoint string) string {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.cloudns.net",
+		Path:   "/dns/" + endpoint,
+	}
+	return u.String()
+}
+
+// getRecords returns the records matching the host and record type.
+// See https://www.cloudns.net/wiki/article/57/
+func (p *Provider) getRecords(ctx context.Context, client *http.Client,
+	recordType string) (records []record, err error) {
+	values := p.authValues()
+	values.Set("host", p.recordName())
+	values.Set("type", recordType)
+	data, err := do(ctx, client, "records.json", values)
+	if err != nil {
+		return nil, err
+	}
+
+	// Records are returned as an object keyed by record id,
+	// or as an empty array if there is no record.
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return nil, nil
+	}
+	var recordsByID map[string]record
+	err = json.Unmarshal(data, &recordsByID)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	for _, record := range recordsByID {
+		// The host filter also matches records of sub-hosts,
+		// so the host is checked again.
+		if record.Host == p.recordName() && record.Type == recordType {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// post sends the values to the endpoint given and checks the status
+// of the response is successful.
+// See https://www.cloudns.net/wiki/article/58/ and https://www.cloudns.net/wiki/article/60/
+func (p *Provider) post(ctx context.Context, client *http.Client,
+	endpoint string, values url.Values) (err error) {
+	data, err := do(ctx, client, endpoint, values)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Status            string `json:"status"`
+		StatusDescription string `json:"statusDescription"`
+	}
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return fmt.Errorf("json decoding response body: %w", err)
+	} else if result.Status != "Success" {
+		return fmt.Errorf("%w: %s: %s", errors.ErrUnsuccessful, result.Status, result.StatusDescription)
+	}
+	return nil
+}
+
+// do sends the values as a POST form to the endpoint given, and returns
+// the response body. It returns an error if the response status code is
+// not 200 or if the response is a failed status.
+func do(ctx context.Context, client *http.Client,
+	endpoint string, values url.Values) (data []byte, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, makeURL(endpoint),
+		strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/x-www-form-urlencoded")
+	headers.SetAccept(request, "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("doing http request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	data, err = io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	var failure struct {
+		Status            string `json:"status"`
+		StatusDescription string `json:"statusDescription"`
+	}
+	if json.Unmarshal(data, &failure) == nil && failure.Status == "Failed" {
+		if strings.Contains(failure.StatusDescription, "auth") {
+			return nil, fmt.Errorf("%w: %s", errors.ErrAuth, failure.StatusDescription)
+		}
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnsuccessful, failure.StatusDescription)
+	}
+	return data, nil
+}
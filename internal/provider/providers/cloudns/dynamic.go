@@ -0,0 +1,57 @@
+package cloudns
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// updateWithDynamicURL requests the dynamic URL of the record, which
+// sets the record to the IP address the request is sent from. The request
+// is sent to the ClouDNS host of the IP version of the IP address given.
+// See https://www.cloudns.net/wiki/article/36/
+func (p *Provider) updateWithDynamicURL(ctx context.Context, client *http.Client,
+	ip netip.Addr) (newIP netip.Addr, err error) {
+	u := *p.dynamicURL
+	switch u.Host {
+	case "ipv4.cloudns.net", "ipv6.cloudns.net":
+		u.Host = "ipv4.cloudns.net"
+		if ip.Is6() {
+			u.Host = "ipv6.cloudns.net"
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+	}
+	headers.SetUserAgent(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("doing http request: %w", err)
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("reading response body: %w", err)
+	}
+	s := strings.TrimSpace(string(b))
+
+	if response.StatusCode != http.StatusOK {
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.ToSingleLine(s))
+	}
+
+	if !strings.HasPrefix(s, "OK") {
+		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrUnknownResponse, utils.ToSingleLine(s))
+	}
+	return ip, nil
+}
@@ -0,0 +1,161 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain     string
+	host       string
+	ipVersion  ipversion.IPVersion
+	ipv6Suffix netip.Prefix
+	// dynamicURL is the dynamic URL of the record. If it is set,
+	// it is used instead of the HTTP API.
+	dynamicURL *url.URL
+	// authID or subAuthID and authPassword are the
+	// credentials of the HTTP API user.
+	authID       string
+	subAuthID    string
+	authPassword string
+	ttl          uint32
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	p *Provider, err error) {
+	extraSettings := struct {
+		DynamicURL   string    `json:"dynamic_url"`
+		AuthID       string    `json:"auth_id"`
+		SubAuthID    string    `json:"sub_auth_id"`
+		AuthPassword string    `json:"auth_password"`
+		TTL          utils.TTL `json:"ttl"`
+	}{}
+	err = json.Unmarshal(data, &extraSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	var dynamicURL *url.URL
+	if extraSettings.DynamicURL != "" {
+		dynamicURL, err = url.Parse(extraSettings.DynamicURL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing dynamic URL: %w", err)
+		}
+	}
+
+	const defaultTTL = 3600
+	if extraSettings.TTL == 0 {
+		extraSettings.TTL = defaultTTL
+	}
+
+	p = &Provider{
+		domain:       domain,
+		host:         host,
+		ipVersion:    ipVersion,
+		ipv6Suffix:   ipv6Suffix,
+		dynamicURL:   dynamicURL,
+		authID:       extraSettings.AuthID,
+		subAuthID:    extraSettings.SubAuthID,
+		authPassword: extraSettings.AuthPassword,
+		ttl:          uint32(extraSettings.TTL),
+	}
+	err = p.isValid()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.dynamicURL != nil {
+		if p.dynamicURL.Scheme != "https" {
+			return fmt.Errorf("%w: %s", errors.ErrURLNotHTTPS, p.dynamicURL.Scheme)
+		}
+		return nil
+	}
+
+	switch {
+	case p.authID == "" && p.subAuthID == "":
+		return fmt.Errorf("%w", errors.ErrAuthIDNotSet)
+	case p.authPassword == "":
+		return fmt.Errorf("%w", errors.ErrPasswordNotSet)
+	}
+
+	// See https://www.cloudns.net/wiki/article/58/
+	allowedTTLs := []uint32{60, 300, 900, 1800, 3600, 21600, 43200, 86400,
+		172800, 259200, 604800, 1209600, 2592000}
+	if !slices.Contains(allowedTTLs, p.ttl) {
+		return fmt.Errorf("%w: %d must be one of %v", errors.ErrTTLNotValid, p.ttl, allowedTTLs)
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.ClouDNS, p.ipVersion)
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) IPv6Suffix() netip.Prefix {
+	return p.ipv6Suffix
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{
+		IPv4:          true,
+		IPv6:          true,
+		CreateMissing: true,
+		TTL:           true,
+	}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName()),
+		Host:      p.Host(),
+		Provider:  "<a href=\"https://www.cloudns.net/\">ClouDNS</a>",
+		IPVersion: p.ipVersion.String(),
+	}
+}
+
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	if p.dynamicURL != nil {
+		return p.updateWithDynamicURL(ctx, client, ip)
+	}
+	return p.updateWithAPI(ctx, client, ip)
+}
@@ -0,0 +1,175 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func newResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings   string
+		errWrapped error
+		errMessage string
+	}{
+		"dynamic_url": {
+			settings: `{"dynamic_url":"https://ipv4.cloudns.net/api/dynamicURL/?q=secret"}`,
+		},
+		"dynamic_url_not_https": {
+			settings:   `{"dynamic_url":"http://ipv4.cloudns.net/api/dynamicURL/?q=secret"}`,
+			errWrapped: errors.ErrURLNotHTTPS,
+			errMessage: "url is not https: http",
+		},
+		"api": {
+			settings: `{"sub_auth_id":"1","auth_password":"password","ttl":"5m"}`,
+		},
+		"api_auth_id_missing": {
+			settings:   `{"auth_password":"password"}`,
+			errWrapped: errors.ErrAuthIDNotSet,
+			errMessage: "auth ID is not set",
+		},
+		"api_ttl_not_allowed": {
+			settings:   `{"auth_id":"1","auth_password":"password","ttl":120}`,
+			errWrapped: errors.ErrTTLNotValid,
+			errMessage: "TTL is not valid: 120 must be one of [60 300 900 1800 3600 21600 " +
+				"43200 86400 172800 259200 604800 1209600 2592000]",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.settings), "example.com", "home",
+				ipversion.IP4or6, netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_Provider_Update_dynamicURL(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New(json.RawMessage(`{"dynamic_url":"https://ipv4.cloudns.net/api/dynamicURL/?q=secret"}`),
+		"example.com", "home", ipversion.IP4or6, netip.Prefix{})
+	require.NoError(t, err)
+
+	var requestedURLs []string
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			requestedURLs = append(requestedURLs, r.URL.String())
+			return newResponse(http.StatusOK, "OK"), nil
+		}),
+	}
+
+	for _, ip := range []netip.Addr{netip.MustParseAddr("1.2.3.4"), netip.MustParseAddr("2001:db8::1")} {
+		newIP, err := provider.Update(context.Background(), client, ip)
+		require.NoError(t, err)
+		assert.Equal(t, ip, newIP)
+	}
+	assert.Equal(t, []string{
+		"https://ipv4.cloudns.net/api/dynamicURL/?q=secret",
+		"https://ipv6.cloudns.net/api/dynamicURL/?q=secret",
+	}, requestedURLs)
+}
+
+func Test_Provider_Update_api(t *testing.T) {
+	t.Parallel()
+
+	const settings = `{"auth_id":"1","auth_password":"password"}`
+
+	testCases := map[string]struct {
+		records    string
+		writes     []string
+		errWrapped error
+		errMessage string
+	}{
+		"up_to_date": {
+			records: `{"10":{"id":"10","type":"A","host":"home","record":"1.2.3.4","ttl":"3600"},` +
+				`"11":{"id":"11","type":"A","host":"sub.home","record":"5.6.7.8","ttl":"3600"}}`,
+		},
+		"modify": {
+			records: `{"10":{"id":"10","type":"A","host":"home","record":"5.6.7.8","ttl":"3600"}}`,
+			writes: []string{"mod-record.json auth-id=1&auth-password=password&domain-name=example.com" +
+				"&host=home&record=1.2.3.4&record-id=10&ttl=3600"},
+		},
+		"add": {
+			records: `[]`,
+			writes: []string{"add-record.json auth-id=1&auth-password=password&domain-name=example.com" +
+				"&host=home&record=1.2.3.4&record-type=A&ttl=3600"},
+		},
+		"bad_credentials": {
+			records:    `{"status":"Failed","statusDescription":"Invalid authentication, incorrect auth-id or auth-password."}`,
+			errWrapped: errors.ErrAuth,
+			errMessage: "getting records: bad authentication: " +
+				"Invalid authentication, incorrect auth-id or auth-password.",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(settings), "example.com", "home",
+				ipversion.IP4, netip.Prefix{})
+			require.NoError(t, err)
+
+			var writes []string
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, http.MethodPost, r.Method)
+					require.NoError(t, r.ParseForm())
+					endpoint := strings.TrimPrefix(r.URL.String(), "https://api.cloudns.net/dns/")
+					if endpoint == "records.json" {
+						assert.Equal(t, "home", r.PostForm.Get("host"))
+						assert.Equal(t, "A", r.PostForm.Get("type"))
+						return newResponse(http.StatusOK, testCase.records), nil
+					}
+					writes = append(writes, endpoint+" "+r.PostForm.Encode())
+					return newResponse(http.StatusOK, `{"status":"Success","statusDescription":"done"}`), nil
+				}),
+			}
+
+			ip := netip.MustParseAddr("1.2.3.4")
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, ip, newIP)
+			assert.Equal(t, testCase.writes, writes)
+		})
+	}
+}
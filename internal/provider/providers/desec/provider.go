@@ -25,14 +25,18 @@ type Provider struct {
 	ipv6Suffix    netip.Prefix
 	token         string
 	useProviderIP bool
+	// ttl is the TTL of the record set. If it is set, the record set
+	// is updated with the RRset API instead of the dynDNS API.
+	ttl uint32
 }
 
 func New(data json.RawMessage, domain, host string,
 	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
 	p *Provider, err error) {
 	extraSettings := struct {
-		Token         string `json:"token"`
-		UseProviderIP bool   `json:"provider_ip"`
+		Token         string    `json:"token"`
+		UseProviderIP bool      `json:"provider_ip"`
+		TTL           utils.TTL `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
@@ -48,6 +52,7 @@ func New(data json.RawMessage, domain, host string,
 		ipv6Suffix:    ipv6Suffix,
 		token:         extraSettings.Token,
 		useProviderIP: extraSettings.UseProviderIP,
+		ttl:           uint32(extraSettings.TTL),
 	}
 	err = p.isValid()
 	if err != nil {
@@ -57,10 +62,11 @@ func New(data json.RawMessage, domain, host string,
 }
 
 func (p *Provider) isValid() error {
+	const minTTL, maxTTL = 1, 604800
 	if p.token == "" {
 		return fmt.Errorf("%w", errors.ErrTokenNotSet)
 	}
-	return nil
+	return utils.CheckTTL(utils.TTL(p.ttl), minTTL, maxTTL)
 }
 
 func (p *Provider) String() string {
@@ -87,6 +93,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true, TTL: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -101,6 +116,10 @@ func (p *Provider) HTML() models.HTMLRow {
 }
 
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	if p.ttl != 0 {
+		return p.putRRSet(ctx, client, ip)
+	}
+
 	u := url.URL{
 		Scheme: "https",
 		User:   url.UserPassword(p.BuildDomainName(), p.token),
@@ -110,7 +129,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	values := url.Values{}
 	values.Set("hostname", utils.BuildURLQueryHostname(p.host, p.domain))
 	useProviderIP := p.useProviderIP && (ip.Is4() || !p.ipv6Suffix.IsValid())
-	if useProviderIP {
+	if !useProviderIP {
 		values.Set("myip", ip.String())
 	}
 	u.RawQuery = values.Encode()
@@ -121,9 +140,11 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	headers.SetUserAgent(request)
 
-	response, err := client.Do(request)
+	response, verified, err := utils.DoDynDNS2Request(ctx, client, request, p.BuildDomainName(), ip)
 	if err != nil {
 		return netip.Addr{}, err
+	} else if verified {
+		return ip, nil
 	}
 	defer response.Body.Close()
 
@@ -140,8 +161,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	case http.StatusNotFound:
 		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrHostnameNotExists, utils.ToSingleLine(s))
 	default:
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
-			response.StatusCode, utils.ToSingleLine(s))
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.ToSingleLine(s))
 	}
 
 	switch {
@@ -0,0 +1,101 @@
+package desec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// putRRSet replaces the record set of the host with a single
+// record with the IP address given and the TTL of the provider.
+// See https://desec.readthedocs.io/en/latest/dns/rrsets.html#modifying-an-rrset
+func (p *Provider) putRRSet(ctx context.Context, client *http.Client,
+	ip netip.Addr) (newIP netip.Addr, err error) {
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+	// The zone apex has an empty subname, written as @ in the URL path.
+	subname := p.host
+	if subname == "@" {
+		subname = ""
+	}
+	pathSubname := subname
+	if pathSubname == "" {
+		pathSubname = "@"
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "desec.io",
+		Path:   "/api/v1/domains/" + p.domain + "/rrsets/" + pathSubname + "/" + recordType + "/",
+	}
+
+	type rrSet struct {
+		Subname string   `json:"subname"`
+		Type    string   `json:"type"`
+		TTL     uint32   `json:"ttl"`
+		Records []string `json:"records"`
+	}
+	requestBody, err := json.Marshal(rrSet{
+		Subname: subname,
+		Type:    recordType,
+		TTL:     p.ttl,
+		Records: []string{ip.String()},
+	})
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(requestBody))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.Header.Set("Authorization", "Token "+p.token)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrRecordNotFound, utils.BodyToSingleLine(response.Body))
+	default:
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	var data rrSet
+	err = json.NewDecoder(response.Body).Decode(&data)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json decoding response body: %w", err)
+	} else if len(data.Records) != 1 {
+		return netip.Addr{}, fmt.Errorf("%w: %d records instead of 1",
+			errors.ErrResultsCountReceived, len(data.Records))
+	}
+
+	newIP, err = netip.ParseAddr(data.Records[0])
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+	} else if newIP.Compare(ip) != 0 {
+		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
+			errors.ErrIPReceivedMismatch, ip, newIP)
+	}
+	return newIP, nil
+}
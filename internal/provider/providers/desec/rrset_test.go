@@ -0,0 +1,99 @@
+package desec
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Provider_putRRSet(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		host         string
+		ip           netip.Addr
+		url          string
+		requestBody  string
+		status       int
+		responseBody string
+		errWrapped   error
+		errMessage   string
+	}{
+		"apex_ipv4": {
+			host:         "@",
+			ip:           netip.MustParseAddr("1.2.3.4"),
+			url:          "https://desec.io/api/v1/domains/example.dedyn.io/rrsets/@/A/",
+			requestBody:  `{"subname":"","type":"A","ttl":60,"records":["1.2.3.4"]}`,
+			status:       http.StatusOK,
+			responseBody: `{"subname":"","type":"A","ttl":60,"records":["1.2.3.4"]}`,
+		},
+		"subname_ipv6": {
+			host:         "home",
+			ip:           netip.MustParseAddr("2001:db8::1"),
+			url:          "https://desec.io/api/v1/domains/example.dedyn.io/rrsets/home/AAAA/",
+			requestBody:  `{"subname":"home","type":"AAAA","ttl":60,"records":["2001:db8::1"]}`,
+			status:       http.StatusOK,
+			responseBody: `{"subname":"home","type":"AAAA","ttl":60,"records":["2001:db8::1"]}`,
+		},
+		"rrset_not_found": {
+			host:         "home",
+			ip:           netip.MustParseAddr("1.2.3.4"),
+			url:          "https://desec.io/api/v1/domains/example.dedyn.io/rrsets/home/A/",
+			requestBody:  `{"subname":"home","type":"A","ttl":60,"records":["1.2.3.4"]}`,
+			status:       http.StatusNotFound,
+			responseBody: `{"detail":"Not found."}`,
+			errWrapped:   errors.ErrRecordNotFound,
+			errMessage:   `record not found: {"detail":"Not found."}`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(`{"token":"token","ttl":60}`), "example.dedyn.io",
+				testCase.host, ipversion.IP4or6, netip.Prefix{})
+			require.NoError(t, err)
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, http.MethodPut, r.Method)
+					assert.Equal(t, testCase.url, r.URL.String())
+					assert.Equal(t, "Token token", r.Header.Get("Authorization"))
+					body, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					assert.Equal(t, testCase.requestBody, string(body))
+					return &http.Response{
+						StatusCode: testCase.status,
+						Body:       io.NopCloser(strings.NewReader(testCase.responseBody)),
+					}, nil
+				}),
+			}
+
+			newIP, err := provider.Update(context.Background(), client, testCase.ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.ip, newIP)
+		})
+	}
+}
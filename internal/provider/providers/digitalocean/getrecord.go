@@ -0,0 +1,297 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// getRecordID obtains the record ID matching the host and record type,
+// following the pagination links until the record is found or all pages
+// are exhausted. Only the page number of the pagination links is used,
+// so the token is never sent to a host given in a response. If content
+// matching is enabled and the IP address last written by this program
+// is known, the record with this content is preferred over other records
+// sharing the same host and record type, with a fallback to the first
+// record matching the host and record type.
+// See https://docs.digitalocean.com/reference/api/api-reference/#operation/domains_list_records
+func (p *Provider) getRecordID(ctx context.Context, recordType string, client *http.Client) (
+	recordID int, err error) {
+	values := url.Values{}
+	values.Set("name", utils.BuildURLQueryHostname(p.host, p.domain))
+	values.Set("type", recordType)
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "api.digitalocean.com",
+		Path:     "/v2/domains/" + p.domain + "/records",
+		RawQuery: values.Encode(),
+	}
+
+	var content netip.Addr
+	if p.matchContent && p.knownIP.Is6() == (recordType == constants.AAAA) {
+		content = p.knownIP
+	}
+
+	var fallbackID int
+	pageURL := u.String()
+	for pageURL != "" {
+		var pageFallbackID int
+		var nextPageURL string
+		recordID, pageFallbackID, nextPageURL, err = p.getRecordIDFromPage(ctx, client,
+			pageURL, recordType, content)
+		if err != nil {
+			return 0, err
+		} else if recordID != 0 {
+			return recordID, nil
+		}
+		if fallbackID == 0 {
+			fallbackID = pageFallbackID
+		}
+		pageURL, err = makeNextPageURL(u, nextPageURL)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if fallbackID != 0 {
+		return fallbackID, nil
+	}
+	return 0, fmt.Errorf("%w", errors.ErrReceivedNoResult)
+}
+
+var errNextPageURLNotValid = stderrors.New("next page URL is not valid")
+
+// makeNextPageURL returns the URL of the next page, built from the first
+// page URL given and the page number of the next page link received.
+// It returns an empty string if the next page link is empty.
+func makeNextPageURL(firstPageURL url.URL, nextPageLink string) (
+	nextPageURL string, err error) {
+	if nextPageLink == "" {
+		return "", nil
+	}
+
+	link, err := url.Parse(nextPageLink)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errNextPageURLNotValid, err)
+	}
+	page := link.Query().Get("page")
+	pageNumber, err := strconv.Atoi(page)
+	if err != nil || pageNumber < 1 {
+		return "", fmt.Errorf("%w: page number %q is not valid: %s",
+			errNextPageURLNotValid, page, nextPageLink)
+	}
+
+	values := firstPageURL.Query()
+	values.Set("page", page)
+	firstPageURL.RawQuery = values.Encode()
+	return firstPageURL.String(), nil
+}
+
+// getRecordIDFromPage fetches a single page of records and returns the
+// ID of the first record matching the host, record type and content,
+// or 0 if no record matches on this page. The content is ignored if
+// it is the zero value. The fallback ID is the ID of the first record
+// matching only the host and record type. The next page URL is returned
+// and is empty if this page is the last one.
+// If the previous response for the page and record type had an ETag and
+// was for the same content, the page is requested conditionally and the
+// cached result is reused if the page is unchanged.
+func (p *Provider) getRecordIDFromPage(ctx context.Context, client *http.Client,
+	pageURL, recordType string, content netip.Addr) (
+	recordID, fallbackID int, nextPageURL string, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("creating http request: %w", err)
+	}
+	p.setCommonHeaders(request)
+
+	cacheKey := pageCacheKey{pageURL: pageURL, recordType: recordType}
+	p.pageCacheMutex.Lock()
+	cached, cacheHit := p.pageCache[cacheKey]
+	p.pageCacheMutex.Unlock()
+	cacheHit = cacheHit && cached.content == content
+	if cacheHit {
+		request.Header.Set("If-None-Match", cached.etag)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusOK:
+	case response.StatusCode == http.StatusNotModified && cacheHit:
+		return cached.recordID, cached.fallbackID, cached.nextPageURL, nil
+	default:
+		return 0, 0, "", errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	recordID, fallbackID, nextPageURL, err = p.decodeRecordsPage(response.Body, recordType, content)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	etag := response.Header.Get("ETag")
+	p.pageCacheMutex.Lock()
+	defer p.pageCacheMutex.Unlock()
+	switch {
+	case etag == "":
+		delete(p.pageCache, cacheKey)
+	case p.pageCache == nil:
+		p.pageCache = make(map[pageCacheKey]pageCacheEntry)
+		fallthrough
+	default:
+		p.pageCache[cacheKey] = pageCacheEntry{
+			etag:        etag,
+			content:     content,
+			recordID:    recordID,
+			fallbackID:  fallbackID,
+			nextPageURL: nextPageURL,
+		}
+	}
+
+	return recordID, fallbackID, nextPageURL, nil
+}
+
+// pageCacheKey identifies the records page requested.
+type pageCacheKey struct {
+	pageURL    string
+	recordType string
+}
+
+// pageCacheEntry is the result of a records page request for
+// a content, together with the ETag of the page response.
+type pageCacheEntry struct {
+	etag        string
+	content     netip.Addr
+	recordID    int
+	fallbackID  int
+	nextPageURL string
+}
+
+// decodeRecordsPage decodes a page of records from the reader given,
+// scanning the records one at a time so the full list is never held
+// in memory. It stops reading as soon as a matching record is found,
+// in which case the next page URL returned is empty.
+func (p *Provider) decodeRecordsPage(reader io.Reader, recordType string,
+	content netip.Addr) (recordID, fallbackID int, nextPageURL string, err error) {
+	decoder := json.NewDecoder(reader)
+	err = expectDelim(decoder, '{')
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, 0, "", err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return 0, 0, "", fmt.Errorf("%w: %v", errUnexpectedJSONToken, token)
+		}
+
+		switch key {
+		case "domain_records":
+			recordID, fallbackID, err = p.scanRecords(decoder, recordType, content)
+			if err != nil {
+				return 0, 0, "", err
+			} else if recordID != 0 {
+				return recordID, 0, "", nil
+			}
+		case "links":
+			var links struct {
+				Pages struct {
+					Next string `json:"next"`
+				} `json:"pages"`
+			}
+			err = decoder.Decode(&links)
+			if err != nil {
+				return 0, 0, "", err
+			}
+			nextPageURL = links.Pages.Next
+		default:
+			var ignored json.RawMessage
+			err = decoder.Decode(&ignored)
+			if err != nil {
+				return 0, 0, "", err
+			}
+		}
+	}
+
+	return 0, fallbackID, nextPageURL, nil
+}
+
+// scanRecords decodes the records array one record at a time and returns
+// the ID of the first record matching the host, record type and content,
+// or 0 if no record matches. The content is ignored if it is the zero
+// value. The fallback ID returned is the ID of the first record matching
+// only the host and record type. The decoder is left positioned after the
+// array only if no record matches.
+func (p *Provider) scanRecords(decoder *json.Decoder, recordType string,
+	content netip.Addr) (recordID, fallbackID int, err error) {
+	err = expectDelim(decoder, '[')
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for decoder.More() {
+		var record struct {
+			ID   int    `json:"id"`
+			Type string `json:"type"`
+			Name string `json:"name"`
+			Data string `json:"data"`
+		}
+		err = decoder.Decode(&record)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if record.Type != recordType || !utils.RecordNamesEqual(record.Name, p.host) {
+			continue
+		} else if record.ID == 0 {
+			return 0, 0, fmt.Errorf("%w", errors.ErrDomainIDNotFound)
+		}
+
+		if !content.IsValid() {
+			return record.ID, 0, nil
+		}
+		data, err := netip.ParseAddr(record.Data)
+		if err == nil && data == content {
+			return record.ID, 0, nil
+		} else if fallbackID == 0 {
+			fallbackID = record.ID
+		}
+	}
+
+	err = expectDelim(decoder, ']')
+	if err != nil {
+		return 0, 0, err
+	}
+	return 0, fallbackID, nil
+}
+
+var errUnexpectedJSONToken = stderrors.New("unexpected JSON token")
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	} else if token != delim {
+		return fmt.Errorf("%w: expected %s but got %v",
+			errUnexpectedJSONToken, delim, token)
+	}
+	return nil
+}
@@ -0,0 +1,385 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func newTestClient(pages map[string]string) *http.Client {
+	return &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			body, ok := pages[r.URL.Query().Get("page")]
+			if !ok || r.URL.Host != "api.digitalocean.com" {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(strings.NewReader("")),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+}
+
+func Test_Provider_getRecordID(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		pages        map[string]string
+		matchContent bool
+		knownIP      netip.Addr
+		recordID     int
+		errWrapped   error
+		errMessage   string
+	}{
+		"record_on_first_page": {
+			pages: map[string]string{
+				"": `{"domain_records":[{"id":1,"type":"A","name":"sub"}]}`,
+			},
+			recordID: 1,
+		},
+		"record_on_second_page": {
+			pages: map[string]string{
+				"": `{"domain_records":[{"id":1,"type":"A","name":"other"}],` +
+					`"links":{"pages":{"next":"https://api.digitalocean.com/v2/domains/example.com/records?page=2"}}}`,
+				"2": `{"domain_records":[{"id":2,"type":"A","name":"sub"}]}`,
+			},
+			recordID: 2,
+		},
+		"next_page_link_on_other_host": {
+			pages: map[string]string{
+				"": `{"domain_records":[{"id":1,"type":"A","name":"other"}],` +
+					`"links":{"pages":{"next":"http://attacker.example.com/records?page=2"}}}`,
+				"2": `{"domain_records":[{"id":2,"type":"A","name":"sub"}]}`,
+			},
+			recordID: 2,
+		},
+		"next_page_link_without_page": {
+			pages: map[string]string{
+				"": `{"domain_records":[{"id":1,"type":"A","name":"other"}],` +
+					`"links":{"pages":{"next":"https://api.digitalocean.com/v2/domains/example.com/records"}}}`,
+			},
+			errWrapped: errNextPageURLNotValid,
+			errMessage: `next page URL is not valid: page number "" is not valid: ` +
+				"https://api.digitalocean.com/v2/domains/example.com/records",
+		},
+		"record_not_found": {
+			pages: map[string]string{
+				"": `{"domain_records":[{"id":1,"type":"AAAA","name":"sub"}],` +
+					`"links":{"pages":{"next":"https://api.digitalocean.com/v2/domains/example.com/records?page=2"}}}`,
+				"2": `{"domain_records":[]}`,
+			},
+			errWrapped: errors.ErrReceivedNoResult,
+			errMessage: "received no result in response",
+		},
+		"multiple_records_without_content_matching": {
+			pages: map[string]string{
+				"": `{"domain_records":[{"id":1,"type":"A","name":"sub","data":"1.1.1.1"},` +
+					`{"id":2,"type":"A","name":"sub","data":"2.2.2.2"}]}`,
+			},
+			knownIP:  netip.MustParseAddr("2.2.2.2"),
+			recordID: 1,
+		},
+		"multiple_records_content_unknown": {
+			pages: map[string]string{
+				"": `{"domain_records":[{"id":1,"type":"A","name":"sub","data":"1.1.1.1"},` +
+					`{"id":2,"type":"A","name":"sub","data":"2.2.2.2"}]}`,
+			},
+			matchContent: true,
+			recordID:     1,
+		},
+		"multiple_records_content_matched": {
+			pages: map[string]string{
+				"": `{"domain_records":[{"id":1,"type":"A","name":"sub","data":"1.1.1.1"},` +
+					`{"id":2,"type":"A","name":"sub","data":"2.2.2.2"}]}`,
+			},
+			matchContent: true,
+			knownIP:      netip.MustParseAddr("2.2.2.2"),
+			recordID:     2,
+		},
+		"multiple_records_content_matched_on_second_page": {
+			pages: map[string]string{
+				"": `{"domain_records":[{"id":1,"type":"A","name":"sub","data":"1.1.1.1"}],` +
+					`"links":{"pages":{"next":"https://api.digitalocean.com/v2/domains/example.com/records?page=2"}}}`,
+				"2": `{"domain_records":[{"id":2,"type":"A","name":"sub","data":"2.2.2.2"}]}`,
+			},
+			matchContent: true,
+			knownIP:      netip.MustParseAddr("2.2.2.2"),
+			recordID:     2,
+		},
+		"multiple_records_content_not_found": {
+			pages: map[string]string{
+				"": `{"domain_records":[{"id":1,"type":"A","name":"sub","data":"1.1.1.1"}],` +
+					`"links":{"pages":{"next":"https://api.digitalocean.com/v2/domains/example.com/records?page=2"}}}`,
+				"2": `{"domain_records":[{"id":2,"type":"A","name":"sub","data":"2.2.2.2"}]}`,
+			},
+			matchContent: true,
+			knownIP:      netip.MustParseAddr("3.3.3.3"),
+			recordID:     1,
+		},
+		"content_of_other_ip_family_ignored": {
+			pages: map[string]string{
+				"": `{"domain_records":[{"id":1,"type":"A","name":"sub","data":"1.1.1.1"},` +
+					`{"id":2,"type":"A","name":"sub","data":"2.2.2.2"}]}`,
+			},
+			matchContent: true,
+			knownIP:      netip.MustParseAddr("::1"),
+			recordID:     1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := Provider{
+				domain:       "example.com",
+				host:         "sub",
+				token:        "token",
+				matchContent: testCase.matchContent,
+				knownIP:      testCase.knownIP,
+			}
+			client := newTestClient(testCase.pages)
+
+			recordID, err := provider.getRecordID(context.Background(), constants.A, client)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				require.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.recordID, recordID)
+		})
+	}
+}
+
+func Test_Provider_getRecordID_etag(t *testing.T) {
+	t.Parallel()
+
+	const etag = `W/"abc"`
+	const body = `{"domain_records":[{"id":1,"type":"A","name":"sub"}]}`
+
+	testCases := map[string]struct {
+		etag          string
+		ifNoneMatches []string
+	}{
+		"not_modified": {
+			etag:          etag,
+			ifNoneMatches: []string{"", etag, etag},
+		},
+		"no_etag": {
+			ifNoneMatches: []string{"", "", ""},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var ifNoneMatches []string
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					ifNoneMatch := r.Header.Get("If-None-Match")
+					ifNoneMatches = append(ifNoneMatches, ifNoneMatch)
+					response := &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{},
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}
+					if testCase.etag != "" {
+						response.Header.Set("ETag", testCase.etag)
+						if ifNoneMatch == testCase.etag {
+							response.StatusCode = http.StatusNotModified
+							response.Body = io.NopCloser(strings.NewReader(""))
+						}
+					}
+					return response, nil
+				}),
+			}
+
+			provider := Provider{
+				domain: "example.com",
+				host:   "sub",
+				token:  "token",
+			}
+
+			for range testCase.ifNoneMatches {
+				recordID, err := provider.getRecordID(context.Background(), constants.A, client)
+				require.NoError(t, err)
+				assert.Equal(t, 1, recordID)
+			}
+			assert.Equal(t, testCase.ifNoneMatches, ifNoneMatches)
+		})
+	}
+}
+
+func Test_Provider_getRecordIDFromPage_cache(t *testing.T) {
+	t.Parallel()
+
+	const etag = `W/"abc"`
+	const pageURL = "https://api.digitalocean.com/v2/domains/example.com/records"
+	const body = `{"domain_records":[{"id":1,"type":"A","name":"sub","data":"1.1.1.1"},` +
+		`{"id":2,"type":"A","name":"sub","data":"2.2.2.2"}]}`
+
+	var mutex sync.Mutex
+	var ifNoneMatches []string
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			ifNoneMatch := r.Header.Get("If-None-Match")
+			mutex.Lock()
+			ifNoneMatches = append(ifNoneMatches, ifNoneMatch)
+			mutex.Unlock()
+			response := &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Etag": []string{etag}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}
+			if ifNoneMatch == etag {
+				response.StatusCode = http.StatusNotModified
+				response.Body = io.NopCloser(strings.NewReader(""))
+			}
+			return response, nil
+		}),
+	}
+
+	provider := Provider{
+		domain: "example.com",
+		host:   "sub",
+		token:  "token",
+	}
+
+	contents := []string{"1.1.1.1", "1.1.1.1", "2.2.2.2", "2.2.2.2", "1.1.1.1"}
+	expectedRecordIDs := []int{1, 1, 2, 2, 1}
+	for i, content := range contents {
+		recordID, _, _, err := provider.getRecordIDFromPage(context.Background(), client,
+			pageURL, constants.A, netip.MustParseAddr(content))
+		require.NoError(t, err)
+		assert.Equal(t, expectedRecordIDs[i], recordID)
+	}
+	assert.Equal(t, []string{"", etag, "", etag, ""}, ifNoneMatches)
+	assert.Len(t, provider.pageCache, 1)
+
+	// Concurrent requests must not race on the cache.
+	const goroutines = 4
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		content := netip.MustParseAddr(contents[i])
+		go func() {
+			defer waitGroup.Done()
+			_, _, _, err := provider.getRecordIDFromPage(context.Background(), client,
+				pageURL, constants.A, content)
+			assert.NoError(t, err)
+		}()
+	}
+	waitGroup.Wait()
+	assert.Len(t, provider.pageCache, 1)
+}
+
+// makeRecordsPage returns a records page body with n records not
+// matching, followed by a record matching host sub and type A.
+func makeRecordsPage(n int) string {
+	var builder strings.Builder
+	builder.WriteString(`{"domain_records":[`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&builder, `{"id":%d,"type":"A","name":"other%d","data":"1.2.3.4","ttl":1800},`, i+1, i)
+	}
+	fmt.Fprintf(&builder, `{"id":%d,"type":"A","name":"sub","data":"1.2.3.4","ttl":1800}`, n+1)
+	builder.WriteString(`],"links":{},"meta":{"total":`)
+	fmt.Fprint(&builder, n+1)
+	builder.WriteString(`}}`)
+	return builder.String()
+}
+
+func Test_Provider_decodeRecordsPage(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		body        string
+		content     netip.Addr
+		recordID    int
+		fallbackID  int
+		nextPageURL string
+		errWrapped  error
+		errMessage  string
+	}{
+		"large_record_list": {
+			body:     makeRecordsPage(100000),
+			recordID: 100001,
+		},
+		"stops_at_match": {
+			body:     `{"domain_records":[{"id":1,"type":"A","name":"sub"},` + "malformed",
+			recordID: 1,
+		},
+		"links_before_records": {
+			body: `{"links":{"pages":{"next":"https://next"}},"meta":{"total":1},` +
+				`"domain_records":[{"id":1,"type":"AAAA","name":"sub"}]}`,
+			nextPageURL: "https://next",
+		},
+		"content_fallback": {
+			body: `{"domain_records":[{"id":1,"type":"A","name":"sub","data":"1.1.1.1"},` +
+				`{"id":2,"type":"A","name":"sub","data":"2.2.2.2"}]}`,
+			content:    netip.MustParseAddr("3.3.3.3"),
+			fallbackID: 1,
+		},
+		"not_an_object": {
+			body:       `[]`,
+			errWrapped: errUnexpectedJSONToken,
+			errMessage: "unexpected JSON token: expected { but got [",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := Provider{host: "sub"}
+
+			recordID, fallbackID, nextPageURL, err := provider.decodeRecordsPage(
+				strings.NewReader(testCase.body), constants.A, testCase.content)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				require.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.recordID, recordID)
+			assert.Equal(t, testCase.fallbackID, fallbackID)
+			assert.Equal(t, testCase.nextPageURL, nextPageURL)
+		})
+	}
+}
+
+func Benchmark_Provider_decodeRecordsPage(b *testing.B) {
+	body := makeRecordsPage(10000)
+	provider := Provider{host: "sub"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _, err := provider.decodeRecordsPage(strings.NewReader(body), constants.A, netip.Addr{})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
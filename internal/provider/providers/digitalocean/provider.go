@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"net/netip"
 	"net/url"
+	"strings"
+	"sync"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
@@ -23,24 +25,46 @@ type Provider struct {
 	ipVersion  ipversion.IPVersion
 	ipv6Suffix netip.Prefix
 	token      string
+	// matchContent is whether to prefer the record with the content
+	// last written by this program, if multiple records share the
+	// same host and record type.
+	matchContent bool
+	// knownIP is the IP address last written by this program,
+	// and is only set if matchContent is true.
+	knownIP netip.Addr
+	// pageCache caches the latest records page result by page URL
+	// and record type, for pages responses with an ETag.
+	pageCache      map[pageCacheKey]pageCacheEntry
+	pageCacheMutex sync.Mutex
+	// recordType is the empty string to update A and AAAA records,
+	// or TXT, MX, SRV or CAA to set the record value rendered from
+	// the value template.
+	recordType string
+	value      string
 }
 
 func New(data json.RawMessage, domain, host string,
 	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
 	p *Provider, err error) {
 	extraSettings := struct {
-		Token string `json:"token"`
+		Token        string `json:"token"`
+		MatchContent bool   `json:"match_content"`
+		RecordType   string `json:"record_type"`
+		Value        string `json:"value"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
 		return nil, err
 	}
 	p = &Provider{
-		domain:     domain,
-		host:       host,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		token:      extraSettings.Token,
+		domain:       domain,
+		host:         host,
+		ipVersion:    ipVersion,
+		ipv6Suffix:   ipv6Suffix,
+		token:        extraSettings.Token,
+		matchContent: extraSettings.MatchContent,
+		recordType:   strings.ToUpper(extraSettings.RecordType),
+		value:        extraSettings.Value,
 	}
 	err = p.isValid()
 	if err != nil {
@@ -50,8 +74,14 @@ func New(data json.RawMessage, domain, host string,
 }
 
 func (p *Provider) isValid() error {
-	if p.token == "" {
+	switch {
+	case p.token == "":
 		return fmt.Errorf("%w", errors.ErrTokenNotSet)
+	case p.recordType == "" && p.value != "":
+		return fmt.Errorf("%w: value can only be set with a record type",
+			errors.ErrRecordValueNotValid)
+	case p.recordType != "":
+		return utils.CheckRecordValue(p.recordType, p.value)
 	}
 	return nil
 }
@@ -76,10 +106,35 @@ func (p *Provider) IPv6Suffix() netip.Prefix {
 	return p.ipv6Suffix
 }
 
+// SetKnownIP sets the IP address last written by this program,
+// typically from the record history at program start.
+// It is a no-op if content matching is disabled.
+func (p *Provider) SetKnownIP(ip netip.Addr) {
+	if p.matchContent {
+		p.knownIP = ip
+	}
+}
+
 func (p *Provider) Proxied() bool {
 	return false
 }
 
+// SkipLookup returns true for TXT, MX, SRV and CAA records, since
+// the IP address cannot be resolved from their name to check if
+// an update is needed.
+func (p *Provider) SkipLookup() bool {
+	return p.recordType != ""
+}
+
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -99,56 +154,11 @@ func (p *Provider) setCommonHeaders(request *http.Request) {
 	headers.SetAuthBearer(request, p.token)
 }
 
-func (p *Provider) getRecordID(ctx context.Context, recordType string, client *http.Client) (
-	recordID int, err error) {
-	values := url.Values{}
-	values.Set("name", utils.BuildURLQueryHostname(p.host, p.domain))
-	values.Set("type", recordType)
-	u := url.URL{
-		Scheme:   "https",
-		Host:     "api.digitalocean.com",
-		Path:     "/v2/domains/" + p.domain + "/records",
-		RawQuery: values.Encode(),
-	}
-
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		return 0, fmt.Errorf("creating http request: %w", err)
-	}
-	p.setCommonHeaders(request)
-
-	response, err := client.Do(request)
-	if err != nil {
-		return 0, err
-	}
-	defer response.Body.Close()
-
-	if response.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
-	}
-
-	decoder := json.NewDecoder(response.Body)
-	var result struct {
-		DomainRecords []struct {
-			ID int `json:"id"`
-		} `json:"domain_records"`
-	}
-	err = decoder.Decode(&result)
-	if err != nil {
-		return 0, fmt.Errorf("json decoding response body: %w", err)
-	}
-
-	if len(result.DomainRecords) == 0 {
-		return 0, fmt.Errorf("%w", errors.ErrReceivedNoResult)
-	} else if result.DomainRecords[0].ID == 0 {
-		return 0, fmt.Errorf("%w", errors.ErrDomainIDNotFound)
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	if p.recordType != "" {
+		return p.updateRecordValue(ctx, client, ip)
 	}
 
-	return result.DomainRecords[0].ID, nil
-}
-
-func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
 	recordType := constants.A
 	if ip.Is6() {
 		recordType = constants.AAAA
@@ -195,8 +205,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
 	}
 
 	decoder := json.NewDecoder(response.Body)
@@ -217,5 +226,9 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
 			errors.ErrIPReceivedMismatch, ip, newIP)
 	}
+
+	if p.matchContent {
+		p.knownIP = newIP
+	}
 	return newIP, nil
 }
@@ -0,0 +1,75 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// ReadIP reads the IP address of the A record, or of the AAAA record
+// if ip is an IPv6 address, without updating it.
+// See https://docs.digitalocean.com/reference/api/api-reference/#operation/domains_get_record
+func (p *Provider) ReadIP(ctx context.Context, client *http.Client, ip netip.Addr) (
+	recordIP netip.Addr, err error) {
+	if p.recordType != "" {
+		return netip.Addr{}, fmt.Errorf("%w: cannot read the IP address of a %s record",
+			errors.ErrRecordTypeNotValid, p.recordType)
+	}
+
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+
+	recordID, err := p.getRecordID(ctx, recordType, client)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting record id: %w", err)
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.digitalocean.com",
+		Path:   "/v2/domains/" + p.domain + "/records/" + strconv.Itoa(recordID),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setCommonHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var responseData struct {
+		DomainRecord struct {
+			Data string `json:"data"`
+		} `json:"domain_record"`
+	}
+	err = decoder.Decode(&responseData)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	recordIP, err = netip.ParseAddr(responseData.DomainRecord.Data)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+	}
+	return recordIP, nil
+}
@@ -0,0 +1,79 @@
+package digitalocean
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Provider_ReadIP(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		recordType string
+		ip         netip.Addr
+		recordIP   netip.Addr
+		errWrapped error
+		errMessage string
+	}{
+		"a_record": {
+			ip:       netip.MustParseAddr("1.2.3.4"),
+			recordIP: netip.MustParseAddr("5.6.7.8"),
+		},
+		"aaaa_record": {
+			ip:       netip.MustParseAddr("::1"),
+			recordIP: netip.MustParseAddr("::2"),
+		},
+		"value_record": {
+			recordType: "TXT",
+			ip:         netip.MustParseAddr("1.2.3.4"),
+			errWrapped: errors.ErrRecordTypeNotValid,
+			errMessage: "record type is not valid: cannot read the IP address of a TXT record",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, http.MethodGet, r.Method)
+					body := `{"domain_records":[{"id":1,"type":"A","name":"sub"},` +
+						`{"id":2,"type":"AAAA","name":"sub"}]}`
+					switch r.URL.Path {
+					case "/v2/domains/example.com/records/1":
+						body = `{"domain_record":{"id":1,"type":"A","name":"sub","data":"5.6.7.8"}}`
+					case "/v2/domains/example.com/records/2":
+						body = `{"domain_record":{"id":2,"type":"AAAA","name":"sub","data":"::2"}}`
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}, nil
+				}),
+			}
+			provider := Provider{
+				domain:     "example.com",
+				host:       "sub",
+				token:      "token",
+				recordType: testCase.recordType,
+			}
+
+			recordIP, err := provider.ReadIP(context.Background(), client, testCase.ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.recordIP, recordIP)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package digitalocean
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// valueRecord is the record sent and received to set
+// the value of a TXT, MX, SRV or CAA record.
+type valueRecord struct {
+	Type     string  `json:"type"`
+	Name     string  `json:"name"`
+	Data     string  `json:"data"`
+	Priority *uint16 `json:"priority,omitempty"`
+	Port     *uint16 `json:"port,omitempty"`
+	Weight   *uint16 `json:"weight,omitempty"`
+	Flags    *uint8  `json:"flags,omitempty"`
+	Tag      string  `json:"tag,omitempty"`
+}
+
+// makeValueRecord returns the record to send for the record value given.
+func (p *Provider) makeValueRecord(value utils.RecordValue) (record valueRecord) {
+	record = valueRecord{
+		Type: value.Type,
+		Name: p.host,
+	}
+	switch value.Type {
+	case constants.TXT:
+		record.Data = value.Text
+	case constants.MX:
+		record.Data = utils.NormalizeRecordName(value.Target, true)
+		record.Priority = &value.Priority
+	case constants.SRV:
+		record.Data = utils.NormalizeRecordName(value.Target, true)
+		record.Priority = &value.Priority
+		record.Port = &value.Port
+		record.Weight = &value.Weight
+	case constants.CAA:
+		record.Data = value.CAA.Value
+		record.Flags = &value.CAA.Flags
+		record.Tag = value.CAA.Tag
+	}
+	return record
+}
+
+// matches returns true if the record received has the value of the
+// record sent, ignoring the trailing dot of MX and SRV targets.
+func (r valueRecord) matches(sent valueRecord) bool {
+	equalUint16 := func(a, b *uint16) bool {
+		return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+	}
+	data := r.Data
+	if sent.Type == constants.MX || sent.Type == constants.SRV {
+		data = utils.NormalizeRecordName(data, true)
+	}
+	return data == sent.Data &&
+		equalUint16(r.Priority, sent.Priority) &&
+		equalUint16(r.Port, sent.Port) &&
+		equalUint16(r.Weight, sent.Weight) &&
+		(sent.Flags == nil || (r.Flags != nil && *r.Flags == *sent.Flags)) &&
+		r.Tag == sent.Tag
+}
+
+// updateRecordValue sets the existing record of the record type configured
+// to the value rendered for the IP address given. The IP address given is
+// returned as is, since it is not the record data.
+// See https://docs.digitalocean.com/reference/api/api-reference/#operation/domains_update_record
+func (p *Provider) updateRecordValue(ctx context.Context, client *http.Client,
+	ip netip.Addr) (newIP netip.Addr, err error) {
+	value, err := utils.RenderRecordValue(p.recordType, p.value, ip)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("rendering value: %w", err)
+	}
+
+	recordID, err := p.getRecordID(ctx, p.recordType, client)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting record id: %w", err)
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.digitalocean.com",
+		Path:   fmt.Sprintf("/v2/domains/%s/records/%d", p.domain, recordID),
+	}
+
+	requestData := p.makeValueRecord(value)
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	err = encoder.Encode(requestData)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), buffer)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setCommonHeaders(request)
+	headers.SetContentType(request, "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var responseData struct {
+		DomainRecord valueRecord `json:"domain_record"`
+	}
+	err = decoder.Decode(&responseData)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	if !responseData.DomainRecord.matches(requestData) {
+		return netip.Addr{}, fmt.Errorf("%w: sent %s record value %q but received data %q",
+			errors.ErrDataReceivedMismatch, value.Type, value, responseData.DomainRecord.Data)
+	}
+	return ip, nil
+}
@@ -0,0 +1,95 @@
+package digitalocean
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update_value(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		recordType   string
+		value        string
+		sent         string
+		responseBody string
+		errWrapped   error
+		errMessage   string
+	}{
+		"srv": {
+			recordType: "SRV",
+			value:      "10 5 5060 sip.example.com",
+			sent: `{"type":"SRV","name":"www","data":"sip.example.com.",` +
+				`"priority":10,"port":5060,"weight":5}`,
+			responseBody: `{"domain_record":{"id":1,"type":"SRV","name":"www",` +
+				`"data":"sip.example.com","priority":10,"port":5060,"weight":5}}`,
+		},
+		"txt_mismatch": {
+			recordType: "TXT",
+			value:      "v=spf1 ip4:{ip} -all",
+			sent:       `{"type":"TXT","name":"www","data":"v=spf1 ip4:1.2.3.4 -all"}`,
+			responseBody: `{"domain_record":{"id":1,"type":"TXT","name":"www",` +
+				`"data":"v=spf1 -all"}}`,
+			errWrapped: errors.ErrDataReceivedMismatch,
+			errMessage: "mismatching record data received: sent TXT record value " +
+				`"v=spf1 ip4:1.2.3.4 -all" but received data "v=spf1 -all"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					var body string
+					switch r.Method {
+					case http.MethodGet:
+						assert.Equal(t, testCase.recordType, r.URL.Query().Get("type"))
+						body = `{"domain_records":[{"id":1,"type":"` + testCase.recordType +
+							`","name":"www","data":"old"}],"links":{}}`
+					case http.MethodPut:
+						assert.Equal(t, "/v2/domains/example.com/records/1", r.URL.Path)
+						requestBody, err := io.ReadAll(r.Body)
+						require.NoError(t, err)
+						assert.JSONEq(t, testCase.sent, string(requestBody))
+						body = testCase.responseBody
+					default:
+						t.Errorf("unexpected method %s", r.Method)
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}, nil
+				}),
+			}
+
+			provider := &Provider{
+				domain:     "example.com",
+				host:       "www",
+				token:      "token",
+				recordType: testCase.recordType,
+				value:      testCase.value,
+			}
+			ip := netip.MustParseAddr("1.2.3.4")
+
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, ip, newIP)
+		})
+	}
+}
@@ -91,6 +91,15 @@ func (p *Provider) Proxied() bool {
 	return p.host == "all"
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -134,9 +143,11 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	headers.SetUserAgent(request)
 
-	response, err := client.Do(request)
+	response, verified, err := utils.DoDynDNS2Request(ctx, client, request, p.BuildDomainName(), ip)
 	if err != nil {
 		return netip.Addr{}, err
+	} else if verified {
+		return ip, nil
 	}
 	defer response.Body.Close()
 
@@ -147,8 +158,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	s := string(b)
 
 	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, s)
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, s)
 	}
 
 	switch s {
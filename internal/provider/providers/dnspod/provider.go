@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"net/netip"
 	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
@@ -24,6 +26,9 @@ type Provider struct {
 	ipVersion  ipversion.IPVersion
 	ipv6Suffix netip.Prefix
 	token      string
+	// line is the resolution line of the record, and
+	// the empty string means the first record found is used.
+	line string
 }
 
 func New(data json.RawMessage, domain, host string,
@@ -31,6 +36,8 @@ func New(data json.RawMessage, domain, host string,
 	p *Provider, err error) {
 	extraSettings := struct {
 		Token string `json:"token"`
+		View  string `json:"view"`
+		Line  string `json:"line"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
@@ -42,6 +49,10 @@ func New(data json.RawMessage, domain, host string,
 		ipVersion:  ipVersion,
 		ipv6Suffix: ipv6Suffix,
 		token:      extraSettings.Token,
+		line:       extraSettings.View,
+	}
+	if p.line == "" {
+		p.line = extraSettings.Line
 	}
 	err = p.isValid()
 	if err != nil {
@@ -50,9 +61,20 @@ func New(data json.RawMessage, domain, host string,
 	return p, nil
 }
 
+// lines are the resolution lines available for all DNSPod plans.
+// See https://docs.dnspod.cn/api/record-line/
+func lines() []string {
+	return []string{"默认", "境内", "境外", "电信", "联通", "移动", "铁通", "广电",
+		"教育网", "鹏博士", "搜索引擎", "百度", "谷歌", "必应", "搜狗", "奇虎", "有道", "搜搜"}
+}
+
 func (p *Provider) isValid() error {
-	if p.token == "" {
+	switch {
+	case p.token == "":
 		return fmt.Errorf("%w", errors.ErrTokenNotSet)
+	case p.line != "" && !slices.Contains(lines(), p.line):
+		return fmt.Errorf("%w: %q must be one of %s",
+			errors.ErrLineNotValid, p.line, strings.Join(lines(), ", "))
 	}
 	return nil
 }
@@ -81,6 +103,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true, View: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -118,6 +149,9 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	values.Set("length", "200")
 	values.Set("sub_domain", p.host)
 	values.Set("record_type", recordType)
+	if p.line != "" {
+		values.Set("record_line", p.line)
+	}
 	encodedValues := values.Encode()
 	buffer := bytes.NewBufferString(encodedValues)
 
@@ -134,8 +168,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
 	}
 
 	decoder := json.NewDecoder(response.Body)
@@ -155,7 +188,8 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 
 	var recordID, recordLine string
 	for _, record := range recordResp.Records {
-		if record.Type == recordType && record.Name == p.host {
+		if record.Type == recordType && utils.RecordNamesEqual(record.Name, p.host) &&
+			(p.line == "" || record.Line == p.line) {
 			receivedIP, err := netip.ParseAddr(record.Value)
 			if err == nil && ip.Compare(receivedIP) == 0 {
 				return ip, nil
@@ -194,8 +228,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
 	}
 
 	data, err := io.ReadAll(response.Body)
@@ -0,0 +1,109 @@
+package dnspod
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_New_line(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		line       string
+		errWrapped error
+		errMessage string
+	}{
+		"no_line": {
+			data: `{"token":"token"}`,
+		},
+		"view": {
+			data: `{"token":"token","view":"电信"}`,
+			line: "电信",
+		},
+		"line_alias": {
+			data: `{"token":"token","line":"联通"}`,
+			line: "联通",
+		},
+		"unknown_line": {
+			data:       `{"token":"token","line":"telecom"}`,
+			errWrapped: errors.ErrLineNotValid,
+			errMessage: `line is not valid: "telecom" must be one of ` + strings.Join(lines(), ", "),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(testCase.data), "example.com", "@", 0, netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.line, provider.line)
+		})
+	}
+}
+
+func Test_Provider_Update_line(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			paths = append(paths, r.URL.Path)
+			err := r.ParseForm()
+			require.NoError(t, err)
+			assert.Equal(t, "电信", r.PostForm.Get("record_line"))
+
+			var body string
+			switch r.URL.Path {
+			case "/Record.List":
+				body = `{"records":[` +
+					`{"id":"1","value":"5.6.7.8","type":"A","name":"@","line":"默认"},` +
+					`{"id":"2","value":"5.6.7.8","type":"A","name":"@","line":"电信"}]}`
+			case "/Record.Ddns":
+				assert.Equal(t, "2", r.PostForm.Get("record_id"))
+				body = `{"record":{"id":2,"value":"1.2.3.4","name":"@"}}`
+			default:
+				t.Errorf("unexpected path %s", r.URL.Path)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	provider := &Provider{
+		domain: "example.com",
+		host:   "@",
+		token:  "token",
+		line:   "电信",
+	}
+
+	newIP, err := provider.Update(context.Background(), client, netip.MustParseAddr("1.2.3.4"))
+
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("1.2.3.4"), newIP)
+	assert.Equal(t, []string{"/Record.List", "/Record.Ddns"}, paths)
+}
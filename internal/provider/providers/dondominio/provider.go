@@ -32,7 +32,6 @@ func New(data json.RawMessage, domain, host string,
 	p *Provider, err error) {
 	extraSettings := struct {
 		Username string `json:"username"`
-		Password string `json:"password"` // retro-compatibility
 		Key      string `json:"key"`
 		Name     string `json:"name"`
 	}{}
@@ -43,9 +42,6 @@ func New(data json.RawMessage, domain, host string,
 	if host == "" {
 		host = "@" // default
 	}
-	if extraSettings.Password != "" { // retro-compatibility
-		extraSettings.Key = extraSettings.Password
-	}
 
 	p = &Provider{
 		domain:     domain,
@@ -99,6 +95,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -19,39 +19,36 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	host       string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	username   string
-	clientKey  string
+	domain        string
+	host          string
+	ipVersion     ipversion.IPVersion
+	ipv6Suffix    netip.Prefix
+	username      string
+	clientKey     string
+	useProviderIP bool
 }
 
 func New(data json.RawMessage, domain, host string,
 	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
 	p *Provider, err error) {
 	extraSettings := struct {
-		Username  string `json:"username"`
-		Password  string `json:"password"` // Retro-compatibility
-		ClientKey string `json:"client_key"`
+		Username      string `json:"username"`
+		ClientKey     string `json:"client_key"`
+		UseProviderIP bool   `json:"provider_ip"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
 		return nil, err
 	}
 
-	clientKey := extraSettings.ClientKey
-	if clientKey == "" { // Retro-compatibility try
-		clientKey = extraSettings.Password
-	}
-
 	p = &Provider{
-		domain:     domain,
-		host:       host,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		username:   extraSettings.Username,
-		clientKey:  clientKey,
+		domain:        domain,
+		host:          host,
+		ipVersion:     ipVersion,
+		ipv6Suffix:    ipv6Suffix,
+		username:      extraSettings.Username,
+		clientKey:     extraSettings.ClientKey,
+		useProviderIP: extraSettings.UseProviderIP,
 	}
 	err = p.isValid()
 	if err != nil {
@@ -94,6 +91,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -117,7 +123,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	values := url.Values{}
 	values.Set("hostname", utils.BuildURLQueryHostname(p.host, p.domain))
-	values.Set("myip", ip.String())
+	providerIPUsed := utils.SetDynDNS2MyIP(values, ip, p.useProviderIP, p.ipv6Suffix)
 	u.RawQuery = values.Encode()
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
@@ -126,9 +132,11 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	headers.SetUserAgent(request)
 
-	response, err := client.Do(request)
+	response, verified, err := utils.DoDynDNS2Request(ctx, client, request, p.BuildDomainName(), ip)
 	if err != nil {
 		return netip.Addr{}, err
+	} else if verified {
+		return ip, nil
 	}
 	defer response.Body.Close()
 
@@ -139,8 +147,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	s := string(b)
 
 	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.ToSingleLine(s))
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.ToSingleLine(s))
 	}
 
 	switch {
@@ -149,7 +156,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	case strings.HasPrefix(s, "badrequest"):
 		return netip.Addr{}, fmt.Errorf("%w", errors.ErrBadRequest)
 	case strings.HasPrefix(s, "good"):
-		return ip, nil
+		return utils.DynDNS2ResponseIP(s, ip, providerIPUsed)
 	default:
 		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrUnknownResponse, s)
 	}
@@ -85,6 +85,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -141,6 +150,5 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	if response.StatusCode == http.StatusOK {
 		return ip, nil
 	}
-	return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-		errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
 }
@@ -90,6 +90,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -113,10 +122,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	values := url.Values{}
 	values.Set("hostname", utils.BuildURLQueryHostname(p.host, p.domain))
-	useProviderIP := p.useProviderIP && (ip.Is4() || !p.ipv6Suffix.IsValid())
-	if !useProviderIP {
-		values.Set("myip", ip.String())
-	}
+	providerIPUsed := utils.SetDynDNS2MyIP(values, ip, p.useProviderIP, p.ipv6Suffix)
 	if p.host == "*" {
 		values.Set("wildcard", "ON")
 	}
@@ -128,9 +134,11 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	headers.SetUserAgent(request)
 
-	response, err := client.Do(request)
+	response, verified, err := utils.DoDynDNS2Request(ctx, client, request, p.BuildDomainName(), ip)
 	if err != nil {
 		return netip.Addr{}, err
+	} else if verified {
+		return ip, nil
 	}
 	defer response.Body.Close()
 
@@ -141,8 +149,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	s := string(b)
 
 	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
-			response.StatusCode, utils.ToSingleLine(s))
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.ToSingleLine(s))
 	}
 
 	switch {
@@ -155,7 +162,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	case strings.Contains(s, "ILLEGAL_INPUT"), strings.Contains(s, "TOO_SOON"):
 		return netip.Addr{}, fmt.Errorf("%w", errors.ErrBannedAbuse)
 	case strings.Contains(s, "NO_ERROR"), strings.Contains(s, "OK"):
-		return ip, nil
+		return utils.DynDNS2ResponseIP(s, ip, providerIPUsed)
 	default:
 		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrUnknownResponse, utils.ToSingleLine(s))
 	}
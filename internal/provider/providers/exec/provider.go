@@ -0,0 +1,218 @@
+// Package exec implements a provider running a user supplied program
+// to update records, to support DNS providers not supported natively.
+package exec
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	osexec "os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+const (
+	// InputArgs gives the domain, host, record type and IP address
+	// to the program as its last four arguments.
+	InputArgs = "args"
+	// InputJSON gives the domain, host, FQDN, record type and IP
+	// address to the program as a JSON object on its standard input.
+	InputJSON = "json"
+)
+
+type Provider struct {
+	domain     string
+	host       string
+	ipVersion  ipversion.IPVersion
+	ipv6Suffix netip.Prefix
+	args       []string
+	input      string
+	timeout    time.Duration
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	p *Provider, err error) {
+	extraSettings := struct {
+		Command string `json:"command"`
+		Input   string `json:"input"`
+	}{}
+	err = json.Unmarshal(data, &extraSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	input := strings.ToLower(extraSettings.Input)
+	if input == "" {
+		input = InputArgs
+	}
+
+	const timeout = time.Minute
+	p = &Provider{
+		domain:     domain,
+		host:       host,
+		ipVersion:  ipVersion,
+		ipv6Suffix: ipv6Suffix,
+		args:       strings.Fields(extraSettings.Command),
+		input:      input,
+		timeout:    timeout,
+	}
+	err = p.isValid()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case len(p.args) == 0:
+		return fmt.Errorf("%w", errors.ErrCommandNotSet)
+	case p.input != InputArgs && p.input != InputJSON:
+		return fmt.Errorf("%w: %q can only be %q or %q",
+			errors.ErrInputNotValid, p.input, InputArgs, InputJSON)
+	}
+	_, err := osexec.LookPath(p.args[0])
+	if err != nil {
+		return fmt.Errorf("command: %w", err)
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Exec, p.ipVersion)
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) IPv6Suffix() netip.Prefix {
+	return p.ipv6Suffix
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName()),
+		Host:      p.Host(),
+		Provider:  fmt.Sprintf("%s: %s", constants.Exec, filepath.Base(p.args[0])),
+		IPVersion: p.ipVersion.String(),
+	}
+}
+
+// Update runs the program to update the record to the IP address given,
+// giving it the record details as arguments or as JSON on its standard
+// input. The update is successful if the program exits with code 0. If
+// the last line of its standard output is an IP address, it must be the
+// IP address given. The HTTP client is not used.
+func (p *Provider) Update(ctx context.Context, _ *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	args := p.args[1:]
+	var stdin bytes.Buffer
+	switch p.input {
+	case InputArgs:
+		args = append(args[:len(args):len(args)], p.domain, p.host, recordType, ip.String())
+	case InputJSON:
+		input := struct {
+			Domain     string `json:"domain"`
+			Host       string `json:"host"`
+			FQDN       string `json:"fqdn"`
+			RecordType string `json:"record_type"`
+			IP         string `json:"ip"`
+		}{
+			Domain:     p.domain,
+			Host:       p.host,
+			FQDN:       p.BuildDomainName(),
+			RecordType: recordType,
+			IP:         ip.String(),
+		}
+		err = json.NewEncoder(&stdin).Encode(input)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("json encoding input: %w", err)
+		}
+	}
+
+	cmd := osexec.CommandContext(ctx, p.args[0], args...) //nolint:gosec
+	cmd.Stdin = &stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Do not wait indefinitely for child processes keeping the output open
+	const waitDelay = time.Second
+	cmd.WaitDelay = waitDelay
+
+	err = cmd.Run()
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("%w: %w", err, ctx.Err())
+		}
+		output := stderr.String()
+		if strings.TrimSpace(output) == "" {
+			output = stdout.String()
+		}
+		return netip.Addr{}, fmt.Errorf("%w: %w: %s", errors.ErrUnsuccessful,
+			err, utils.ToSingleLine(strings.TrimSpace(output)))
+	}
+
+	receivedIP, err := netip.ParseAddr(lastLine(stdout.Bytes()))
+	if err == nil && receivedIP.Unmap() != ip {
+		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
+			errors.ErrIPReceivedMismatch, ip, receivedIP)
+	}
+	return ip, nil
+}
+
+// lastLine returns the last non empty line of the output given, trimmed.
+func lastLine(output []byte) (line string) {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text != "" {
+			line = text
+		}
+	}
+	return line
+}
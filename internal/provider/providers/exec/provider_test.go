@@ -0,0 +1,177 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_helperProcess is not a real test, it is the program run by the
+// provider in the tests below, behaving according to its first argument.
+func Test_helperProcess(t *testing.T) {
+	t.Parallel()
+
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	if len(args) < 2 { //nolint:gomnd
+		return // not run by a test
+	}
+	mode, args := args[1], args[2:]
+
+	switch mode {
+	case "echo_args":
+		fmt.Println("updating")
+		fmt.Println(args[len(args)-1])
+	case "echo_json":
+		var input struct {
+			FQDN string `json:"fqdn"`
+			IP   string `json:"ip"`
+		}
+		err := json.NewDecoder(os.Stdin).Decode(&input)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(input.IP)
+	case "print_args":
+		data, _ := io.ReadAll(os.Stdin)
+		fmt.Fprint(os.Stderr, strings.TrimSpace(strings.Join(args, " ")+" "+string(data)))
+		os.Exit(1)
+	case "wrong_ip":
+		fmt.Println("1.2.3.4")
+	case "fail":
+		fmt.Fprintln(os.Stderr, "record not found")
+		os.Exit(2) //nolint:gomnd
+	}
+	os.Exit(0)
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+		errMessage string
+	}{
+		"command_not_set": {
+			data:       `{}`,
+			errWrapped: errors.ErrCommandNotSet,
+			errMessage: "command is not set",
+		},
+		"input_not_valid": {
+			data:       `{"command":"go","input":"xml"}`,
+			errWrapped: errors.ErrInputNotValid,
+			errMessage: `input is not valid: "xml" can only be "args" or "json"`,
+		},
+		"command_not_found": {
+			data:       `{"command":"/nonexistent/program"}`,
+			errWrapped: os.ErrNotExist,
+			errMessage: `command: exec: "/nonexistent/program": stat /nonexistent/program: no such file or directory`,
+		},
+		"valid": {
+			data: fmt.Sprintf(`{"command":%q,"input":"JSON"}`, os.Args[0]),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "example.com", "@",
+				ipversion.IP4or6, netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		mode       string
+		input      string
+		ip         netip.Addr
+		errWrapped error
+		errMessage string
+	}{
+		"args_success": {
+			mode:  "echo_args",
+			input: InputArgs,
+			ip:    netip.MustParseAddr("192.0.2.1"),
+		},
+		"json_success": {
+			mode:  "echo_json",
+			input: InputJSON,
+			ip:    netip.MustParseAddr("2001:db8::1"),
+		},
+		"args_given": {
+			mode:       "print_args",
+			input:      InputArgs,
+			ip:         netip.MustParseAddr("2001:db8::1"),
+			errWrapped: errors.ErrUnsuccessful,
+			errMessage: "unsuccessful result: exit status 1: example.com home AAAA 2001:db8::1",
+		},
+		"json_given": {
+			mode:       "print_args",
+			input:      InputJSON,
+			ip:         netip.MustParseAddr("192.0.2.1"),
+			errWrapped: errors.ErrUnsuccessful,
+			errMessage: `unsuccessful result: exit status 1: {"domain":"example.com","host":"home",` +
+				`"fqdn":"home.example.com","record_type":"A","ip":"192.0.2.1"}`,
+		},
+		"ip_mismatch": {
+			mode:       "wrong_ip",
+			input:      InputArgs,
+			ip:         netip.MustParseAddr("192.0.2.1"),
+			errWrapped: errors.ErrIPReceivedMismatch,
+			errMessage: "mismatching IP address received: sent ip 192.0.2.1 to update but received 1.2.3.4",
+		},
+		"failure": {
+			mode:       "fail",
+			input:      InputArgs,
+			ip:         netip.MustParseAddr("192.0.2.1"),
+			errWrapped: errors.ErrUnsuccessful,
+			errMessage: "unsuccessful result: exit status 2: record not found",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			command := os.Args[0] + " -test.run=^Test_helperProcess$ -- " + testCase.mode
+			data := fmt.Sprintf(`{"command":%q,"input":%q}`, command, testCase.input)
+			provider, err := New(json.RawMessage(data), "example.com", "home",
+				ipversion.IP4or6, netip.Prefix{})
+			require.NoError(t, err)
+
+			newIP, err := provider.Update(context.Background(), nil, testCase.ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.ip, newIP)
+		})
+	}
+}
@@ -30,15 +30,19 @@ type Provider struct {
 	// apiKey is deprecated so personalAccessToken should be used
 	// instead.
 	apiKey string
+	// staticIPs are IP addresses always included
+	// in the record set, after the public IP address.
+	staticIPs []netip.Addr
 }
 
 func New(data json.RawMessage, domain, host string,
 	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
 	p *Provider, err error) {
 	extraSettings := struct {
-		PersonalAccessToken string `json:"personal_access_token"`
-		APIKey              string `json:"key"`
-		TTL                 int    `json:"ttl"`
+		PersonalAccessToken string       `json:"personal_access_token"`
+		APIKey              string       `json:"key"`
+		TTL                 utils.TTL    `json:"ttl"`
+		StaticIPs           []netip.Addr `json:"static_ips"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
@@ -51,7 +55,8 @@ func New(data json.RawMessage, domain, host string,
 		ipv6Suffix:          ipv6Suffix,
 		personalAccessToken: extraSettings.PersonalAccessToken,
 		apiKey:              extraSettings.APIKey,
-		ttl:                 extraSettings.TTL,
+		ttl:                 int(extraSettings.TTL),
+		staticIPs:           extraSettings.StaticIPs,
 	}
 	err = p.isValid()
 	if err != nil {
@@ -64,7 +69,8 @@ func (p *Provider) isValid() error {
 	if p.apiKey == "" && p.personalAccessToken == "" {
 		return fmt.Errorf("%w: API key and personal access token not set", errors.ErrKeyNotSet)
 	}
-	return nil
+	const minTTL, maxTTL = 300, 2592000
+	return utils.CheckTTL(utils.TTL(p.ttl), minTTL, maxTTL)
 }
 
 func (p *Provider) String() string {
@@ -91,6 +97,20 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{
+		IPv4:      true,
+		IPv6:      true,
+		TTL:       true,
+		StaticIPs: true,
+	}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -110,10 +130,17 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		recordType = constants.AAAA
 	}
 
+	// See https://api.gandi.net/docs/livedns/#put-v5-livedns-domains-fqdn-records-rrset_name-rrset_type
 	u := url.URL{
 		Scheme: "https",
-		Host:   "dns.api.gandi.net",
-		Path:   fmt.Sprintf("/api/v5/domains/%s/records/%s/%s", p.domain, p.host, recordType),
+		Host:   "api.gandi.net",
+		Path:   fmt.Sprintf("/v5/livedns/domains/%s/records/%s/%s", p.domain, p.host, recordType),
+	}
+
+	recordSet := utils.MakeRecordSet(ip, p.staticIPs)
+	values := make([]string, len(recordSet))
+	for i, recordSetIP := range recordSet {
+		values[i] = recordSetIP.String()
 	}
 
 	buffer := bytes.NewBuffer(nil)
@@ -124,10 +151,10 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		ttl = p.ttl
 	}
 	requestData := struct {
-		Values [1]string `json:"rrset_values"`
-		TTL    int       `json:"rrset_ttl"`
+		Values []string `json:"rrset_values"`
+		TTL    int      `json:"rrset_ttl"`
 	}{
-		Values: [1]string{ip.Unmap().String()},
+		Values: values,
 		TTL:    ttl,
 	}
 	err = encoder.Encode(requestData)
@@ -139,26 +166,73 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	if err != nil {
 		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
 	}
-	headers.SetUserAgent(request)
+	p.setHeaders(request)
 	headers.SetContentType(request, "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("doing http request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusCreated {
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	if len(p.staticIPs) == 0 {
+		return ip, nil
+	}
+
+	receivedValues, err := p.getRecordSetValues(ctx, client, u.String())
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting record set values: %w", err)
+	}
+
+	err = utils.CheckRecordSet(recordSet, receivedValues)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return ip, nil
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
 	headers.SetAccept(request, "application/json")
 	if p.personalAccessToken != "" {
 		request.Header.Set("Authorization", "Bearer "+p.personalAccessToken)
 	} else {
 		// Note the API key is deprecated.
-		request.Header.Set("X-Api-Key", p.apiKey)
+		request.Header.Set("Authorization", "Apikey "+p.apiKey)
 	}
+}
+
+// getRecordSetValues returns the values of the record set at the given URL.
+// See https://api.gandi.net/docs/livedns/#get-v5-livedns-domains-fqdn-records-rrset_name-rrset_type
+func (p *Provider) getRecordSetValues(ctx context.Context, client *http.Client,
+	url string) (values []string, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
 
 	response, err := client.Do(request)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("doing http request: %w", err)
+		return nil, fmt.Errorf("doing http request: %w", err)
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusCreated {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
 	}
 
-	return ip, nil
+	var data struct {
+		Values []string `json:"rrset_values"`
+	}
+	decoder := json.NewDecoder(response.Body)
+	err = decoder.Decode(&data)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+	return data.Values, nil
 }
@@ -0,0 +1,93 @@
+package gandi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings      string
+		authorization string
+		requestBody   string
+		status        int
+		errWrapped    error
+		errMessage    string
+	}{
+		"personal_access_token": {
+			settings:      `{"personal_access_token":"token"}`,
+			authorization: "Bearer token",
+			requestBody:   `{"rrset_values":["1.2.3.4"],"rrset_ttl":3600}`,
+			status:        http.StatusCreated,
+		},
+		"api_key_and_ttl": {
+			settings:      `{"key":"key","ttl":"10m"}`,
+			authorization: "Apikey key",
+			requestBody:   `{"rrset_values":["1.2.3.4"],"rrset_ttl":600}`,
+			status:        http.StatusCreated,
+		},
+		"bad_status": {
+			settings:      `{"personal_access_token":"token"}`,
+			authorization: "Bearer token",
+			requestBody:   `{"rrset_values":["1.2.3.4"],"rrset_ttl":3600}`,
+			status:        http.StatusForbidden,
+			errWrapped:    errors.ErrHTTPStatusNotValid,
+			errMessage:    "HTTP status is not valid: 403: denied",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(testCase.settings), "example.com", "www",
+				ipversion.IP4, netip.Prefix{})
+			require.NoError(t, err)
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, http.MethodPut, r.Method)
+					assert.Equal(t, "https://api.gandi.net/v5/livedns/domains/example.com/records/www/A",
+						r.URL.String())
+					assert.Equal(t, testCase.authorization, r.Header.Get("Authorization"))
+					b, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					assert.JSONEq(t, testCase.requestBody, string(b))
+					return &http.Response{
+						StatusCode: testCase.status,
+						Body:       io.NopCloser(strings.NewReader("denied")),
+					}, nil
+				}),
+			}
+
+			ip := netip.MustParseAddr("1.2.3.4")
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, ip, newIP)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package gcp
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/netip"
 
 	"github.com/qdm12/ddns-updater/internal/models"
@@ -20,6 +21,10 @@ type Provider struct {
 	project     string
 	zone        string
 	credentials json.RawMessage
+	// ttl is the record TTL in seconds, and is zero to
+	// use the default TTL for new records and to keep the
+	// TTL of existing records.
+	ttl uint32
 }
 
 func New(data json.RawMessage, domain, host string,
@@ -29,6 +34,7 @@ func New(data json.RawMessage, domain, host string,
 		Project     string          `json:"project"`
 		Zone        string          `json:"zone"`
 		Credentials json.RawMessage `json:"credentials"`
+		TTL         utils.TTL       `json:"ttl"`
 	}
 
 	err = json.Unmarshal(data, &extraSettings)
@@ -44,6 +50,7 @@ func New(data json.RawMessage, domain, host string,
 		project:     extraSettings.Project,
 		zone:        extraSettings.Zone,
 		credentials: extraSettings.Credentials,
+		ttl:         uint32(extraSettings.TTL),
 	}
 
 	err = p.isValid()
@@ -67,7 +74,7 @@ func (p *Provider) isValid() error {
 		return fmt.Errorf("%w", ddnserrors.ErrCredentialsNotSet)
 	}
 
-	return nil
+	return utils.CheckTTL(utils.TTL(p.ttl), 0, math.MaxInt32)
 }
 
 func (p *Provider) String() string {
@@ -94,6 +101,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true, CreateMissing: true, TTL: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
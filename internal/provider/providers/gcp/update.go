@@ -9,6 +9,7 @@ import (
 
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
 	ddnserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
 	clouddns "google.golang.org/api/dns/v1"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
@@ -28,7 +29,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	rrSetsService := clouddns.NewResourceRecordSetsService(ddnsService)
 
-	fqdn := fmt.Sprintf("%s.%s.", p.host, p.domain)
+	fqdn := utils.BuildURLQueryHostname(p.host, p.domain) + "."
 
 	recordResourceSet, err := p.getResourceRecordSet(rrSetsService, fqdn, recordType)
 	rrSetFound := true
@@ -40,21 +41,26 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		}
 	}
 
-	for _, rrdata := range recordResourceSet.Rrdatas {
-		if rrdata == ip.String() {
-			// already up to date
-			return ip, nil
-		}
-	}
-
 	if !rrSetFound {
+		if !utils.CreationAllowed(ctx) {
+			return netip.Addr{}, fmt.Errorf("%w", ddnserrors.ErrRecordNotFound)
+		}
 		err = p.createRecord(rrSetsService, fqdn, recordType, ip)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
 		}
+		utils.SignalCreated(ctx)
 		return ip, nil
 	}
 
+	ttlUpToDate := p.ttl == 0 || recordResourceSet.Ttl == int64(p.ttl)
+	for _, rrdata := range recordResourceSet.Rrdatas {
+		if rrdata == ip.String() && ttlUpToDate {
+			// already up to date
+			return ip, nil
+		}
+	}
+
 	err = p.updateRecord(rrSetsService, fqdn, recordType, ip)
 	if err != nil {
 		return netip.Addr{}, fmt.Errorf("updating record: %w", err)
@@ -84,6 +90,7 @@ func (p *Provider) createRecord(rrSetsService *clouddns.ResourceRecordSetsServic
 		Name:    fqdn,
 		Rrdatas: []string{ip.String()},
 		Type:    recordType,
+		Ttl:     int64(p.ttl),
 	}
 	rrSetCall := rrSetsService.Create(p.project, p.zone, rrSet)
 	_, err = rrSetCall.Do()
@@ -96,6 +103,7 @@ func (p *Provider) updateRecord(rrSetsService *clouddns.ResourceRecordSetsServic
 		Name:    fqdn,
 		Rrdatas: []string{ip.String()},
 		Type:    recordType,
+		Ttl:     int64(p.ttl),
 	}
 	rrSetCall := rrSetsService.Patch(p.project, p.zone, fqdn, recordType, rrSet)
 	_, err = rrSetCall.Do()
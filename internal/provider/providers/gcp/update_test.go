@@ -0,0 +1,88 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	const rrSetPath = "/dns/v1/projects/project/managedZones/zone/rrsets/example.com./A"
+
+	testCases := map[string]struct {
+		ttl        string
+		existing   string
+		patchBody  string
+		patchCalls int
+	}{
+		"up_to_date": {
+			existing: `{"name":"example.com.","type":"A","ttl":300,"rrdatas":["1.2.3.4"]}`,
+		},
+		"ip_changed": {
+			existing:   `{"name":"example.com.","type":"A","ttl":300,"rrdatas":["5.6.7.8"]}`,
+			patchBody:  `{"name":"example.com.","rrdatas":["1.2.3.4"],"type":"A"}`,
+			patchCalls: 1,
+		},
+		"ttl_changed": {
+			ttl:        `,"ttl":"10m"`,
+			existing:   `{"name":"example.com.","type":"A","ttl":300,"rrdatas":["1.2.3.4"]}`,
+			patchBody:  `{"name":"example.com.","rrdatas":["1.2.3.4"],"ttl":600,"type":"A"}`,
+			patchCalls: 1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := `{"project":"project","zone":"zone","credentials":{}` + testCase.ttl + `}`
+			provider, err := New(json.RawMessage(settings), "example.com", "@",
+				ipversion.IP4, netip.Prefix{})
+			require.NoError(t, err)
+
+			patchCalls := 0
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, rrSetPath, r.URL.Path)
+					body := testCase.existing
+					if r.Method == http.MethodPatch {
+						patchCalls++
+						b, err := io.ReadAll(r.Body)
+						require.NoError(t, err)
+						assert.JSONEq(t, testCase.patchBody, string(b))
+						body = string(b)
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{"Content-Type": []string{"application/json"}},
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}, nil
+				}),
+			}
+
+			ip := netip.MustParseAddr("1.2.3.4")
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			require.NoError(t, err)
+			assert.Equal(t, ip, newIP)
+			assert.Equal(t, testCase.patchCalls, patchCalls)
+		})
+	}
+}
@@ -52,8 +52,7 @@ func (p *Provider) getRecordID(ctx context.Context, client *http.Client, ip neti
 	case http.StatusNotFound:
 		return "", false, fmt.Errorf("%w", errors.ErrReceivedNoResult)
 	default:
-		return "", false, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+		return "", false, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
 	}
 
 	decoder := json.NewDecoder(response.Body)
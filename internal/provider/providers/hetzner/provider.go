@@ -29,9 +29,9 @@ func New(data json.RawMessage, domain, host string,
 	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
 	p *Provider, err error) {
 	extraSettings := struct {
-		Token          string `json:"token"`
-		ZoneIdentifier string `json:"zone_identifier"`
-		TTL            uint   `json:"ttl"`
+		Token          string    `json:"token"`
+		ZoneIdentifier string    `json:"zone_identifier"`
+		TTL            utils.TTL `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
@@ -44,7 +44,7 @@ func New(data json.RawMessage, domain, host string,
 		ipv6Suffix:     ipv6Suffix,
 		token:          extraSettings.Token,
 		zoneIdentifier: extraSettings.ZoneIdentifier,
-		ttl:            extraSettings.TTL,
+		ttl:            uint(extraSettings.TTL),
 	}
 	if p.ttl == 0 {
 		p.ttl = 1
@@ -90,6 +90,20 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{
+		IPv4:          true,
+		IPv6:          true,
+		CreateMissing: true,
+		TTL:           true,
+	}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -107,10 +121,14 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	recordID, upToDate, err := p.getRecordID(ctx, client, ip)
 	switch {
 	case stderrors.Is(err, errors.ErrReceivedNoResult):
+		if !utils.CreationAllowed(ctx) {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
 		err = p.createRecord(ctx, client, ip)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
 		}
+		utils.SignalCreated(ctx)
 		return ip, nil
 	case err != nil:
 		return netip.Addr{}, fmt.Errorf("getting record id: %w", err)
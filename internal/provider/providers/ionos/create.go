@@ -72,7 +72,7 @@ func (p *Provider) createRecord(ctx context.Context, client *http.Client,
 		return fmt.Errorf("%w: %s", errors.ErrAuth,
 			decodeErrorMessage(response.Body))
 	default:
-		return fmt.Errorf("%w: %s: %s", errors.ErrHTTPStatusNotValid,
-			response.Status, decodeErrorMessage(response.Body))
+		return errors.NewHTTPStatusError(response.StatusCode,
+			decodeErrorMessage(response.Body))
 	}
 }
@@ -64,8 +64,8 @@ func (p *Provider) get(ctx context.Context, client *http.Client,
 		return fmt.Errorf("%w: %s", errors.ErrAuth,
 			decodeErrorMessage(response.Body))
 	default:
-		return fmt.Errorf("%w: %s: %s", errors.ErrHTTPStatusNotValid,
-			response.Status, decodeErrorMessage(response.Body))
+		return errors.NewHTTPStatusError(response.StatusCode,
+			decodeErrorMessage(response.Body))
 	}
 
 	decoder := json.NewDecoder(response.Body)
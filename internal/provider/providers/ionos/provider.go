@@ -75,6 +75,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true, CreateMissing: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -98,7 +107,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 
 	var zoneID string
 	for _, zone := range zones {
-		if zone.Name == p.domain {
+		if utils.RecordNamesEqual(zone.Name, p.domain) {
 			zoneID = zone.ID
 			break
 		}
@@ -123,16 +132,20 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	matchingRecords := make([]apiRecord, 0, usualRecordsCount)
 	fullDomainName := p.BuildDomainName()
 	for _, record := range records {
-		if record.Name == fullDomainName {
+		if utils.RecordNamesEqual(record.Name, fullDomainName) {
 			matchingRecords = append(matchingRecords, record)
 		}
 	}
 
 	if len(matchingRecords) == 0 {
+		if !utils.CreationAllowed(ctx) {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
 		err = p.createRecord(ctx, client, zoneID, ip)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
 		}
+		utils.SignalCreated(ctx)
 		return ip, nil
 	}
 
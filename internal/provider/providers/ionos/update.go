@@ -68,7 +68,7 @@ func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
 		return fmt.Errorf("%w: %s", errors.ErrRecordNotFound,
 			decodeErrorMessage(response.Body))
 	default:
-		return fmt.Errorf("%w: %s: %s", errors.ErrHTTPStatusNotValid,
-			response.Status, decodeErrorMessage(response.Body))
+		return errors.NewHTTPStatusError(response.StatusCode,
+			decodeErrorMessage(response.Body))
 	}
 }
@@ -82,6 +82,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true, CreateMissing: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -109,10 +118,14 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 
 	recordID, err := p.getRecordID(ctx, client, domainID, recordType)
 	if goerrors.Is(err, errors.ErrRecordNotFound) {
+		if !utils.CreationAllowed(ctx) {
+			return netip.Addr{}, err
+		}
 		err := p.createRecord(ctx, client, domainID, recordType, ip)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
 		}
+		utils.SignalCreated(ctx)
 		return ip, nil
 	} else if err != nil {
 		return netip.Addr{}, fmt.Errorf("getting record id: %w", err)
@@ -161,7 +174,7 @@ func (p *Provider) getDomainID(ctx context.Context, client *http.Client) (domain
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		err = errors.NewHTTPStatusError(response.StatusCode, "")
 		return 0, fmt.Errorf("%w: %s", err, p.getErrorMessage(response.Body))
 	}
 
@@ -221,7 +234,7 @@ func (p *Provider) getRecordID(ctx context.Context, client *http.Client,
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		err = errors.NewHTTPStatusError(response.StatusCode, "")
 		return 0, fmt.Errorf("%w: %s", err, p.getErrorMessage(response.Body))
 	}
 
@@ -287,7 +300,7 @@ func (p *Provider) createRecord(ctx context.Context, client *http.Client,
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		err = errors.NewHTTPStatusError(response.StatusCode, "")
 		return fmt.Errorf("%w: %s", err, p.getErrorMessage(response.Body))
 	}
 
@@ -343,7 +356,7 @@ func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		err = errors.NewHTTPStatusError(response.StatusCode, "")
 		return fmt.Errorf("%w: %s", err, p.getErrorMessage(response.Body))
 	}
 
@@ -93,6 +93,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -171,7 +180,7 @@ func (p *Provider) getZoneID(ctx context.Context, client *http.Client) (zoneID i
 	}
 
 	if response.StatusCode != http.StatusOK {
-		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		err = errors.NewHTTPStatusError(response.StatusCode, "")
 		var errorObj luaDNSError
 		if jsonErr := json.Unmarshal(b, &errorObj); jsonErr != nil {
 			return 0, fmt.Errorf("%w: %s", err, utils.ToSingleLine(string(b)))
@@ -189,7 +198,7 @@ func (p *Provider) getZoneID(ctx context.Context, client *http.Client) (zoneID i
 		return 0, fmt.Errorf("json decoding response body: %w", err)
 	}
 	for _, zone := range zones {
-		if zone.Name == p.domain {
+		if utils.RecordNamesEqual(zone.Name, p.domain) {
 			return zone.ID, nil
 		}
 	}
@@ -223,7 +232,7 @@ func (p *Provider) getRecord(ctx context.Context, client *http.Client, zoneID in
 	}
 
 	if response.StatusCode != http.StatusOK {
-		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		err = errors.NewHTTPStatusError(response.StatusCode, "")
 		var errorObj luaDNSError
 		if jsonErr := json.Unmarshal(b, &errorObj); jsonErr != nil {
 			return record, fmt.Errorf("%w: %s", err, utils.ToSingleLine(string(b)))
@@ -241,9 +250,9 @@ func (p *Provider) getRecord(ctx context.Context, client *http.Client, zoneID in
 	if ip.Is6() {
 		recordType = constants.AAAA
 	}
-	recordName := utils.BuildURLQueryHostname(p.host, p.domain) + "."
+	recordName := utils.BuildURLQueryHostname(p.host, p.domain)
 	for _, record := range records {
-		if record.Type == recordType && record.Name == recordName {
+		if record.Type == recordType && utils.RecordNamesEqual(record.Name, recordName) {
 			return record, nil
 		}
 	}
@@ -286,7 +295,7 @@ func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
 	}
 
 	if response.StatusCode != http.StatusOK {
-		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		err = errors.NewHTTPStatusError(response.StatusCode, "")
 		var errorObj luaDNSError
 		if jsonErr := json.Unmarshal(b, &errorObj); jsonErr != nil {
 			return fmt.Errorf("%w: %s", err, utils.ToSingleLine(string(b)))
@@ -0,0 +1,213 @@
+// Package mock implements a DNS provider for tests, which either returns
+// results configured in advance, or sends its updates to an HTTP API which
+// can be replaced by the stub server of this package with the record
+// "api_url" setting. It can be used to test the update loop, retries and
+// failure paths end to end without a real DNS provider.
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain     string
+	host       string
+	ipVersion  ipversion.IPVersion
+	ipv6Suffix netip.Prefix
+	// results are returned in order by Update, with the last result
+	// repeated once all results are returned. If empty, updates are
+	// sent to the mock HTTP API instead.
+	results []Result
+	mutex   sync.Mutex
+	// calls are the IP addresses given to Update, in order.
+	calls []netip.Addr
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	provider *Provider, err error) {
+	var providerSpecificSettings settings
+	err = json.Unmarshal(data, &providerSpecificSettings)
+	if err != nil {
+		return nil, fmt.Errorf("decoding provider specific settings: %w", err)
+	}
+
+	switch {
+	case domain == "":
+		return nil, fmt.Errorf("%w", errors.ErrDomainNotSet)
+	case host == "":
+		return nil, fmt.Errorf("%w", errors.ErrHostNotSet)
+	}
+
+	results := make([]Result, len(providerSpecificSettings.Results))
+	for i, resultSettings := range providerSpecificSettings.Results {
+		results[i] = resultSettings.toResult()
+	}
+
+	return NewWithResults(domain, host, ipVersion, ipv6Suffix, results...), nil
+}
+
+// NewWithResults creates a mock provider returning the results given
+// in order, repeating the last result once all results are returned.
+// If no result is given, updates are sent to the mock HTTP API.
+func NewWithResults(domain, host string, ipVersion ipversion.IPVersion,
+	ipv6Suffix netip.Prefix, results ...Result) *Provider {
+	return &Provider{
+		domain:     domain,
+		host:       host,
+		ipVersion:  ipVersion,
+		ipv6Suffix: ipv6Suffix,
+		results:    results,
+	}
+}
+
+type settings struct {
+	Results []resultSettings `json:"results"`
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Mock, p.ipVersion)
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) IPv6Suffix() netip.Prefix {
+	return p.ipv6Suffix
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    p.BuildDomainName(),
+		Host:      p.Host(),
+		Provider:  "Mock",
+		IPVersion: p.ipVersion.String(),
+	}
+}
+
+// Calls returns the IP addresses given to Update, in order.
+func (p *Provider) Calls() (ips []netip.Addr) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	ips = make([]netip.Addr, len(p.calls))
+	copy(ips, p.calls)
+	return ips
+}
+
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	p.mutex.Lock()
+	callIndex := len(p.calls)
+	p.calls = append(p.calls, ip)
+	p.mutex.Unlock()
+
+	if len(p.results) == 0 {
+		return p.updateHTTP(ctx, client, ip)
+	}
+
+	result := p.results[min(callIndex, len(p.results)-1)]
+	if result.Delay > 0 {
+		timer := time.NewTimer(result.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return netip.Addr{}, ctx.Err()
+		}
+	}
+
+	switch {
+	case result.Err != nil:
+		return netip.Addr{}, result.Err
+	case result.IP.IsValid():
+		return result.IP, nil
+	default:
+		return ip, nil
+	}
+}
+
+// DefaultAPIURL is the URL of the mock HTTP API, which does not
+// exist and must be replaced with the record "api_url" setting,
+// for example with the URL of a stub server created with NewServer.
+const DefaultAPIURL = "https://mock.invalid"
+
+func (p *Provider) updateHTTP(ctx context.Context, client *http.Client, ip netip.Addr) (
+	newIP netip.Addr, err error) {
+	u, err := url.Parse(DefaultAPIURL)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("parsing API URL: %w", err)
+	}
+	u.Path = updatePath
+	values := url.Values{}
+	values.Set("hostname", p.BuildDomainName())
+	values.Set("ip", ip.String())
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+	}
+	headers.SetUserAgent(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("reading response body: %w", err)
+	}
+	s := utils.ToSingleLine(string(b))
+
+	if response.StatusCode != http.StatusOK {
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, s)
+	}
+
+	newIP, err = netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrUnknownResponse, s)
+	}
+	return newIP, nil
+}
@@ -0,0 +1,108 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	data := json.RawMessage(`{"results": [
+		{"status": 503, "error": "service unavailable"},
+		{"error": "connection reset"},
+		{"ip": "5.6.7.8"},
+		{}
+	]}`)
+	provider, err := New(data, "example.com", "@", ipversion.IP4, netip.Prefix{})
+	require.NoError(t, err)
+
+	ip := netip.MustParseAddr("1.2.3.4")
+	ctx := context.Background()
+
+	_, err = provider.Update(ctx, nil, ip)
+	assert.ErrorIs(t, err, errors.ErrHTTPStatusNotValid)
+	assert.EqualError(t, err, "HTTP status is not valid: 503: service unavailable")
+
+	_, err = provider.Update(ctx, nil, ip)
+	assert.EqualError(t, err, "connection reset")
+
+	newIP, err := provider.Update(ctx, nil, ip)
+	assert.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("5.6.7.8"), newIP)
+
+	for i := 0; i < 2; i++ { // last result is repeated
+		newIP, err = provider.Update(ctx, nil, ip)
+		assert.NoError(t, err)
+		assert.Equal(t, ip, newIP)
+	}
+
+	assert.Equal(t, []netip.Addr{ip, ip, ip, ip, ip}, provider.Calls())
+}
+
+func Test_Provider_Update_delay(t *testing.T) {
+	t.Parallel()
+
+	data := json.RawMessage(`{"results": [{"delay": "1h"}]}`)
+	provider, err := New(data, "example.com", "@", ipversion.IP4, netip.Prefix{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = provider.Update(ctx, nil, netip.MustParseAddr("1.2.3.4"))
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		domain     string
+		errWrapped error
+		errMessage string
+	}{
+		"no_results": {
+			data:   `{}`,
+			domain: "example.com",
+		},
+		"domain_not_set": {
+			data:       `{}`,
+			errWrapped: errors.ErrDomainNotSet,
+			errMessage: "domain is not set",
+		},
+		"delay_not_valid": {
+			data:       `{"results": [{"delay": "soon"}]}`,
+			domain:     "example.com",
+			errMessage: `decoding provider specific settings: time: invalid duration "soon"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), testCase.domain, "@",
+				ipversion.IP4, netip.Prefix{})
+
+			if testCase.errMessage == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+			}
+			assert.EqualError(t, err, testCase.errMessage)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package mock
+
+import (
+	stderrors "errors"
+	"net/netip"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+)
+
+// Result is the result of an update of the mock provider.
+type Result struct {
+	// IP is the IP address returned by the update. If it is not
+	// valid, the IP address given to the update is returned.
+	IP netip.Addr
+	// Err is the error returned by the update, if not nil.
+	Err error
+	// Delay is the duration to wait for before returning the
+	// result, or until the update context is canceled.
+	Delay time.Duration
+}
+
+// resultSettings is the JSON form of a result.
+type resultSettings struct {
+	IP netip.Addr `json:"ip"`
+	// Status is the HTTP status code of the error returned,
+	// such that it is classified as a provider HTTP error.
+	Status int `json:"status"`
+	// Error is the message of the error returned.
+	Error string   `json:"error"`
+	Delay duration `json:"delay"`
+}
+
+func (r resultSettings) toResult() (result Result) {
+	result = Result{
+		IP:    r.IP,
+		Delay: time.Duration(r.Delay),
+	}
+	switch {
+	case r.Status != 0:
+		result.Err = errors.NewHTTPStatusError(r.Status, r.Error)
+	case r.Error != "":
+		result.Err = stderrors.New(r.Error) //nolint:goerr113
+	}
+	return result
+}
+
+type duration time.Duration
+
+func (d *duration) UnmarshalText(text []byte) (err error) {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = duration(parsed)
+	return nil
+}
@@ -0,0 +1,74 @@
+package mock
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+const updatePath = "/update"
+
+// Response is a response of the stub server.
+type Response struct {
+	// Status is the HTTP status code, and defaults to 200.
+	Status int
+	// Body is the response body. It defaults to the IP address
+	// sent if the status is 200, such that the update succeeds.
+	Body string
+}
+
+// Server is a stub of the mock provider HTTP API, to be served
+// for example with httptest.NewServer and set as the record
+// "api_url" of mock provider records.
+type Server struct {
+	// responses are returned in order, with the last response
+	// repeated once all responses are returned.
+	responses []Response
+	mutex     sync.Mutex
+	requests  []url.Values
+}
+
+// NewServer creates a stub server returning the responses given in
+// order, repeating the last response once all responses are returned.
+// If no response is given, every update succeeds.
+func NewServer(responses ...Response) *Server {
+	return &Server{
+		responses: responses,
+	}
+}
+
+// Requests returns the query parameters of the update requests
+// received, in order.
+func (s *Server) Requests() (requests []url.Values) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	requests = make([]url.Values, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != updatePath {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mutex.Lock()
+	requestIndex := len(s.requests)
+	s.requests = append(s.requests, r.URL.Query())
+	s.mutex.Unlock()
+
+	var response Response
+	if len(s.responses) > 0 {
+		response = s.responses[min(requestIndex, len(s.responses)-1)]
+	}
+	if response.Status == 0 {
+		response.Status = http.StatusOK
+	}
+	if response.Body == "" && response.Status == http.StatusOK {
+		response.Body = r.URL.Query().Get("ip")
+	}
+
+	w.WriteHeader(response.Status)
+	_, _ = w.Write([]byte(response.Body))
+}
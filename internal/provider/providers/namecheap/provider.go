@@ -10,6 +10,7 @@ import (
 	"net/netip"
 	"net/url"
 	"regexp"
+	"strings"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
@@ -83,6 +84,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -129,8 +139,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
 	}
 
 	decoder := xml.NewDecoder(response.Body)
@@ -139,8 +148,11 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 
 	var parsedXML struct {
-		Errors struct {
-			Error string `xml:"errors.Err1"`
+		ErrCount uint `xml:"ErrCount"`
+		Errors   struct {
+			// Errors contains the text of each of
+			// the <Err1>, <Err2>, ... elements.
+			Errors []string `xml:",any"`
 		} `xml:"errors"`
 		IP string `xml:"IP"`
 	}
@@ -149,8 +161,20 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		return netip.Addr{}, fmt.Errorf("xml decoding response body: %w", err)
 	}
 
-	if parsedXML.Errors.Error != "" {
-		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrUnsuccessful, parsedXML.Errors.Error)
+	errorMessages := make([]string, 0, len(parsedXML.Errors.Errors))
+	for _, message := range parsedXML.Errors.Errors {
+		message = strings.TrimSpace(message)
+		if message != "" {
+			errorMessages = append(errorMessages, message)
+		}
+	}
+	switch {
+	case len(errorMessages) > 0:
+		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrUnsuccessful,
+			strings.Join(errorMessages, "; "))
+	case parsedXML.ErrCount > 0:
+		return netip.Addr{}, fmt.Errorf("%w: error count %d without error message",
+			errors.ErrUnsuccessful, parsedXML.ErrCount)
 	}
 
 	if parsedXML.IP == "" {
@@ -0,0 +1,127 @@
+package namecheap
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		useProviderIP bool
+		ip            netip.Addr
+		responseBody  string
+		newIP         netip.Addr
+		errWrapped    error
+		errMessage    string
+	}{
+		"ip_sent": {
+			ip:           netip.MustParseAddr("1.2.3.4"),
+			responseBody: `<interface-response><IP>1.2.3.4</IP><ErrCount>0</ErrCount></interface-response>`,
+			newIP:        netip.MustParseAddr("1.2.3.4"),
+		},
+		"ip_sent_mismatch": {
+			ip:           netip.MustParseAddr("1.2.3.4"),
+			responseBody: `<interface-response><IP>5.6.7.8</IP><ErrCount>0</ErrCount></interface-response>`,
+			errWrapped:   errors.ErrIPReceivedMismatch,
+			errMessage:   "mismatching IP address received: sent ip 1.2.3.4 to update but received 5.6.7.8",
+		},
+		"provider_ip_returned": {
+			useProviderIP: true,
+			ip:            netip.MustParseAddr("1.2.3.4"),
+			responseBody:  `<interface-response><IP>5.6.7.8</IP><ErrCount>0</ErrCount></interface-response>`,
+			newIP:         netip.MustParseAddr("5.6.7.8"),
+		},
+		"provider_ip_missing_in_response": {
+			useProviderIP: true,
+			ip:            netip.MustParseAddr("1.2.3.4"),
+			responseBody:  `<interface-response><ErrCount>0</ErrCount></interface-response>`,
+			newIP:         netip.MustParseAddr("1.2.3.4"),
+		},
+		"provider_ip_malformed": {
+			useProviderIP: true,
+			ip:            netip.MustParseAddr("1.2.3.4"),
+			responseBody:  `<interface-response><IP>bad</IP><ErrCount>0</ErrCount></interface-response>`,
+			errWrapped:    errors.ErrIPReceivedMalformed,
+			errMessage:    `malformed IP address received: ParseAddr("bad"): unable to parse IP`,
+		},
+		"single_error": {
+			ip: netip.MustParseAddr("1.2.3.4"),
+			responseBody: `<interface-response><ErrCount>1</ErrCount>` +
+				`<errors><Err1>Passwords do not match</Err1></errors></interface-response>`,
+			errWrapped: errors.ErrUnsuccessful,
+			errMessage: "unsuccessful result: Passwords do not match",
+		},
+		"multiple_errors": {
+			ip: netip.MustParseAddr("1.2.3.4"),
+			responseBody: `<interface-response><ErrCount>2</ErrCount><errors>` +
+				`<Err1>Domain name not found</Err1>` +
+				`<Err2>Invalid IP</Err2>` +
+				`</errors><Done>true</Done></interface-response>`,
+			errWrapped: errors.ErrUnsuccessful,
+			errMessage: "unsuccessful result: Domain name not found; Invalid IP",
+		},
+		"error_count_without_message": {
+			ip:           netip.MustParseAddr("1.2.3.4"),
+			responseBody: `<interface-response><ErrCount>1</ErrCount><errors/></interface-response>`,
+			errWrapped:   errors.ErrUnsuccessful,
+			errMessage:   "unsuccessful result: error count 1 without error message",
+		},
+		"no_error": {
+			ip: netip.MustParseAddr("1.2.3.4"),
+			responseBody: `<interface-response><IP>1.2.3.4</IP><ErrCount>0</ErrCount>` +
+				`<errors /><Done>true</Done></interface-response>`,
+			newIP: netip.MustParseAddr("1.2.3.4"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					values := r.URL.Query()
+					if testCase.useProviderIP {
+						assert.False(t, values.Has("ip"), "ip must not be sent")
+					} else {
+						assert.Equal(t, testCase.ip.String(), values.Get("ip"))
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(testCase.responseBody)),
+					}, nil
+				}),
+			}
+			provider := &Provider{
+				domain:        "example.com",
+				host:          "@",
+				password:      "0123456789abcdef0123456789abcdef",
+				useProviderIP: testCase.useProviderIP,
+			}
+
+			newIP, err := provider.Update(context.Background(), client, testCase.ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.newIP, newIP)
+		})
+	}
+}
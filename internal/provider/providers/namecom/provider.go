@@ -29,24 +29,26 @@ func New(data json.RawMessage, domain, host string,
 	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
 	p *Provider, err error) {
 	extraSettings := struct {
-		Username string  `json:"username"`
-		Token    string  `json:"token"`
-		TTL      *uint32 `json:"ttl,omitempty"`
+		Username string     `json:"username"`
+		Token    string     `json:"token"`
+		TTL      *utils.TTL `json:"ttl,omitempty"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
 		return nil, err
 	}
 
+	ttl := (*uint32)(extraSettings.TTL)
+
 	const minTTL = 300
 	switch {
 	case extraSettings.Username == "":
 		return nil, fmt.Errorf("%w", errors.ErrUsernameNotSet)
 	case extraSettings.Token == "":
 		return nil, fmt.Errorf("%w", errors.ErrPasswordNotSet)
-	case extraSettings.TTL != nil && *extraSettings.TTL < minTTL:
+	case ttl != nil && *ttl < minTTL:
 		return nil, fmt.Errorf("%w: %d must be at least %d",
-			errors.ErrTTLTooLow, *extraSettings.TTL, minTTL)
+			errors.ErrTTLTooLow, *ttl, minTTL)
 	}
 
 	return &Provider{
@@ -56,7 +58,7 @@ func New(data json.RawMessage, domain, host string,
 		ipv6Suffix: ipv6Suffix,
 		username:   extraSettings.Username,
 		token:      extraSettings.Token,
-		ttl:        extraSettings.TTL,
+		ttl:        ttl,
 	}, nil
 }
 
@@ -84,6 +86,20 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{
+		IPv4:          true,
+		IPv6:          true,
+		CreateMissing: true,
+		TTL:           true,
+	}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -107,11 +123,14 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	recordID, err := p.getRecordID(ctx, client, recordType)
 
 	if stderrors.Is(err, errors.ErrRecordNotFound) {
+		if !utils.CreationAllowed(ctx) {
+			return netip.Addr{}, err
+		}
 		err = p.createRecord(ctx, client, ip)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
 		}
-
+		utils.SignalCreated(ctx)
 		return ip, nil
 	} else if err != nil {
 		return netip.Addr{}, fmt.Errorf("getting record id: %w", err)
@@ -0,0 +1,145 @@
+package netlify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+type record struct {
+	ID       string `json:"id,omitempty"`
+	Hostname string `json:"hostname"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	TTL      uint32 `json:"ttl"`
+}
+
+func makeURL(path string) url.URL {
+	return url.URL{
+		Scheme: "https",
+		Host:   "api.netlify.com",
+		Path:   "/api/v1/dns_zones" + path,
+	}
+}
+
+// getZoneID returns the identifier of the DNS zone of the domain.
+// See https://open-api.netlify.com/#tag/dnsZone/operation/getDnsZones
+func (p *Provider) getZoneID(ctx context.Context, client *http.Client) (zoneID string, err error) {
+	u := makeURL("")
+	var zones []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	err = p.do(ctx, client, http.MethodGet, u, nil, http.StatusOK, &zones)
+	if err != nil {
+		return "", err
+	}
+
+	for _, zone := range zones {
+		if zone.Name == p.domain {
+			return zone.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", errors.ErrZoneNotFound, p.domain)
+}
+
+// getRecords returns the records of the host with the record type given.
+// See https://open-api.netlify.com/#tag/dnsZone/operation/getDnsRecords
+func (p *Provider) getRecords(ctx context.Context, client *http.Client,
+	zoneID, recordType string) (records []record, err error) {
+	u := makeURL("/" + zoneID + "/dns_records")
+	var zoneRecords []record
+	err = p.do(ctx, client, http.MethodGet, u, nil, http.StatusOK, &zoneRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname := p.BuildDomainName()
+	for _, record := range zoneRecords {
+		if record.Hostname == hostname && record.Type == recordType {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// See https://open-api.netlify.com/#tag/dnsZone/operation/createDnsRecord
+func (p *Provider) createRecord(ctx context.Context, client *http.Client,
+	zoneID, recordType string, ip netip.Addr) (err error) {
+	u := makeURL("/" + zoneID + "/dns_records")
+	requestData := record{
+		Hostname: p.BuildDomainName(),
+		Type:     recordType,
+		Value:    ip.String(),
+		TTL:      p.ttl,
+	}
+	var created record
+	err = p.do(ctx, client, http.MethodPost, u, requestData, http.StatusCreated, &created)
+	if err != nil {
+		return err
+	} else if created.Value != ip.String() {
+		return fmt.Errorf("%w: sent ip %s to update but received %s",
+			errors.ErrIPReceivedMismatch, ip, created.Value)
+	}
+	return nil
+}
+
+// See https://open-api.netlify.com/#tag/dnsZone/operation/deleteDnsRecord
+func (p *Provider) deleteRecord(ctx context.Context, client *http.Client,
+	zoneID, recordID string) (err error) {
+	u := makeURL("/" + zoneID + "/dns_records/" + recordID)
+	return p.do(ctx, client, http.MethodDelete, u, nil, http.StatusNoContent, nil)
+}
+
+// do sends a request with the JSON encoded request data if it is not nil,
+// checks the response status code and JSON decodes the response body
+// into responseData if it is not nil.
+func (p *Provider) do(ctx context.Context, client *http.Client, method string,
+	u url.URL, requestData any, expectedStatus int, responseData any) (err error) {
+	var body *bytes.Reader
+	if requestData != nil {
+		b, err := json.Marshal(requestData)
+		if err != nil {
+			return fmt.Errorf("json encoding request data: %w", err)
+		}
+		body = bytes.NewReader(b)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case expectedStatus:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	default:
+		return errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	if responseData == nil {
+		return nil
+	}
+	err = json.NewDecoder(response.Body).Decode(responseData)
+	if err != nil {
+		return fmt.Errorf("json decoding response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package netlify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain     string
+	host       string
+	ipVersion  ipversion.IPVersion
+	ipv6Suffix netip.Prefix
+	token      string
+	ttl        uint32
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	p *Provider, err error) {
+	extraSettings := struct {
+		Token string    `json:"token"`
+		TTL   utils.TTL `json:"ttl"`
+	}{}
+	err = json.Unmarshal(data, &extraSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	const defaultTTL = 3600
+	if extraSettings.TTL == 0 {
+		extraSettings.TTL = defaultTTL
+	}
+
+	p = &Provider{
+		domain:     domain,
+		host:       host,
+		ipVersion:  ipVersion,
+		ipv6Suffix: ipv6Suffix,
+		token:      extraSettings.Token,
+		ttl:        uint32(extraSettings.TTL),
+	}
+	err = p.isValid()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	const minTTL, maxTTL = 1, 2147483647
+	if p.token == "" {
+		return fmt.Errorf("%w", errors.ErrTokenNotSet)
+	}
+	return utils.CheckTTL(utils.TTL(p.ttl), minTTL, maxTTL)
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Netlify, p.ipVersion)
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) IPv6Suffix() netip.Prefix {
+	return p.ipv6Suffix
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{
+		IPv4:          true,
+		IPv6:          true,
+		CreateMissing: true,
+		TTL:           true,
+	}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName()),
+		Host:      p.Host(),
+		Provider:  "<a href=\"https://www.netlify.com/\">Netlify</a>",
+		IPVersion: p.ipVersion.String(),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	headers.SetAuthBearer(request, p.token)
+}
+
+// Update replaces the records of the host with a record with the IP
+// address given, since the Netlify API cannot modify records in place.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+
+	zoneID, err := p.getZoneID(ctx, client)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting zone id: %w", err)
+	}
+
+	records, err := p.getRecords(ctx, client, zoneID, recordType)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting records: %w", err)
+	}
+
+	switch {
+	case len(records) == 0 && !utils.CreationAllowed(ctx):
+		return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+	case len(records) == 1 && records[0].Value == ip.String() && records[0].TTL == p.ttl:
+		return ip, nil
+	}
+
+	// Create the new record before deleting the stale ones, so the
+	// host keeps resolving if the creation fails.
+	err = p.createRecord(ctx, client, zoneID, recordType, ip)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("creating record: %w", err)
+	}
+	if len(records) == 0 {
+		utils.SignalCreated(ctx)
+	}
+
+	for _, record := range records {
+		err = p.deleteRecord(ctx, client, zoneID, record.ID)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("deleting stale record: %w", err)
+		}
+	}
+	return ip, nil
+}
@@ -0,0 +1,131 @@
+package netlify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func newResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	const (
+		zonesURL   = "https://api.netlify.com/api/v1/dns_zones"
+		recordsURL = zonesURL + "/example_com/dns_records"
+		zones      = `[{"id":"other_com","name":"other.com"},{"id":"example_com","name":"example.com"}]`
+	)
+
+	testCases := map[string]struct {
+		zones        string
+		records      string
+		createStatus int
+		requests     []string
+		errWrapped   error
+		errMessage   string
+	}{
+		"up_to_date": {
+			zones: zones,
+			records: `[{"id":"1","hostname":"home.example.com","type":"A","value":"1.2.3.4","ttl":3600},` +
+				`{"id":"2","hostname":"example.com","type":"A","value":"5.6.7.8","ttl":3600}]`,
+			requests: []string{"GET " + zonesURL, "GET " + recordsURL},
+		},
+		"replace": {
+			zones: zones,
+			records: `[{"id":"1","hostname":"home.example.com","type":"A","value":"5.6.7.8","ttl":3600},` +
+				`{"id":"2","hostname":"home.example.com","type":"AAAA","value":"::1","ttl":3600}]`,
+			requests: []string{
+				"GET " + zonesURL, "GET " + recordsURL,
+				"POST " + recordsURL,
+				"DELETE " + recordsURL + "/1",
+			},
+		},
+		"replace_create_failed": {
+			zones:        zones,
+			records:      `[{"id":"1","hostname":"home.example.com","type":"A","value":"5.6.7.8","ttl":3600}]`,
+			createStatus: http.StatusUnprocessableEntity,
+			requests:     []string{"GET " + zonesURL, "GET " + recordsURL, "POST " + recordsURL},
+			errWrapped:   errors.ErrHTTPStatusNotValid,
+			errMessage:   "creating record: HTTP status is not valid: 422: invalid",
+		},
+		"create": {
+			zones:    zones,
+			records:  `[]`,
+			requests: []string{"GET " + zonesURL, "GET " + recordsURL, "POST " + recordsURL},
+		},
+		"zone_not_found": {
+			zones:      `[{"id":"other_com","name":"other.com"}]`,
+			requests:   []string{"GET " + zonesURL},
+			errWrapped: errors.ErrZoneNotFound,
+			errMessage: "getting zone id: zone not found: example.com",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(`{"token":"token"}`), "example.com", "home",
+				ipversion.IP4, netip.Prefix{})
+			require.NoError(t, err)
+
+			var requests []string
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+					requests = append(requests, r.Method+" "+r.URL.String())
+					switch r.Method + " " + r.URL.String() {
+					case "GET " + zonesURL:
+						return newResponse(http.StatusOK, testCase.zones), nil
+					case "GET " + recordsURL:
+						return newResponse(http.StatusOK, testCase.records), nil
+					case "POST " + recordsURL:
+						body, err := io.ReadAll(r.Body)
+						require.NoError(t, err)
+						assert.Equal(t, `{"hostname":"home.example.com","type":"A","value":"1.2.3.4","ttl":3600}`,
+							string(body))
+						if testCase.createStatus != 0 {
+							return newResponse(testCase.createStatus, "invalid"), nil
+						}
+						return newResponse(http.StatusCreated, string(body)), nil
+					default:
+						return newResponse(http.StatusNoContent, ""), nil
+					}
+				}),
+			}
+
+			ip := netip.MustParseAddr("1.2.3.4")
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			assert.Equal(t, testCase.requests, requests)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, ip, newIP)
+		})
+	}
+}
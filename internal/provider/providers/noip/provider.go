@@ -96,6 +96,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -118,13 +127,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	values := url.Values{}
 	values.Set("hostname", utils.BuildURLQueryHostname(p.host, p.domain))
-	useProviderIP := p.useProviderIP && (ip.Is4() || !p.ipv6Suffix.IsValid())
-	if !useProviderIP {
-		// See https://help.dyn.com/remote-access-api/perform-update/ stating:
-		// This authentication method supports both IPv6 and IPv4 addresses.
-		// Use commas to separate multiple IP addresses in the myip field.
-		values.Set("myip", ip.String())
-	}
+	providerIPUsed := utils.SetDynDNS2MyIP(values, ip, p.useProviderIP, p.ipv6Suffix)
 	u.RawQuery = values.Encode()
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
@@ -133,9 +136,11 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	headers.SetUserAgent(request)
 
-	response, err := client.Do(request)
+	response, verified, err := utils.DoDynDNS2Request(ctx, client, request, p.BuildDomainName(), ip)
 	if err != nil {
 		return netip.Addr{}, fmt.Errorf("doing http request: %w", err)
+	} else if verified {
+		return ip, nil
 	}
 	defer response.Body.Close()
 
@@ -146,7 +151,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	s := string(b)
 
 	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid, response.StatusCode, s)
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, s)
 	}
 
 	switch s {
@@ -177,14 +182,9 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		ips = ipextract.IPv6(s)
 	}
 
-	if !useProviderIP && len(ips) == 0 {
+	if !providerIPUsed && len(ips) == 0 {
 		return netip.Addr{}, fmt.Errorf("%w", errors.ErrReceivedNoIP)
 	}
 
-	newIP = ips[0]
-	if !useProviderIP && ip.Compare(newIP) != 0 {
-		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
-			errors.ErrIPReceivedMismatch, ip, newIP)
-	}
-	return newIP, nil
+	return utils.DynDNS2ResponseIP(s, ip, providerIPUsed)
 }
@@ -0,0 +1,79 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// updateCNAME sets the CNAME record of the host to the target rendered
+// from the target template, so the host follows a CNAME chain to a name
+// derived from the IP address, for example its reverse DNS name.
+func (p *Provider) updateCNAME(ctx context.Context, client *http.Client,
+	ip netip.Addr) (newIP netip.Addr, err error) {
+	target, err := utils.RenderCNAMETarget(p.target, p.variables, ip, p.timeNow())
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("rendering target: %w", err)
+	}
+
+	name := utils.BuildURLQueryHostname(p.host, p.domain)
+	existing, err := p.getRRSet(ctx, client, name, constants.CNAME)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting record set: %w", err)
+	}
+
+	if len(existing) == 1 && utils.RecordNamesEqual(existing[0].RData, target) {
+		return ip, nil
+	}
+
+	type operation struct {
+		record
+		Operation string `json:"operation"`
+	}
+	operations := make([]operation, 0, len(existing)+1)
+	for _, existingRecord := range existing {
+		operations = append(operations, operation{
+			record:    existingRecord,
+			Operation: "REMOVE",
+		})
+	}
+	operations = append(operations, operation{
+		record: record{
+			Domain: name,
+			RData:  utils.NormalizeRecordName(target, true),
+			RType:  constants.CNAME,
+			TTL:    p.ttl,
+		},
+		Operation: "ADD",
+	})
+
+	requestData := struct {
+		Items []operation `json:"items"`
+	}{Items: operations}
+	requestBody, err := json.Marshal(requestData)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	records, err := p.do(ctx, client, http.MethodPatch, p.makeRRSetURL(name, constants.CNAME), requestBody)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("patching record set: %w", err)
+	}
+
+	if len(records) != 1 {
+		return netip.Addr{}, fmt.Errorf("%w: %d records instead of 1",
+			errors.ErrResultsCountReceived, len(records))
+	}
+	receivedTarget := utils.NormalizeRecordName(records[0].RData, false)
+	if !utils.RecordNamesEqual(receivedTarget, target) {
+		return netip.Addr{}, fmt.Errorf("%w: sent target %s to update but received %s",
+			errors.ErrTargetReceivedMismatch, target, receivedTarget)
+	}
+	return ip, nil
+}
@@ -0,0 +1,175 @@
+package oci
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update_cname(t *testing.T) {
+	t.Parallel()
+
+	const keySize = 2048
+	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		existingTarget string
+		patchBody      string
+		newIP          netip.Addr
+		errWrapped     error
+		errMessage     string
+	}{
+		"success": {
+			existingTarget: "old.example.com.",
+			patchBody: `{"items":[{"domain":"www.example.com",` +
+				`"rdata":"192-0-2-10.eu.example.net.","rtype":"CNAME","ttl":300}]}`,
+			newIP: netip.MustParseAddr("192.0.2.10"),
+		},
+		"up_to_date": {
+			existingTarget: "192-0-2-10.eu.example.net.",
+			newIP:          netip.MustParseAddr("192.0.2.10"),
+		},
+		"target_mismatch": {
+			existingTarget: "old.example.com.",
+			patchBody: `{"items":[{"domain":"www.example.com",` +
+				`"rdata":"other.example.net.","rtype":"CNAME","ttl":300}]}`,
+			errWrapped: errors.ErrTargetReceivedMismatch,
+			errMessage: "mismatching target received: sent target 192-0-2-10.eu.example.net " +
+				"to update but received other.example.net",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, "/20180115/zones/example.com/records/www.example.com/CNAME", r.URL.Path)
+					response := &http.Response{StatusCode: http.StatusOK}
+					switch r.Method {
+					case http.MethodGet:
+						response.Body = io.NopCloser(strings.NewReader(`{"items":[{"domain":` +
+							`"www.example.com","recordHash":"hash","rdata":"` + testCase.existingTarget + `",` +
+							`"rtype":"CNAME","ttl":300}]}`))
+					case http.MethodPatch:
+						var requestData struct {
+							Items json.RawMessage `json:"items"`
+						}
+						err := json.NewDecoder(r.Body).Decode(&requestData)
+						require.NoError(t, err)
+						assert.JSONEq(t, `[{"domain":"www.example.com","recordHash":"hash",`+
+							`"rdata":"old.example.com.","rtype":"CNAME","ttl":300,"operation":"REMOVE"},`+
+							`{"domain":"www.example.com","rdata":"192-0-2-10.eu.example.net.",`+
+							`"rtype":"CNAME","ttl":300,"operation":"ADD"}]`, string(requestData.Items))
+						response.Body = io.NopCloser(strings.NewReader(testCase.patchBody))
+					default:
+						t.Errorf("unexpected method %s", r.Method)
+					}
+					return response, nil
+				}),
+			}
+
+			provider := &Provider{
+				domain:      "example.com",
+				host:        "www",
+				tenancyOCID: "tenancy",
+				userOCID:    "user",
+				fingerprint: "fingerprint",
+				privateKey:  privateKey,
+				region:      "us-ashburn-1",
+				ttl:         300,
+				recordType:  "CNAME",
+				target:      "{ip_dashed}.{region}.example.net",
+				variables:   map[string]string{"region": "EU"},
+				timeNow:     time.Now,
+			}
+
+			newIP, err := provider.Update(context.Background(), client, netip.MustParseAddr("192.0.2.10"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.newIP, newIP)
+		})
+	}
+}
+
+func Test_Provider_isValid_cname(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		host       string
+		recordType string
+		target     string
+		variables  map[string]string
+		errWrapped error
+		errMessage string
+	}{
+		"cname": {
+			host:       "www",
+			recordType: "CNAME",
+			target:     "{ip_dashed}.{region}.example.net",
+			variables:  map[string]string{"region": "eu"},
+		},
+		"cname_at_apex": {
+			host:       "@",
+			recordType: "CNAME",
+			target:     "host.example.net",
+			errWrapped: errors.ErrRecordTypeNotValid,
+			errMessage: "record type is not valid: CNAME records cannot be set at the zone apex",
+		},
+		"cname_variable_not_defined": {
+			host:       "www",
+			recordType: "CNAME",
+			target:     "{ip_dashed}.{region}.example.net",
+			errWrapped: errors.ErrTargetNotValid,
+			errMessage: `target is not valid: variable "region" is not defined`,
+		},
+		"variables_without_cname": {
+			host:       "www",
+			variables:  map[string]string{"region": "eu"},
+			errWrapped: errors.ErrRecordTypeNotValid,
+			errMessage: "record type is not valid: variables can only be set with CNAME records",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &Provider{
+				host:        testCase.host,
+				tenancyOCID: "tenancy",
+				userOCID:    "user",
+				fingerprint: "fingerprint",
+				region:      "us-ashburn-1",
+				recordType:  testCase.recordType,
+				target:      testCase.target,
+				variables:   testCase.variables,
+			}
+
+			err := provider.isValid()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
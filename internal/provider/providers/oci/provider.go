@@ -0,0 +1,261 @@
+package oci
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain      string
+	host        string
+	ipVersion   ipversion.IPVersion
+	ipv6Suffix  netip.Prefix
+	tenancyOCID string
+	userOCID    string
+	fingerprint string
+	privateKey  *rsa.PrivateKey
+	region      string
+	ttl         uint32
+	// staticIPs are IP addresses always included
+	// in the record set, after the public IP address.
+	staticIPs []netip.Addr
+	// recordType is the empty string to update A and AAAA
+	// records, PTR to update the reverse DNS record of the
+	// IP address to point to the target hostname, CNAME to
+	// point the host to the target rendered from the target
+	// template and variables, or TLSA or CAA to set the record
+	// data given in tlsa or caa.
+	recordType string
+	target     string
+	variables  map[string]string
+	tlsa       *utils.TLSA
+	caa        *utils.CAA
+	timeNow    func() time.Time
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	p *Provider, err error) {
+	extraSettings := struct {
+		TenancyOCID string            `json:"tenancy_ocid"`
+		UserOCID    string            `json:"user_ocid"`
+		Fingerprint string            `json:"fingerprint"`
+		PrivateKey  string            `json:"private_key"`
+		Region      string            `json:"region"`
+		TTL         utils.TTL         `json:"ttl"`
+		StaticIPs   []netip.Addr      `json:"static_ips"`
+		RecordType  string            `json:"record_type"`
+		Target      string            `json:"target"`
+		Variables   map[string]string `json:"variables"`
+		TLSA        *utils.TLSA       `json:"tlsa"`
+		CAA         *utils.CAA        `json:"caa"`
+	}{}
+	err = json.Unmarshal(data, &extraSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	const defaultTTL = 300
+	if extraSettings.TTL == 0 {
+		extraSettings.TTL = defaultTTL
+	}
+
+	p = &Provider{
+		domain:      domain,
+		host:        host,
+		ipVersion:   ipVersion,
+		ipv6Suffix:  ipv6Suffix,
+		tenancyOCID: extraSettings.TenancyOCID,
+		userOCID:    extraSettings.UserOCID,
+		fingerprint: extraSettings.Fingerprint,
+		region:      extraSettings.Region,
+		ttl:         uint32(extraSettings.TTL),
+		staticIPs:   extraSettings.StaticIPs,
+		recordType:  strings.ToUpper(extraSettings.RecordType),
+		target:      utils.NormalizeRecordName(extraSettings.Target, false),
+		variables:   extraSettings.Variables,
+		tlsa:        extraSettings.TLSA,
+		caa:         extraSettings.CAA,
+		timeNow:     time.Now,
+	}
+	err = p.isValid()
+	if err != nil {
+		return nil, err
+	}
+
+	p.privateKey, err = parsePrivateKey(extraSettings.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.tenancyOCID == "":
+		return fmt.Errorf("%w", errors.ErrTenancyOCIDNotSet)
+	case p.userOCID == "":
+		return fmt.Errorf("%w", errors.ErrUserOCIDNotSet)
+	case p.fingerprint == "":
+		return fmt.Errorf("%w", errors.ErrFingerprintNotSet)
+	case p.region == "":
+		return fmt.Errorf("%w", errors.ErrRegionNotSet)
+	case p.recordType != "" && p.recordType != constants.PTR && p.recordType != constants.CNAME &&
+		p.recordType != constants.TLSA && p.recordType != constants.CAA:
+		return fmt.Errorf("%w: %q can only be empty, %q, %q, %q or %q",
+			errors.ErrRecordTypeNotValid, p.recordType, constants.PTR,
+			constants.CNAME, constants.TLSA, constants.CAA)
+	case p.recordType == constants.PTR && p.target == "":
+		return fmt.Errorf("%w", errors.ErrTargetNotSet)
+	case p.recordType != constants.CNAME && len(p.variables) > 0:
+		return fmt.Errorf("%w: variables can only be set with CNAME records",
+			errors.ErrRecordTypeNotValid)
+	case p.recordType == constants.CNAME && p.host == "@":
+		return fmt.Errorf("%w: CNAME records cannot be set at the zone apex",
+			errors.ErrRecordTypeNotValid)
+	case p.recordType != "" && len(p.staticIPs) > 0:
+		return fmt.Errorf("%w: static IP addresses cannot be used with %s records",
+			errors.ErrRecordTypeNotValid, p.recordType)
+	case p.recordType != constants.TLSA && p.tlsa != nil,
+		p.recordType != constants.CAA && p.caa != nil:
+		return fmt.Errorf("%w: tlsa and caa can only be set with their record type",
+			errors.ErrRecordTypeNotValid)
+	case p.recordType == constants.CNAME:
+		return utils.CheckCNAMETarget(p.target, p.variables)
+	case p.recordType == constants.TLSA && p.tlsa == nil:
+		return fmt.Errorf("%w: tlsa is not set", errors.ErrTLSANotValid)
+	case p.recordType == constants.TLSA:
+		return p.tlsa.Validate()
+	case p.recordType == constants.CAA && p.caa == nil:
+		return fmt.Errorf("%w: caa is not set", errors.ErrCAANotValid)
+	case p.recordType == constants.CAA:
+		return p.caa.Validate()
+	}
+	return nil
+}
+
+// parsePrivateKey parses a PEM encoded RSA private key,
+// either in the PKCS#1 or in the PKCS#8 format.
+func parsePrivateKey(pemString string) (privateKey *rsa.PrivateKey, err error) {
+	if pemString == "" {
+		return nil, fmt.Errorf("%w", errors.ErrKeyNotSet)
+	}
+
+	block, _ := pem.Decode([]byte(pemString))
+	if block == nil {
+		return nil, fmt.Errorf("%w: private key is not PEM encoded", errors.ErrKeyNotValid)
+	}
+
+	privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err == nil {
+		return privateKey, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: parsing private key: %w", errors.ErrKeyNotValid, err)
+	}
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: private key is not an RSA key but %T", errors.ErrKeyNotValid, key)
+	}
+	return privateKey, nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.OCI, p.ipVersion)
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) IPv6Suffix() netip.Prefix {
+	return p.ipv6Suffix
+}
+
+// Proxied returns true for PTR, CNAME, TLSA and CAA records, since their
+// name cannot be resolved to the IP address to check if an update
+// is needed.
+func (p *Provider) Proxied() bool {
+	return p.recordType != ""
+}
+
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{
+		IPv4:      true,
+		IPv6:      true,
+		TTL:       true,
+		StaticIPs: true,
+	}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName()),
+		Host:      p.Host(),
+		Provider:  "<a href=\"https://www.oracle.com/cloud/networking/dns/\">Oracle Cloud</a>",
+		IPVersion: p.ipVersion.String(),
+	}
+}
+
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+
+	switch p.recordType {
+	case constants.PTR:
+		return p.updatePTR(ctx, client, ip)
+	case constants.CNAME:
+		return p.updateCNAME(ctx, client, ip)
+	case constants.TLSA, constants.CAA:
+		return p.updateRecordData(ctx, client, ip)
+	}
+
+	name := utils.BuildURLQueryHostname(p.host, p.domain)
+	records, err := p.getRRSet(ctx, client, name, recordType)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting record set: %w", err)
+	}
+
+	recordSet := utils.MakeRecordSet(ip, p.staticIPs)
+	newIP, err = p.patchRRSet(ctx, client, recordType, records, recordSet)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("patching record set: %w", err)
+	}
+	return newIP, nil
+}
@@ -0,0 +1,160 @@
+package oci
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	const keySize = 2048
+	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	require.NoError(t, err)
+
+	const rrSetPath = "/20180115/zones/example.com/records/sub.example.com/A"
+
+	testCases := map[string]struct {
+		staticIPs   []netip.Addr
+		getStatus   int
+		getBody     string
+		patchStatus int
+		patchBody   string
+		patchItems  string
+		newIP       netip.Addr
+		errWrapped  error
+		errMessage  string
+	}{
+		"replace_existing": {
+			getStatus: http.StatusOK,
+			getBody: `{"items":[{"domain":"sub.example.com","recordHash":"hash",` +
+				`"rdata":"5.6.7.8","rtype":"A","ttl":300}]}`,
+			patchStatus: http.StatusOK,
+			patchBody:   `{"items":[{"domain":"sub.example.com","rdata":"1.2.3.4","rtype":"A","ttl":300}]}`,
+			patchItems: `[{"domain":"sub.example.com","recordHash":"hash","rdata":"5.6.7.8",` +
+				`"rtype":"A","ttl":300,"operation":"REMOVE"},` +
+				`{"domain":"sub.example.com","rdata":"1.2.3.4","rtype":"A","ttl":300,"operation":"ADD"}]`,
+			newIP: netip.MustParseAddr("1.2.3.4"),
+		},
+		"create": {
+			getStatus:   http.StatusNotFound,
+			patchStatus: http.StatusOK,
+			patchBody:   `{"items":[{"domain":"sub.example.com","rdata":"1.2.3.4","rtype":"A","ttl":300}]}`,
+			patchItems:  `[{"domain":"sub.example.com","rdata":"1.2.3.4","rtype":"A","ttl":300,"operation":"ADD"}]`,
+			newIP:       netip.MustParseAddr("1.2.3.4"),
+		},
+		"static_ips": {
+			staticIPs:   []netip.Addr{netip.MustParseAddr("5.6.7.8"), netip.MustParseAddr("::1")},
+			getStatus:   http.StatusOK,
+			getBody:     `{"items":[]}`,
+			patchStatus: http.StatusOK,
+			patchBody: `{"items":[{"domain":"sub.example.com","rdata":"5.6.7.8","rtype":"A","ttl":300},` +
+				`{"domain":"sub.example.com","rdata":"1.2.3.4","rtype":"A","ttl":300}]}`,
+			patchItems: `[{"domain":"sub.example.com","rdata":"1.2.3.4","rtype":"A","ttl":300,"operation":"ADD"},` +
+				`{"domain":"sub.example.com","rdata":"5.6.7.8","rtype":"A","ttl":300,"operation":"ADD"}]`,
+			newIP: netip.MustParseAddr("1.2.3.4"),
+		},
+		"static_ips_mismatch": {
+			staticIPs:   []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+			getStatus:   http.StatusOK,
+			getBody:     `{"items":[]}`,
+			patchStatus: http.StatusOK,
+			patchBody:   `{"items":[{"domain":"sub.example.com","rdata":"1.2.3.4","rtype":"A","ttl":300}]}`,
+			patchItems: `[{"domain":"sub.example.com","rdata":"1.2.3.4","rtype":"A","ttl":300,"operation":"ADD"},` +
+				`{"domain":"sub.example.com","rdata":"5.6.7.8","rtype":"A","ttl":300,"operation":"ADD"}]`,
+			errWrapped: errors.ErrIPReceivedMismatch,
+			errMessage: "patching record set: mismatching IP address received: " +
+				"sent ips [1.2.3.4 5.6.7.8] to update but received [1.2.3.4]",
+		},
+		"unauthorized": {
+			getStatus:  http.StatusUnauthorized,
+			getBody:    `{"code":"NotAuthenticated"}`,
+			errWrapped: errors.ErrAuth,
+			errMessage: `getting record set: bad authentication: {"code":"NotAuthenticated"}`,
+		},
+		"mismatch": {
+			getStatus:   http.StatusOK,
+			getBody:     `{"items":[]}`,
+			patchStatus: http.StatusOK,
+			patchBody:   `{"items":[{"domain":"sub.example.com","rdata":"5.6.7.8","rtype":"A","ttl":300}]}`,
+			patchItems:  `[{"domain":"sub.example.com","rdata":"1.2.3.4","rtype":"A","ttl":300,"operation":"ADD"}]`,
+			errWrapped:  errors.ErrIPReceivedMismatch,
+			errMessage: "patching record set: mismatching IP address received: " +
+				"sent ip 1.2.3.4 to update but received 5.6.7.8",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, "dns.us-ashburn-1.oraclecloud.com", r.URL.Host)
+					assert.Equal(t, rrSetPath, r.URL.Path)
+					assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+						`Signature version="1",keyId="tenancy/user/fingerprint"`))
+
+					response := &http.Response{Body: io.NopCloser(strings.NewReader(""))}
+					switch r.Method {
+					case http.MethodGet:
+						response.StatusCode = testCase.getStatus
+						response.Body = io.NopCloser(strings.NewReader(testCase.getBody))
+					case http.MethodPatch:
+						var requestData struct {
+							Items json.RawMessage `json:"items"`
+						}
+						err := json.NewDecoder(r.Body).Decode(&requestData)
+						require.NoError(t, err)
+						assert.JSONEq(t, testCase.patchItems, string(requestData.Items))
+						response.StatusCode = testCase.patchStatus
+						response.Body = io.NopCloser(strings.NewReader(testCase.patchBody))
+					default:
+						t.Errorf("unexpected method %s", r.Method)
+					}
+					return response, nil
+				}),
+			}
+
+			provider := &Provider{
+				domain:      "example.com",
+				host:        "sub",
+				tenancyOCID: "tenancy",
+				userOCID:    "user",
+				fingerprint: "fingerprint",
+				privateKey:  privateKey,
+				region:      "us-ashburn-1",
+				ttl:         300,
+				staticIPs:   testCase.staticIPs,
+				timeNow:     time.Now,
+			}
+
+			newIP, err := provider.Update(context.Background(), client, netip.MustParseAddr("1.2.3.4"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.newIP, newIP)
+		})
+	}
+}
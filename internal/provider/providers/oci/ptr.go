@@ -0,0 +1,76 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// updatePTR sets the PTR record of the reverse name of the IP address
+// to the target hostname. The zone of the provider must be the reverse
+// zone containing the reverse name, for example 2.0.192.in-addr.arpa.
+func (p *Provider) updatePTR(ctx context.Context, client *http.Client,
+	ip netip.Addr) (newIP netip.Addr, err error) {
+	name := utils.ReverseName(ip)
+	if !strings.HasSuffix(name, "."+p.domain) {
+		return netip.Addr{}, fmt.Errorf("%w: %s is not in zone %s",
+			errors.ErrReverseNameNotInZone, name, p.domain)
+	}
+
+	existing, err := p.getRRSet(ctx, client, name, constants.PTR)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting record set: %w", err)
+	}
+
+	type operation struct {
+		record
+		Operation string `json:"operation"`
+	}
+	operations := make([]operation, 0, len(existing)+1)
+	for _, existingRecord := range existing {
+		operations = append(operations, operation{
+			record:    existingRecord,
+			Operation: "REMOVE",
+		})
+	}
+	operations = append(operations, operation{
+		record: record{
+			Domain: name,
+			RData:  utils.NormalizeRecordName(p.target, true),
+			RType:  constants.PTR,
+			TTL:    p.ttl,
+		},
+		Operation: "ADD",
+	})
+
+	requestData := struct {
+		Items []operation `json:"items"`
+	}{Items: operations}
+	requestBody, err := json.Marshal(requestData)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	records, err := p.do(ctx, client, http.MethodPatch, p.makeRRSetURL(name, constants.PTR), requestBody)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("patching record set: %w", err)
+	}
+
+	if len(records) != 1 {
+		return netip.Addr{}, fmt.Errorf("%w: %d records instead of 1",
+			errors.ErrResultsCountReceived, len(records))
+	}
+	receivedTarget := utils.NormalizeRecordName(records[0].RData, false)
+	if !utils.RecordNamesEqual(receivedTarget, p.target) {
+		return netip.Addr{}, fmt.Errorf("%w: sent target %s to update but received %s",
+			errors.ErrTargetReceivedMismatch, p.target, receivedTarget)
+	}
+	return ip, nil
+}
@@ -0,0 +1,162 @@
+package oci
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update_ptr(t *testing.T) {
+	t.Parallel()
+
+	const keySize = 2048
+	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		domain     string
+		patchBody  string
+		newIP      netip.Addr
+		errWrapped error
+		errMessage string
+	}{
+		"success": {
+			domain: "2.0.192.in-addr.arpa",
+			patchBody: `{"items":[{"domain":"10.2.0.192.in-addr.arpa",` +
+				`"rdata":"host.example.com.","rtype":"PTR","ttl":300}]}`,
+			newIP: netip.MustParseAddr("192.0.2.10"),
+		},
+		"target_mismatch": {
+			domain: "2.0.192.in-addr.arpa",
+			patchBody: `{"items":[{"domain":"10.2.0.192.in-addr.arpa",` +
+				`"rdata":"other.example.com.","rtype":"PTR","ttl":300}]}`,
+			errWrapped: errors.ErrTargetReceivedMismatch,
+			errMessage: "mismatching target received: sent target host.example.com " +
+				"to update but received other.example.com",
+		},
+		"not_in_zone": {
+			domain:     "3.0.192.in-addr.arpa",
+			errWrapped: errors.ErrReverseNameNotInZone,
+			errMessage: "reverse name is not in zone: " +
+				"10.2.0.192.in-addr.arpa is not in zone 3.0.192.in-addr.arpa",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, "/20180115/zones/2.0.192.in-addr.arpa/records/"+
+						"10.2.0.192.in-addr.arpa/PTR", r.URL.Path)
+					response := &http.Response{StatusCode: http.StatusOK}
+					switch r.Method {
+					case http.MethodGet:
+						response.Body = io.NopCloser(strings.NewReader(`{"items":[{"domain":` +
+							`"10.2.0.192.in-addr.arpa","recordHash":"hash","rdata":"old.example.com.",` +
+							`"rtype":"PTR","ttl":300}]}`))
+					case http.MethodPatch:
+						var requestData struct {
+							Items json.RawMessage `json:"items"`
+						}
+						err := json.NewDecoder(r.Body).Decode(&requestData)
+						require.NoError(t, err)
+						assert.JSONEq(t, `[{"domain":"10.2.0.192.in-addr.arpa","recordHash":"hash",`+
+							`"rdata":"old.example.com.","rtype":"PTR","ttl":300,"operation":"REMOVE"},`+
+							`{"domain":"10.2.0.192.in-addr.arpa","rdata":"host.example.com.",`+
+							`"rtype":"PTR","ttl":300,"operation":"ADD"}]`, string(requestData.Items))
+						response.Body = io.NopCloser(strings.NewReader(testCase.patchBody))
+					default:
+						t.Errorf("unexpected method %s", r.Method)
+					}
+					return response, nil
+				}),
+			}
+
+			provider := &Provider{
+				domain:      testCase.domain,
+				host:        "@",
+				tenancyOCID: "tenancy",
+				userOCID:    "user",
+				fingerprint: "fingerprint",
+				privateKey:  privateKey,
+				region:      "us-ashburn-1",
+				ttl:         300,
+				recordType:  "PTR",
+				target:      "host.example.com",
+				timeNow:     time.Now,
+			}
+
+			newIP, err := provider.Update(context.Background(), client, netip.MustParseAddr("192.0.2.10"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.newIP, newIP)
+		})
+	}
+}
+
+func Test_Provider_isValid_ptr(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		recordType string
+		target     string
+		errWrapped error
+		errMessage string
+	}{
+		"ptr": {
+			recordType: "PTR",
+			target:     "host.example.com",
+		},
+		"ptr_without_target": {
+			recordType: "PTR",
+			errWrapped: errors.ErrTargetNotSet,
+			errMessage: "target is not set",
+		},
+		"unsupported_record_type": {
+			recordType: "MX",
+			target:     "host.example.com",
+			errWrapped: errors.ErrRecordTypeNotValid,
+			errMessage: `record type is not valid: "MX" can only be empty, "PTR", "CNAME", "TLSA" or "CAA"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &Provider{
+				tenancyOCID: "tenancy",
+				userOCID:    "user",
+				fingerprint: "fingerprint",
+				region:      "us-ashburn-1",
+				recordType:  testCase.recordType,
+				target:      testCase.target,
+			}
+
+			err := provider.isValid()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
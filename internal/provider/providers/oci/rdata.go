@@ -0,0 +1,105 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// updateRecordData sets the TLSA or CAA record of the host to the record
+// data configured, replacing any existing record of the same type. The
+// IP address given is returned as is, since it is not part of the record.
+func (p *Provider) updateRecordData(ctx context.Context, client *http.Client,
+	ip netip.Addr) (newIP netip.Addr, err error) {
+	name := utils.BuildURLQueryHostname(p.host, p.domain)
+	existing, err := p.getRRSet(ctx, client, name, p.recordType)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting record set: %w", err)
+	}
+
+	if len(existing) == 1 && p.matchesRecordData(existing[0].RData) == nil {
+		return ip, nil // already up to date
+	}
+
+	type operation struct {
+		record
+		Operation string `json:"operation"`
+	}
+	operations := make([]operation, 0, len(existing)+1)
+	for _, existingRecord := range existing {
+		operations = append(operations, operation{
+			record:    existingRecord,
+			Operation: "REMOVE",
+		})
+	}
+	operations = append(operations, operation{
+		record: record{
+			Domain: name,
+			RData:  p.rdata(),
+			RType:  p.recordType,
+			TTL:    p.ttl,
+		},
+		Operation: "ADD",
+	})
+
+	requestData := struct {
+		Items []operation `json:"items"`
+	}{Items: operations}
+	requestBody, err := json.Marshal(requestData)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	records, err := p.do(ctx, client, http.MethodPatch, p.makeRRSetURL(name, p.recordType), requestBody)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("patching record set: %w", err)
+	}
+
+	if len(records) != 1 {
+		return netip.Addr{}, fmt.Errorf("%w: %d records instead of 1",
+			errors.ErrResultsCountReceived, len(records))
+	}
+	err = p.matchesRecordData(records[0].RData)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return ip, nil
+}
+
+func (p *Provider) rdata() string {
+	if p.recordType == constants.TLSA {
+		return p.tlsa.RData()
+	}
+	return p.caa.RData()
+}
+
+// matchesRecordData returns an error if the record data received does not
+// match the record data configured, ignoring formatting differences such
+// as the case or the chunking of the TLSA certificate data.
+func (p *Provider) matchesRecordData(rdata string) (err error) {
+	var matches bool
+	if p.recordType == constants.TLSA {
+		var tlsa utils.TLSA
+		tlsa, err = utils.ParseTLSA(rdata)
+		matches = err == nil && tlsa.RData() == p.tlsa.RData()
+	} else {
+		var caa utils.CAA
+		caa, err = utils.ParseCAA(rdata)
+		matches = err == nil && caa == *p.caa
+	}
+
+	switch {
+	case err != nil:
+		return err
+	case !matches:
+		return fmt.Errorf("%w: sent %s record data %q but received %q",
+			errors.ErrDataReceivedMismatch, p.recordType, p.rdata(), rdata)
+	}
+	return nil
+}
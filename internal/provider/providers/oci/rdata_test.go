@@ -0,0 +1,146 @@
+package oci
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update_recordData(t *testing.T) {
+	t.Parallel()
+
+	const keySize = 2048
+	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	require.NoError(t, err)
+
+	const certificateData = "8cb0fc6c527506a053f4f14c8464bebbd6dede2738d11468dd953d7d6a3021f1"
+
+	testCases := map[string]struct {
+		recordType   string
+		tlsa         *utils.TLSA
+		caa          *utils.CAA
+		path         string
+		existingBody string
+		patchItems   string
+		patchBody    string
+		errWrapped   error
+		errMessage   string
+	}{
+		"tlsa_round_trip": {
+			recordType: "TLSA",
+			tlsa: &utils.TLSA{
+				Usage:           3,
+				Selector:        1,
+				MatchingType:    1,
+				CertificateData: certificateData,
+			},
+			path:         "/20180115/zones/example.com/records/_443._tcp.www.example.com/TLSA",
+			existingBody: `{"items":[]}`,
+			patchItems: `[{"domain":"_443._tcp.www.example.com","rdata":"3 1 1 ` + certificateData +
+				`","rtype":"TLSA","ttl":300,"operation":"ADD"}]`,
+			// The certificate data is returned chunked and uppercased.
+			patchBody: `{"items":[{"domain":"_443._tcp.www.example.com","rdata":"3 1 1 ` +
+				strings.ToUpper(certificateData[:32]) + " " + strings.ToUpper(certificateData[32:]) +
+				`","rtype":"TLSA","ttl":300}]}`,
+		},
+		"caa_round_trip": {
+			recordType: "CAA",
+			caa:        &utils.CAA{Tag: "issue", Value: "letsencrypt.org"},
+			path:       "/20180115/zones/example.com/records/_443._tcp.www.example.com/CAA",
+			existingBody: `{"items":[{"domain":"_443._tcp.www.example.com","recordHash":"hash",` +
+				`"rdata":"0 issue \"pki.goog\"","rtype":"CAA","ttl":300}]}`,
+			patchItems: `[{"domain":"_443._tcp.www.example.com","recordHash":"hash",` +
+				`"rdata":"0 issue \"pki.goog\"","rtype":"CAA","ttl":300,"operation":"REMOVE"},` +
+				`{"domain":"_443._tcp.www.example.com","rdata":"0 issue \"letsencrypt.org\"",` +
+				`"rtype":"CAA","ttl":300,"operation":"ADD"}]`,
+			patchBody: `{"items":[{"domain":"_443._tcp.www.example.com",` +
+				`"rdata":"0 issue \"letsencrypt.org\"","rtype":"CAA","ttl":300}]}`,
+		},
+		"caa_up_to_date": {
+			recordType: "CAA",
+			caa:        &utils.CAA{Tag: "issue", Value: "letsencrypt.org"},
+			path:       "/20180115/zones/example.com/records/_443._tcp.www.example.com/CAA",
+			existingBody: `{"items":[{"domain":"_443._tcp.www.example.com","recordHash":"hash",` +
+				`"rdata":"0 issue \"letsencrypt.org\"","rtype":"CAA","ttl":300}]}`,
+		},
+		"caa_mismatch": {
+			recordType:   "CAA",
+			caa:          &utils.CAA{Tag: "issue", Value: "letsencrypt.org"},
+			path:         "/20180115/zones/example.com/records/_443._tcp.www.example.com/CAA",
+			existingBody: `{"items":[]}`,
+			patchItems: `[{"domain":"_443._tcp.www.example.com","rdata":"0 issue \"letsencrypt.org\"",` +
+				`"rtype":"CAA","ttl":300,"operation":"ADD"}]`,
+			patchBody: `{"items":[{"domain":"_443._tcp.www.example.com",` +
+				`"rdata":"0 issuewild \"letsencrypt.org\"","rtype":"CAA","ttl":300}]}`,
+			errWrapped: errors.ErrDataReceivedMismatch,
+			errMessage: `mismatching record data received: sent CAA record data ` +
+				`"0 issue \"letsencrypt.org\"" but received "0 issuewild \"letsencrypt.org\""`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, testCase.path, r.URL.Path)
+					response := &http.Response{StatusCode: http.StatusOK}
+					switch r.Method {
+					case http.MethodGet:
+						response.Body = io.NopCloser(strings.NewReader(testCase.existingBody))
+					case http.MethodPatch:
+						var requestData struct {
+							Items json.RawMessage `json:"items"`
+						}
+						err := json.NewDecoder(r.Body).Decode(&requestData)
+						require.NoError(t, err)
+						assert.JSONEq(t, testCase.patchItems, string(requestData.Items))
+						response.Body = io.NopCloser(strings.NewReader(testCase.patchBody))
+					default:
+						t.Errorf("unexpected method %s", r.Method)
+					}
+					return response, nil
+				}),
+			}
+
+			provider := &Provider{
+				domain:      "example.com",
+				host:        "_443._tcp.www",
+				tenancyOCID: "tenancy",
+				userOCID:    "user",
+				fingerprint: "fingerprint",
+				privateKey:  privateKey,
+				region:      "us-ashburn-1",
+				ttl:         300,
+				recordType:  testCase.recordType,
+				tlsa:        testCase.tlsa,
+				caa:         testCase.caa,
+				timeNow:     time.Now,
+			}
+			ip := netip.MustParseAddr("192.0.2.10")
+
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, ip, newIP)
+		})
+	}
+}
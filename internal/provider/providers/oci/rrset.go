@@ -0,0 +1,169 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+type record struct {
+	Domain     string `json:"domain"`
+	RecordHash string `json:"recordHash,omitempty"`
+	RData      string `json:"rdata"`
+	RType      string `json:"rtype"`
+	TTL        uint32 `json:"ttl"`
+}
+
+func (p *Provider) makeRRSetURL(name, recordType string) string {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "dns." + p.region + ".oraclecloud.com",
+		Path:   fmt.Sprintf("/20180115/zones/%s/records/%s/%s", p.domain, name, recordType),
+	}
+	return u.String()
+}
+
+func (p *Provider) do(ctx context.Context, client *http.Client,
+	method, url string, requestBody []byte) (records []record, err error) {
+	request, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	headers.SetUserAgent(request)
+	headers.SetAccept(request, "application/json")
+	if requestBody != nil {
+		headers.SetContentType(request, "application/json")
+	}
+
+	keyID := p.tenancyOCID + "/" + p.userOCID + "/" + p.fingerprint
+	err = signRequest(request, requestBody, keyID, p.privateKey, p.timeNow())
+	if err != nil {
+		return nil, fmt.Errorf("signing request: %w", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", errors.ErrRecordResourceSetNotFound,
+			utils.BodyToSingleLine(response.Body))
+	default:
+		return nil, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var data struct {
+		Items []record `json:"items"`
+	}
+	err = decoder.Decode(&data)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+	return data.Items, nil
+}
+
+// getRRSet returns the existing records for the name and record type,
+// and returns no record if the record set does not exist yet.
+// See https://docs.oracle.com/en-us/iaas/api/#/en/dns/20180115/RRSet/GetRRSet
+func (p *Provider) getRRSet(ctx context.Context, client *http.Client,
+	name, recordType string) (records []record, err error) {
+	records, err = p.do(ctx, client, http.MethodGet, p.makeRRSetURL(name, recordType), nil)
+	if stderrors.Is(err, errors.ErrRecordResourceSetNotFound) {
+		return nil, nil
+	}
+	return records, err
+}
+
+// patchRRSet removes the existing records given and adds a record for
+// each IP address of the record set given, in a single atomic operation.
+// The first IP address of the record set is the public IP address.
+// See https://docs.oracle.com/en-us/iaas/api/#/en/dns/20180115/RRSet/PatchRRSet
+func (p *Provider) patchRRSet(ctx context.Context, client *http.Client,
+	recordType string, existing []record, recordSet []netip.Addr) (newIP netip.Addr, err error) {
+	type operation struct {
+		record
+		Operation string `json:"operation"`
+	}
+
+	operations := make([]operation, 0, len(existing)+len(recordSet))
+	for _, existingRecord := range existing {
+		operations = append(operations, operation{
+			record: record{
+				Domain:     existingRecord.Domain,
+				RecordHash: existingRecord.RecordHash,
+				RData:      existingRecord.RData,
+				RType:      existingRecord.RType,
+				TTL:        existingRecord.TTL,
+			},
+			Operation: "REMOVE",
+		})
+	}
+	for _, ip := range recordSet {
+		operations = append(operations, operation{
+			record: record{
+				Domain: utils.BuildURLQueryHostname(p.host, p.domain),
+				RData:  ip.String(),
+				RType:  recordType,
+				TTL:    p.ttl,
+			},
+			Operation: "ADD",
+		})
+	}
+
+	requestData := struct {
+		Items []operation `json:"items"`
+	}{Items: operations}
+	requestBody, err := json.Marshal(requestData)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	name := utils.BuildURLQueryHostname(p.host, p.domain)
+	records, err := p.do(ctx, client, http.MethodPatch, p.makeRRSetURL(name, recordType), requestBody)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	ip := recordSet[0]
+	if len(recordSet) > 1 {
+		received := make([]string, len(records))
+		for i, record := range records {
+			received[i] = record.RData
+		}
+		err = utils.CheckRecordSet(recordSet, received)
+		if err != nil {
+			return netip.Addr{}, err
+		}
+		return ip, nil
+	}
+
+	if len(records) != 1 {
+		return netip.Addr{}, fmt.Errorf("%w: %d records instead of 1",
+			errors.ErrResultsCountReceived, len(records))
+	}
+
+	newIP, err = netip.ParseAddr(records[0].RData)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+	} else if newIP.Compare(ip) != 0 {
+		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
+			errors.ErrIPReceivedMismatch, ip, newIP)
+	}
+	return newIP, nil
+}
@@ -0,0 +1,68 @@
+package oci
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signRequest signs the request following the OCI request signing
+// specification, which is based on the draft-cavage HTTP signatures
+// using RSA-SHA256. The request body must be given since it is
+// hashed for requests with a body.
+// See https://docs.oracle.com/en-us/iaas/Content/API/Concepts/signingrequests.htm
+func signRequest(request *http.Request, body []byte, keyID string,
+	privateKey *rsa.PrivateKey, now time.Time) (err error) {
+	request.Header.Set("Date", now.UTC().Format(http.TimeFormat))
+
+	headerNames := []string{"date", "(request-target)", "host"}
+	switch request.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		bodyHash := sha256.Sum256(body)
+		request.Header.Set("X-Content-Sha256", base64.StdEncoding.EncodeToString(bodyHash[:]))
+		request.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		headerNames = append(headerNames, "content-length", "content-type", "x-content-sha256")
+	}
+
+	signingString := buildSigningString(request, headerNames)
+	hashed := sha256.Sum256([]byte(signingString))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return fmt.Errorf("signing: %w", err)
+	}
+
+	authorization := fmt.Sprintf(`Signature version="1",keyId="%s",algorithm="rsa-sha256",`+
+		`headers="%s",signature="%s"`, keyID, strings.Join(headerNames, " "),
+		base64.StdEncoding.EncodeToString(signature))
+	request.Header.Set("Authorization", authorization)
+	return nil
+}
+
+// buildSigningString builds the string to sign from the request,
+// using the header names given in their order.
+func buildSigningString(request *http.Request, headerNames []string) string {
+	buffer := bytes.NewBuffer(nil)
+	for i, headerName := range headerNames {
+		if i > 0 {
+			buffer.WriteByte('\n')
+		}
+		buffer.WriteString(headerName + ": ")
+		switch headerName {
+		case "(request-target)":
+			buffer.WriteString(strings.ToLower(request.Method) + " " + request.URL.RequestURI())
+		case "host":
+			buffer.WriteString(request.URL.Host)
+		default:
+			buffer.WriteString(request.Header.Get(headerName))
+		}
+	}
+	return buffer.String()
+}
@@ -0,0 +1,107 @@
+package oci
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_buildSigningString(t *testing.T) {
+	t.Parallel()
+
+	request, err := http.NewRequest(http.MethodPatch,
+		"https://dns.us-ashburn-1.oraclecloud.com/20180115/zones/example.com/records/example.com/A?a=b", nil)
+	require.NoError(t, err)
+	request.Header.Set("Date", "Thu, 05 Jan 2014 21:31:40 GMT")
+	request.Header.Set("Content-Length", "316")
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Content-Sha256", "V9Z20UJTvkvpJ50flBzKE32+6m2zJjweHpDMX/U4Uy0=")
+
+	signingString := buildSigningString(request, []string{"date", "(request-target)",
+		"host", "content-length", "content-type", "x-content-sha256"})
+
+	const expected = "date: Thu, 05 Jan 2014 21:31:40 GMT\n" +
+		"(request-target): patch /20180115/zones/example.com/records/example.com/A?a=b\n" +
+		"host: dns.us-ashburn-1.oraclecloud.com\n" +
+		"content-length: 316\n" +
+		"content-type: application/json\n" +
+		"x-content-sha256: V9Z20UJTvkvpJ50flBzKE32+6m2zJjweHpDMX/U4Uy0="
+	assert.Equal(t, expected, signingString)
+}
+
+func Test_signRequest(t *testing.T) {
+	t.Parallel()
+
+	const keySize = 2048
+	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	require.NoError(t, err)
+
+	now := time.Date(2014, time.January, 5, 21, 31, 40, 0, time.UTC)
+	authorizationRegex := regexp.MustCompile(`^Signature version="1",keyId="tenancy/user/fingerprint",` +
+		`algorithm="rsa-sha256",headers="([^"]+)",signature="([^"]+)"$`)
+
+	testCases := map[string]struct {
+		method      string
+		body        []byte
+		headerNames string
+		headers     map[string]string
+	}{
+		"get": {
+			method:      http.MethodGet,
+			headerNames: "date (request-target) host",
+			headers: map[string]string{
+				"Date": "Sun, 05 Jan 2014 21:31:40 GMT",
+			},
+		},
+		"patch": {
+			method:      http.MethodPatch,
+			body:        []byte(`{"items":[]}`),
+			headerNames: "date (request-target) host content-length content-type x-content-sha256",
+			headers: map[string]string{
+				"Date":             "Sun, 05 Jan 2014 21:31:40 GMT",
+				"Content-Length":   "12",
+				"X-Content-Sha256": "7vRnQa38Op92KU07ePN6RfETCSrJ1E7nfHoDioj/CaE=",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			request, err := http.NewRequest(testCase.method,
+				"https://dns.us-ashburn-1.oraclecloud.com/20180115/zones/example.com", nil)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			err = signRequest(request, testCase.body, "tenancy/user/fingerprint", privateKey, now)
+			require.NoError(t, err)
+
+			for key, value := range testCase.headers {
+				assert.Equal(t, value, request.Header.Get(key), key)
+			}
+
+			matches := authorizationRegex.FindStringSubmatch(request.Header.Get("Authorization"))
+			require.Len(t, matches, 3)
+			assert.Equal(t, testCase.headerNames, matches[1])
+
+			signature, err := base64.StdEncoding.DecodeString(matches[2])
+			require.NoError(t, err)
+			signingString := buildSigningString(request, strings.Split(matches[1], " "))
+			hashed := sha256.Sum256([]byte(signingString))
+			err = rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, hashed[:], signature)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -27,6 +27,6 @@ func extractAPIError(response *http.Response) (err error) {
 
 	_ = response.Body.Close()
 
-	return fmt.Errorf("%w: %s: %s: for query ID: %s",
-		errors.ErrHTTPStatusNotValid, response.Status, apiError.Message, queryID)
+	return fmt.Errorf("%w: for query ID: %s",
+		errors.NewHTTPStatusError(response.StatusCode, apiError.Message), queryID)
 }
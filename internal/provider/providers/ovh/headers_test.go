@@ -0,0 +1,61 @@
+package ovh
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Provider_setHeaderAuth(t *testing.T) {
+	t.Parallel()
+
+	apiURL, err := url.Parse("https://eu.api.ovh.com/1.0")
+	require.NoError(t, err)
+	provider := Provider{
+		apiURL:      apiURL,
+		appKey:      "key",
+		appSecret:   "secret",
+		consumerKey: "consumer",
+		timeNow: func() time.Time {
+			return time.Unix(1700000030, 0)
+		},
+	}
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			assert.Equal(t, "https://eu.api.ovh.com/1.0/auth/time", r.URL.String())
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("1700000000")),
+			}, nil
+		}),
+	}
+
+	timestamp, err := provider.getAdjustedUnixTimestamp(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000000), timestamp)
+
+	recordURL, err := url.Parse("https://eu.api.ovh.com/1.0/domain/zone/example.com/record/1")
+	require.NoError(t, err)
+	header := make(http.Header)
+	provider.setHeaderAuth(header, timestamp, http.MethodPut, recordURL, []byte(`{"target":"1.2.3.4"}`))
+
+	expectedHeader := http.Header{
+		"X-Ovh-Timestamp": {"1700000000"},
+		"X-Ovh-Consumer":  {"consumer"},
+		"X-Ovh-Signature": {"$1$ae8bd3da24eaebf0d7f9404e1379a74312222762"},
+	}
+	assert.Equal(t, expectedHeader, header)
+}
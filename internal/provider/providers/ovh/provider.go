@@ -59,6 +59,12 @@ func New(data json.RawMessage, domain, host string,
 		return nil, err
 	}
 
+	mode := extraSettings.Mode
+	if mode == "" && extraSettings.Username == "" && extraSettings.AppKey != "" {
+		// API credentials without DynHost credentials select the API mode.
+		mode = "api"
+	}
+
 	p = &Provider{
 		domain:        domain,
 		host:          host,
@@ -67,7 +73,7 @@ func New(data json.RawMessage, domain, host string,
 		username:      extraSettings.Username,
 		password:      extraSettings.Password,
 		useProviderIP: extraSettings.UseProviderIP,
-		mode:          extraSettings.Mode,
+		mode:          mode,
 		apiURL:        apiURL,
 		appKey:        extraSettings.AppKey,
 		appSecret:     extraSettings.AppSecret,
@@ -128,6 +134,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true, CreateMissing: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -164,9 +179,11 @@ func (p *Provider) updateWithDynHost(ctx context.Context, client *http.Client,
 	}
 	headers.SetUserAgent(request)
 
-	response, err := client.Do(request)
+	response, verified, err := utils.DoDynDNS2Request(ctx, client, request, p.BuildDomainName(), ip)
 	if err != nil {
 		return netip.Addr{}, fmt.Errorf("doing http request: %w", err)
+	} else if verified {
+		return ip, nil
 	}
 	defer response.Body.Close()
 
@@ -177,7 +194,7 @@ func (p *Provider) updateWithDynHost(ctx context.Context, client *http.Client,
 	s := string(b)
 
 	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid, response.StatusCode, s)
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, s)
 	}
 
 	switch {
@@ -220,10 +237,14 @@ func (p *Provider) updateWithZoneDNS(ctx context.Context, client *http.Client, i
 	}
 
 	if len(recordIDs) == 0 {
+		if !utils.CreationAllowed(ctx) {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
 		err = p.createRecord(ctx, client, recordType, subDomain, ipStr, timestamp)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
 		}
+		utils.SignalCreated(ctx)
 	} else {
 		for _, recordID := range recordIDs {
 			err = p.updateRecord(ctx, client, recordID, ipStr, timestamp)
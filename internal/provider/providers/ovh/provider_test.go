@@ -0,0 +1,69 @@
+package ovh
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New_mode(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings   string
+		host       string
+		mode       string
+		errWrapped error
+		errMessage string
+	}{
+		"dynhost": {
+			settings: `{"username":"user","password":"pass"}`,
+			host:     "@",
+		},
+		"api_explicit": {
+			settings: `{"mode":"api","app_key":"key","app_secret":"secret","consumer_key":"consumer"}`,
+			host:     "*",
+			mode:     "api",
+		},
+		"api_from_credentials": {
+			settings: `{"app_key":"key","app_secret":"secret","consumer_key":"consumer"}`,
+			host:     "*",
+			mode:     "api",
+		},
+		"api_credentials_missing": {
+			settings:   `{"app_key":"key","app_secret":"secret"}`,
+			host:       "@",
+			errWrapped: errors.ErrConsumerKeyNotSet,
+			errMessage: "consumer key is not set",
+		},
+		"dynamic_explicit": {
+			settings:   `{"mode":"dynamic","app_key":"key","app_secret":"secret","consumer_key":"consumer"}`,
+			host:       "@",
+			errWrapped: errors.ErrUsernameNotSet,
+			errMessage: "username is not set",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(testCase.settings), "example.com",
+				testCase.host, ipversion.IP4, netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.NotNil(t, provider)
+			assert.Equal(t, testCase.mode, provider.mode)
+		})
+	}
+}
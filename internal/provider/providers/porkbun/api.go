@@ -11,9 +11,15 @@ import (
 	"github.com/qdm12/ddns-updater/internal/provider/errors"
 )
 
+type record struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+	TTL     string `json:"ttl"`
+}
+
 // See https://porkbun.com/api/json/v3/documentation#DNS%20Retrieve%20Records%20by%20Domain,%20Subdomain%20and%20Type
-func (p *Provider) getRecordIDs(ctx context.Context, client *http.Client, recordType string) (
-	recordIDs []string, err error) {
+func (p *Provider) getRecords(ctx context.Context, client *http.Client, recordType string) (
+	records []record, err error) {
 	u := url.URL{
 		Scheme: "https",
 		Host:   "porkbun.com",
@@ -50,14 +56,11 @@ func (p *Provider) getRecordIDs(ctx context.Context, client *http.Client, record
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
-			response.StatusCode, makeErrorMessage(response.Body))
+		return nil, errors.NewHTTPStatusError(response.StatusCode, makeErrorMessage(response.Body))
 	}
 
 	var responseData struct {
-		Records []struct {
-			ID string `json:"id"`
-		} `json:"records"`
+		Records []record `json:"records"`
 	}
 	decoder := json.NewDecoder(response.Body)
 	err = decoder.Decode(&responseData)
@@ -65,11 +68,7 @@ func (p *Provider) getRecordIDs(ctx context.Context, client *http.Client, record
 		return nil, fmt.Errorf("json decoding response body: %w", err)
 	}
 
-	for _, record := range responseData.Records {
-		recordIDs = append(recordIDs, record.ID)
-	}
-
-	return recordIDs, nil
+	return responseData.Records, nil
 }
 
 // See https://porkbun.com/api/json/v3/documentation#DNS%20Create%20Record
@@ -115,34 +114,35 @@ func (p *Provider) createRecord(ctx context.Context, client *http.Client,
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
-			response.StatusCode, makeErrorMessage(response.Body))
+		return errors.NewHTTPStatusError(response.StatusCode, makeErrorMessage(response.Body))
 	}
 	return nil
 }
 
-// See https://porkbun.com/api/json/v3/documentation#DNS%20Edit%20Record%20by%20Domain%20and%20ID
-func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
-	recordType string, ipStr string, recordID string) (err error) {
+// updateRecords edits all the records matching the host and record type.
+// See https://porkbun.com/api/json/v3/documentation#DNS%20Edit%20Record%20by%20Domain,%20Subdomain%20and%20Type
+func (p *Provider) updateRecords(ctx context.Context, client *http.Client,
+	recordType string, ipStr string) (err error) {
 	u := url.URL{
 		Scheme: "https",
 		Host:   "porkbun.com",
-		Path:   "/api/json/v3/dns/edit/" + p.domain + "/" + recordID,
+		Path:   "/api/json/v3/dns/editByNameType/" + p.domain + "/" + recordType + "/",
+	}
+	if p.host != "@" {
+		u.Path += p.host
 	}
 	postRecordsParams := struct {
 		SecretAPIKey string `json:"secretapikey"`
 		APIKey       string `json:"apikey"`
 		Content      string `json:"content"`
-		Type         string `json:"type"`
-		TTL          string `json:"ttl"`
-		Name         string `json:"name,omitempty"`
+		TTL          string `json:"ttl,omitempty"`
 	}{
 		SecretAPIKey: p.secretAPIKey,
 		APIKey:       p.apiKey,
 		Content:      ipStr,
-		Type:         recordType,
-		TTL:          fmt.Sprint(p.ttl),
-		Name:         p.host,
+	}
+	if p.ttl != 0 {
+		postRecordsParams.TTL = fmt.Sprint(p.ttl)
 	}
 	buffer := bytes.NewBuffer(nil)
 	encoder := json.NewEncoder(buffer)
@@ -164,8 +164,7 @@ func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
-			response.StatusCode, makeErrorMessage(response.Body))
+		return errors.NewHTTPStatusError(response.StatusCode, makeErrorMessage(response.Body))
 	}
 	return nil
 }
@@ -208,8 +207,7 @@ func (p *Provider) deleteAliasRecord(ctx context.Context, client *http.Client) (
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
-			response.StatusCode, makeErrorMessage(response.Body))
+		return errors.NewHTTPStatusError(response.StatusCode, makeErrorMessage(response.Body))
 	}
 	return nil
 }
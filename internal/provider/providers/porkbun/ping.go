@@ -0,0 +1,73 @@
+package porkbun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+)
+
+// Verify checks the API key and secret API key are accepted by Porkbun,
+// using the ping endpoint which requires authentication.
+// See https://porkbun.com/api/json/v3/documentation#Authentication
+func (p *Provider) Verify(ctx context.Context, client *http.Client) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "porkbun.com",
+		Path:   "/api/json/v3/ping",
+	}
+
+	requestData := struct {
+		SecretAPIKey string `json:"secretapikey"`
+		APIKey       string `json:"apikey"`
+	}{
+		SecretAPIKey: p.secretAPIKey,
+		APIKey:       p.apiKey,
+	}
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	err = encoder.Encode(requestData)
+	if err != nil {
+		return fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), buffer)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("doing http request: %w", err)
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %d: %s", errors.ErrAuth,
+			response.StatusCode, makeErrorMessage(response.Body))
+	default:
+		return errors.NewHTTPStatusError(response.StatusCode, makeErrorMessage(response.Body))
+	}
+
+	var responseData struct {
+		Status string `json:"status"`
+	}
+	decoder := json.NewDecoder(response.Body)
+	err = decoder.Decode(&responseData)
+	if err != nil {
+		return fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	if responseData.Status != "SUCCESS" {
+		return fmt.Errorf("%w: status %q is not SUCCESS",
+			errors.ErrUnsuccessful, responseData.Status)
+	}
+	return nil
+}
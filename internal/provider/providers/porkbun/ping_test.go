@@ -0,0 +1,94 @@
+package porkbun
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Provider_Verify(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		statusCode   int
+		responseBody string
+		errWrapped   error
+		errMessage   string
+	}{
+		"success": {
+			statusCode:   http.StatusOK,
+			responseBody: `{"status":"SUCCESS","yourIp":"192.0.2.10"}`,
+		},
+		"invalid_api_key": {
+			statusCode:   http.StatusBadRequest,
+			responseBody: `{"status":"ERROR","message":"Invalid API key. (002)"}`,
+			errWrapped:   errors.ErrAuth,
+			errMessage:   "bad authentication: 400: Invalid API key. (002)",
+		},
+		"server_error": {
+			statusCode:   http.StatusInternalServerError,
+			responseBody: `internal error`,
+			errWrapped:   errors.ErrHTTPStatusNotValid,
+			errMessage:   "HTTP status is not valid: 500: internal error",
+		},
+		"status_not_success": {
+			statusCode:   http.StatusOK,
+			responseBody: `{"status":"ERROR"}`,
+			errWrapped:   errors.ErrUnsuccessful,
+			errMessage:   `unsuccessful result: status "ERROR" is not SUCCESS`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, http.MethodPost, r.Method)
+					assert.Equal(t, "https://porkbun.com/api/json/v3/ping", r.URL.String())
+					var requestData struct {
+						SecretAPIKey string `json:"secretapikey"`
+						APIKey       string `json:"apikey"`
+					}
+					err := json.NewDecoder(r.Body).Decode(&requestData)
+					require.NoError(t, err)
+					assert.Equal(t, "secret", requestData.SecretAPIKey)
+					assert.Equal(t, "key", requestData.APIKey)
+					return &http.Response{
+						StatusCode: testCase.statusCode,
+						Body:       io.NopCloser(strings.NewReader(testCase.responseBody)),
+					}, nil
+				}),
+			}
+
+			provider := &Provider{
+				domain:       "example.com",
+				host:         "@",
+				apiKey:       "key",
+				secretAPIKey: "secret",
+			}
+
+			err := provider.Verify(context.Background(), client)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
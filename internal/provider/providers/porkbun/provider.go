@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"net/netip"
 
@@ -29,9 +30,9 @@ func New(data json.RawMessage, domain, host string,
 	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
 	p *Provider, err error) {
 	extraSettings := struct {
-		SecretAPIKey string `json:"secret_api_key"`
-		APIKey       string `json:"api_key"`
-		TTL          uint   `json:"ttl"`
+		SecretAPIKey string    `json:"secret_api_key"`
+		APIKey       string    `json:"api_key"`
+		TTL          utils.TTL `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
@@ -44,7 +45,7 @@ func New(data json.RawMessage, domain, host string,
 		ipv6Suffix:   ipv6Suffix,
 		secretAPIKey: extraSettings.SecretAPIKey,
 		apiKey:       extraSettings.APIKey,
-		ttl:          extraSettings.TTL,
+		ttl:          uint(extraSettings.TTL),
 	}
 	err = p.isValid()
 	if err != nil {
@@ -60,7 +61,8 @@ func (p *Provider) isValid() error {
 	case p.secretAPIKey == "":
 		return fmt.Errorf("%w", errors.ErrAPISecretNotSet)
 	}
-	return nil
+	const minTTL = 600
+	return utils.CheckTTL(utils.TTL(p.ttl), minTTL, math.MaxUint32)
 }
 
 func (p *Provider) String() string {
@@ -87,6 +89,20 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{
+		IPv4:          true,
+		IPv6:          true,
+		CreateMissing: true,
+		TTL:           true,
+	}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -113,12 +129,15 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		recordType = constants.AAAA
 	}
 	ipStr := ip.String()
-	recordIDs, err := p.getRecordIDs(ctx, client, recordType)
+	records, err := p.getRecords(ctx, client, recordType)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("getting record IDs: %w", err)
+		return netip.Addr{}, fmt.Errorf("getting records: %w", err)
 	}
 
-	if len(recordIDs) == 0 {
+	if len(records) == 0 {
+		if !utils.CreationAllowed(ctx) {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
 		// ALIAS record needs to be deleted to allow creating an A record.
 		err = p.deleteALIASRecordIfNeeded(ctx, client)
 		if err != nil {
@@ -129,24 +148,40 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
 		}
+		utils.SignalCreated(ctx)
 		return ip, nil
 	}
 
-	for _, recordID := range recordIDs {
-		err = p.updateRecord(ctx, client, recordType, ipStr, recordID)
-		if err != nil {
-			return netip.Addr{}, fmt.Errorf("updating record: %w", err)
-		}
+	if p.upToDate(records, ipStr) {
+		// Porkbun fails to edit records which would not change.
+		return ip, nil
+	}
+
+	err = p.updateRecords(ctx, client, recordType, ipStr)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("updating records: %w", err)
 	}
 
 	return ip, nil
 }
 
+// upToDate returns true if all the records given have the IP address
+// and, if it is set, the TTL of the provider.
+func (p *Provider) upToDate(records []record, ipStr string) bool {
+	for _, record := range records {
+		if record.Content != ipStr ||
+			(p.ttl != 0 && record.TTL != fmt.Sprint(p.ttl)) {
+			return false
+		}
+	}
+	return true
+}
+
 func (p *Provider) deleteALIASRecordIfNeeded(ctx context.Context, client *http.Client) (err error) {
-	aliasRecordIDs, err := p.getRecordIDs(ctx, client, "ALIAS")
+	aliasRecords, err := p.getRecords(ctx, client, "ALIAS")
 	if err != nil {
-		return fmt.Errorf("getting ALIAS record IDs: %w", err)
-	} else if len(aliasRecordIDs) == 0 {
+		return fmt.Errorf("getting ALIAS records: %w", err)
+	} else if len(aliasRecords) == 0 {
 		return nil
 	}
 
@@ -0,0 +1,91 @@
+package porkbun
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	const (
+		retrieveURL = "https://porkbun.com/api/json/v3/dns/retrieveByNameType/example.com/A/home"
+		editURL     = "https://porkbun.com/api/json/v3/dns/editByNameType/example.com/A/home"
+	)
+
+	testCases := map[string]struct {
+		settings string
+		records  string
+		editBody string
+	}{
+		"up_to_date": {
+			settings: `{"api_key":"key","secret_api_key":"secret"}`,
+			records:  `[{"id":"1","content":"1.2.3.4","ttl":"600"},{"id":"2","content":"1.2.3.4","ttl":"600"}]`,
+		},
+		"ip_changed": {
+			settings: `{"api_key":"key","secret_api_key":"secret"}`,
+			records:  `[{"id":"1","content":"1.2.3.4","ttl":"600"},{"id":"2","content":"5.6.7.8","ttl":"600"}]`,
+			editBody: `{"secretapikey":"secret","apikey":"key","content":"1.2.3.4"}`,
+		},
+		"ttl_changed": {
+			settings: `{"api_key":"key","secret_api_key":"secret","ttl":"1h"}`,
+			records:  `[{"id":"1","content":"1.2.3.4","ttl":"600"}]`,
+			editBody: `{"secretapikey":"secret","apikey":"key","content":"1.2.3.4","ttl":"3600"}`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(testCase.settings), "example.com", "home",
+				ipversion.IP4, netip.Prefix{})
+			require.NoError(t, err)
+
+			editRequests := 0
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, http.MethodPost, r.Method)
+					body, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+
+					responseBody := `{"status":"SUCCESS"}`
+					switch r.URL.String() {
+					case retrieveURL:
+						responseBody = `{"status":"SUCCESS","records":` + testCase.records + `}`
+					case editURL:
+						editRequests++
+						assert.JSONEq(t, testCase.editBody, string(body))
+					default:
+						t.Errorf("unexpected request to %s", r.URL)
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(responseBody)),
+					}, nil
+				}),
+			}
+
+			ip := netip.MustParseAddr("1.2.3.4")
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			require.NoError(t, err)
+			assert.Equal(t, ip, newIP)
+			expectedEdits := 0
+			if testCase.editBody != "" {
+				expectedEdits = 1
+			}
+			assert.Equal(t, expectedEdits, editRequests)
+		})
+	}
+}
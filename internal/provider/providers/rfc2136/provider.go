@@ -0,0 +1,311 @@
+package rfc2136
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain       string
+	host         string
+	ipVersion    ipversion.IPVersion
+	ipv6Suffix   netip.Prefix
+	nameserver   string
+	zone         string
+	keyName      string
+	keyAlgorithm string
+	keySecret    string
+	ttl          uint32
+	// recordType is the empty string to update A and AAAA records,
+	// or TXT, MX, SRV or CAA to set the record value rendered from
+	// the value template.
+	recordType string
+	value      string
+	timeNow    func() time.Time
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	p *Provider, err error) {
+	extraSettings := struct {
+		Nameserver   string    `json:"nameserver"`
+		Zone         string    `json:"zone"`
+		KeyName      string    `json:"tsig_key_name"`
+		KeyAlgorithm string    `json:"tsig_algorithm"`
+		KeySecret    string    `json:"tsig_secret"`
+		TTL          utils.TTL `json:"ttl"`
+		RecordType   string    `json:"record_type"`
+		Value        string    `json:"value"`
+	}{}
+	err = json.Unmarshal(data, &extraSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	nameserver := extraSettings.Nameserver
+	if nameserver != "" {
+		_, _, err = net.SplitHostPort(nameserver)
+		if err != nil {
+			const defaultPort = "53"
+			nameserver = net.JoinHostPort(nameserver, defaultPort)
+		}
+	}
+
+	zone := extraSettings.Zone
+	if zone == "" {
+		zone = domain
+	}
+
+	keyAlgorithm := strings.ToLower(extraSettings.KeyAlgorithm)
+	if keyAlgorithm == "" {
+		keyAlgorithm = "hmac-sha256"
+	}
+
+	ttl := uint32(extraSettings.TTL)
+	if ttl == 0 {
+		const defaultTTL = 300
+		ttl = defaultTTL
+	}
+
+	p = &Provider{
+		domain:       domain,
+		host:         host,
+		ipVersion:    ipVersion,
+		ipv6Suffix:   ipv6Suffix,
+		nameserver:   nameserver,
+		zone:         dns.Fqdn(zone),
+		keyName:      dns.Fqdn(strings.ToLower(extraSettings.KeyName)),
+		keyAlgorithm: keyAlgorithm,
+		keySecret:    extraSettings.KeySecret,
+		ttl:          ttl,
+		recordType:   strings.ToUpper(extraSettings.RecordType),
+		value:        extraSettings.Value,
+		timeNow:      time.Now,
+	}
+	err = p.isValid(extraSettings.KeyName)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// tsigAlgorithms maps the TSIG algorithm names
+// accepted in the settings to their DNS names.
+func tsigAlgorithms() map[string]string {
+	return map[string]string{
+		"hmac-sha1":   dns.HmacSHA1,
+		"hmac-sha224": dns.HmacSHA224,
+		"hmac-sha256": dns.HmacSHA256,
+		"hmac-sha384": dns.HmacSHA384,
+		"hmac-sha512": dns.HmacSHA512,
+	}
+}
+
+func (p *Provider) isValid(keyName string) error {
+	_, algorithmValid := tsigAlgorithms()[p.keyAlgorithm]
+	switch {
+	case p.domain == "":
+		return fmt.Errorf("%w", errors.ErrDomainNotSet)
+	case p.host == "":
+		return fmt.Errorf("%w", errors.ErrHostNotSet)
+	case p.nameserver == "":
+		return fmt.Errorf("%w", errors.ErrNameserverNotSet)
+	case !dns.IsSubDomain(p.zone, dns.Fqdn(p.BuildDomainName())):
+		return fmt.Errorf("%w: %s is not in zone %s",
+			errors.ErrDomainNotValid, p.BuildDomainName(), p.zone)
+	case keyName == "":
+		return fmt.Errorf("%w", errors.ErrKeyNameNotSet)
+	case !algorithmValid:
+		return fmt.Errorf("%w: %s", errors.ErrKeyAlgorithmNotValid, p.keyAlgorithm)
+	case p.keySecret == "":
+		return fmt.Errorf("%w", errors.ErrSecretNotSet)
+	case p.recordType == "" && p.value != "":
+		return fmt.Errorf("%w: value can only be set with a record type",
+			errors.ErrRecordValueNotValid)
+	}
+	_, err := base64.StdEncoding.DecodeString(p.keySecret)
+	if err != nil {
+		return fmt.Errorf("%w: decoding base64: %w", errors.ErrSecretNotValid, err)
+	}
+	if p.recordType != "" {
+		err = utils.CheckRecordValue(p.recordType, p.value)
+		if err != nil {
+			return err
+		}
+	}
+	// RFC 2181 section 8 limits the TTL to 31 bits.
+	const minTTL, maxTTL = 1, math.MaxInt32
+	return utils.CheckTTL(utils.TTL(p.ttl), minTTL, maxTTL)
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.RFC2136, p.ipVersion)
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) IPv6Suffix() netip.Prefix {
+	return p.ipv6Suffix
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+// SkipLookup returns true for TXT, MX, SRV and CAA records, since
+// the IP address cannot be resolved from their name to check if
+// an update is needed.
+func (p *Provider) SkipLookup() bool {
+	return p.recordType != ""
+}
+
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{
+		IPv4:          true,
+		IPv6:          true,
+		CreateMissing: true,
+		TTL:           true,
+	}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName()),
+		Host:      p.Host(),
+		Provider:  "<a href=\"https://www.rfc-editor.org/rfc/rfc2136\">RFC 2136</a>",
+		IPVersion: p.ipVersion.String(),
+	}
+}
+
+// Update replaces the A or AAAA records of the hostname with a single
+// record for the IP address given, or the records of the record type
+// configured with a single record with the value rendered for the IP
+// address given, using a TSIG signed DNS UPDATE message sent to the
+// nameserver. The record is created if it does not exist, unless record
+// creation is disabled for the context. The nameserver is then queried
+// to verify it serves the new record.
+// The HTTP client is not used since the update is done over DNS.
+// See https://www.rfc-editor.org/rfc/rfc2136 and
+// https://www.rfc-editor.org/rfc/rfc8945
+func (p *Provider) Update(ctx context.Context, _ *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	fqdn := dns.Fqdn(p.BuildDomainName())
+	record, err := p.makeRecord(fqdn, ip)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("making record: %w", err)
+	}
+
+	client := &dns.Client{
+		TsigSecret: map[string]string{p.keyName: p.keySecret},
+	}
+
+	message := new(dns.Msg)
+	message.SetUpdate(p.zone)
+	if !utils.CreationAllowed(ctx) {
+		// Prerequisite for the record set to exist
+		message.RRsetUsed([]dns.RR{record})
+	}
+	message.RemoveRRset([]dns.RR{record})
+	message.Insert([]dns.RR{record})
+	const fudgeSeconds = 300
+	message.SetTsig(p.keyName, tsigAlgorithms()[p.keyAlgorithm], fudgeSeconds, p.timeNow().Unix())
+
+	response, _, err := client.ExchangeContext(ctx, message, p.nameserver)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("exchanging update message: %w", err)
+	} else if response.Rcode == dns.RcodeNXRrset {
+		return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+	} else if response.Rcode != dns.RcodeSuccess {
+		return netip.Addr{}, fmt.Errorf("%w: update response code %s",
+			errors.ErrUnsuccessful, dns.RcodeToString[response.Rcode])
+	}
+
+	if p.recordType != "" {
+		err = p.queryRecord(ctx, client, record)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("verifying update: %w", err)
+		}
+		return ip, nil
+	}
+
+	newIP, err = p.queryIP(ctx, client, fqdn, record.Header().Rrtype, ip)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("verifying update: %w", err)
+	}
+	return newIP, nil
+}
+
+// queryIP queries the nameserver for the records of the type given and
+// returns the IP address given if one of them matches it. Otherwise, it
+// returns an error mentioning the IP addresses received.
+func (p *Provider) queryIP(ctx context.Context, client *dns.Client, fqdn string,
+	recordType uint16, ip netip.Addr) (newIP netip.Addr, err error) {
+	message := new(dns.Msg)
+	message.SetQuestion(fqdn, recordType)
+	response, _, err := client.ExchangeContext(ctx, message, p.nameserver)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("exchanging query message: %w", err)
+	} else if response.Rcode != dns.RcodeSuccess {
+		return netip.Addr{}, fmt.Errorf("%w: query response code %s",
+			errors.ErrUnsuccessful, dns.RcodeToString[response.Rcode])
+	}
+
+	receivedIPs := make([]string, 0, len(response.Answer))
+	for _, answer := range response.Answer {
+		var netIP net.IP
+		switch record := answer.(type) {
+		case *dns.A:
+			netIP = record.A
+		case *dns.AAAA:
+			netIP = record.AAAA
+		default:
+			continue
+		}
+		receivedIP, ok := netip.AddrFromSlice(netIP)
+		if !ok {
+			return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrIPReceivedMalformed, netIP)
+		} else if receivedIP.Unmap() == ip {
+			return ip, nil
+		}
+		receivedIPs = append(receivedIPs, receivedIP.Unmap().String())
+	}
+
+	if len(receivedIPs) == 0 {
+		return netip.Addr{}, fmt.Errorf("%w", errors.ErrReceivedNoIP)
+	}
+	return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
+		errors.ErrIPReceivedMismatch, ip, strings.Join(receivedIPs, ", "))
+}
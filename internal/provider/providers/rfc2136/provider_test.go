@@ -0,0 +1,330 @@
+package rfc2136
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKeyName   = "ddns."
+	testKeySecret = "c2VjcmV0LXNlY3JldC1zZWNyZXQtc2VjcmV0"
+)
+
+// stubServer is a nameserver applying the TSIG signed DNS UPDATE
+// messages it receives, and serving the A and AAAA records it holds.
+type stubServer struct {
+	ignoreUpdates bool
+	mutex         sync.Mutex
+	records       map[string][]dns.RR
+	// tsig and tsigStatus are the TSIG record and its verification
+	// status of the last update message received.
+	tsig       *dns.TSIG
+	tsigStatus error
+}
+
+func (s *stubServer) ServeDNS(w dns.ResponseWriter, request *dns.Msg) {
+	response := new(dns.Msg)
+	response.SetReply(request)
+
+	if request.Opcode == dns.OpcodeUpdate {
+		s.mutex.Lock()
+		s.tsig, s.tsigStatus = request.IsTsig(), w.TsigStatus()
+		s.mutex.Unlock()
+		if w.TsigStatus() != nil || request.IsTsig() == nil {
+			response.Rcode = dns.RcodeNotAuth
+			_ = w.WriteMsg(response)
+			return
+		}
+		if !s.ignoreUpdates {
+			s.applyUpdate(request.Ns)
+		}
+		tsig := request.IsTsig()
+		response.SetTsig(tsig.Hdr.Name, tsig.Algorithm, tsig.Fudge, time.Now().Unix())
+		_ = w.WriteMsg(response)
+		return
+	}
+
+	s.mutex.Lock()
+	question := request.Question[0]
+	key := dns.CanonicalName(question.Name) + dns.TypeToString[question.Qtype]
+	response.Answer = s.records[key]
+	s.mutex.Unlock()
+	_ = w.WriteMsg(response)
+}
+
+func (s *stubServer) applyUpdate(updates []dns.RR) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, rr := range updates {
+		header := rr.Header()
+		key := dns.CanonicalName(header.Name) + dns.TypeToString[header.Rrtype]
+		switch header.Class {
+		case dns.ClassANY: // delete RRset
+			delete(s.records, key)
+		case dns.ClassINET: // add to RRset
+			s.records[key] = append(s.records[key], rr)
+		}
+	}
+}
+
+func startStubServer(t *testing.T, stub *stubServer) (address string) {
+	t.Helper()
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	started := make(chan struct{})
+	server := &dns.Server{
+		PacketConn:        packetConn,
+		Handler:           stub,
+		TsigSecret:        map[string]string{testKeyName: testKeySecret},
+		NotifyStartedFunc: func() { close(started) },
+		// The default accept function rejects DNS UPDATE messages
+		MsgAcceptFunc: func(dns.Header) dns.MsgAcceptAction { return dns.MsgAccept },
+	}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	<-started
+	t.Cleanup(func() {
+		_ = server.Shutdown()
+	})
+	return packetConn.LocalAddr().String()
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		secret        string
+		recordType    string
+		value         string
+		ignoreUpdates bool
+		existing      []dns.RR
+		ip            netip.Addr
+		newIP         netip.Addr
+		errWrapped    error
+		errMessage    string
+	}{
+		"create_ipv4": {
+			secret: testKeySecret,
+			ip:     netip.MustParseAddr("1.2.3.4"),
+			newIP:  netip.MustParseAddr("1.2.3.4"),
+		},
+		"replace_ipv6": {
+			secret: testKeySecret,
+			existing: []dns.RR{&dns.AAAA{
+				Hdr:  dns.RR_Header{Name: "www.example.com.", Rrtype: dns.TypeAAAA, Class: dns.ClassINET},
+				AAAA: net.ParseIP("2001:db8::2"),
+			}},
+			ip:    netip.MustParseAddr("2001:db8::1"),
+			newIP: netip.MustParseAddr("2001:db8::1"),
+		},
+		"txt_value": {
+			secret:     testKeySecret,
+			recordType: "TXT",
+			value:      `v=spf1 ip4:{ip} "-all"`,
+			existing: []dns.RR{&dns.TXT{
+				Hdr: dns.RR_Header{Name: "www.example.com.", Rrtype: dns.TypeTXT, Class: dns.ClassINET},
+				Txt: []string{"old"},
+			}},
+			ip:    netip.MustParseAddr("1.2.3.4"),
+			newIP: netip.MustParseAddr("1.2.3.4"),
+		},
+		"srv_value": {
+			secret:     testKeySecret,
+			recordType: "SRV",
+			value:      "10 5 5060 sip.example.com",
+			ip:         netip.MustParseAddr("1.2.3.4"),
+			newIP:      netip.MustParseAddr("1.2.3.4"),
+		},
+		"mx_value_not_served": {
+			secret:        testKeySecret,
+			recordType:    "MX",
+			value:         "10 mail.example.com",
+			ignoreUpdates: true,
+			ip:            netip.MustParseAddr("1.2.3.4"),
+			errWrapped:    errors.ErrDataReceivedMismatch,
+			errMessage: "verifying update: mismatching record data received: " +
+				`sent MX record data "10 mail.example.com." but received 0 records without it`,
+		},
+		"bad_tsig_secret": {
+			secret:     "b3RoZXItc2VjcmV0",
+			ip:         netip.MustParseAddr("1.2.3.4"),
+			errWrapped: errors.ErrUnsuccessful,
+			errMessage: "unsuccessful result: update response code NOTAUTH",
+		},
+		"not_served": {
+			secret:        testKeySecret,
+			ignoreUpdates: true,
+			existing: []dns.RR{&dns.A{
+				Hdr: dns.RR_Header{Name: "www.example.com.", Rrtype: dns.TypeA, Class: dns.ClassINET},
+				A:   net.ParseIP("5.6.7.8"),
+			}},
+			ip:         netip.MustParseAddr("1.2.3.4"),
+			errWrapped: errors.ErrIPReceivedMismatch,
+			errMessage: "verifying update: mismatching IP address received: " +
+				"sent ip 1.2.3.4 to update but received 5.6.7.8",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			stub := &stubServer{
+				ignoreUpdates: testCase.ignoreUpdates,
+				records:       make(map[string][]dns.RR),
+			}
+			for _, rr := range testCase.existing {
+				stub.applyUpdate([]dns.RR{rr})
+			}
+			address := startStubServer(t, stub)
+
+			settings, err := json.Marshal(map[string]string{
+				"nameserver":    address,
+				"tsig_key_name": "ddns",
+				"tsig_secret":   testCase.secret,
+				"record_type":   testCase.recordType,
+				"value":         testCase.value,
+			})
+			require.NoError(t, err)
+			provider, err := New(settings, "example.com", "www", ipversion.IP4or6, netip.Prefix{})
+			require.NoError(t, err)
+
+			newIP, err := provider.Update(context.Background(), nil, testCase.ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.newIP, newIP)
+		})
+	}
+}
+
+func Test_Provider_Update_tsigTime(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubServer{records: make(map[string][]dns.RR)}
+	address := startStubServer(t, stub)
+
+	settings, err := json.Marshal(map[string]string{
+		"nameserver":    address,
+		"tsig_key_name": "ddns",
+		"tsig_secret":   testKeySecret,
+	})
+	require.NoError(t, err)
+	provider, err := New(settings, "example.com", "www", ipversion.IP4or6, netip.Prefix{})
+	require.NoError(t, err)
+	signedAt := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	provider.timeNow = func() time.Time { return signedAt }
+
+	newIP, err := provider.Update(context.Background(), nil, netip.MustParseAddr("1.2.3.4"))
+
+	assert.ErrorIs(t, err, errors.ErrUnsuccessful)
+	assert.EqualError(t, err, "unsuccessful result: update response code NOTAUTH")
+	assert.Equal(t, netip.Addr{}, newIP)
+
+	stub.mutex.Lock()
+	defer stub.mutex.Unlock()
+	require.NotNil(t, stub.tsig)
+	assert.Equal(t, uint64(signedAt.Unix()), stub.tsig.TimeSigned)
+	assert.Equal(t, uint16(300), stub.tsig.Fudge)
+	assert.Equal(t, dns.HmacSHA256, stub.tsig.Algorithm)
+	assert.ErrorIs(t, stub.tsigStatus, dns.ErrTime)
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings   string
+		errWrapped error
+		errMessage string
+	}{
+		"valid": {
+			settings: `{"nameserver":"ns1.example.com","tsig_key_name":"ddns","tsig_secret":"c2VjcmV0"}`,
+		},
+		"nameserver_not_set": {
+			settings:   `{"tsig_key_name":"ddns","tsig_secret":"c2VjcmV0"}`,
+			errWrapped: errors.ErrNameserverNotSet,
+			errMessage: "nameserver is not set",
+		},
+		"host_not_in_zone": {
+			settings: `{"nameserver":"ns1.example.com","zone":"other.com",` +
+				`"tsig_key_name":"ddns","tsig_secret":"c2VjcmV0"}`,
+			errWrapped: errors.ErrDomainNotValid,
+			errMessage: "domain is not valid: www.example.com is not in zone other.com.",
+		},
+		"algorithm_not_valid": {
+			settings: `{"nameserver":"ns1.example.com","tsig_key_name":"ddns",` +
+				`"tsig_algorithm":"hmac-md4","tsig_secret":"c2VjcmV0"}`,
+			errWrapped: errors.ErrKeyAlgorithmNotValid,
+			errMessage: "key algorithm is not valid: hmac-md4",
+		},
+		"secret_not_base64": {
+			settings:   `{"nameserver":"ns1.example.com","tsig_key_name":"ddns","tsig_secret":"%"}`,
+			errWrapped: errors.ErrSecretNotValid,
+			errMessage: "secret is not valid: decoding base64: illegal base64 data at input byte 0",
+		},
+		"ttl_duration": {
+			settings: `{"nameserver":"ns1.example.com","tsig_key_name":"ddns",` +
+				`"tsig_secret":"c2VjcmV0","ttl":"1h"}`,
+		},
+		"record_value": {
+			settings: `{"nameserver":"ns1.example.com","tsig_key_name":"ddns",` +
+				`"tsig_secret":"c2VjcmV0","record_type":"caa","value":"0 issue \"letsencrypt.org\""}`,
+		},
+		"record_value_without_type": {
+			settings: `{"nameserver":"ns1.example.com","tsig_key_name":"ddns",` +
+				`"tsig_secret":"c2VjcmV0","value":"text"}`,
+			errWrapped: errors.ErrRecordValueNotValid,
+			errMessage: "record value is not valid: value can only be set with a record type",
+		},
+		"record_value_not_valid": {
+			settings: `{"nameserver":"ns1.example.com","tsig_key_name":"ddns",` +
+				`"tsig_secret":"c2VjcmV0","record_type":"MX","value":"mail.example.com"}`,
+			errWrapped: errors.ErrRecordValueNotValid,
+			errMessage: `record value is not valid: MX value "mail.example.com" ` +
+				"must be the priority and the mail server",
+		},
+		"record_type_not_valid": {
+			settings: `{"nameserver":"ns1.example.com","tsig_key_name":"ddns",` +
+				`"tsig_secret":"c2VjcmV0","record_type":"NS","value":"ns2.example.com"}`,
+			errWrapped: errors.ErrRecordTypeNotValid,
+			errMessage: `record type is not valid: "NS" can only be "TXT", "MX", "SRV" or "CAA"`,
+		},
+		"ttl_too_high": {
+			settings: `{"nameserver":"ns1.example.com","tsig_key_name":"ddns",` +
+				`"tsig_secret":"c2VjcmV0","ttl":4294967295}`,
+			errWrapped: errors.ErrTTLTooHigh,
+			errMessage: "TTL is too high: 4294967295 must be at most 2147483647",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.settings), "example.com", "www",
+				ipversion.IP4or6, netip.Prefix{})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package rfc2136
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// makeRecord returns the A or AAAA record of the IP address given, or
+// the record of the record type configured with its value rendered for
+// the IP address given.
+func (p *Provider) makeRecord(fqdn string, ip netip.Addr) (record dns.RR, err error) {
+	header := dns.RR_Header{Name: fqdn, Class: dns.ClassINET, Ttl: p.ttl}
+	switch {
+	case p.recordType == "" && ip.Is6():
+		header.Rrtype = dns.TypeAAAA
+		return &dns.AAAA{Hdr: header, AAAA: ip.AsSlice()}, nil
+	case p.recordType == "":
+		header.Rrtype = dns.TypeA
+		return &dns.A{Hdr: header, A: ip.AsSlice()}, nil
+	}
+
+	value, err := utils.RenderRecordValue(p.recordType, p.value, ip)
+	if err != nil {
+		return nil, fmt.Errorf("rendering value: %w", err)
+	}
+
+	if value.Type == constants.TXT {
+		header.Rrtype = dns.TypeTXT
+		return &dns.TXT{Hdr: header, Txt: txtStrings(value.Text)}, nil
+	}
+	return dns.NewRR(fmt.Sprintf("%s %d IN %s %s", fqdn, p.ttl, value.Type, value))
+}
+
+// txtStrings splits the text given in character strings of at most
+// 255 bytes, as required for TXT records, escaping quotes and
+// backslashes as expected by the dns library.
+func txtStrings(text string) (txt []string) {
+	const maxLength = 255
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	for len(text) > maxLength {
+		txt = append(txt, escaper.Replace(text[:maxLength]))
+		text = text[maxLength:]
+	}
+	return append(txt, escaper.Replace(text))
+}
+
+// queryRecord queries the nameserver for the records of the name and
+// type of the record given, and returns an error if none of them has
+// the data of the record given.
+func (p *Provider) queryRecord(ctx context.Context, client *dns.Client, record dns.RR) (err error) {
+	header := record.Header()
+	message := new(dns.Msg)
+	message.SetQuestion(header.Name, header.Rrtype)
+	response, _, err := client.ExchangeContext(ctx, message, p.nameserver)
+	if err != nil {
+		return fmt.Errorf("exchanging query message: %w", err)
+	} else if response.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("%w: query response code %s",
+			errors.ErrUnsuccessful, dns.RcodeToString[response.Rcode])
+	}
+
+	for _, answer := range response.Answer {
+		if dns.IsDuplicate(answer, record) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: sent %s record data %q but received %d records without it",
+		errors.ErrDataReceivedMismatch, dns.TypeToString[header.Rrtype],
+		strings.TrimPrefix(record.String(), header.String()), len(response.Answer))
+}
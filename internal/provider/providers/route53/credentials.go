@@ -0,0 +1,131 @@
+package route53
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+type credentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	// expiration is the expiration time of instance profile
+	// credentials, and is zero for static credentials.
+	expiration time.Time
+}
+
+// getCredentials returns the credentials set in the settings if any,
+// and otherwise the credentials of the instance profile, obtained from
+// the instance metadata service and cached until shortly before they
+// expire.
+func (p *Provider) getCredentials(ctx context.Context, client *http.Client) (
+	c credentials, err error) {
+	if p.staticCredentials.accessKeyID != "" {
+		return p.staticCredentials, nil
+	}
+
+	p.instanceCredentialsMutex.Lock()
+	defer p.instanceCredentialsMutex.Unlock()
+
+	const expiryMargin = 5 * time.Minute
+	if p.instanceCredentials.accessKeyID != "" &&
+		p.timeNow().Add(expiryMargin).Before(p.instanceCredentials.expiration) {
+		return p.instanceCredentials, nil
+	}
+
+	c, err = p.fetchInstanceCredentials(ctx, client)
+	if err != nil {
+		return credentials{}, fmt.Errorf("fetching instance profile credentials: %w", err)
+	}
+	p.instanceCredentials = c
+	return c, nil
+}
+
+// fetchInstanceCredentials obtains the credentials of the first role of the
+// instance profile from the instance metadata service version 2.
+// See https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/instancedata-data-retrieval.html
+func (p *Provider) fetchInstanceCredentials(ctx context.Context, client *http.Client) (
+	c credentials, err error) {
+	token, err := p.metadataRequest(ctx, client, http.MethodPut, "/latest/api/token", "")
+	if err != nil {
+		return credentials{}, fmt.Errorf("getting session token: %w", err)
+	}
+
+	const credentialsPath = "/latest/meta-data/iam/security-credentials/"
+	roles, err := p.metadataRequest(ctx, client, http.MethodGet, credentialsPath, token)
+	if err != nil {
+		return credentials{}, fmt.Errorf("getting role: %w", err)
+	}
+	scanner := bufio.NewScanner(strings.NewReader(roles))
+	if !scanner.Scan() || scanner.Text() == "" {
+		return credentials{}, fmt.Errorf("%w: no role attached to the instance", errors.ErrReceivedNoResult)
+	}
+	role := scanner.Text()
+
+	body, err := p.metadataRequest(ctx, client, http.MethodGet, credentialsPath+role, token)
+	if err != nil {
+		return credentials{}, fmt.Errorf("getting credentials of role %s: %w", role, err)
+	}
+	var data struct {
+		Code            string    `json:"Code"`
+		AccessKeyID     string    `json:"AccessKeyId"`
+		SecretAccessKey string    `json:"SecretAccessKey"`
+		Token           string    `json:"Token"`
+		Expiration      time.Time `json:"Expiration"`
+	}
+	err = json.Unmarshal([]byte(body), &data)
+	if err != nil {
+		return credentials{}, fmt.Errorf("json decoding credentials: %w", err)
+	} else if data.Code != "Success" {
+		return credentials{}, fmt.Errorf("%w: code %s", errors.ErrUnsuccessful, data.Code)
+	}
+
+	return credentials{
+		accessKeyID:     data.AccessKeyID,
+		secretAccessKey: data.SecretAccessKey,
+		sessionToken:    data.Token,
+		expiration:      data.Expiration,
+	}, nil
+}
+
+// metadataRequest sends a request to the instance metadata service,
+// using the session token given if it is not empty, and returns the
+// response body.
+func (p *Provider) metadataRequest(ctx context.Context, client *http.Client,
+	method, path, token string) (body string, err error) {
+	request, err := http.NewRequestWithContext(ctx, method, p.metadataURL+path, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating http request: %w", err)
+	}
+	if token == "" {
+		const tokenTTLSeconds = "21600"
+		request.Header.Set("X-Aws-Ec2-Metadata-Token-Ttl-Seconds", tokenTTLSeconds)
+	} else {
+		request.Header.Set("X-Aws-Ec2-Metadata-Token", token)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading response body: %w", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,152 @@
+package route53
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain     string
+	host       string
+	ipVersion  ipversion.IPVersion
+	ipv6Suffix netip.Prefix
+	zoneID     string
+	ttl        uint32
+	// staticCredentials are the credentials set in the settings,
+	// and are zero if the instance profile credentials are used.
+	staticCredentials credentials
+	// instanceCredentials are the instance profile credentials
+	// last obtained, protected by the instanceCredentialsMutex.
+	instanceCredentials      credentials
+	instanceCredentialsMutex sync.Mutex
+	// metadataURL is the URL of the instance metadata service.
+	metadataURL string
+	timeNow     func() time.Time
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	p *Provider, err error) {
+	extraSettings := struct {
+		AccessKeyID     string    `json:"access_key_id"`
+		SecretAccessKey string    `json:"secret_access_key"`
+		SessionToken    string    `json:"session_token"`
+		ZoneID          string    `json:"zone_id"`
+		TTL             utils.TTL `json:"ttl"`
+	}{}
+	err = json.Unmarshal(data, &extraSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	const defaultTTL = 300
+	if extraSettings.TTL == 0 {
+		extraSettings.TTL = defaultTTL
+	}
+
+	p = &Provider{
+		domain:     domain,
+		host:       host,
+		ipVersion:  ipVersion,
+		ipv6Suffix: ipv6Suffix,
+		// The zone ID can be given as shown in the hosted zone ARN.
+		zoneID: strings.TrimPrefix(extraSettings.ZoneID, "/hostedzone/"),
+		ttl:    uint32(extraSettings.TTL),
+		staticCredentials: credentials{
+			accessKeyID:     extraSettings.AccessKeyID,
+			secretAccessKey: extraSettings.SecretAccessKey,
+			sessionToken:    extraSettings.SessionToken,
+		},
+		metadataURL: "http://169.254.169.254",
+		timeNow:     time.Now,
+	}
+	err = p.isValid()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	const minTTL, maxTTL = 0, 2147483647
+	switch {
+	case p.zoneID == "":
+		return fmt.Errorf("%w", errors.ErrZoneIdentifierNotSet)
+	case p.staticCredentials.accessKeyID == "" && p.staticCredentials.secretAccessKey != "":
+		return fmt.Errorf("%w", errors.ErrAccessKeyIDNotSet)
+	case p.staticCredentials.accessKeyID != "" && p.staticCredentials.secretAccessKey == "":
+		return fmt.Errorf("%w", errors.ErrAccessKeySecretNotSet)
+	}
+	return utils.CheckTTL(utils.TTL(p.ttl), minTTL, maxTTL)
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Route53, p.ipVersion)
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) IPv6Suffix() netip.Prefix {
+	return p.ipv6Suffix
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true, TTL: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName()),
+		Host:      p.Host(),
+		Provider:  "<a href=\"https://aws.amazon.com/route53/\">AWS Route 53</a>",
+		IPVersion: p.ipVersion.String(),
+	}
+}
+
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	credentials, err := p.getCredentials(ctx, client)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("getting credentials: %w", err)
+	}
+
+	err = p.changeRecordSet(ctx, client, credentials, ip)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("changing record set: %w", err)
+	}
+	return ip, nil
+}
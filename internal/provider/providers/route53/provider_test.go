@@ -0,0 +1,141 @@
+package route53
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func newResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	const expectedBody = `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<ChangeResourceRecordSetsRequest xmlns="https://route53.amazonaws.com/doc/2013-04-01/">` +
+		`<ChangeBatch><Changes><Change><Action>UPSERT</Action><ResourceRecordSet>` +
+		`<Name>sub.example.com</Name><Type>AAAA</Type><TTL>300</TTL>` +
+		`<ResourceRecords><ResourceRecord><Value>2001:db8::1</Value></ResourceRecord></ResourceRecords>` +
+		`</ResourceRecordSet></Change></Changes></ChangeBatch></ChangeResourceRecordSetsRequest>`
+	const errorBody = `<?xml version="1.0"?><ErrorResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">` +
+		`<Error><Type>Sender</Type><Code>InvalidChangeBatch</Code>` +
+		`<Message>RRSet with DNS name sub.example.com. is not permitted in zone other.com.</Message>` +
+		`</Error></ErrorResponse>`
+
+	testCases := map[string]struct {
+		settings        string
+		instanceProfile bool
+		status          int
+		body            string
+		errWrapped      error
+		errMessage      string
+	}{
+		"static_credentials": {
+			settings: `{"zone_id":"/hostedzone/Z123","access_key_id":"AKID","secret_access_key":"secret"}`,
+			status:   http.StatusOK,
+		},
+		"instance_profile": {
+			settings:        `{"zone_id":"Z123"}`,
+			instanceProfile: true,
+			status:          http.StatusOK,
+		},
+		"bad_request": {
+			settings:   `{"zone_id":"Z123","access_key_id":"AKID","secret_access_key":"secret"}`,
+			status:     http.StatusBadRequest,
+			body:       errorBody,
+			errWrapped: errors.ErrBadRequest,
+			errMessage: "changing record set: bad request sent: InvalidChangeBatch: " +
+				"RRSet with DNS name sub.example.com. is not permitted in zone other.com.",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(testCase.settings), "example.com", "sub",
+				ipversion.IP6, netip.Prefix{})
+			require.NoError(t, err)
+			provider.metadataURL = "http://metadata"
+			provider.timeNow = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					switch r.URL.String() {
+					case "http://metadata/latest/api/token":
+						assert.Equal(t, http.MethodPut, r.Method)
+						return newResponse(http.StatusOK, "session"), nil
+					case "http://metadata/latest/meta-data/iam/security-credentials/":
+						assert.Equal(t, "session", r.Header.Get("X-Aws-Ec2-Metadata-Token"))
+						return newResponse(http.StatusOK, "role\n"), nil
+					case "http://metadata/latest/meta-data/iam/security-credentials/role":
+						return newResponse(http.StatusOK, `{"Code":"Success","AccessKeyId":"ASIA",`+
+							`"SecretAccessKey":"secret","Token":"token","Expiration":"2024-01-01T06:00:00Z"}`), nil
+					}
+
+					assert.Equal(t, http.MethodPost, r.Method)
+					assert.Equal(t, "https://route53.amazonaws.com/2013-04-01/hostedzone/Z123/rrset", r.URL.String())
+					body, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					assert.Equal(t, expectedBody, string(body))
+					authorization := r.Header.Get("Authorization")
+					if testCase.instanceProfile {
+						assert.True(t, strings.HasPrefix(authorization,
+							"AWS4-HMAC-SHA256 Credential=ASIA/20240101/us-east-1/route53/aws4_request, "+
+								"SignedHeaders=content-type;host;x-amz-date;x-amz-security-token, "))
+						assert.Equal(t, "token", r.Header.Get("X-Amz-Security-Token"))
+					} else {
+						assert.True(t, strings.HasPrefix(authorization,
+							"AWS4-HMAC-SHA256 Credential=AKID/20240101/us-east-1/route53/aws4_request, "+
+								"SignedHeaders=content-type;host;x-amz-date, "))
+					}
+					return newResponse(testCase.status, testCase.body), nil
+				}),
+			}
+
+			ip := netip.MustParseAddr("2001:db8::1")
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, ip, newIP)
+		})
+	}
+}
+
+func Test_New_validation(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(json.RawMessage(`{"access_key_id":"AKID"}`), "example.com", "@",
+		ipversion.IP4, netip.Prefix{})
+	assert.ErrorIs(t, err, errors.ErrZoneIdentifierNotSet)
+
+	_, err = New(json.RawMessage(`{"zone_id":"Z123","access_key_id":"AKID"}`), "example.com", "@",
+		ipversion.IP4, netip.Prefix{})
+	assert.ErrorIs(t, err, errors.ErrAccessKeySecretNotSet)
+}
@@ -0,0 +1,85 @@
+package route53
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// signRequest signs the request with the AWS signature version 4, for
+// the region and service given. The request body must be given since
+// it is hashed in the signature.
+// See https://docs.aws.amazon.com/IAM/latest/UserGuide/create-signed-request.html
+func signRequest(request *http.Request, body []byte, c credentials,
+	region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	request.Header.Set("X-Amz-Date", amzDate)
+	if c.sessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", c.sessionToken)
+	}
+
+	headerNames := []string{"host"}
+	for name := range request.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "content-type" {
+			headerNames = append(headerNames, name)
+		}
+	}
+	sort.Strings(headerNames)
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := buildCanonicalRequest(request, body, headerNames)
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" +
+		hashHex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+c.secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+c.accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// buildCanonicalRequest builds the canonical request from the request,
+// using the sorted lowercase header names given.
+func buildCanonicalRequest(request *http.Request, body []byte, headerNames []string) string {
+	buffer := bytes.NewBuffer(nil)
+	buffer.WriteString(request.Method + "\n")
+	path := request.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	buffer.WriteString(path + "\n")
+	// Go encodes the query with its keys sorted, as required.
+	buffer.WriteString(strings.ReplaceAll(request.URL.Query().Encode(), "+", "%20") + "\n")
+	for _, name := range headerNames {
+		value := request.Header.Get(name)
+		if name == "host" {
+			value = request.URL.Host
+		}
+		buffer.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	buffer.WriteString("\n" + strings.Join(headerNames, ";") + "\n")
+	buffer.WriteString(hashHex(body))
+	return buffer.String()
+}
+
+func hashHex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
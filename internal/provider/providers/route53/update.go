@@ -0,0 +1,112 @@
+package route53
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+type changeRequest struct {
+	XMLName xml.Name `xml:"https://route53.amazonaws.com/doc/2013-04-01/ ChangeResourceRecordSetsRequest"`
+	Changes []change `xml:"ChangeBatch>Changes>Change"`
+}
+
+type change struct {
+	Action string        `xml:"Action"`
+	Name   string        `xml:"ResourceRecordSet>Name"`
+	Type   string        `xml:"ResourceRecordSet>Type"`
+	TTL    uint32        `xml:"ResourceRecordSet>TTL"`
+	Values []recordValue `xml:"ResourceRecordSet>ResourceRecords>ResourceRecord"`
+}
+
+type recordValue struct {
+	Value string `xml:"Value"`
+}
+
+// changeRecordSet creates or replaces the record set of the host with
+// a record with the IP address given, with a single UPSERT change.
+// See https://docs.aws.amazon.com/Route53/latest/APIReference/API_ChangeResourceRecordSets.html
+func (p *Provider) changeRecordSet(ctx context.Context, client *http.Client,
+	credentials credentials, ip netip.Addr) (err error) {
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+
+	requestData := changeRequest{
+		Changes: []change{{
+			Action: "UPSERT",
+			Name:   utils.BuildURLQueryHostname(p.host, p.domain),
+			Type:   recordType,
+			TTL:    p.ttl,
+			Values: []recordValue{{Value: ip.String()}},
+		}},
+	}
+	body, err := xml.Marshal(requestData)
+	if err != nil {
+		return fmt.Errorf("xml encoding request data: %w", err)
+	}
+	body = append([]byte(xml.Header), body...)
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "route53.amazonaws.com",
+		Path:   "/2013-04-01/hostedzone/" + p.zoneID + "/rrset",
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/xml")
+	// Route 53 is a global service with its endpoint in us-east-1.
+	signRequest(request, body, credentials, "us-east-1", "route53", p.timeNow())
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, decodeError(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrZoneNotFound, decodeError(response.Body))
+	case http.StatusBadRequest:
+		return fmt.Errorf("%w: %s", errors.ErrBadRequest, decodeError(response.Body))
+	default:
+		return errors.NewHTTPStatusError(response.StatusCode, decodeError(response.Body))
+	}
+}
+
+// decodeError returns the error code and message of the Route 53
+// error response, or the response body on a single line if it
+// cannot be decoded.
+func decodeError(body io.Reader) string {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err.Error()
+	}
+	var errorResponse struct {
+		Code    string `xml:"Error>Code"`
+		Message string `xml:"Error>Message"`
+	}
+	err = xml.Unmarshal(b, &errorResponse)
+	if err != nil || errorResponse.Code == "" {
+		return utils.BodyToSingleLine(bytes.NewReader(b))
+	}
+	return errorResponse.Code + ": " + errorResponse.Message
+}
@@ -0,0 +1,111 @@
+package scaleway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		host         string
+		ip           netip.Addr
+		requestBody  string
+		status       int
+		responseBody string
+		errWrapped   error
+		errMessage   string
+	}{
+		"root_ipv4": {
+			host: "@",
+			ip:   netip.MustParseAddr("1.2.3.4"),
+			requestBody: `{"changes":[{"set":{"id_fields":{"name":"","type":"A"},` +
+				`"records":[{"name":"","type":"A","data":"1.2.3.4","ttl":300}]}}],"return_all_records":false}`,
+			status:       http.StatusOK,
+			responseBody: `{"records":[{"name":"","type":"A","data":"1.2.3.4","ttl":300}]}`,
+		},
+		"subdomain_ipv6": {
+			host: "home",
+			ip:   netip.MustParseAddr("2001:db8::1"),
+			requestBody: `{"changes":[{"set":{"id_fields":{"name":"home","type":"AAAA"},` +
+				`"records":[{"name":"home","type":"AAAA","data":"2001:db8::1","ttl":300}]}}],` +
+				`"return_all_records":false}`,
+			status:       http.StatusOK,
+			responseBody: `{"records":[{"name":"home","type":"AAAA","data":"2001:db8::1","ttl":300}]}`,
+		},
+		"bad_secret_key": {
+			host: "@",
+			ip:   netip.MustParseAddr("1.2.3.4"),
+			requestBody: `{"changes":[{"set":{"id_fields":{"name":"","type":"A"},` +
+				`"records":[{"name":"","type":"A","data":"1.2.3.4","ttl":300}]}}],"return_all_records":false}`,
+			status:       http.StatusUnauthorized,
+			responseBody: `{"message":"authentication is denied"}`,
+			errWrapped:   errors.ErrAuth,
+			errMessage:   `setting record: bad authentication: {"message":"authentication is denied"}`,
+		},
+		"ip_mismatch": {
+			host: "@",
+			ip:   netip.MustParseAddr("1.2.3.4"),
+			requestBody: `{"changes":[{"set":{"id_fields":{"name":"","type":"A"},` +
+				`"records":[{"name":"","type":"A","data":"1.2.3.4","ttl":300}]}}],"return_all_records":false}`,
+			status:       http.StatusOK,
+			responseBody: `{"records":[{"name":"","type":"A","data":"5.6.7.8","ttl":300}]}`,
+			errWrapped:   errors.ErrIPReceivedMismatch,
+			errMessage: "setting record: mismatching IP address received: " +
+				"sent ip 1.2.3.4 to update but received 5.6.7.8",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(`{"secret_key":"secret"}`), "example.com", testCase.host,
+				ipversion.IP4or6, netip.Prefix{})
+			require.NoError(t, err)
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, http.MethodPatch, r.Method)
+					assert.Equal(t, "https://api.scaleway.com/domain/v2beta1/dns-zones/example.com/records",
+						r.URL.String())
+					assert.Equal(t, "secret", r.Header.Get("X-Auth-Token"))
+					body, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					assert.Equal(t, testCase.requestBody, string(body))
+					return &http.Response{
+						StatusCode: testCase.status,
+						Body:       io.NopCloser(strings.NewReader(testCase.responseBody)),
+					}, nil
+				}),
+			}
+
+			newIP, err := provider.Update(context.Background(), client, testCase.ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.ip, newIP)
+		})
+	}
+}
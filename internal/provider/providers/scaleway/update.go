@@ -0,0 +1,119 @@
+package scaleway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+type record struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Data string `json:"data"`
+	TTL  uint32 `json:"ttl"`
+}
+
+// setRecord replaces the records matching the host and record type
+// with a single record with the IP address given, creating it if
+// it does not exist.
+// See https://www.scaleway.com/en/developers/api/domains-and-dns/#path-records-update-records-within-a-dns-zone
+func (p *Provider) setRecord(ctx context.Context, client *http.Client,
+	ip netip.Addr) (newIP netip.Addr, err error) {
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+	// The Scaleway API uses an empty name for the zone root.
+	name := p.host
+	if name == "@" {
+		name = ""
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.scaleway.com",
+		Path:   "/domain/v2beta1/dns-zones/" + p.domain + "/records",
+	}
+
+	type idFields struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	type setChange struct {
+		IDFields idFields `json:"id_fields"`
+		Records  []record `json:"records"`
+	}
+	type change struct {
+		Set setChange `json:"set"`
+	}
+	requestData := struct {
+		Changes          []change `json:"changes"`
+		ReturnAllRecords bool     `json:"return_all_records"`
+	}{
+		Changes: []change{{Set: setChange{
+			IDFields: idFields{Name: name, Type: recordType},
+			Records:  []record{{Name: name, Type: recordType, Data: ip.String(), TTL: p.ttl}},
+		}}},
+	}
+	requestBody, err := json.Marshal(requestData)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, u.String(), bytes.NewReader(requestBody))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.Header.Set("X-Auth-Token", p.secretKey)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrZoneNotFound, utils.BodyToSingleLine(response.Body))
+	default:
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	var data struct {
+		Records []record `json:"records"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&data)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	for _, record := range data.Records {
+		if record.Name != name || record.Type != recordType {
+			continue
+		}
+		newIP, err = netip.ParseAddr(record.Data)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+		} else if newIP.Compare(ip) != 0 {
+			return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
+				errors.ErrIPReceivedMismatch, ip, newIP)
+		}
+		return newIP, nil
+	}
+	return netip.Addr{}, fmt.Errorf("%w", errors.ErrReceivedNoIP)
+}
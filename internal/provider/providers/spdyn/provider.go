@@ -99,6 +99,15 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+// Capabilities returns the features supported by the provider.
+func Capabilities() models.Capabilities {
+	return models.Capabilities{IPv4: true, IPv6: true}
+}
+
+func (p *Provider) Capabilities() models.Capabilities {
+	return Capabilities()
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -122,10 +131,10 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	hostname := utils.BuildURLQueryHostname(p.host, p.domain)
 	values := url.Values{}
 	values.Set("hostname", hostname)
-	if p.useProviderIP {
+	providerIPUsed := utils.SetDynDNS2MyIP(values, ip, p.useProviderIP, p.ipv6Suffix)
+	if providerIPUsed {
+		// Spdyn uses the source IP of the request if given a private IP.
 		values.Set("myip", "10.0.0.1")
-	} else {
-		values.Set("myip", ip.String())
 	}
 	if p.token != "" {
 		values.Set("user", hostname)
@@ -142,9 +151,11 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	headers.SetUserAgent(request)
 
-	response, err := client.Do(request)
+	response, verified, err := utils.DoDynDNS2Request(ctx, client, request, p.BuildDomainName(), ip)
 	if err != nil {
 		return netip.Addr{}, fmt.Errorf("doing http request: %w", err)
+	} else if verified {
+		return ip, nil
 	}
 	defer response.Body.Close()
 
@@ -155,8 +166,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	bodyString := string(b)
 
 	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.ToSingleLine(bodyString))
+		return netip.Addr{}, errors.NewHTTPStatusError(response.StatusCode, utils.ToSingleLine(bodyString))
 	}
 
 	switch {
@@ -165,11 +175,11 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	case isAny(bodyString, constants.Badauth, "!yours"):
 		return netip.Addr{}, fmt.Errorf("%w", errors.ErrAuth)
 	case strings.HasPrefix(bodyString, "good"):
-		return ip, nil
+		return utils.DynDNS2ResponseIP(bodyString, ip, providerIPUsed)
 	case bodyString == constants.Notfqdn:
 		return netip.Addr{}, fmt.Errorf("%w: not fqdn", errors.ErrBadRequest)
 	case strings.HasPrefix(bodyString, "nochg"):
-		return ip, nil
+		return utils.DynDNS2ResponseIP(bodyString, ip, providerIPUsed)
 	case isAny(bodyString, "nohost", "fatal"):
 		return netip.Addr{}, fmt.Errorf("%w", errors.ErrHostnameNotExists)
 	default:
@@ -0,0 +1,93 @@
+package spdyn
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings     string
+		responseBody string
+		expectedMyIP string
+		newIP        netip.Addr
+		errWrapped   error
+		errMessage   string
+	}{
+		"local_ip": {
+			settings:     `{"token":"token"}`,
+			responseBody: "good 1.2.3.4",
+			expectedMyIP: "1.2.3.4",
+			newIP:        netip.MustParseAddr("1.2.3.4"),
+		},
+		"local_ip_mismatch": {
+			settings:     `{"token":"token"}`,
+			responseBody: "good 5.6.7.8",
+			expectedMyIP: "1.2.3.4",
+			errWrapped:   errors.ErrIPReceivedMismatch,
+			errMessage: "mismatching IP address received: " +
+				"sent ip 1.2.3.4 to update but received 5.6.7.8",
+		},
+		"provider_ip": {
+			settings:     `{"token":"token","provider_ip":true}`,
+			responseBody: "good 5.6.7.8",
+			expectedMyIP: "10.0.0.1",
+			newIP:        netip.MustParseAddr("5.6.7.8"),
+		},
+		"provider_ip_without_ip": {
+			settings:     `{"token":"token","provider_ip":true}`,
+			responseBody: "good",
+			expectedMyIP: "10.0.0.1",
+			newIP:        netip.MustParseAddr("1.2.3.4"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(testCase.settings), "example.com", "home",
+				ipversion.IP4, netip.Prefix{})
+			require.NoError(t, err)
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, "update.spdyn.de", r.URL.Host)
+					assert.Equal(t, testCase.expectedMyIP, r.URL.Query().Get("myip"))
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(testCase.responseBody)),
+					}, nil
+				}),
+			}
+
+			newIP, err := provider.Update(context.Background(), client,
+				netip.MustParseAddr("1.2.3.4"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.newIP, newIP)
+		})
+	}
+}
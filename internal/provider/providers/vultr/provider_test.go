@@ -0,0 +1,132 @@
+package vultr
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func newResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	const recordsURL = "https://api.vultr.com/v2/domains/example.com/records"
+
+	testCases := map[string]struct {
+		host        string
+		pages       map[string]string
+		writeMethod string
+		writeURL    string
+		writeBody   string
+		writeStatus int
+		listStatus  int
+		errWrapped  error
+		errMessage  string
+	}{
+		"up_to_date": {
+			host: "@",
+			pages: map[string]string{
+				"": `{"records":[{"id":"1","type":"A","name":"","data":"1.2.3.4","ttl":300}]}`,
+			},
+		},
+		"update_on_second_page": {
+			host: "home",
+			pages: map[string]string{
+				"": `{"records":[{"id":"1","type":"A","name":"","data":"5.6.7.8","ttl":300}],` +
+					`"meta":{"links":{"next":"next"}}}`,
+				"next": `{"records":[{"id":"2","type":"A","name":"home","data":"5.6.7.8","ttl":300}]}`,
+			},
+			writeMethod: http.MethodPatch,
+			writeURL:    recordsURL + "/2",
+			writeBody:   `{"data":"1.2.3.4","ttl":300}`,
+			writeStatus: http.StatusNoContent,
+		},
+		"create": {
+			host: "home",
+			pages: map[string]string{
+				"": `{"records":[{"id":"1","type":"AAAA","name":"home","data":"::1","ttl":300}]}`,
+			},
+			writeMethod: http.MethodPost,
+			writeURL:    recordsURL,
+			writeBody:   `{"name":"home","type":"A","data":"1.2.3.4","ttl":300}`,
+			writeStatus: http.StatusCreated,
+		},
+		"bad_api_key": {
+			host:       "@",
+			listStatus: http.StatusUnauthorized,
+			errWrapped: errors.ErrAuth,
+			errMessage: "getting record: bad authentication: denied",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(`{"apikey":"key"}`), "example.com", testCase.host,
+				ipversion.IP4, netip.Prefix{})
+			require.NoError(t, err)
+
+			writeRequests := 0
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+					if r.Method == http.MethodGet {
+						assert.Equal(t, recordsURL, r.URL.Scheme+"://"+r.URL.Host+r.URL.Path)
+						if testCase.listStatus != 0 {
+							return newResponse(testCase.listStatus, "denied"), nil
+						}
+						body, ok := testCase.pages[r.URL.Query().Get("cursor")]
+						require.True(t, ok)
+						return newResponse(http.StatusOK, body), nil
+					}
+
+					writeRequests++
+					assert.Equal(t, testCase.writeMethod, r.Method)
+					assert.Equal(t, testCase.writeURL, r.URL.String())
+					body, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					assert.JSONEq(t, testCase.writeBody, string(body))
+					return newResponse(testCase.writeStatus, ""), nil
+				}),
+			}
+
+			ip := netip.MustParseAddr("1.2.3.4")
+			newIP, err := provider.Update(context.Background(), client, ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, ip, newIP)
+			expectedWrites := 0
+			if testCase.writeMethod != "" {
+				expectedWrites = 1
+			}
+			assert.Equal(t, expectedWrites, writeRequests)
+		})
+	}
+}
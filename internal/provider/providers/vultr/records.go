@@ -0,0 +1,164 @@
+package vultr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+type record struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Data string `json:"data"`
+	TTL  uint32 `json:"ttl"`
+}
+
+func (p *Provider) recordsURL() url.URL {
+	return url.URL{
+		Scheme: "https",
+		Host:   "api.vultr.com",
+		Path:   "/v2/domains/" + p.domain + "/records",
+	}
+}
+
+// getRecord returns the record matching the host and record type,
+// following the pagination cursors until the record is found.
+// See https://www.vultr.com/api/#tag/dns/operation/list-dns-domain-records
+func (p *Provider) getRecord(ctx context.Context, client *http.Client,
+	recordType string) (found record, err error) {
+	name := p.recordName()
+	cursor := ""
+	for {
+		u := p.recordsURL()
+		values := url.Values{}
+		values.Set("per_page", "500")
+		if cursor != "" {
+			values.Set("cursor", cursor)
+		}
+		u.RawQuery = values.Encode()
+
+		var records []record
+		records, cursor, err = p.getRecordsPage(ctx, client, u.String())
+		if err != nil {
+			return record{}, err
+		}
+		for _, r := range records {
+			if r.Type == recordType && r.Name == name {
+				return r, nil
+			}
+		}
+		if cursor == "" {
+			return record{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
+	}
+}
+
+func (p *Provider) getRecordsPage(ctx context.Context, client *http.Client,
+	pageURL string) (records []record, nextCursor string, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, "", err
+	}
+	defer response.Body.Close()
+
+	err = checkStatus(response, http.StatusOK)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var data struct {
+		Records []record `json:"records"`
+		Meta    struct {
+			Links struct {
+				Next string `json:"next"`
+			} `json:"links"`
+		} `json:"meta"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&data)
+	if err != nil {
+		return nil, "", fmt.Errorf("json decoding response body: %w", err)
+	}
+	return data.Records, data.Meta.Links.Next, nil
+}
+
+// See https://www.vultr.com/api/#tag/dns/operation/create-dns-domain-record
+func (p *Provider) createRecord(ctx context.Context, client *http.Client,
+	recordType string, ip netip.Addr) (err error) {
+	u := p.recordsURL()
+	requestData := struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+		Data string `json:"data"`
+		TTL  uint32 `json:"ttl"`
+	}{
+		Name: p.recordName(),
+		Type: recordType,
+		Data: ip.String(),
+		TTL:  p.ttl,
+	}
+	return p.sendRecord(ctx, client, http.MethodPost, u.String(), requestData, http.StatusCreated)
+}
+
+// See https://www.vultr.com/api/#tag/dns/operation/update-dns-domain-record
+func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
+	recordID string, ip netip.Addr) (err error) {
+	u := p.recordsURL()
+	u.Path += "/" + recordID
+	requestData := struct {
+		Data string `json:"data"`
+		TTL  uint32 `json:"ttl"`
+	}{
+		Data: ip.String(),
+		TTL:  p.ttl,
+	}
+	return p.sendRecord(ctx, client, http.MethodPatch, u.String(), requestData, http.StatusNoContent)
+}
+
+func (p *Provider) sendRecord(ctx context.Context, client *http.Client,
+	method, url string, requestData any, expectedStatus int) (err error) {
+	requestBody, err := json.Marshal(requestData)
+	if err != nil {
+		return fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(requestBody))
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	return checkStatus(response, expectedStatus)
+}
+
+func checkStatus(response *http.Response, expectedStatus int) error {
+	switch response.StatusCode {
+	case expectedStatus:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrDomainNotFound, utils.BodyToSingleLine(response.Body))
+	default:
+		return errors.NewHTTPStatusError(response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/aliyun"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/allinkl"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/azure"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/cloudflare"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/cloudns"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/custom"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/dd24"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/ddnss"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/desec"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/digitalocean"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/dnsomatic"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/dnspod"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/dondominio"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/dreamhost"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/duckdns"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/dyn"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/dynu"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/dynv6"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/easydns"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/example"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/exec"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/freedns"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/gandi"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/gcp"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/godaddy"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/goip"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/he"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/hetzner"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/infomaniak"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/inwx"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/ionos"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/linode"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/luadns"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/mock"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/namecheap"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/namecom"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/netcup"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/netlify"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/njalla"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/noip"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/nowdns"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/oci"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/opendns"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/ovh"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/porkbun"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/rfc2136"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/route53"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/scaleway"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/selfhostde"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/servercow"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/spdyn"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/strato"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/variomedia"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/vultr"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/zoneedit"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type constructor func(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (provider Provider, err error)
+
+// registration is a provider constructor with the
+// features supported by the provider.
+type registration struct {
+	constructor  constructor
+	capabilities models.Capabilities
+}
+
+// register returns the registration for the constructor of a
+// provider implementation and its capabilities.
+func register[T Provider](newProvider func(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (T, error),
+	capabilities models.Capabilities) registration {
+	return registration{
+		constructor: func(data json.RawMessage, domain, host string, //nolint:ireturn
+			ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (provider Provider, err error) {
+			provider, err = newProvider(data, domain, host, ipVersion, ipv6Suffix)
+			if err != nil {
+				return nil, err // do not return a typed nil pointer
+			}
+			return provider, nil
+		},
+		capabilities: capabilities,
+	}
+}
+
+// registry maps each provider name to its constructor and capabilities.
+var registry = map[models.Provider]registration{ //nolint:gochecknoglobals
+	constants.Aliyun:       register(aliyun.New, aliyun.Capabilities()),
+	constants.AllInkl:      register(allinkl.New, allinkl.Capabilities()),
+	constants.Azure:        register(azure.New, azure.Capabilities()),
+	constants.Cloudflare:   register(cloudflare.New, cloudflare.Capabilities()),
+	constants.ClouDNS:      register(cloudns.New, cloudns.Capabilities()),
+	constants.Custom:       register(custom.New, custom.Capabilities()),
+	constants.Dd24:         register(dd24.New, dd24.Capabilities()),
+	constants.DdnssDe:      register(ddnss.New, ddnss.Capabilities()),
+	constants.DeSEC:        register(desec.New, desec.Capabilities()),
+	constants.DigitalOcean: register(digitalocean.New, digitalocean.Capabilities()),
+	constants.DNSOMatic:    register(dnsomatic.New, dnsomatic.Capabilities()),
+	constants.DNSPod:       register(dnspod.New, dnspod.Capabilities()),
+	constants.DonDominio:   register(dondominio.New, dondominio.Capabilities()),
+	constants.Dreamhost:    register(dreamhost.New, dreamhost.Capabilities()),
+	constants.DuckDNS:      register(duckdns.New, duckdns.Capabilities()),
+	constants.Dyn:          register(dyn.New, dyn.Capabilities()),
+	constants.Dynu:         register(dynu.New, dynu.Capabilities()),
+	constants.DynV6:        register(dynv6.New, dynv6.Capabilities()),
+	constants.EasyDNS:      register(easydns.New, easydns.Capabilities()),
+	constants.Example:      register(example.New, example.Capabilities()),
+	constants.Exec:         register(exec.New, exec.Capabilities()),
+	constants.FreeDNS:      register(freedns.New, freedns.Capabilities()),
+	constants.Gandi:        register(gandi.New, gandi.Capabilities()),
+	constants.GCP:          register(gcp.New, gcp.Capabilities()),
+	constants.GoDaddy:      register(godaddy.New, godaddy.Capabilities()),
+	constants.GoIP:         register(goip.New, goip.Capabilities()),
+	constants.HE:           register(he.New, he.Capabilities()),
+	constants.Hetzner:      register(hetzner.New, hetzner.Capabilities()),
+	constants.Infomaniak:   register(infomaniak.New, infomaniak.Capabilities()),
+	constants.INWX:         register(inwx.New, inwx.Capabilities()),
+	constants.Ionos:        register(ionos.New, ionos.Capabilities()),
+	constants.Linode:       register(linode.New, linode.Capabilities()),
+	constants.LuaDNS:       register(luadns.New, luadns.Capabilities()),
+	constants.Mock:         register(mock.New, mock.Capabilities()),
+	constants.Namecheap: register(func(data json.RawMessage, domain, host string,
+		_ ipversion.IPVersion, _ netip.Prefix) (*namecheap.Provider, error) {
+		return namecheap.New(data, domain, host)
+	}, namecheap.Capabilities()),
+	constants.NameCom: register(namecom.New, namecom.Capabilities()),
+	constants.Netcup:  register(netcup.New, netcup.Capabilities()),
+	constants.Netlify: register(netlify.New, netlify.Capabilities()),
+	constants.Njalla:  register(njalla.New, njalla.Capabilities()),
+	constants.NoIP:    register(noip.New, noip.Capabilities()),
+	constants.NowDNS: register(func(data json.RawMessage, domain, _ string,
+		ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (*nowdns.Provider, error) {
+		return nowdns.New(data, domain, ipVersion, ipv6Suffix)
+	}, nowdns.Capabilities()),
+	constants.OCI:        register(oci.New, oci.Capabilities()),
+	constants.OpenDNS:    register(opendns.New, opendns.Capabilities()),
+	constants.OVH:        register(ovh.New, ovh.Capabilities()),
+	constants.Porkbun:    register(porkbun.New, porkbun.Capabilities()),
+	constants.RFC2136:    register(rfc2136.New, rfc2136.Capabilities()),
+	constants.Route53:    register(route53.New, route53.Capabilities()),
+	constants.Scaleway:   register(scaleway.New, scaleway.Capabilities()),
+	constants.SelfhostDe: register(selfhostde.New, selfhostde.Capabilities()),
+	constants.Servercow:  register(servercow.New, servercow.Capabilities()),
+	constants.Spdyn:      register(spdyn.New, spdyn.Capabilities()),
+	constants.Strato:     register(strato.New, strato.Capabilities()),
+	constants.Variomedia: register(variomedia.New, variomedia.Capabilities()),
+	constants.Vultr:      register(vultr.New, vultr.Capabilities()),
+	constants.Zoneedit:   register(zoneedit.New, zoneedit.Capabilities()),
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Capabilities(t *testing.T) {
+	t.Parallel()
+
+	dualStack := models.Capabilities{IPv4: true, IPv6: true}
+	expected := map[models.Provider]models.Capabilities{
+		constants.Aliyun:       {IPv4: true, IPv6: true, CreateMissing: true, View: true},
+		constants.AllInkl:      dualStack,
+		constants.Azure:        {IPv4: true, IPv6: true, TTL: true},
+		constants.Cloudflare:   {IPv4: true, IPv6: true, CreateMissing: true, Proxied: true, TTL: true},
+		constants.ClouDNS:      {IPv4: true, IPv6: true, CreateMissing: true, TTL: true},
+		constants.Custom:       dualStack,
+		constants.Dd24:         dualStack,
+		constants.DdnssDe:      dualStack,
+		constants.DeSEC:        {IPv4: true, IPv6: true, TTL: true},
+		constants.DigitalOcean: dualStack,
+		constants.DNSOMatic:    dualStack,
+		constants.DNSPod:       {IPv4: true, IPv6: true, View: true},
+		constants.DonDominio:   dualStack,
+		constants.Dreamhost:    {IPv4: true, IPv6: true, CreateMissing: true},
+		constants.DuckDNS:      dualStack,
+		constants.Dyn:          dualStack,
+		constants.Dynu:         dualStack,
+		constants.DynV6:        dualStack,
+		constants.EasyDNS:      dualStack,
+		constants.Example:      dualStack,
+		constants.Exec:         dualStack,
+		constants.FreeDNS:      dualStack,
+		constants.Gandi:        {IPv4: true, IPv6: true, TTL: true, StaticIPs: true},
+		constants.GCP:          {IPv4: true, IPv6: true, CreateMissing: true, TTL: true},
+		constants.GoDaddy:      dualStack,
+		constants.GoIP:         dualStack,
+		constants.HE:           dualStack,
+		constants.Hetzner:      {IPv4: true, IPv6: true, CreateMissing: true, TTL: true},
+		constants.Infomaniak:   dualStack,
+		constants.INWX:         dualStack,
+		constants.Ionos:        {IPv4: true, IPv6: true, CreateMissing: true},
+		constants.Linode:       {IPv4: true, IPv6: true, CreateMissing: true},
+		constants.LuaDNS:       dualStack,
+		constants.Mock:         dualStack,
+		constants.Namecheap:    {IPv4: true},
+		constants.NameCom:      {IPv4: true, IPv6: true, CreateMissing: true, TTL: true},
+		constants.Netcup:       dualStack,
+		constants.Netlify:      {IPv4: true, IPv6: true, CreateMissing: true, TTL: true},
+		constants.Njalla:       dualStack,
+		constants.NoIP:         dualStack,
+		constants.NowDNS:       dualStack,
+		constants.OCI:          {IPv4: true, IPv6: true, TTL: true, StaticIPs: true},
+		constants.OpenDNS:      dualStack,
+		constants.OVH:          {IPv4: true, IPv6: true, CreateMissing: true},
+		constants.Porkbun:      {IPv4: true, IPv6: true, CreateMissing: true, TTL: true},
+		constants.RFC2136:      {IPv4: true, IPv6: true, CreateMissing: true, TTL: true},
+		constants.Route53:      {IPv4: true, IPv6: true, TTL: true},
+		constants.Scaleway:     {IPv4: true, IPv6: true, TTL: true},
+		constants.SelfhostDe:   dualStack,
+		constants.Servercow:    {IPv4: true, IPv6: true, TTL: true},
+		constants.Spdyn:        dualStack,
+		constants.Strato:       dualStack,
+		constants.Variomedia:   dualStack,
+		constants.Vultr:        {IPv4: true, IPv6: true, CreateMissing: true, TTL: true},
+		constants.Zoneedit:     dualStack,
+	}
+
+	assert.Len(t, registry, len(expected))
+	for providerName, expectedCapabilities := range expected {
+		capabilities, err := Capabilities(providerName)
+		require.NoError(t, err)
+		assert.Equal(t, expectedCapabilities, capabilities, providerName)
+	}
+
+	for _, providerName := range constants.ProviderChoices() {
+		_, err := Capabilities(providerName)
+		assert.NoError(t, err, providerName)
+	}
+
+	_, err := Capabilities("unknown")
+	assert.ErrorIs(t, err, ErrProviderUnknown)
+	assert.EqualError(t, err, "unknown provider: unknown")
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+)
+
+// CAA is the data of a CAA record, restricting the certificate
+// authorities allowed to issue certificates, as described in RFC 8659.
+type CAA struct {
+	// Flags is 0, or 128 for the issuer critical flag.
+	Flags uint8 `json:"flags"`
+	// Tag is the property tag, which is issue, issuewild or iodef.
+	Tag string `json:"tag"`
+	// Value is the property value, for example the
+	// certificate authority domain for the issue tag.
+	Value string `json:"value"`
+}
+
+// Validate verifies the CAA record fields are valid and consistent,
+// such as the value being a mailto, http or https URL for the iodef tag.
+func (c CAA) Validate() (err error) {
+	const issuerCritical = 128
+	if c.Flags != 0 && c.Flags != issuerCritical {
+		return fmt.Errorf("%w: flags %d must be 0 or %d",
+			errors.ErrCAANotValid, c.Flags, issuerCritical)
+	}
+
+	if strings.ContainsAny(c.Value, "\"\\") {
+		return fmt.Errorf("%w: value %q cannot contain quotes or backslashes",
+			errors.ErrCAANotValid, c.Value)
+	}
+
+	switch c.Tag {
+	case "issue", "issuewild":
+		// Note an empty value is valid and forbids any issuance.
+	case "iodef":
+		u, err := url.Parse(c.Value)
+		if err != nil || (u.Scheme != "mailto" && u.Scheme != "https" && u.Scheme != "http") {
+			return fmt.Errorf("%w: value %q must be a mailto, http or https URL for tag iodef",
+				errors.ErrCAANotValid, c.Value)
+		}
+	default:
+		return fmt.Errorf("%w: tag %q must be issue, issuewild or iodef",
+			errors.ErrCAANotValid, c.Tag)
+	}
+	return nil
+}
+
+// RData returns the CAA record data in its presentation
+// format, for example `0 issue "letsencrypt.org"`.
+func (c CAA) RData() string {
+	return fmt.Sprintf(`%d %s "%s"`, c.Flags, c.Tag, c.Value)
+}
+
+// ParseCAA parses CAA record data in its presentation format,
+// with its value quoted or not.
+func ParseCAA(rdata string) (caa CAA, err error) {
+	const parts = 3
+	fields := strings.SplitN(strings.TrimSpace(rdata), " ", parts)
+	if len(fields) != parts {
+		return caa, fmt.Errorf("%w: %q has %d fields instead of %d",
+			errors.ErrDataReceivedMalformed, rdata, len(fields), parts)
+	}
+
+	flags, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil {
+		return caa, fmt.Errorf("%w: %q: %w", errors.ErrDataReceivedMalformed, rdata, err)
+	}
+	caa.Flags = uint8(flags)
+	caa.Tag = strings.ToLower(fields[1])
+	caa.Value = fields[2]
+	if len(caa.Value) >= 2 && strings.HasPrefix(caa.Value, `"`) && strings.HasSuffix(caa.Value, `"`) {
+		caa.Value = caa.Value[1 : len(caa.Value)-1]
+	}
+	return caa, nil
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CAA_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		caa        CAA
+		errWrapped error
+		errMessage string
+	}{
+		"issue": {
+			caa: CAA{Tag: "issue", Value: "letsencrypt.org"},
+		},
+		"issuewild_forbidding_issuance": {
+			caa: CAA{Flags: 128, Tag: "issuewild"},
+		},
+		"iodef_mailto": {
+			caa: CAA{Tag: "iodef", Value: "mailto:security@example.com"},
+		},
+		"flags_not_valid": {
+			caa:        CAA{Flags: 1, Tag: "issue", Value: "letsencrypt.org"},
+			errWrapped: errors.ErrCAANotValid,
+			errMessage: "CAA record data is not valid: flags 1 must be 0 or 128",
+		},
+		"unknown_tag": {
+			caa:        CAA{Tag: "issuevmc", Value: "letsencrypt.org"},
+			errWrapped: errors.ErrCAANotValid,
+			errMessage: `CAA record data is not valid: tag "issuevmc" must be issue, issuewild or iodef`,
+		},
+		"iodef_not_url": {
+			caa:        CAA{Tag: "iodef", Value: "security@example.com"},
+			errWrapped: errors.ErrCAANotValid,
+			errMessage: `CAA record data is not valid: value "security@example.com" ` +
+				`must be a mailto, http or https URL for tag iodef`,
+		},
+		"quote_in_value": {
+			caa:        CAA{Tag: "issue", Value: `letsencrypt.org"`},
+			errWrapped: errors.ErrCAANotValid,
+			errMessage: `CAA record data is not valid: value "letsencrypt.org\"" ` +
+				`cannot contain quotes or backslashes`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.caa.Validate()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_ParseCAA(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		rdata      string
+		caa        CAA
+		errWrapped error
+		errMessage string
+	}{
+		"quoted_value": {
+			rdata: `0 issue "letsencrypt.org"`,
+			caa:   CAA{Tag: "issue", Value: "letsencrypt.org"},
+		},
+		"unquoted_value_uppercase_tag": {
+			rdata: `128 ISSUEWILD letsencrypt.org`,
+			caa:   CAA{Flags: 128, Tag: "issuewild", Value: "letsencrypt.org"},
+		},
+		"missing_value": {
+			rdata:      "0 issue",
+			errWrapped: errors.ErrDataReceivedMalformed,
+			errMessage: `malformed record data received: "0 issue" has 2 fields instead of 3`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			caa, err := ParseCAA(testCase.rdata)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.caa, caa)
+		})
+	}
+}
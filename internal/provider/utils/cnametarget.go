@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+)
+
+// Variables available in CNAME target templates, in addition
+// to the variables configured.
+const (
+	// CNAMEVariableIPReverse is the reverse DNS name of the IP address,
+	// for example 4.3.2.1.in-addr.arpa.
+	CNAMEVariableIPReverse = "ip_reverse"
+	// CNAMEVariableIPDashed is the IP address with its dots or colons
+	// replaced by dashes, for example 1-2-3-4.
+	CNAMEVariableIPDashed = "ip_dashed"
+	// CNAMEVariableTimestamp is the Unix time in seconds of the update.
+	CNAMEVariableTimestamp = "timestamp"
+)
+
+var cnameVariableRegex = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// CheckCNAMETarget returns an error if the CNAME target template uses
+// a variable which is not defined, if a variable configured overrides
+// a built-in variable, or if the target rendered is not a valid hostname.
+func CheckCNAMETarget(template string, variables map[string]string) (err error) {
+	if template == "" {
+		return fmt.Errorf("%w", errors.ErrTargetNotSet)
+	}
+
+	for name := range variables {
+		switch name {
+		case CNAMEVariableIPReverse, CNAMEVariableIPDashed, CNAMEVariableTimestamp:
+			return fmt.Errorf("%w: variable %q is built-in and cannot be set",
+				errors.ErrTargetNotValid, name)
+		}
+	}
+
+	for _, match := range cnameVariableRegex.FindAllStringSubmatch(template, -1) {
+		name := match[1]
+		switch name {
+		case CNAMEVariableIPReverse, CNAMEVariableIPDashed, CNAMEVariableTimestamp:
+			continue
+		}
+		_, ok := variables[name]
+		if !ok {
+			return fmt.Errorf("%w: variable %q is not defined", errors.ErrTargetNotValid, name)
+		}
+	}
+
+	// Render the target with example values to check the
+	// rest of the template is a valid hostname.
+	_, err = RenderCNAMETarget(template, variables,
+		netip.AddrFrom4([4]byte{192, 0, 2, 1}), time.Unix(0, 0)) //nolint:gomnd
+	return err
+}
+
+// RenderCNAMETarget returns the CNAME target rendered from the template,
+// replacing each variable written as {name} with its value. The variables
+// are the variables configured and the built-in variables derived from the
+// IP address and the time given. It returns an error if the target rendered
+// is not a valid hostname.
+func RenderCNAMETarget(template string, variables map[string]string,
+	ip netip.Addr, now time.Time) (target string, err error) {
+	target = cnameVariableRegex.ReplaceAllStringFunc(template, func(match string) string {
+		name := match[1 : len(match)-1]
+		switch name {
+		case CNAMEVariableIPReverse:
+			return ReverseName(ip)
+		case CNAMEVariableIPDashed:
+			return strings.NewReplacer(".", "-", ":", "-").Replace(ip.Unmap().String())
+		case CNAMEVariableTimestamp:
+			return strconv.FormatInt(now.Unix(), 10)
+		}
+		value, ok := variables[name]
+		if !ok {
+			return match // left as is to fail the hostname check
+		}
+		return value
+	})
+	target = NormalizeRecordName(target, false)
+
+	err = checkHostname(target)
+	if err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+var hostnameLabelRegex = regexp.MustCompile(`^[a-z0-9_]([a-z0-9_-]*[a-z0-9_])?$`)
+
+func checkHostname(hostname string) (err error) {
+	const maxLength = 253
+	if len(hostname) > maxLength {
+		return fmt.Errorf("%w: %s: length %d exceeds %d",
+			errors.ErrTargetNotValid, hostname, len(hostname), maxLength)
+	}
+
+	const maxLabelLength = 63
+	for _, label := range strings.Split(hostname, ".") {
+		switch {
+		case len(label) > maxLabelLength:
+			return fmt.Errorf("%w: %s: label %q length exceeds %d",
+				errors.ErrTargetNotValid, hostname, label, maxLabelLength)
+		case !hostnameLabelRegex.MatchString(label):
+			return fmt.Errorf("%w: %s: label %q is not valid",
+				errors.ErrTargetNotValid, hostname, label)
+		}
+	}
+	return nil
+}
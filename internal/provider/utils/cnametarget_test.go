@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RenderCNAMETarget(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		template   string
+		variables  map[string]string
+		ip         netip.Addr
+		target     string
+		errWrapped error
+		errMessage string
+	}{
+		"no_variable": {
+			template: "Host.Example.com.",
+			ip:       netip.MustParseAddr("192.0.2.10"),
+			target:   "host.example.com",
+		},
+		"ip_reverse": {
+			template: "{ip_reverse}",
+			ip:       netip.MustParseAddr("192.0.2.10"),
+			target:   "10.2.0.192.in-addr.arpa",
+		},
+		"ip_dashed_ipv6": {
+			template: "{ip_dashed}.example.com",
+			ip:       netip.MustParseAddr("2001:db8::1"),
+			target:   "2001-db8--1.example.com",
+		},
+		"timestamp_and_variable": {
+			template:  "v{timestamp}.{region}.example.com",
+			variables: map[string]string{"region": "EU-West"},
+			ip:        netip.MustParseAddr("192.0.2.10"),
+			target:    "v1700000000.eu-west.example.com",
+		},
+		"variable_not_defined": {
+			template:   "{region}.example.com",
+			ip:         netip.MustParseAddr("192.0.2.10"),
+			errWrapped: errors.ErrTargetNotValid,
+			errMessage: `target is not valid: {region}.example.com: label "{region}" is not valid`,
+		},
+		"label_not_valid": {
+			template:   "{region}.example.com",
+			variables:  map[string]string{"region": "-eu"},
+			ip:         netip.MustParseAddr("192.0.2.10"),
+			errWrapped: errors.ErrTargetNotValid,
+			errMessage: `target is not valid: -eu.example.com: label "-eu" is not valid`,
+		},
+		"label_too_long": {
+			template:   "{region}.example.com",
+			variables:  map[string]string{"region": strings.Repeat("a", 64)},
+			ip:         netip.MustParseAddr("192.0.2.10"),
+			errWrapped: errors.ErrTargetNotValid,
+			errMessage: "target is not valid: " + strings.Repeat("a", 64) + ".example.com: label \"" +
+				strings.Repeat("a", 64) + "\" length exceeds 63",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			target, err := RenderCNAMETarget(testCase.template, testCase.variables,
+				testCase.ip, time.Unix(1700000000, 0))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.target, target)
+		})
+	}
+}
+
+func Test_CheckCNAMETarget(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		template   string
+		variables  map[string]string
+		errWrapped error
+		errMessage string
+	}{
+		"valid": {
+			template:  "{ip_dashed}.{region}.example.com",
+			variables: map[string]string{"region": "eu"},
+		},
+		"empty": {
+			errWrapped: errors.ErrTargetNotSet,
+			errMessage: "target is not set",
+		},
+		"built_in_variable_set": {
+			template:   "{ip_dashed}.example.com",
+			variables:  map[string]string{"ip_dashed": "x"},
+			errWrapped: errors.ErrTargetNotValid,
+			errMessage: `target is not valid: variable "ip_dashed" is built-in and cannot be set`,
+		},
+		"variable_not_defined": {
+			template:   "{ip_dashed}.{region}.example.com",
+			errWrapped: errors.ErrTargetNotValid,
+			errMessage: `target is not valid: variable "region" is not defined`,
+		},
+		"hostname_not_valid": {
+			template:   "{ip_dashed}.example..com",
+			errWrapped: errors.ErrTargetNotValid,
+			errMessage: `target is not valid: 192-0-2-1.example..com: label "" is not valid`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := CheckCNAMETarget(testCase.template, testCase.variables)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type createdKey struct{}
+
+// ContextWithCreatedSignal returns a context which providers can use
+// to signal they created a missing record instead of updating an
+// existing one, using SignalCreated. The created function returns true
+// once the record creation has been signaled.
+func ContextWithCreatedSignal(ctx context.Context) (
+	createdCtx context.Context, created func() bool) {
+	flag := new(atomic.Bool)
+	return context.WithValue(ctx, createdKey{}, flag), flag.Load
+}
+
+// SignalCreated signals the record got created by the provider.
+// It is a no-op if the context was not created with
+// ContextWithCreatedSignal.
+func SignalCreated(ctx context.Context) {
+	flag, ok := ctx.Value(createdKey{}).(*atomic.Bool)
+	if ok {
+		flag.Store(true)
+	}
+}
+
+type noCreationKey struct{}
+
+// ContextWithoutCreation returns a context signaling providers
+// must not create a missing record, and must instead return an
+// error wrapping errors.ErrRecordNotFound.
+func ContextWithoutCreation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCreationKey{}, struct{}{})
+}
+
+// CreationAllowed returns true unless the context was created
+// with ContextWithoutCreation, in which case providers must not
+// create a missing record.
+func CreationAllowed(ctx context.Context) bool {
+	return ctx.Value(noCreationKey{}) == nil
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	ddnserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/ipextract"
+)
+
+// SetDynDNS2MyIP sets the `myip` query parameter for dyndns2 compatible
+// providers, unless the provider should determine the IP address from the
+// source address of the request. The provider IP cannot be used for IPv6
+// addresses with a suffix set, since the provider would not know the suffix.
+// It returns true if the provider IP is to be used.
+func SetDynDNS2MyIP(values url.Values, ip netip.Addr, useProviderIP bool,
+	ipv6Suffix netip.Prefix) (providerIPUsed bool) {
+	providerIPUsed = useProviderIP && (ip.Is4() || !ipv6Suffix.IsValid())
+	if !providerIPUsed {
+		values.Set("myip", ip.String())
+	}
+	return providerIPUsed
+}
+
+// DynDNS2ResponseIP returns the IP address the record is set to, given
+// the successful response body of a dyndns2 compatible provider such as
+// `good 1.2.3.4`. If the provider IP is used, this is the IP address found
+// in the response, or the IP address given if the response contains none.
+// Otherwise, the IP address found in the response, if any, must match the
+// IP address given.
+func DynDNS2ResponseIP(body string, ip netip.Addr, providerIPUsed bool) (
+	newIP netip.Addr, err error) {
+	var ips []netip.Addr
+	if ip.Is4() {
+		ips = ipextract.IPv4(body)
+	} else {
+		ips = ipextract.IPv6(body)
+	}
+
+	switch {
+	case len(ips) == 0:
+		return ip, nil
+	case providerIPUsed:
+		return ips[0], nil
+	case ips[0].Compare(ip) != 0:
+		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
+			ddnserrors.ErrIPReceivedMismatch, ip, ips[0])
+	}
+	return ip, nil
+}
+
+// LookupNetIPer looks up the IP addresses of a hostname.
+type LookupNetIPer interface {
+	LookupNetIP(ctx context.Context, network, host string) (ips []netip.Addr, err error)
+}
+
+// DoDynDNS2Request sends a dyndns2 update request, which sets the record to
+// a value and is therefore safe to send again. If the request times out,
+// it is ambiguous whether the update was applied, so the request is sent
+// again, up to 2 more times. If the last try also times out, the hostname is
+// resolved and, if it resolves to the IP address given, the update is
+// considered applied and verified is returned as true with a nil response.
+// Otherwise, the caller must close the response body.
+func DoDynDNS2Request(ctx context.Context, client *http.Client, request *http.Request,
+	hostname string, ip netip.Addr) (response *http.Response, verified bool, err error) {
+	const retries = 2
+	return doDynDNS2Request(ctx, client, request, hostname, ip, net.DefaultResolver, retries)
+}
+
+func doDynDNS2Request(ctx context.Context, client *http.Client, request *http.Request,
+	hostname string, ip netip.Addr, resolver LookupNetIPer, retries uint) (
+	response *http.Response, verified bool, err error) {
+	for try := uint(0); try <= retries; try++ {
+		response, err = client.Do(request)
+		if err == nil {
+			return response, false, nil
+		} else if ctx.Err() != nil || !isTimeout(err) {
+			return nil, false, err
+		}
+	}
+
+	ips, lookupErr := resolver.LookupNetIP(ctx, "ip", hostname)
+	if lookupErr != nil {
+		return nil, false, fmt.Errorf("%w (after %d retries, and verification failed: %w)",
+			err, retries, lookupErr)
+	}
+	for _, resolvedIP := range ips {
+		if resolvedIP.Unmap() == ip.Unmap() {
+			return nil, true, nil
+		}
+	}
+	return nil, false, fmt.Errorf("%w (after %d retries, and %s does not resolve to %s)",
+		err, retries, hostname, ip)
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
@@ -0,0 +1,262 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ddnserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SetDynDNS2MyIP(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ip             netip.Addr
+		useProviderIP  bool
+		ipv6Suffix     netip.Prefix
+		values         url.Values
+		providerIPUsed bool
+	}{
+		"local_ip": {
+			ip:     netip.MustParseAddr("1.2.3.4"),
+			values: url.Values{"myip": []string{"1.2.3.4"}},
+		},
+		"provider_ipv4": {
+			ip:             netip.MustParseAddr("1.2.3.4"),
+			useProviderIP:  true,
+			values:         url.Values{},
+			providerIPUsed: true,
+		},
+		"provider_ipv6": {
+			ip:             netip.MustParseAddr("::1"),
+			useProviderIP:  true,
+			values:         url.Values{},
+			providerIPUsed: true,
+		},
+		"provider_ipv6_with_suffix": {
+			ip:            netip.MustParseAddr("::1"),
+			useProviderIP: true,
+			ipv6Suffix:    netip.MustParsePrefix("0:0:0:0:72ad:8fbb:a54e:bedd/64"),
+			values:        url.Values{"myip": []string{"::1"}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			values := url.Values{}
+			providerIPUsed := SetDynDNS2MyIP(values, testCase.ip,
+				testCase.useProviderIP, testCase.ipv6Suffix)
+
+			assert.Equal(t, testCase.values, values)
+			assert.Equal(t, testCase.providerIPUsed, providerIPUsed)
+		})
+	}
+}
+
+func Test_DynDNS2ResponseIP(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		body           string
+		ip             netip.Addr
+		providerIPUsed bool
+		newIP          netip.Addr
+		errWrapped     error
+		errMessage     string
+	}{
+		"no_ip_in_response": {
+			body:  "good",
+			ip:    netip.MustParseAddr("1.2.3.4"),
+			newIP: netip.MustParseAddr("1.2.3.4"),
+		},
+		"matching_ip": {
+			body:  "nochg 1.2.3.4",
+			ip:    netip.MustParseAddr("1.2.3.4"),
+			newIP: netip.MustParseAddr("1.2.3.4"),
+		},
+		"mismatching_ip": {
+			body:       "good 5.6.7.8",
+			ip:         netip.MustParseAddr("1.2.3.4"),
+			errWrapped: ddnserrors.ErrIPReceivedMismatch,
+			errMessage: "mismatching IP address received: sent ip 1.2.3.4 to update but received 5.6.7.8",
+		},
+		"provider_ip": {
+			body:           "good 5.6.7.8",
+			ip:             netip.MustParseAddr("1.2.3.4"),
+			providerIPUsed: true,
+			newIP:          netip.MustParseAddr("5.6.7.8"),
+		},
+		"provider_ipv6": {
+			body:           "good 1.2.3.4 ::2",
+			ip:             netip.MustParseAddr("::1"),
+			providerIPUsed: true,
+			newIP:          netip.MustParseAddr("::2"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			newIP, err := DynDNS2ResponseIP(testCase.body, testCase.ip, testCase.providerIPUsed)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.newIP, newIP)
+		})
+	}
+}
+
+func Test_DoDynDNS2Request_timeoutThenSuccess(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			// The update reached the server but the response is lost.
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte("good 1.2.3.4"))
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	ctx := context.Background()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/nic/update", nil)
+	require.NoError(t, err)
+
+	response, verified, err := DoDynDNS2Request(ctx, client, request,
+		"host.example.com", netip.MustParseAddr("1.2.3.4"))
+
+	require.NoError(t, err)
+	assert.False(t, verified)
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, "good 1.2.3.4", string(body))
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+type testResolver struct {
+	ips []netip.Addr
+	err error
+}
+
+func (r *testResolver) LookupNetIP(_ context.Context, _, _ string) ([]netip.Addr, error) {
+	return r.ips, r.err
+}
+
+func Test_doDynDNS2Request(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		errs       []error
+		resolver   *testResolver
+		tries      int
+		verified   bool
+		errMessage string
+	}{
+		"success": {
+			errs:  []error{nil},
+			tries: 1,
+		},
+		"not_timeout_not_retried": {
+			errs:       []error{errTest},
+			tries:      1,
+			errMessage: `Get "https://example.com/nic/update": test error`,
+		},
+		"timeouts_then_verified": {
+			errs:     []error{timeoutError{}, timeoutError{}, timeoutError{}},
+			resolver: &testResolver{ips: []netip.Addr{netip.MustParseAddr("1.2.3.4")}},
+			tries:    3,
+			verified: true,
+		},
+		"timeouts_then_not_verified": {
+			errs:     []error{timeoutError{}, timeoutError{}, timeoutError{}},
+			resolver: &testResolver{ips: []netip.Addr{netip.MustParseAddr("5.6.7.8")}},
+			tries:    3,
+			errMessage: `Get "https://example.com/nic/update": timeout ` +
+				"(after 2 retries, and host.example.com does not resolve to 1.2.3.4)",
+		},
+		"timeouts_then_lookup_error": {
+			errs:     []error{timeoutError{}, timeoutError{}, timeoutError{}},
+			resolver: &testResolver{err: errTest},
+			tries:    3,
+			errMessage: `Get "https://example.com/nic/update": timeout ` +
+				"(after 2 retries, and verification failed: test error)",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tries := 0
+			client := &http.Client{
+				Transport: roundTripFunc(func(_ *http.Request) (*http.Response, error) {
+					err := testCase.errs[tries]
+					tries++
+					if err != nil {
+						return nil, err
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader("good 1.2.3.4")),
+					}, nil
+				}),
+			}
+			ctx := context.Background()
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet,
+				"https://example.com/nic/update", nil)
+			require.NoError(t, err)
+
+			const retries = 2
+			response, verified, err := doDynDNS2Request(ctx, client, request,
+				"host.example.com", netip.MustParseAddr("1.2.3.4"), testCase.resolver, retries)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			if response != nil {
+				_ = response.Body.Close()
+			}
+			assert.Equal(t, testCase.verified, verified)
+			assert.Equal(t, testCase.tries, tries)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package utils
+
+import "net/netip"
+
+// AnonymizeIP returns a string representation of the IP address
+// with its last IPv4 octet or its IPv6 interface identifier masked,
+// for display purposes only. For example 1.2.3.4 gives 1.2.3.0/24
+// and 2001:db8::1 gives 2001:db8::/64.
+func AnonymizeIP(ip netip.Addr) string {
+	if !ip.IsValid() {
+		return ip.String()
+	}
+
+	const ipv4Bits, ipv6Bits = 24, 64
+	bits := ipv6Bits
+	if ip.Is4() || ip.Is4In6() {
+		ip = ip.Unmap()
+		bits = ipv4Bits
+	}
+	prefix := netip.PrefixFrom(ip, bits).Masked()
+	return prefix.String()
+}
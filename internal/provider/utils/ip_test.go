@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AnonymizeIP(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ip         netip.Addr
+		anonymized string
+	}{
+		"invalid": {
+			anonymized: "invalid IP",
+		},
+		"ipv4": {
+			ip:         netip.MustParseAddr("1.2.3.4"),
+			anonymized: "1.2.3.0/24",
+		},
+		"ipv4_in_ipv6": {
+			ip:         netip.MustParseAddr("::ffff:1.2.3.4"),
+			anonymized: "1.2.3.0/24",
+		},
+		"ipv6": {
+			ip:         netip.MustParseAddr("2001:db8:1:2:72ad:8fbb:a54e:bedd"),
+			anonymized: "2001:db8:1:2::/64",
+		},
+		"ipv6_with_zone": {
+			ip:         netip.MustParseAddr("fe80::1%eth0"),
+			anonymized: "fe80::/64",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			anonymized := AnonymizeIP(testCase.ip)
+
+			assert.Equal(t, testCase.anonymized, anonymized)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package utils
+
+import "strings"
+
+// NormalizeRecordName returns the record name lowercased and without
+// trailing dots. If fqdn is true, a single trailing dot is added, for
+// APIs expecting fully qualified names. DNS names are case insensitive,
+// so this should be used on names before comparing or sending them.
+func NormalizeRecordName(name string, fqdn bool) string {
+	name = strings.ToLower(strings.TrimRight(name, "."))
+	if fqdn {
+		name += "."
+	}
+	return name
+}
+
+// RecordNamesEqual returns true if the two names are the same
+// record name, ignoring case and trailing dots.
+func RecordNamesEqual(a, b string) bool {
+	return NormalizeRecordName(a, false) == NormalizeRecordName(b, false)
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NormalizeRecordName(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		name       string
+		fqdn       bool
+		normalized string
+	}{
+		"empty": {},
+		"empty_fqdn": {
+			fqdn:       true,
+			normalized: ".",
+		},
+		"already_normalized": {
+			name:       "host.example.com",
+			normalized: "host.example.com",
+		},
+		"mixed_case_trailing_dot": {
+			name:       "Host.Example.COM.",
+			normalized: "host.example.com",
+		},
+		"fqdn_added": {
+			name:       "Host.Example.com",
+			fqdn:       true,
+			normalized: "host.example.com.",
+		},
+		"fqdn_not_doubled": {
+			name:       "host.example.com..",
+			fqdn:       true,
+			normalized: "host.example.com.",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			normalized := NormalizeRecordName(testCase.name, testCase.fqdn)
+
+			assert.Equal(t, testCase.normalized, normalized)
+		})
+	}
+}
+
+func Test_RecordNamesEqual(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		a, b  string
+		equal bool
+	}{
+		"same": {
+			a:     "host.example.com",
+			b:     "host.example.com",
+			equal: true,
+		},
+		"case_and_trailing_dot": {
+			a:     "Host.Example.com",
+			b:     "host.example.com.",
+			equal: true,
+		},
+		"different": {
+			a: "host.example.com",
+			b: "other.example.com",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			equal := RecordNamesEqual(testCase.a, testCase.b)
+
+			assert.Equal(t, testCase.equal, equal)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+)
+
+// MakeRecordSet returns the IP addresses to set in a record set,
+// which are the IP address given followed by the static IP addresses
+// of the same IP version.
+func MakeRecordSet(ip netip.Addr, staticIPs []netip.Addr) (ips []netip.Addr) {
+	ip = ip.Unmap()
+	ips = make([]netip.Addr, 1, 1+len(staticIPs))
+	ips[0] = ip
+	for _, staticIP := range staticIPs {
+		staticIP = staticIP.Unmap()
+		if staticIP.Is4() == ip.Is4() && !slices.Contains(ips, staticIP) {
+			ips = append(ips, staticIP)
+		}
+	}
+	return ips
+}
+
+// CheckRecordSet verifies the values received contain exactly the
+// IP addresses sent, in any order.
+func CheckRecordSet(sent []netip.Addr, received []string) (err error) {
+	receivedIPs := make([]netip.Addr, len(received))
+	for i, value := range received {
+		receivedIPs[i], err = netip.ParseAddr(value)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+		}
+		receivedIPs[i] = receivedIPs[i].Unmap()
+	}
+
+	if len(receivedIPs) == len(sent) {
+		allFound := true
+		for _, ip := range sent {
+			if !slices.Contains(receivedIPs, ip) {
+				allFound = false
+				break
+			}
+		}
+		if allFound {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: sent ips %v to update but received %v",
+		errors.ErrIPReceivedMismatch, sent, receivedIPs)
+}
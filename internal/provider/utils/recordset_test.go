@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MakeRecordSet(t *testing.T) {
+	t.Parallel()
+
+	staticIPs := []netip.Addr{
+		netip.MustParseAddr("5.6.7.8"),
+		netip.MustParseAddr("2001:db8::2"),
+		netip.MustParseAddr("1.2.3.4"),
+		netip.MustParseAddr("9.9.9.9"),
+	}
+
+	testCases := map[string]struct {
+		ip  netip.Addr
+		ips []netip.Addr
+	}{
+		"ipv4": {
+			ip: netip.MustParseAddr("1.2.3.4"),
+			ips: []netip.Addr{
+				netip.MustParseAddr("1.2.3.4"),
+				netip.MustParseAddr("5.6.7.8"),
+				netip.MustParseAddr("9.9.9.9"),
+			},
+		},
+		"ipv6": {
+			ip: netip.MustParseAddr("2001:db8::1"),
+			ips: []netip.Addr{
+				netip.MustParseAddr("2001:db8::1"),
+				netip.MustParseAddr("2001:db8::2"),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ips := MakeRecordSet(testCase.ip, staticIPs)
+
+			assert.Equal(t, testCase.ips, ips)
+		})
+	}
+}
+
+func Test_CheckRecordSet(t *testing.T) {
+	t.Parallel()
+
+	sent := []netip.Addr{
+		netip.MustParseAddr("1.2.3.4"),
+		netip.MustParseAddr("5.6.7.8"),
+	}
+
+	testCases := map[string]struct {
+		received   []string
+		errWrapped error
+		errMessage string
+	}{
+		"same_order": {
+			received: []string{"1.2.3.4", "5.6.7.8"},
+		},
+		"different_order": {
+			received: []string{"5.6.7.8", "1.2.3.4"},
+		},
+		"missing_value": {
+			received:   []string{"1.2.3.4"},
+			errWrapped: errors.ErrIPReceivedMismatch,
+			errMessage: "mismatching IP address received: " +
+				"sent ips [1.2.3.4 5.6.7.8] to update but received [1.2.3.4]",
+		},
+		"extra_value": {
+			received:   []string{"1.2.3.4", "5.6.7.8", "9.9.9.9"},
+			errWrapped: errors.ErrIPReceivedMismatch,
+			errMessage: "mismatching IP address received: " +
+				"sent ips [1.2.3.4 5.6.7.8] to update but received [1.2.3.4 5.6.7.8 9.9.9.9]",
+		},
+		"malformed_value": {
+			received:   []string{"1.2.3.4", "x"},
+			errWrapped: errors.ErrIPReceivedMalformed,
+			errMessage: `malformed IP address received: ParseAddr("x"): unable to parse IP`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := CheckRecordSet(sent, testCase.received)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
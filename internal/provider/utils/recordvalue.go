@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+)
+
+// RecordValueIP is replaced by the IP address in record value templates,
+// for example in the TXT record value template "v=spf1 ip4:{ip} -all".
+const RecordValueIP = "{ip}"
+
+// RecordValue is the value of a TXT, MX, SRV or CAA record.
+type RecordValue struct {
+	// Type is the record type, which is TXT, MX, SRV or CAA.
+	Type string
+	// Text is the text of a TXT record.
+	Text string
+	// Priority is the priority of a MX or SRV record.
+	Priority uint16
+	// Weight is the weight of a SRV record.
+	Weight uint16
+	// Port is the port of a SRV record.
+	Port uint16
+	// Target is the mail server of a MX record,
+	// or the target host of a SRV record.
+	Target string
+	// CAA is the data of a CAA record.
+	CAA CAA
+}
+
+// IsRecordValueType returns true if the record type given
+// is a record type supported by RenderRecordValue.
+func IsRecordValueType(recordType string) bool {
+	switch recordType {
+	case constants.TXT, constants.MX, constants.SRV, constants.CAA:
+		return true
+	default:
+		return false
+	}
+}
+
+// CheckRecordValue returns an error if the value template given
+// cannot be rendered to a valid value of the record type given.
+func CheckRecordValue(recordType, template string) (err error) {
+	_, err = RenderRecordValue(recordType, template,
+		netip.AddrFrom4([4]byte{192, 0, 2, 1})) //nolint:gomnd
+	return err
+}
+
+// RenderRecordValue replaces {ip} in the value template with the IP address
+// given, and parses the value obtained in the presentation format of the
+// record type given, for example `10 mail.example.com` for MX records,
+// `10 5 5060 sip.example.com` for SRV records or `0 issue "letsencrypt.org"`
+// for CAA records. The value of TXT records is the text as is.
+func RenderRecordValue(recordType, template string, ip netip.Addr) (
+	value RecordValue, err error) {
+	rendered := strings.ReplaceAll(template, RecordValueIP, ip.Unmap().String())
+	value.Type = recordType
+	fields := strings.Fields(rendered)
+
+	switch recordType {
+	case constants.TXT:
+		if rendered == "" {
+			return value, fmt.Errorf("%w: TXT value is empty", errors.ErrRecordValueNotValid)
+		}
+		value.Text = rendered
+	case constants.MX:
+		const mxFields = 2
+		if len(fields) != mxFields {
+			return value, fmt.Errorf("%w: MX value %q must be the priority and the mail server",
+				errors.ErrRecordValueNotValid, rendered)
+		}
+		value.Priority, err = parseUint16(fields[0])
+		if err != nil {
+			return value, fmt.Errorf("%w: MX priority: %w", errors.ErrRecordValueNotValid, err)
+		}
+		value.Target, err = parseTarget(fields[1])
+		if err != nil {
+			return value, fmt.Errorf("MX mail server: %w", err)
+		}
+	case constants.SRV:
+		const srvFields = 4
+		if len(fields) != srvFields {
+			return value, fmt.Errorf("%w: SRV value %q must be the priority, weight, port and target",
+				errors.ErrRecordValueNotValid, rendered)
+		}
+		for i, field := range []*uint16{&value.Priority, &value.Weight, &value.Port} {
+			*field, err = parseUint16(fields[i])
+			if err != nil {
+				return value, fmt.Errorf("%w: SRV field %d: %w", errors.ErrRecordValueNotValid, i+1, err)
+			}
+		}
+		value.Target, err = parseTarget(fields[3])
+		if err != nil {
+			return value, fmt.Errorf("SRV target: %w", err)
+		}
+	case constants.CAA:
+		value.CAA, err = ParseCAA(rendered)
+		if err != nil {
+			return value, fmt.Errorf("%w: CAA value %q must be the flags, tag and value",
+				errors.ErrRecordValueNotValid, rendered)
+		}
+		err = value.CAA.Validate()
+		if err != nil {
+			return value, err
+		}
+	default:
+		return value, fmt.Errorf("%w: %q can only be %q, %q, %q or %q",
+			errors.ErrRecordTypeNotValid, recordType,
+			constants.TXT, constants.MX, constants.SRV, constants.CAA)
+	}
+	return value, nil
+}
+
+func parseUint16(s string) (n uint16, err error) {
+	n64, err := strconv.ParseUint(s, 10, 16) //nolint:gomnd
+	if err != nil {
+		return 0, err
+	}
+	return uint16(n64), nil
+}
+
+func parseTarget(s string) (target string, err error) {
+	target = NormalizeRecordName(s, false)
+	err = checkHostname(target)
+	if err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// String returns the record value in its presentation format,
+// with fully qualified MX and SRV targets. The value of TXT
+// records is returned as is, without quotes.
+func (v RecordValue) String() string {
+	switch v.Type {
+	case constants.TXT:
+		return v.Text
+	case constants.MX:
+		return fmt.Sprintf("%d %s", v.Priority, NormalizeRecordName(v.Target, true))
+	case constants.SRV:
+		return fmt.Sprintf("%d %d %d %s", v.Priority, v.Weight, v.Port,
+			NormalizeRecordName(v.Target, true))
+	case constants.CAA:
+		return v.CAA.RData()
+	default:
+		return ""
+	}
+}
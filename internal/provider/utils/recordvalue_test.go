@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RenderRecordValue(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		recordType string
+		template   string
+		ip         netip.Addr
+		value      RecordValue
+		rendered   string
+		errWrapped error
+		errMessage string
+	}{
+		"txt": {
+			recordType: "TXT",
+			template:   "v=spf1 ip6:{ip} -all",
+			ip:         netip.MustParseAddr("2001:db8::1"),
+			value:      RecordValue{Type: "TXT", Text: "v=spf1 ip6:2001:db8::1 -all"},
+			rendered:   "v=spf1 ip6:2001:db8::1 -all",
+		},
+		"txt_empty": {
+			recordType: "TXT",
+			ip:         netip.MustParseAddr("192.0.2.10"),
+			errWrapped: errors.ErrRecordValueNotValid,
+			errMessage: "record value is not valid: TXT value is empty",
+		},
+		"mx": {
+			recordType: "MX",
+			template:   "10 Mail.Example.com.",
+			ip:         netip.MustParseAddr("192.0.2.10"),
+			value:      RecordValue{Type: "MX", Priority: 10, Target: "mail.example.com"},
+			rendered:   "10 mail.example.com.",
+		},
+		"mx_priority_not_valid": {
+			recordType: "MX",
+			template:   "high mail.example.com",
+			ip:         netip.MustParseAddr("192.0.2.10"),
+			errWrapped: errors.ErrRecordValueNotValid,
+			errMessage: `record value is not valid: MX priority: strconv.ParseUint: ` +
+				`parsing "high": invalid syntax`,
+		},
+		"srv": {
+			recordType: "SRV",
+			template:   "10 5 5060 sip.example.com",
+			ip:         netip.MustParseAddr("192.0.2.10"),
+			value: RecordValue{Type: "SRV", Priority: 10, Weight: 5, Port: 5060,
+				Target: "sip.example.com"},
+			rendered: "10 5 5060 sip.example.com.",
+		},
+		"srv_target_not_valid": {
+			recordType: "SRV",
+			template:   "10 5 5060 -sip.example.com",
+			ip:         netip.MustParseAddr("192.0.2.10"),
+			errWrapped: errors.ErrTargetNotValid,
+			errMessage: `SRV target: target is not valid: -sip.example.com: label "-sip" is not valid`,
+		},
+		"caa": {
+			recordType: "CAA",
+			template:   `0 issue "letsencrypt.org"`,
+			ip:         netip.MustParseAddr("192.0.2.10"),
+			value: RecordValue{Type: "CAA", CAA: CAA{Flags: 0, Tag: "issue",
+				Value: "letsencrypt.org"}},
+			rendered: `0 issue "letsencrypt.org"`,
+		},
+		"caa_tag_not_valid": {
+			recordType: "CAA",
+			template:   `0 issuer "letsencrypt.org"`,
+			ip:         netip.MustParseAddr("192.0.2.10"),
+			errWrapped: errors.ErrCAANotValid,
+			errMessage: `CAA record data is not valid: tag "issuer" must be issue, issuewild or iodef`,
+		},
+		"record_type_not_valid": {
+			recordType: "NS",
+			template:   "ns1.example.com",
+			ip:         netip.MustParseAddr("192.0.2.10"),
+			errWrapped: errors.ErrRecordTypeNotValid,
+			errMessage: `record type is not valid: "NS" can only be "TXT", "MX", "SRV" or "CAA"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := RenderRecordValue(testCase.recordType, testCase.template, testCase.ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.value, value)
+			assert.Equal(t, testCase.rendered, value.String())
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// ReverseName returns the reverse DNS name of the IP address, used as the
+// name of its PTR record. It is in the form 4.3.2.1.in-addr.arpa for IPv4
+// addresses, and in the nibble format ending with ip6.arpa for IPv6 addresses.
+func ReverseName(ip netip.Addr) string {
+	ip = ip.Unmap()
+	if ip.Is4() {
+		bytes := ip.As4()
+		return strconv.Itoa(int(bytes[3])) + "." + strconv.Itoa(int(bytes[2])) + "." +
+			strconv.Itoa(int(bytes[1])) + "." + strconv.Itoa(int(bytes[0])) + ".in-addr.arpa"
+	}
+
+	const hexDigits = "0123456789abcdef"
+	bytes := ip.As16()
+	var builder strings.Builder
+	for i := len(bytes) - 1; i >= 0; i-- {
+		builder.WriteByte(hexDigits[bytes[i]&0xf]) //nolint:gomnd
+		builder.WriteByte('.')
+		builder.WriteByte(hexDigits[bytes[i]>>4]) //nolint:gomnd
+		builder.WriteByte('.')
+	}
+	builder.WriteString("ip6.arpa")
+	return builder.String()
+}
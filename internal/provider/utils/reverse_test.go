@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReverseName(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ip   netip.Addr
+		name string
+	}{
+		"ipv4": {
+			ip:   netip.MustParseAddr("192.0.2.10"),
+			name: "10.2.0.192.in-addr.arpa",
+		},
+		"ipv4_mapped_ipv6": {
+			ip:   netip.MustParseAddr("::ffff:192.0.2.10"),
+			name: "10.2.0.192.in-addr.arpa",
+		},
+		"ipv6": {
+			ip: netip.MustParseAddr("2001:db8::567:89ab"),
+			name: "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0." +
+				"8.b.d.0.1.0.0.2.ip6.arpa",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			reverseName := ReverseName(testCase.ip)
+
+			assert.Equal(t, testCase.name, reverseName)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+)
+
+// TLSA is the data of a TLSA record, used for DANE as described in RFC 6698.
+type TLSA struct {
+	// Usage is the certificate usage, from 0 (PKIX-TA) to 3 (DANE-EE).
+	Usage uint8 `json:"usage"`
+	// Selector is 0 to match the full certificate,
+	// or 1 to match its subject public key info.
+	Selector uint8 `json:"selector"`
+	// MatchingType is 0 for the exact data, 1 for
+	// its SHA-256 hash and 2 for its SHA-512 hash.
+	MatchingType uint8 `json:"matching_type"`
+	// CertificateData is the hex encoded data to match.
+	CertificateData string `json:"certificate_data"`
+}
+
+// Validate verifies the TLSA record fields are valid and consistent,
+// such as the certificate data length matching the matching type.
+func (t TLSA) Validate() (err error) {
+	const maxUsage, maxSelector, maxMatchingType = 3, 1, 2
+	switch {
+	case t.Usage > maxUsage:
+		return fmt.Errorf("%w: usage %d must be between 0 and %d",
+			errors.ErrTLSANotValid, t.Usage, maxUsage)
+	case t.Selector > maxSelector:
+		return fmt.Errorf("%w: selector %d must be between 0 and %d",
+			errors.ErrTLSANotValid, t.Selector, maxSelector)
+	case t.MatchingType > maxMatchingType:
+		return fmt.Errorf("%w: matching type %d must be between 0 and %d",
+			errors.ErrTLSANotValid, t.MatchingType, maxMatchingType)
+	}
+
+	data, err := hex.DecodeString(t.CertificateData)
+	if err != nil {
+		return fmt.Errorf("%w: certificate data is not hexadecimal: %w",
+			errors.ErrTLSANotValid, err)
+	}
+
+	// Lengths of SHA-256 and SHA-512 digests in bytes.
+	hashLengths := map[uint8]int{1: 32, 2: 64} //nolint:gomnd
+	expectedLength, isHash := hashLengths[t.MatchingType]
+	switch {
+	case len(data) == 0:
+		return fmt.Errorf("%w: certificate data is empty", errors.ErrTLSANotValid)
+	case isHash && len(data) != expectedLength:
+		return fmt.Errorf("%w: certificate data is %d bytes instead of %d bytes for matching type %d",
+			errors.ErrTLSANotValid, len(data), expectedLength, t.MatchingType)
+	}
+	return nil
+}
+
+// RData returns the TLSA record data in its presentation format,
+// for example "3 1 1 0123...cdef", with the certificate data lowercased.
+func (t TLSA) RData() string {
+	return fmt.Sprintf("%d %d %d %s", t.Usage, t.Selector,
+		t.MatchingType, strings.ToLower(t.CertificateData))
+}
+
+// ParseTLSA parses TLSA record data in its presentation format.
+// The certificate data can be split with spaces, as some DNS
+// servers return it in multiple chunks.
+func ParseTLSA(rdata string) (tlsa TLSA, err error) {
+	const minFields = 4
+	fields := strings.Fields(rdata)
+	if len(fields) < minFields {
+		return tlsa, fmt.Errorf("%w: %q has %d fields instead of at least %d",
+			errors.ErrDataReceivedMalformed, rdata, len(fields), minFields)
+	}
+
+	values := [3]*uint8{&tlsa.Usage, &tlsa.Selector, &tlsa.MatchingType}
+	for i, value := range values {
+		parsed, err := strconv.ParseUint(fields[i], 10, 8)
+		if err != nil {
+			return tlsa, fmt.Errorf("%w: %q: %w", errors.ErrDataReceivedMalformed, rdata, err)
+		}
+		*value = uint8(parsed)
+	}
+	tlsa.CertificateData = strings.ToLower(strings.Join(fields[3:], ""))
+	return tlsa, nil
+}
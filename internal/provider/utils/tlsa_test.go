@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TLSA_Validate(t *testing.T) {
+	t.Parallel()
+
+	sha256Data := strings.Repeat("ab", 32)
+
+	testCases := map[string]struct {
+		tlsa       TLSA
+		errWrapped error
+		errMessage string
+	}{
+		"dane_ee_sha256": {
+			tlsa: TLSA{Usage: 3, Selector: 1, MatchingType: 1, CertificateData: sha256Data},
+		},
+		"full_certificate": {
+			tlsa: TLSA{Usage: 2, MatchingType: 0, CertificateData: "3082"},
+		},
+		"usage_too_high": {
+			tlsa:       TLSA{Usage: 4, MatchingType: 1, CertificateData: sha256Data},
+			errWrapped: errors.ErrTLSANotValid,
+			errMessage: "TLSA record data is not valid: usage 4 must be between 0 and 3",
+		},
+		"selector_too_high": {
+			tlsa:       TLSA{Selector: 2, MatchingType: 1, CertificateData: sha256Data},
+			errWrapped: errors.ErrTLSANotValid,
+			errMessage: "TLSA record data is not valid: selector 2 must be between 0 and 1",
+		},
+		"matching_type_too_high": {
+			tlsa:       TLSA{MatchingType: 3, CertificateData: sha256Data},
+			errWrapped: errors.ErrTLSANotValid,
+			errMessage: "TLSA record data is not valid: matching type 3 must be between 0 and 2",
+		},
+		"not_hexadecimal": {
+			tlsa:       TLSA{CertificateData: "xyz"},
+			errWrapped: errors.ErrTLSANotValid,
+			errMessage: "TLSA record data is not valid: certificate data is not hexadecimal: " +
+				"encoding/hex: invalid byte: U+0078 'x'",
+		},
+		"empty_data": {
+			errWrapped: errors.ErrTLSANotValid,
+			errMessage: "TLSA record data is not valid: certificate data is empty",
+		},
+		"sha512_length_mismatch": {
+			tlsa:       TLSA{MatchingType: 2, CertificateData: sha256Data},
+			errWrapped: errors.ErrTLSANotValid,
+			errMessage: "TLSA record data is not valid: certificate data is 32 bytes " +
+				"instead of 64 bytes for matching type 2",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.tlsa.Validate()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_ParseTLSA(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		rdata      string
+		tlsa       TLSA
+		errWrapped error
+		errMessage string
+	}{
+		"chunked_uppercase": {
+			rdata: "3 1 1 ABCD EF01",
+			tlsa:  TLSA{Usage: 3, Selector: 1, MatchingType: 1, CertificateData: "abcdef01"},
+		},
+		"too_few_fields": {
+			rdata:      "3 1 1",
+			errWrapped: errors.ErrDataReceivedMalformed,
+			errMessage: `malformed record data received: "3 1 1" has 3 fields instead of at least 4`,
+		},
+		"malformed_usage": {
+			rdata:      "x 1 1 abcd",
+			errWrapped: errors.ErrDataReceivedMalformed,
+			errMessage: `malformed record data received: "x 1 1 abcd": ` +
+				`strconv.ParseUint: parsing "x": invalid syntax`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tlsa, err := ParseTLSA(testCase.rdata)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.tlsa, tlsa)
+		})
+	}
+}
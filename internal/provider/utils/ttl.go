@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+)
+
+// TTL is a record time to live in seconds. It can be set in the
+// JSON settings as a number of seconds such as 300, or as a
+// duration string such as "300s", "5m" or "1h".
+type TTL uint32
+
+func (t *TTL) UnmarshalJSON(data []byte) error {
+	var seconds uint32
+	err := json.Unmarshal(data, &seconds)
+	if err == nil {
+		*t = TTL(seconds)
+		return nil
+	}
+
+	var s string
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("%w: %s must be a number of seconds or a duration string",
+			errors.ErrTTLNotValid, data)
+	}
+
+	ttl, err := ParseTTL(s)
+	if err != nil {
+		return err
+	}
+	*t = ttl
+	return nil
+}
+
+// ParseTTL parses a TTL given as a number of seconds or
+// as a duration string, which must be a whole number of seconds.
+func ParseTTL(s string) (ttl TTL, err error) {
+	seconds, err := strconv.ParseUint(s, 10, 32) //nolint:gomnd
+	if err == nil {
+		return TTL(seconds), nil
+	}
+
+	duration, err := time.ParseDuration(s)
+	switch {
+	case err != nil:
+		return 0, fmt.Errorf("%w: %w", errors.ErrTTLNotValid, err)
+	case duration < 0:
+		return 0, fmt.Errorf("%w: %s is negative", errors.ErrTTLNotValid, s)
+	case duration%time.Second != 0:
+		return 0, fmt.Errorf("%w: %s is not a whole number of seconds", errors.ErrTTLNotValid, s)
+	case duration/time.Second > math.MaxUint32:
+		return 0, fmt.Errorf("%w: %s is too large", errors.ErrTTLNotValid, s)
+	}
+	return TTL(duration / time.Second), nil
+}
+
+// CheckTTL returns an error if the TTL is not zero and is not within
+// the inclusive range given. A zero TTL is the provider default.
+func CheckTTL(ttl TTL, minTTL, maxTTL uint32) error {
+	switch {
+	case ttl == 0:
+		return nil
+	case uint32(ttl) < minTTL:
+		return fmt.Errorf("%w: %d must be at least %d", errors.ErrTTLTooLow, ttl, minTTL)
+	case uint32(ttl) > maxTTL:
+		return fmt.Errorf("%w: %d must be at most %d", errors.ErrTTLTooHigh, ttl, maxTTL)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TTL_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		ttl        TTL
+		errWrapped error
+		errMessage string
+	}{
+		"seconds": {
+			data: `300`,
+			ttl:  300,
+		},
+		"seconds_string": {
+			data: `"300"`,
+			ttl:  300,
+		},
+		"duration_seconds": {
+			data: `"300s"`,
+			ttl:  300,
+		},
+		"duration_hours": {
+			data: `"1h"`,
+			ttl:  3600,
+		},
+		"duration_mixed": {
+			data: `"1h30m"`,
+			ttl:  5400,
+		},
+		"negative_seconds": {
+			data:       `-1`,
+			errWrapped: errors.ErrTTLNotValid,
+			errMessage: "TTL is not valid: -1 must be a number of seconds or a duration string",
+		},
+		"negative_duration": {
+			data:       `"-5m"`,
+			errWrapped: errors.ErrTTLNotValid,
+			errMessage: "TTL is not valid: -5m is negative",
+		},
+		"sub_second_duration": {
+			data:       `"1500ms"`,
+			errWrapped: errors.ErrTTLNotValid,
+			errMessage: "TTL is not valid: 1500ms is not a whole number of seconds",
+		},
+		"duration_too_large": {
+			data:       `"2000000h"`,
+			errWrapped: errors.ErrTTLNotValid,
+			errMessage: "TTL is not valid: 2000000h is too large",
+		},
+		"malformed_duration": {
+			data:       `"one hour"`,
+			errWrapped: errors.ErrTTLNotValid,
+			errMessage: `TTL is not valid: time: invalid duration "one hour"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var ttl TTL
+			err := json.Unmarshal([]byte(testCase.data), &ttl)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.ttl, ttl)
+		})
+	}
+}
+
+func Test_CheckTTL(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ttl        TTL
+		errWrapped error
+		errMessage string
+	}{
+		"provider_default": {},
+		"minimum": {
+			ttl: 300,
+		},
+		"maximum": {
+			ttl: 86400,
+		},
+		"too_low": {
+			ttl:        60,
+			errWrapped: errors.ErrTTLTooLow,
+			errMessage: "TTL is too low: 60 must be at least 300",
+		},
+		"too_high": {
+			ttl:        86401,
+			errWrapped: errors.ErrTTLTooHigh,
+			errMessage: "TTL is too high: 86401 must be at most 86400",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := CheckTTL(testCase.ttl, 300, 86400)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
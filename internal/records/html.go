@@ -2,37 +2,55 @@ package records
 
 import (
 	"fmt"
+	"html"
+	"net/netip"
 	"strings"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/constants"
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
 )
 
-func (r *Record) HTML(now time.Time) models.HTMLRow {
+// HTML returns the HTML row for the record. If anonymizeIPs is true,
+// IP addresses are anonymized and not linked to an IP information website.
+func (r *Record) HTML(now time.Time, anonymizeIPs bool) models.HTMLRow {
 	const NotAvailable = "N/A"
 	row := r.Provider.HTML()
+	row.Capabilities = r.Provider.Capabilities().String()
 	message := r.Message
-	if r.Status == constants.UPTODATE {
+	switch {
+	case r.Status == constants.UPTODATE:
 		message = "no IP change for " + r.History.GetDurationSinceSuccess(now)
+	case r.Status == constants.FAIL && r.LastError != "":
+		message = "" // shown in the last error column
 	}
 	if message != "" {
-		message = fmt.Sprintf("(%s)", message)
+		message = fmt.Sprintf(" (%s)", message)
 	}
 	if r.Status == "" {
 		row.Status = NotAvailable
 	} else {
-		row.Status = fmt.Sprintf("%s %s, %s",
+		row.Status = fmt.Sprintf("%s%s, %s",
 			convertStatus(r.Status),
 			message,
 			time.Since(r.Time).Round(time.Second).String()+" ago")
 	}
 	currentIP := r.History.GetCurrentIP()
-	if currentIP.IsValid() {
-		row.CurrentIP = `<a href="https://ipinfo.io/` + currentIP.String() + `">` + currentIP.String() + "</a>"
-	} else {
+	switch {
+	case !currentIP.IsValid():
 		row.CurrentIP = NotAvailable
+	case anonymizeIPs:
+		row.CurrentIP = utils.AnonymizeIP(currentIP)
+	default:
+		row.CurrentIP = `<a href="https://ipinfo.io/` + currentIP.String() + `">` + currentIP.String() + "</a>"
+	}
+	if r.BehindCGNAT {
+		row.CurrentIP += ` <span title="The public IP address is behind carrier-grade NAT, ` +
+			`inbound connections are likely not to work">(CGNAT)</span>`
 	}
+	row.Preview = r.previewHTML(anonymizeIPs)
+	row.LastError = lastErrorHTML(r.LastError)
 	previousIPs := r.History.GetPreviousIPs()
 	row.PreviousIPs = NotAvailable
 	if len(previousIPs) > 0 {
@@ -43,13 +61,55 @@ func (r *Record) HTML(now time.Time) models.HTMLRow {
 				previousIPsStr = append(previousIPsStr, fmt.Sprintf("and %d more", len(previousIPs)-i))
 				break
 			}
-			previousIPsStr = append(previousIPsStr, previousIP.String())
+			previousIPStr := previousIP.String()
+			if anonymizeIPs {
+				previousIPStr = utils.AnonymizeIP(previousIP)
+			}
+			previousIPsStr = append(previousIPsStr, previousIPStr)
 		}
 		row.PreviousIPs = strings.Join(previousIPsStr, ", ")
 	}
 	return row
 }
 
+// previewHTML returns the IP address observed at the provider alongside
+// the public IP address detected, to show if the record is about to change.
+func (r *Record) previewHTML(anonymizeIPs bool) string {
+	const NotAvailable = "N/A"
+	if !r.DetectedIP.IsValid() {
+		return NotAvailable
+	}
+	ipToString := func(ip netip.Addr) string {
+		switch {
+		case !ip.IsValid():
+			return NotAvailable
+		case anonymizeIPs:
+			return utils.AnonymizeIP(ip)
+		default:
+			return ip.String()
+		}
+	}
+	if r.ProviderIP == r.DetectedIP {
+		return ipToString(r.DetectedIP) + ` <font color="#00CC66">(unchanged)</font>`
+	}
+	return ipToString(r.ProviderIP) + " &rarr; " + ipToString(r.DetectedIP) +
+		` <font color="orange"><b>(change)</b></font>`
+}
+
+// lastErrorHTML returns the last error HTML escaped, truncated
+// to keep the row short. The full error is available in the API.
+func lastErrorHTML(lastError string) string {
+	if lastError == "" {
+		return ""
+	}
+	const maxRunes = 80
+	runes := []rune(lastError)
+	if len(runes) > maxRunes {
+		lastError = string(runes[:maxRunes-1]) + "…"
+	}
+	return html.EscapeString(lastError)
+}
+
 func convertStatus(status models.Status) string {
 	switch status {
 	case constants.SUCCESS:
@@ -60,6 +120,8 @@ func convertStatus(status models.Status) string {
 		return `<font color="#00CC66"><b>Up to date</b></font>`
 	case constants.UPDATING:
 		return `<font color="orange"><b>Updating</b></font>`
+	case constants.SKIPPED:
+		return `<font color="gray"><b>Skipped</b></font>`
 	case constants.UNSET:
 		return `<font color="purple"><b>Unset</b></font>`
 	default:
@@ -0,0 +1,70 @@
+package records
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func (p *testProvider) HTML() models.HTMLRow {
+	return models.HTMLRow{Domain: p.domain, Host: p.host}
+}
+
+func (p *testProvider) Capabilities() models.Capabilities {
+	return models.Capabilities{}
+}
+
+func Test_Record_HTML_preview(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		providerIP   netip.Addr
+		detectedIP   netip.Addr
+		anonymizeIPs bool
+		preview      string
+	}{
+		"not_checked": {
+			preview: "N/A",
+		},
+		"unchanged": {
+			providerIP: netip.MustParseAddr("1.2.3.4"),
+			detectedIP: netip.MustParseAddr("1.2.3.4"),
+			preview:    `1.2.3.4 <font color="#00CC66">(unchanged)</font>`,
+		},
+		"changed": {
+			providerIP: netip.MustParseAddr("1.2.3.4"),
+			detectedIP: netip.MustParseAddr("5.6.7.8"),
+			preview:    `1.2.3.4 &rarr; 5.6.7.8 <font color="orange"><b>(change)</b></font>`,
+		},
+		"changed_provider_ip_unknown": {
+			detectedIP: netip.MustParseAddr("5.6.7.8"),
+			preview:    `N/A &rarr; 5.6.7.8 <font color="orange"><b>(change)</b></font>`,
+		},
+		"changed_anonymized": {
+			providerIP:   netip.MustParseAddr("1.2.3.4"),
+			detectedIP:   netip.MustParseAddr("5.6.7.8"),
+			anonymizeIPs: true,
+			preview:      `1.2.3.0/24 &rarr; 5.6.7.0/24 <font color="orange"><b>(change)</b></font>`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			record := Record{
+				Provider:   &testProvider{domain: "example.com", host: "@"},
+				ProviderIP: testCase.providerIP,
+				DetectedIP: testCase.detectedIP,
+			}
+
+			row := record.HTML(time.Now(), testCase.anonymizeIPs)
+
+			assert.Equal(t, testCase.preview, row.Preview)
+		})
+	}
+}
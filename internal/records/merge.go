@@ -0,0 +1,50 @@
+package records
+
+import (
+	"github.com/qdm12/ddns-updater/internal/provider"
+)
+
+// key identifies a record across configuration reloads.
+func (r *Record) key() string {
+	return string(r.Options.ProviderName) + "|" + r.Provider.Domain() + "|" +
+		r.Provider.Host() + "|" + r.Provider.IPVersion().String()
+}
+
+// Merge returns the records of newRecords, typically read from a reloaded
+// configuration, keeping the state of the records of oldRecords with the
+// same provider, domain, host and IP version, such as their history, status
+// and managed record IDs. Their provider and options are the ones of
+// newRecords, so changed settings are applied. For each merged record,
+// oldIDs contains the index of the old record it kept the state of,
+// or -1 if the record is new.
+func Merge(oldRecords, newRecords []Record) (merged []Record, oldIDs []int) {
+	oldIDsByKey := make(map[string]int, len(oldRecords))
+	for i := range oldRecords {
+		oldIDsByKey[oldRecords[i].key()] = i
+	}
+
+	merged = make([]Record, len(newRecords))
+	oldIDs = make([]int, len(newRecords))
+	for i, newRecord := range newRecords {
+		oldID, ok := oldIDsByKey[newRecord.key()]
+		if !ok {
+			merged[i] = newRecord
+			oldIDs[i] = -1
+			continue
+		}
+		delete(oldIDsByKey, newRecord.key()) // in case of duplicates
+
+		record := oldRecords[oldID]
+		oldProvider := record.Provider
+		record.Provider = newRecord.Provider
+		record.Options = newRecord.Options
+		oldManager, oldOK := oldProvider.(provider.RecordManager)
+		newManager, newOK := newRecord.Provider.(provider.RecordManager)
+		if oldOK && newOK {
+			newManager.SetManagedRecordIDs(oldManager.ManagedRecordIDs())
+		}
+		merged[i] = record
+		oldIDs[i] = oldID
+	}
+	return merged, oldIDs
+}
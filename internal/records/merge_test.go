@@ -0,0 +1,72 @@
+package records
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRecordManager struct {
+	testProvider
+	ids []string
+}
+
+func (p *testRecordManager) ManagedRecordIDs() []string       { return p.ids }
+func (p *testRecordManager) SetManagedRecordIDs(ids []string) { p.ids = ids }
+
+func Test_Merge(t *testing.T) {
+	t.Parallel()
+
+	oldRecords := []Record{
+		{
+			Provider: &testProvider{domain: "a.com", host: "@", ipVersion: ipversion.IP4},
+			Options:  Options{Period: time.Hour},
+			Status:   constants.SUCCESS,
+			Message:  "kept",
+		},
+		{
+			Provider: &testProvider{domain: "b.com", host: "@", ipVersion: ipversion.IP4},
+			Status:   constants.FAIL,
+		},
+		{
+			Provider: &testRecordManager{
+				testProvider: testProvider{domain: "c.com", host: "@", ipVersion: ipversion.IP6},
+				ids:          []string{"1"},
+			},
+			Status: constants.UPTODATE,
+		},
+	}
+	newManager := &testRecordManager{
+		testProvider: testProvider{domain: "c.com", host: "@", ipVersion: ipversion.IP6},
+	}
+	newRecords := []Record{
+		{Provider: newManager},
+		{Provider: &testProvider{domain: "d.com", host: "@", ipVersion: ipversion.IP4}},
+		{
+			Provider: &testProvider{domain: "a.com", host: "@", ipVersion: ipversion.IP4},
+			Options:  Options{Period: time.Minute},
+		},
+		{Provider: &testProvider{domain: "b.com", host: "@", ipVersion: ipversion.IP6}},
+	}
+
+	merged, oldIDs := Merge(oldRecords, newRecords)
+
+	assert.Equal(t, []int{2, -1, 0, -1}, oldIDs)
+	assert.Len(t, merged, len(newRecords))
+
+	assert.Same(t, newManager, merged[0].Provider)
+	assert.Equal(t, constants.UPTODATE, merged[0].Status)
+	assert.Equal(t, []string{"1"}, newManager.ids)
+
+	assert.Equal(t, newRecords[1], merged[1])
+
+	assert.Same(t, newRecords[2].Provider, merged[2].Provider)
+	assert.Equal(t, time.Minute, merged[2].Options.Period)
+	assert.Equal(t, constants.SUCCESS, merged[2].Status)
+	assert.Equal(t, "kept", merged[2].Message)
+
+	assert.Equal(t, newRecords[3], merged[3])
+}
@@ -2,27 +2,126 @@ package records
 
 import (
 	"fmt"
+	"net/netip"
+	"net/url"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/failover"
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/nscheck"
 	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/transform"
 )
 
 // Record contains all the information to update and display a DNS record.
 type Record struct { // internal
 	Provider provider.Provider // fixed
+	Options  Options           // fixed
 	History  models.History    // past information
 	Status   models.Status
 	Message  string
 	Time     time.Time
 	LastBan  *time.Time // nil means no last ban
+	// LastError is the error of the last failed update, with secrets
+	// redacted. It is cleared on the next successful update.
+	LastError string
+	// ProviderIP is the IP address last observed for the record at
+	// the provider, either using a DNS lookup or, for proxied records,
+	// the last IP address set. It is the zero value if unknown.
+	ProviderIP netip.Addr
+	// DetectedIP is the public IP address last detected for the record,
+	// and is the zero value if no public IP address was detected yet.
+	DetectedIP netip.Addr
+	// BehindCGNAT is true if the last public IP address obtained
+	// for the record is in the carrier-grade NAT range.
+	BehindCGNAT bool
 }
 
-// New returns a new Record with provider and some history.
-func New(provider provider.Provider, events []models.HistoryEvent) Record {
+// Options contains record settings common to all providers,
+// used by the program and not by the provider itself.
+type Options struct {
+	// SkipVerify treats an update accepted by the provider as
+	// successful, even if the IP address it returns does not
+	// match the IP address sent.
+	SkipVerify bool
+	// ProviderName is the name of the DNS provider of the record.
+	ProviderName models.Provider
+	// Tags are user defined tags for the record,
+	// available in the notification template.
+	Tags []string
+	// IgnoreErrors is a list of HTTP status codes or error message
+	// substrings to treat as a successful update, for providers
+	// returning errors for effectively no-op updates.
+	IgnoreErrors []string
+	// ExtraHeaders are HTTP headers set on every request
+	// sent to the DNS provider, for example for an
+	// authentication proxy in front of its API.
+	ExtraHeaders map[string]string
+	// APIURL, if not nil, replaces the scheme, host and base path of
+	// every request sent to the DNS provider, for example for a
+	// regional endpoint or a mock server.
+	APIURL *url.URL
+	// Failover, if not nil, makes the record point to a primary IP
+	// address or to a backup IP address depending on the primary
+	// health, instead of pointing to the public IP address.
+	Failover *failover.Settings
+	// AllowedIPRanges, if not empty, are the only IP ranges the
+	// record can be updated to. Updates to IP addresses outside
+	// these ranges are skipped.
+	AllowedIPRanges []netip.Prefix
+	// Fallback, if not nil, is a second provider serving the same
+	// record, used to update the record if its provider fails.
+	Fallback *Fallback
+	// NameserverCheck, if not nil, makes an update successful only
+	// once the nameserver configured serves the new IP address,
+	// instead of trusting the IP address returned by the provider.
+	NameserverCheck *nscheck.Settings
+	// MissingRecord is the policy applied if the record does not
+	// exist at the provider, and can be MissingRecordError,
+	// MissingRecordSkip or MissingRecordCreate. If empty, the record
+	// is created for providers supporting it.
+	MissingRecord string
+	// Transforms are applied in order to the public IP address
+	// to derive the IP address to set in the record. They are not
+	// applied to failover IP addresses.
+	Transforms []transform.Transform
+	// Period, if not zero, is the period at which the record is
+	// checked and updated, instead of the global update period.
+	Period time.Duration
+}
+
+const (
+	// MissingRecordError fails the update of a missing record.
+	MissingRecordError = "error"
+	// MissingRecordSkip skips the update of a missing record,
+	// without failing.
+	MissingRecordSkip = "skip"
+	// MissingRecordCreate creates a missing record.
+	MissingRecordCreate = "create"
+)
+
+// Fallback is a provider used to update a record
+// when its primary provider fails to update it.
+type Fallback struct {
+	Name     models.Provider
+	Provider provider.Provider
+}
+
+// New returns a new Record with provider, options and some history.
+// If the provider implements provider.KnownIPSetter, its known IP
+// address is set to the current IP address of the history.
+func New(p provider.Provider, options Options,
+	events []models.HistoryEvent) Record {
+	if setter, ok := p.(provider.KnownIPSetter); ok {
+		currentIP := models.History(events).GetCurrentIP()
+		if currentIP.IsValid() {
+			setter.SetKnownIP(currentIP)
+		}
+	}
 	return Record{
-		Provider: provider,
+		Provider: p,
+		Options:  options,
 		History:  events,
 		Status:   constants.UNSET,
 	}
@@ -0,0 +1,78 @@
+package records
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/digitalocean"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Test_New_knownIP checks the record content last written before a
+// program restart is matched again once the record is created.
+func Test_New_knownIP(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		history    models.History
+		recordPath string
+	}{
+		"no_history": {
+			recordPath: "/v2/domains/example.com/records/1",
+		},
+		"history": {
+			history: models.History{
+				{IP: netip.MustParseAddr("1.1.1.1")},
+				{IP: netip.MustParseAddr("2.2.2.2")},
+			},
+			recordPath: "/v2/domains/example.com/records/2",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := digitalocean.New(json.RawMessage(`{"token":"token","match_content":true}`),
+				"example.com", "sub", ipversion.IP4, netip.Prefix{})
+			require.NoError(t, err)
+
+			_ = New(provider, Options{}, testCase.history)
+
+			var recordPath string
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					body := `{"domain_records":[{"id":1,"type":"A","name":"sub","data":"1.1.1.1"},` +
+						`{"id":2,"type":"A","name":"sub","data":"2.2.2.2"}]}`
+					if r.Method == http.MethodPut {
+						recordPath = r.URL.Path
+						body = `{"domain_record":{"data":"3.3.3.3"}}`
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}, nil
+				}),
+			}
+
+			_, err = provider.Update(context.Background(), client, netip.MustParseAddr("3.3.3.3"))
+			require.NoError(t, err)
+			assert.Equal(t, testCase.recordPath, recordPath)
+		})
+	}
+}
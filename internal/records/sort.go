@@ -0,0 +1,19 @@
+package records
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Sort sorts the records in place by domain and then by host,
+// keeping the configuration order for records with the same
+// domain and host, for example for different IP versions.
+func Sort(records []Record) {
+	slices.SortStableFunc(records, func(a, b Record) int {
+		result := cmp.Compare(a.Provider.Domain(), b.Provider.Domain())
+		if result != 0 {
+			return result
+		}
+		return cmp.Compare(a.Provider.Host(), b.Provider.Host())
+	})
+}
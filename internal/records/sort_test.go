@@ -0,0 +1,48 @@
+package records
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+type testProvider struct {
+	provider.Provider
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+}
+
+func (p *testProvider) Domain() string                 { return p.domain }
+func (p *testProvider) Host() string                   { return p.host }
+func (p *testProvider) IPVersion() ipversion.IPVersion { return p.ipVersion }
+
+func Test_Sort(t *testing.T) {
+	t.Parallel()
+
+	makeRecord := func(domain, host string, options Options) Record {
+		return Record{
+			Provider: &testProvider{domain: domain, host: host},
+			Options:  options,
+		}
+	}
+
+	records := []Record{
+		makeRecord("b.com", "@", Options{}),
+		makeRecord("a.com", "www", Options{}),
+		makeRecord("a.com", "@", Options{}),
+		makeRecord("b.com", "@", Options{SkipVerify: true}),
+	}
+
+	Sort(records)
+
+	expected := []Record{
+		makeRecord("a.com", "@", Options{}),
+		makeRecord("a.com", "www", Options{}),
+		makeRecord("b.com", "@", Options{}),
+		makeRecord("b.com", "@", Options{SkipVerify: true}),
+	}
+	assert.Equal(t, expected, records)
+}
@@ -0,0 +1,62 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/gosettings/reader/sources/env"
+)
+
+// EnvSource is an environment variables settings source where each
+// variable KEY not set can be read from the file at the path given
+// by the variable KEY_FILE.
+type EnvSource struct {
+	env      *env.Source
+	readFile func(path string) (value string, err error)
+	errs     []error
+}
+
+// NewEnvSource creates an environment variables source
+// from the environment given, typically from os.Environ().
+func NewEnvSource(environ []string) *EnvSource {
+	return &EnvSource{
+		env:      env.New(env.Settings{Environ: environ}),
+		readFile: ReadFile,
+	}
+}
+
+func (s *EnvSource) String() string {
+	return s.env.String()
+}
+
+// Get returns the value of the environment variable for the key given,
+// or the content of the file at the path given by the key suffixed with
+// _FILE if the key is not set. Errors reading files are returned by Err.
+func (s *EnvSource) Get(key string) (value string, isSet bool) {
+	value, isSet = s.env.Get(key)
+	if isSet {
+		return value, true
+	}
+
+	fileKey := key + "_FILE"
+	path, isSet := s.env.Get(fileKey)
+	if !isSet || path == "" {
+		return "", false
+	}
+	value, err := s.readFile(path)
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("environment variable %s: %w", fileKey, err))
+		return "", false
+	}
+	return value, true
+}
+
+func (s *EnvSource) KeyTransform(key string) string {
+	return s.env.KeyTransform(key)
+}
+
+// Err returns the errors encountered reading files, joined together,
+// and should be checked once all the settings are read.
+func (s *EnvSource) Err() error {
+	return errors.Join(s.errs...)
+}
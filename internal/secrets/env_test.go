@@ -0,0 +1,42 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnvSource_Get(t *testing.T) {
+	t.Parallel()
+
+	secretPath := filepath.Join(t.TempDir(), "token")
+	const perm = 0600
+	err := os.WriteFile(secretPath, []byte("secret\n"), perm)
+	require.NoError(t, err)
+
+	source := NewEnvSource([]string{
+		"SERVER_AUTH_TOKEN_FILE=" + secretPath,
+		"SERVER_AUTH_PASSWORD=password",
+		"SERVER_AUTH_PASSWORD_FILE=" + secretPath,
+		"WEBHOOK_URLS_FILE=" + filepath.Join(t.TempDir(), "missing"),
+	})
+
+	value, isSet := source.Get("SERVER_AUTH_TOKEN")
+	assert.True(t, isSet)
+	assert.Equal(t, "secret", value)
+
+	value, isSet = source.Get("SERVER_AUTH_PASSWORD")
+	assert.True(t, isSet)
+	assert.Equal(t, "password", value)
+
+	_, isSet = source.Get("SERVER_AUTH_USERNAME")
+	assert.False(t, isSet)
+	assert.NoError(t, source.Err())
+
+	_, isSet = source.Get("WEBHOOK_URLS")
+	assert.False(t, isSet)
+	assert.ErrorIs(t, source.Err(), os.ErrNotExist)
+}
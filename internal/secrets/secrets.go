@@ -0,0 +1,19 @@
+// Package secrets reads secret values from files, such as Docker
+// and Kubernetes secrets mounted in the container, instead of
+// having them in plaintext in the configuration.
+package secrets
+
+import (
+	"os"
+	"strings"
+)
+
+// ReadFile reads the secret value from the file at the path
+// given, without its trailing new line characters.
+func ReadFile(path string) (value string, err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/internal/update"
+)
+
+// record writes the current state of the record with
+// the id given in the URL path as JSON.
+func (h *handlers) record(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseRecordID(w, r)
+	if !ok {
+		return
+	}
+	record := h.db.SelectAll()[id]
+	writeJSON(w, http.StatusOK, h.makeAPIRecord(id, record))
+}
+
+// updateRecord runs an update cycle for the record with the id given
+// in the URL path, and writes its resulting state as JSON.
+func (h *handlers) updateRecord(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseRecordID(w, r)
+	if !ok {
+		return
+	}
+	errors := h.runner.ForceUpdateRecord(h.ctx, id)
+	if len(errors) > 0 {
+		httpErrors(w, http.StatusInternalServerError, errors)
+		return
+	}
+	record := h.db.SelectAll()[id]
+	writeJSON(w, http.StatusOK, h.makeAPIRecord(id, record))
+}
+
+// updateRecords runs an update cycle for all the records,
+// and writes their resulting state as JSON.
+func (h *handlers) updateRecords(w http.ResponseWriter, r *http.Request) {
+	errors := h.runner.ForceUpdate(h.ctx)
+	if len(errors) > 0 {
+		httpErrors(w, http.StatusInternalServerError, errors)
+		return
+	}
+	h.records(w, r)
+}
+
+// parseRecordID parses the record id from the URL path and checks
+// the record exists. If it returns false, an error response was
+// written and the caller must return.
+func (h *handlers) parseRecordID(w http.ResponseWriter, r *http.Request) (id uint, ok bool) {
+	idString := chi.URLParam(r, "id")
+	parsed, err := strconv.ParseUint(idString, 10, 0)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, "record id is not valid: "+idString)
+		return 0, false
+	}
+	if parsed >= uint64(len(h.db.SelectAll())) {
+		httpError(w, http.StatusNotFound, "record not found for id "+idString)
+		return 0, false
+	}
+	return uint(parsed), true
+}
+
+type apiDetectedIP struct {
+	IP   string    `json:"ip"`
+	Time time.Time `json:"time"`
+}
+
+type apiPublicIPs struct {
+	// IP is the IP address fetched for records
+	// updated with an IPv4 or IPv6 address.
+	IP   *apiDetectedIP `json:"ip,omitempty"`
+	IPv4 *apiDetectedIP `json:"ipv4,omitempty"`
+	IPv6 *apiDetectedIP `json:"ipv6,omitempty"`
+}
+
+// publicIPs writes the public IP addresses last fetched as JSON.
+func (h *handlers) publicIPs(w http.ResponseWriter, _ *http.Request) {
+	publicIPs := h.runner.PublicIPs()
+	writeJSON(w, http.StatusOK, apiPublicIPs{
+		IP:   h.makeAPIDetectedIP(publicIPs.IP),
+		IPv4: h.makeAPIDetectedIP(publicIPs.IPv4),
+		IPv6: h.makeAPIDetectedIP(publicIPs.IPv6),
+	})
+}
+
+func (h *handlers) makeAPIDetectedIP(detected update.DetectedIP) *apiDetectedIP {
+	if !detected.IP.IsValid() {
+		return nil
+	}
+	ip := detected.IP.String()
+	if h.anonymizeIPs {
+		ip = utils.AnonymizeIP(detected.IP)
+	}
+	return &apiDetectedIP{IP: ip, Time: detected.Time}
+}
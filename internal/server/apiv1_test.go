@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	providerconstants "github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/update"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRunner struct {
+	db        *testDatabase
+	errs      []error
+	publicIPs update.PublicIPs
+}
+
+func (r *testRunner) ForceUpdate(context.Context) []error {
+	for i := range r.db.records {
+		r.db.records[i].Status = constants.SUCCESS
+	}
+	return r.errs
+}
+
+func (r *testRunner) ForceUpdateRecord(_ context.Context, id uint) []error {
+	r.db.records[id].Status = constants.SUCCESS
+	return r.errs
+}
+
+func (r *testRunner) PublicIPs() update.PublicIPs { return r.publicIPs }
+
+func Test_handlers_apiV1(t *testing.T) {
+	t.Parallel()
+
+	const recordJSONFormat = `{
+		"id": 0,
+		"domain": "example.com",
+		"host": "www",
+		"provider": "cloudflare",
+		"ip_version": "ipv4",
+		"status": "%s",
+		"time": "1970-01-01T00:00:02Z",
+		"ip": "1.2.3.4"
+	}`
+	upToDate := fmt.Sprintf(recordJSONFormat, constants.UPTODATE)
+	updated := fmt.Sprintf(recordJSONFormat, constants.SUCCESS)
+
+	testCases := map[string]struct {
+		method string
+		path   string
+		errs   []error
+		status int
+		body   string
+	}{
+		"list_records": {
+			method: http.MethodGet,
+			path:   "/api/v1/records",
+			status: http.StatusOK,
+			body:   `[` + upToDate + `]`,
+		},
+		"get_record": {
+			method: http.MethodGet,
+			path:   "/api/v1/records/0",
+			status: http.StatusOK,
+			body:   upToDate,
+		},
+		"get_record_not_found": {
+			method: http.MethodGet,
+			path:   "/api/v1/records/1",
+			status: http.StatusNotFound,
+			body:   `{"error": "record not found for id 1"}`,
+		},
+		"get_record_invalid_id": {
+			method: http.MethodGet,
+			path:   "/api/v1/records/x",
+			status: http.StatusBadRequest,
+			body:   `{"error": "record id is not valid: x"}`,
+		},
+		"update_record": {
+			method: http.MethodPost,
+			path:   "/api/v1/records/0/update",
+			status: http.StatusOK,
+			body:   updated,
+		},
+		"update_records": {
+			method: http.MethodPost,
+			path:   "/api/v1/records/update",
+			status: http.StatusOK,
+			body:   `[` + updated + `]`,
+		},
+		"update_records_error": {
+			method: http.MethodPost,
+			path:   "/api/v1/records/update",
+			errs:   []error{errors.New("test error")},
+			status: http.StatusInternalServerError,
+			body:   `{"errors": ["test error"]}`,
+		},
+		"public_ips": {
+			method: http.MethodGet,
+			path:   "/api/v1/ip",
+			status: http.StatusOK,
+			body:   `{"ipv4": {"ip": "1.2.3.4", "time": "1970-01-01T00:00:03Z"}}`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &testDatabase{records: []records.Record{{
+				Provider: &testProvider{},
+				Options:  records.Options{ProviderName: providerconstants.Cloudflare},
+				History: models.History{
+					{IP: netip.MustParseAddr("1.2.3.4"), Time: time.Unix(1, 0)},
+				},
+				Status: constants.UPTODATE,
+				Time:   time.Unix(2, 0).UTC(),
+			}}}
+			runner := &testRunner{
+				db:   db,
+				errs: testCase.errs,
+				publicIPs: update.PublicIPs{
+					IPv4: update.DetectedIP{IP: netip.MustParseAddr("1.2.3.4"), Time: time.Unix(3, 0).UTC()},
+				},
+			}
+			handler := newHandler(context.Background(), "/", db, runner, nil, nil, false, Auth{})
+
+			request := httptest.NewRequest(testCase.method, testCase.path, nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.JSONEq(t, testCase.body, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Auth contains the credentials required to access the server.
+// Authentication is disabled if all its fields are empty.
+type Auth struct {
+	// Username and Password are the HTTP basic authentication
+	// credentials, and are both empty to disable it.
+	Username string
+	Password string
+	// Token is the bearer token accepted in the Authorization
+	// header, and is empty to disable it.
+	Token string
+}
+
+func (a Auth) basicEnabled() bool { return a.Username != "" }
+func (a Auth) tokenEnabled() bool { return a.Token != "" }
+
+// makeAuthMiddleware returns a middleware responding with a 401
+// status to requests not authenticated with the basic authentication
+// credentials or the bearer token configured.
+func makeAuthMiddleware(auth Auth) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !auth.basicEnabled() && !auth.tokenEnabled() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if auth.authenticated(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if auth.basicEnabled() {
+				// Prompts browsers for the username and password.
+				w.Header().Set("WWW-Authenticate", `Basic realm="ddns-updater", charset="UTF-8"`)
+			} else {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			httpError(w, http.StatusUnauthorized, "")
+		})
+	}
+}
+
+func (a Auth) authenticated(r *http.Request) bool {
+	if a.tokenEnabled() {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if ok && secretsEqual(token, a.Token) {
+			return true
+		}
+	}
+	if a.basicEnabled() {
+		username, password, ok := r.BasicAuth()
+		// Both comparisons are done to not leak which one failed through timing.
+		usernameOK := secretsEqual(username, a.Username)
+		passwordOK := secretsEqual(password, a.Password)
+		if ok && usernameOK && passwordOK {
+			return true
+		}
+	}
+	return false
+}
+
+// secretsEqual compares two secrets in constant time. The secrets are
+// hashed first so the comparison time does not depend on their lengths.
+func secretsEqual(a, b string) bool {
+	aHash := sha256.Sum256([]byte(a))
+	bHash := sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(aHash[:], bHash[:]) == 1
+}
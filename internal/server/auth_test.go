@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_makeAuthMiddleware(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		auth            Auth
+		setCredentials  func(r *http.Request)
+		status          int
+		wwwAuthenticate string
+	}{
+		"disabled": {
+			status: http.StatusOK,
+		},
+		"basic_valid": {
+			auth:           Auth{Username: "user", Password: "pass"},
+			setCredentials: func(r *http.Request) { r.SetBasicAuth("user", "pass") },
+			status:         http.StatusOK,
+		},
+		"basic_wrong_password": {
+			auth:            Auth{Username: "user", Password: "pass"},
+			setCredentials:  func(r *http.Request) { r.SetBasicAuth("user", "wrong") },
+			status:          http.StatusUnauthorized,
+			wwwAuthenticate: `Basic realm="ddns-updater", charset="UTF-8"`,
+		},
+		"basic_missing": {
+			auth:            Auth{Username: "user", Password: "pass"},
+			status:          http.StatusUnauthorized,
+			wwwAuthenticate: `Basic realm="ddns-updater", charset="UTF-8"`,
+		},
+		"token_valid": {
+			auth:           Auth{Token: "secret"},
+			setCredentials: func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			status:         http.StatusOK,
+		},
+		"token_wrong": {
+			auth:            Auth{Token: "secret"},
+			setCredentials:  func(r *http.Request) { r.Header.Set("Authorization", "Bearer wrong") },
+			status:          http.StatusUnauthorized,
+			wwwAuthenticate: "Bearer",
+		},
+		"token_with_basic_enabled": {
+			auth:           Auth{Username: "user", Password: "pass", Token: "secret"},
+			setCredentials: func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			status:         http.StatusOK,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			handler := makeAuthMiddleware(testCase.auth)(next)
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if testCase.setCredentials != nil {
+				testCase.setCredentials(request)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.Equal(t, testCase.wwwAuthenticate, recorder.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// export writes a JSON snapshot of the current state of all records,
+// which can be imported on startup of another instance.
+// Note IP addresses are never anonymized, since the snapshot is a backup.
+func (h *handlers) export(w http.ResponseWriter, _ *http.Request) {
+	snapshot := h.db.Snapshot()
+	body, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="ddns-updater-snapshot.json"`)
+	_, _ = w.Write(body)
+}
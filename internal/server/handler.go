@@ -17,7 +17,11 @@ type handlers struct {
 	// Objects
 	db            Database
 	runner        UpdateForcer
+	events        EventSubscriber
+	metrics       MetricsWriter
 	indexTemplate *template.Template
+	// Settings
+	anonymizeIPs bool
 	// Mockable functions
 	timeNow func() time.Time
 }
@@ -26,26 +30,47 @@ type handlers struct {
 var uiFS embed.FS
 
 func newHandler(ctx context.Context, rootURL string,
-	db Database, runner UpdateForcer) http.Handler {
+	db Database, runner UpdateForcer, events EventSubscriber, metrics MetricsWriter,
+	anonymizeIPs bool, auth Auth) http.Handler {
 	indexTemplate := template.Must(template.ParseFS(uiFS, "ui/index.html"))
 
 	handlers := &handlers{
 		ctx:           ctx,
 		db:            db,
 		indexTemplate: indexTemplate,
+		anonymizeIPs:  anonymizeIPs,
 		// TODO build information
 		timeNow: time.Now,
 		runner:  runner,
+		events:  events,
+		metrics: metrics,
 	}
 
 	router := chi.NewRouter()
 
 	router.Use(middleware.Logger)
+	router.Use(makeAuthMiddleware(auth))
 	rootURL = strings.TrimSuffix(rootURL, "/")
 
 	router.Get(rootURL+"/", handlers.index)
 
 	router.Get(rootURL+"/update", handlers.update)
 
+	router.Get(rootURL+"/events", handlers.eventStream)
+
+	router.Get(rootURL+"/api/export", handlers.export)
+
+	router.Get(rootURL+"/api/records", handlers.records)
+
+	router.Route(rootURL+"/api/v1", func(r chi.Router) {
+		r.Get("/records", handlers.records)
+		r.Get("/records/{id}", handlers.record)
+		r.Post("/records/update", handlers.updateRecords)
+		r.Post("/records/{id}/update", handlers.updateRecord)
+		r.Get("/ip", handlers.publicIPs)
+	})
+
+	router.Get(rootURL+"/metrics", handlers.metricsHandler)
+
 	return router
 }
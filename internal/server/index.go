@@ -7,9 +7,11 @@ import (
 )
 
 func (h *handlers) index(w http.ResponseWriter, _ *http.Request) {
-	var htmlData models.HTMLData
+	htmlData := models.HTMLData{
+		PersistenceDegraded: h.db.PersistenceDegraded(),
+	}
 	for _, record := range h.db.SelectAll() {
-		row := record.HTML(h.timeNow())
+		row := record.HTML(h.timeNow(), h.anonymizeIPs)
 		htmlData.Rows = append(htmlData.Rows, row)
 	}
 	err := h.indexTemplate.ExecuteTemplate(w, "index.html", htmlData)
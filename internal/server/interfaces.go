@@ -2,16 +2,32 @@ package server
 
 import (
 	"context"
+	"io"
 
+	"github.com/qdm12/ddns-updater/internal/events"
+	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/update"
 )
 
 type Database interface {
 	SelectAll() (records []records.Record)
+	Snapshot() (snapshot models.Snapshot)
+	PersistenceDegraded() bool
 }
 
 type UpdateForcer interface {
 	ForceUpdate(ctx context.Context) (errors []error)
+	ForceUpdateRecord(ctx context.Context, id uint) (errors []error)
+	PublicIPs() update.PublicIPs
+}
+
+type MetricsWriter interface {
+	WriteTo(w io.Writer) (n int64, err error)
+}
+
+type EventSubscriber interface {
+	Subscribe() (events <-chan events.Event, unsubscribe func())
 }
 
 type Logger interface {
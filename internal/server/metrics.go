@@ -0,0 +1,45 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// metricsHandler writes the record counters, the current IP address
+// of each record and the persistence state in the Prometheus text
+// exposition format.
+func (h *handlers) metricsHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = h.metrics.WriteTo(w)
+
+	sb := new(strings.Builder)
+	sb.WriteString("# HELP ddns_record_ip_info Current IP address of each record.\n" +
+		"# TYPE ddns_record_ip_info gauge\n")
+	for _, record := range h.db.SelectAll() {
+		currentIP := record.History.GetCurrentIP()
+		if !currentIP.IsValid() {
+			continue
+		}
+		ip := currentIP.String()
+		if h.anonymizeIPs {
+			ip = utils.AnonymizeIP(currentIP)
+		}
+		fmt.Fprintf(sb, "ddns_record_ip_info{provider=%q,record=%q,ip_version=%q,ip=%q} 1\n",
+			string(record.Options.ProviderName), record.Provider.BuildDomainName(),
+			record.Provider.IPVersion().String(), ip)
+	}
+	_, _ = io.WriteString(w, sb.String())
+
+	degraded := "0"
+	if h.db.PersistenceDegraded() {
+		degraded = "1"
+	}
+	_, _ = io.WriteString(w, "# HELP ddns_persistence_degraded "+
+		"Whether data is only kept in memory because the persistent database cannot be written.\n"+
+		"# TYPE ddns_persistence_degraded gauge\n"+
+		"ddns_persistence_degraded "+degraded+"\n")
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+type apiRecord struct {
+	// ID is the index of the record in the configuration,
+	// used to refer to the record in the API paths.
+	ID        uint          `json:"id"`
+	Domain    string        `json:"domain"`
+	Host      string        `json:"host"`
+	Provider  string        `json:"provider"`
+	IPVersion string        `json:"ip_version"`
+	Status    models.Status `json:"status"`
+	Message   string        `json:"message,omitempty"`
+	Time      time.Time     `json:"time"`
+	// IP is the current IP address of the record,
+	// and is empty if the record was never updated.
+	IP string `json:"ip,omitempty"`
+	// LastError is the full error of the last failed update,
+	// and is empty if the last update succeeded.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// records writes the current state of all records as JSON.
+func (h *handlers) records(w http.ResponseWriter, _ *http.Request) {
+	allRecords := h.db.SelectAll()
+	apiRecords := make([]apiRecord, len(allRecords))
+	for i, record := range allRecords {
+		apiRecords[i] = h.makeAPIRecord(uint(i), record)
+	}
+	writeJSON(w, http.StatusOK, apiRecords)
+}
+
+func (h *handlers) makeAPIRecord(id uint, record records.Record) apiRecord {
+	result := apiRecord{
+		ID:        id,
+		Domain:    record.Provider.Domain(),
+		Host:      record.Provider.Host(),
+		Provider:  string(record.Options.ProviderName),
+		IPVersion: record.Provider.IPVersion().String(),
+		Status:    record.Status,
+		Message:   record.Message,
+		Time:      record.Time,
+		LastError: record.LastError,
+	}
+	currentIP := record.History.GetCurrentIP()
+	switch {
+	case !currentIP.IsValid():
+	case h.anonymizeIPs:
+		result.IP = utils.AnonymizeIP(currentIP)
+	default:
+		result.IP = currentIP.String()
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	body, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
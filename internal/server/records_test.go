@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	providerconstants "github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testProvider struct {
+	provider.Provider
+}
+
+func (p *testProvider) Domain() string                 { return "example.com" }
+func (p *testProvider) Host() string                   { return "www" }
+func (p *testProvider) IPVersion() ipversion.IPVersion { return ipversion.IP4 }
+func (p *testProvider) HTML() models.HTMLRow {
+	return models.HTMLRow{Domain: "example.com", Host: "www"}
+}
+func (p *testProvider) Capabilities() models.Capabilities { return models.Capabilities{IPv4: true} }
+
+type testDatabase struct {
+	Database
+	records []records.Record
+}
+
+func (db *testDatabase) SelectAll() []records.Record { return db.records }
+func (db *testDatabase) PersistenceDegraded() bool   { return false }
+
+func Test_handlers_lastError(t *testing.T) {
+	t.Parallel()
+
+	lastError := "bad HTTP status: 403: the API token does not have the permission " +
+		"to edit DNS records of the zone example.com, please check its scopes"
+	db := &testDatabase{records: []records.Record{{
+		Provider: &testProvider{},
+		Options:  records.Options{ProviderName: providerconstants.Cloudflare},
+		History: models.History{
+			{IP: netip.MustParseAddr("1.2.3.4"), Time: time.Unix(1, 0)},
+		},
+		Status:    constants.FAIL,
+		Message:   lastError,
+		Time:      time.Unix(2, 0).UTC(),
+		LastError: lastError,
+	}}}
+	handler := newHandler(context.Background(), "/", db, nil, nil, nil, false, Auth{})
+
+	request := httptest.NewRequest(http.MethodGet, "/api/records", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `[{
+		"id": 0,
+		"domain": "example.com",
+		"host": "www",
+		"provider": "cloudflare",
+		"ip_version": "ipv4",
+		"status": "failure",
+		"message": "`+lastError+`",
+		"time": "1970-01-01T00:00:02Z",
+		"ip": "1.2.3.4",
+		"last_error": "`+lastError+`"
+	}]`, recorder.Body.String())
+
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "<td>bad HTTP status: 403: the API token "+
+		"does not have the permission to edit DNS re…</td>")
+}
@@ -13,8 +13,9 @@ type Server struct {
 }
 
 func New(ctx context.Context, address, rootURL string, db Database,
-	logger Logger, runner UpdateForcer) *Server {
-	handler := newHandler(ctx, rootURL, db, runner)
+	logger Logger, runner UpdateForcer, events EventSubscriber, metrics MetricsWriter,
+	anonymizeIPs bool, auth Auth) *Server {
+	handler := newHandler(ctx, rootURL, db, runner, events, metrics, anonymizeIPs, auth)
 	return &Server{
 		address: address,
 		logger:  logger,
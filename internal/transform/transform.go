@@ -0,0 +1,171 @@
+// Package transform derives the IP address to publish in a record from
+// the public IP address detected, for example to publish the address of
+// a host behind a one to one NAT instead of the router address.
+package transform
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Transform is a named transformation of an IP address.
+type Transform struct {
+	// spec is the transform as configured, such as add_offset:1.
+	spec  string
+	apply func(ip netip.Addr) netip.Addr
+}
+
+func (t Transform) String() string {
+	return t.spec
+}
+
+const (
+	// StripToV4 converts an IPv4-mapped IPv6 address to its IPv4 address.
+	StripToV4 = "strip_to_v4"
+	// AddOffset adds a signed offset to the address, for example
+	// add_offset:1 or add_offset:-2.
+	AddOffset = "add_offset"
+	// NAT maps an address of a prefix to the same host address in
+	// another prefix of the same size, for example
+	// nat:203.0.113.0/24=198.51.100.0/24. Addresses outside of
+	// the first prefix are left unchanged.
+	NAT = "nat"
+)
+
+var (
+	ErrTransformUnknown  = errors.New("transform is unknown")
+	ErrArgumentMissing   = errors.New("transform argument is missing")
+	ErrArgumentNotNeeded = errors.New("transform takes no argument")
+	ErrArgumentNotValid  = errors.New("transform argument is not valid")
+)
+
+// Parse parses a transform in the form name or name:argument.
+func Parse(spec string) (transform Transform, err error) {
+	name, argument, hasArgument := strings.Cut(spec, ":")
+	transform.spec = spec
+	switch name {
+	case StripToV4:
+		if hasArgument {
+			return Transform{}, fmt.Errorf("%w: %s", ErrArgumentNotNeeded, name)
+		}
+		transform.apply = stripToV4
+	case AddOffset:
+		if !hasArgument {
+			return Transform{}, fmt.Errorf("%w: %s needs an integer offset", ErrArgumentMissing, name)
+		}
+		offset, err := strconv.ParseInt(argument, 10, 64)
+		if err != nil {
+			return Transform{}, fmt.Errorf("%w: %s: %w", ErrArgumentNotValid, name, err)
+		}
+		transform.apply = func(ip netip.Addr) netip.Addr { return addOffset(ip, offset) }
+	case NAT:
+		if !hasArgument {
+			return Transform{}, fmt.Errorf("%w: %s needs two prefixes separated by =",
+				ErrArgumentMissing, name)
+		}
+		from, to, err := parseNATArgument(argument)
+		if err != nil {
+			return Transform{}, fmt.Errorf("%w: %s: %w", ErrArgumentNotValid, name, err)
+		}
+		transform.apply = func(ip netip.Addr) netip.Addr { return nat(ip, from, to) }
+	default:
+		return Transform{}, fmt.Errorf("%w: %q must be one of %s, %s or %s",
+			ErrTransformUnknown, name, StripToV4, AddOffset, NAT)
+	}
+	return transform, nil
+}
+
+// Apply applies the transforms given in order to the IP address given.
+// It returns the zero address if a transform cannot be applied, for
+// example if adding an offset overflows, in which case the record
+// should not be updated.
+func Apply(transforms []Transform, ip netip.Addr) netip.Addr {
+	for _, transform := range transforms {
+		if !ip.IsValid() {
+			break
+		}
+		ip = transform.apply(ip)
+	}
+	return ip
+}
+
+func stripToV4(ip netip.Addr) netip.Addr {
+	ip = ip.Unmap()
+	if !ip.Is4() {
+		return netip.Addr{}
+	}
+	return ip
+}
+
+func addOffset(ip netip.Addr, offset int64) netip.Addr {
+	if ip.Is4() {
+		b := ip.As4()
+		value := int64(binary.BigEndian.Uint32(b[:])) + offset
+		if value < 0 || value > math.MaxUint32 {
+			return netip.Addr{}
+		}
+		binary.BigEndian.PutUint32(b[:], uint32(value))
+		return netip.AddrFrom4(b)
+	}
+
+	b := ip.As16()
+	high, low := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	var carry uint64
+	if offset >= 0 {
+		low, carry = bits.Add64(low, uint64(offset), 0)
+		high, carry = bits.Add64(high, 0, carry)
+	} else {
+		low, carry = bits.Sub64(low, uint64(-offset), 0)
+		high, carry = bits.Sub64(high, 0, carry)
+	}
+	if carry != 0 {
+		return netip.Addr{}
+	}
+	binary.BigEndian.PutUint64(b[:8], high)
+	binary.BigEndian.PutUint64(b[8:], low)
+	return netip.AddrFrom16(b).WithZone(ip.Zone())
+}
+
+var ErrPrefixesMismatch = errors.New("prefixes are not of the same IP version and size")
+
+func parseNATArgument(argument string) (from, to netip.Prefix, err error) {
+	fromString, toString, ok := strings.Cut(argument, "=")
+	if !ok {
+		return from, to, fmt.Errorf("%q is not in the form prefix=prefix", argument)
+	}
+	from, err = netip.ParsePrefix(fromString)
+	if err != nil {
+		return from, to, err
+	}
+	to, err = netip.ParsePrefix(toString)
+	if err != nil {
+		return from, to, err
+	}
+	if from.Addr().Is4() != to.Addr().Is4() || from.Bits() != to.Bits() {
+		return from, to, fmt.Errorf("%w: %s and %s", ErrPrefixesMismatch, from, to)
+	}
+	return from.Masked(), to.Masked(), nil
+}
+
+// nat replaces the network bits of the IP address with the bits of the
+// prefix to, if the IP address is in the prefix from.
+func nat(ip netip.Addr, from, to netip.Prefix) netip.Addr {
+	if !from.Contains(ip) {
+		return ip
+	}
+	ipBytes := ip.AsSlice()
+	toBytes := to.Addr().AsSlice()
+	for i := range ipBytes {
+		networkBits := min(max(to.Bits()-i*8, 0), 8) //nolint:gomnd
+		mask := byte(0xff << (8 - networkBits))      //nolint:gomnd
+		ipBytes[i] = ipBytes[i]&^mask | toBytes[i]&mask
+	}
+	result, _ := netip.AddrFromSlice(ipBytes)
+	return result
+}
@@ -0,0 +1,183 @@
+package transform
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		spec       string
+		errWrapped error
+		errMessage string
+	}{
+		"strip_to_v4": {
+			spec: "strip_to_v4",
+		},
+		"add_offset": {
+			spec: "add_offset:-2",
+		},
+		"nat": {
+			spec: "nat:203.0.113.0/24=198.51.100.0/24",
+		},
+		"unknown": {
+			spec:       "reverse",
+			errWrapped: ErrTransformUnknown,
+			errMessage: `transform is unknown: "reverse" must be one of strip_to_v4, add_offset or nat`,
+		},
+		"strip_to_v4_with_argument": {
+			spec:       "strip_to_v4:1",
+			errWrapped: ErrArgumentNotNeeded,
+			errMessage: "transform takes no argument: strip_to_v4",
+		},
+		"add_offset_without_argument": {
+			spec:       "add_offset",
+			errWrapped: ErrArgumentMissing,
+			errMessage: "transform argument is missing: add_offset needs an integer offset",
+		},
+		"add_offset_not_integer": {
+			spec:       "add_offset:one",
+			errWrapped: ErrArgumentNotValid,
+			errMessage: `transform argument is not valid: add_offset: strconv.ParseInt: parsing "one": invalid syntax`,
+		},
+		"nat_prefixes_mismatch": {
+			spec:       "nat:203.0.113.0/24=198.51.100.0/25",
+			errWrapped: ErrPrefixesMismatch,
+			errMessage: "transform argument is not valid: nat: prefixes are not of " +
+				"the same IP version and size: 203.0.113.0/24 and 198.51.100.0/25",
+		},
+		"nat_not_prefixes": {
+			spec:       "nat:203.0.113.0/24",
+			errWrapped: ErrArgumentNotValid,
+			errMessage: `transform argument is not valid: nat: "203.0.113.0/24" is not in the form prefix=prefix`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			transform, err := Parse(testCase.spec)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.spec, transform.String())
+		})
+	}
+}
+
+func Test_Apply(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		specs []string
+		ip    netip.Addr
+		ipOut netip.Addr
+	}{
+		"no_transform": {
+			ip:    netip.MustParseAddr("1.2.3.4"),
+			ipOut: netip.MustParseAddr("1.2.3.4"),
+		},
+		"strip_to_v4_mapped": {
+			specs: []string{"strip_to_v4"},
+			ip:    netip.MustParseAddr("::ffff:1.2.3.4"),
+			ipOut: netip.MustParseAddr("1.2.3.4"),
+		},
+		"strip_to_v4_ipv4": {
+			specs: []string{"strip_to_v4"},
+			ip:    netip.MustParseAddr("1.2.3.4"),
+			ipOut: netip.MustParseAddr("1.2.3.4"),
+		},
+		"strip_to_v4_ipv6": {
+			specs: []string{"strip_to_v4"},
+			ip:    netip.MustParseAddr("2001:db8::1"),
+		},
+		"add_offset_ipv4_carry": {
+			specs: []string{"add_offset:2"},
+			ip:    netip.MustParseAddr("1.2.3.255"),
+			ipOut: netip.MustParseAddr("1.2.4.1"),
+		},
+		"add_offset_ipv4_negative": {
+			specs: []string{"add_offset:-4"},
+			ip:    netip.MustParseAddr("1.2.3.4"),
+			ipOut: netip.MustParseAddr("1.2.3.0"),
+		},
+		"add_offset_ipv4_overflow": {
+			specs: []string{"add_offset:1"},
+			ip:    netip.MustParseAddr("255.255.255.255"),
+		},
+		"add_offset_ipv6_carry": {
+			specs: []string{"add_offset:1"},
+			ip:    netip.MustParseAddr("2001:db8::ffff:ffff:ffff:ffff"),
+			ipOut: netip.MustParseAddr("2001:db8:0:1::"),
+		},
+		"add_offset_ipv6_negative": {
+			specs: []string{"add_offset:-1"},
+			ip:    netip.MustParseAddr("2001:db8:0:1::"),
+			ipOut: netip.MustParseAddr("2001:db8::ffff:ffff:ffff:ffff"),
+		},
+		"add_offset_ipv6_underflow": {
+			specs: []string{"add_offset:-1"},
+			ip:    netip.MustParseAddr("::"),
+		},
+		"nat_ipv4": {
+			specs: []string{"nat:203.0.113.0/24=198.51.100.0/24"},
+			ip:    netip.MustParseAddr("203.0.113.7"),
+			ipOut: netip.MustParseAddr("198.51.100.7"),
+		},
+		"nat_ipv4_not_in_prefix": {
+			specs: []string{"nat:203.0.113.0/24=198.51.100.0/24"},
+			ip:    netip.MustParseAddr("1.2.3.4"),
+			ipOut: netip.MustParseAddr("1.2.3.4"),
+		},
+		"nat_ipv6_unaligned": {
+			specs: []string{"nat:2001:db8:aaaa::/44=2001:db8:bbb0::/44"},
+			ip:    netip.MustParseAddr("2001:db8:aaab::1"),
+			ipOut: netip.MustParseAddr("2001:db8:bbbb::1"),
+		},
+		"nat_table": {
+			specs: []string{
+				"nat:203.0.113.0/24=198.51.100.0/24",
+				"nat:192.0.2.0/24=198.51.100.0/24",
+			},
+			ip:    netip.MustParseAddr("192.0.2.9"),
+			ipOut: netip.MustParseAddr("198.51.100.9"),
+		},
+		"chain": {
+			specs: []string{"strip_to_v4", "add_offset:1"},
+			ip:    netip.MustParseAddr("::ffff:1.2.3.4"),
+			ipOut: netip.MustParseAddr("1.2.3.5"),
+		},
+		"chain_stops_on_failure": {
+			specs: []string{"strip_to_v4", "add_offset:1"},
+			ip:    netip.MustParseAddr("2001:db8::1"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			transforms := make([]Transform, len(testCase.specs))
+			for i, spec := range testCase.specs {
+				var err error
+				transforms[i], err = Parse(spec)
+				require.NoError(t, err)
+			}
+
+			ipOut := Apply(transforms, testCase.ip)
+
+			assert.Equal(t, testCase.ipOut, ipOut)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package update
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+)
+
+var ErrIPNotAllowed = errors.New("IP address is not in the allowed IP ranges")
+
+// checkIPAllowed returns an error if the IP address is not within any
+// of the allowed IP ranges. It returns nil if no IP range is given.
+func checkIPAllowed(ip netip.Addr, allowedRanges []netip.Prefix,
+	anonymizeIPs bool) (err error) {
+	if len(allowedRanges) == 0 {
+		return nil
+	}
+	ip = ip.Unmap()
+	for _, allowedRange := range allowedRanges {
+		if allowedRange.Contains(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrIPNotAllowed, ipToString(ip, anonymizeIPs))
+}
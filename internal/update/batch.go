@@ -0,0 +1,80 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/provider/batch"
+	settingserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+// batchKey returns the key to update the record in a batch with other
+// records having the same key, or the empty string if the record cannot
+// be updated in a batch. Records with options changing how their provider
+// is called, such as extra headers, an API URL, a fallback provider or a
+// missing record policy, or how their update is verified, such as a
+// nameserver check, are not batched.
+func batchKey(record librecords.Record) string {
+	updater, ok := record.Provider.(batch.Updater)
+	options := record.Options
+	if !ok || len(options.ExtraHeaders) > 0 || options.APIURL != nil ||
+		options.Fallback != nil || options.NameserverCheck != nil ||
+		(options.MissingRecord != "" && options.MissingRecord != librecords.MissingRecordCreate) {
+		return ""
+	}
+	return updater.BatchKey()
+}
+
+// UpdateBatch updates the records given, which all have the same batch
+// key, to the IP address given using a single batch update. Each record
+// status is set from its own result, such that a record failing to update
+// does not fail the other records of the batch. Note mismatching IP
+// addresses received are not retried, unlike with Update.
+func (u *Updater) UpdateBatch(ctx context.Context, ids []uint, ip netip.Addr) (errs []error) {
+	startedIDs := make([]uint, 0, len(ids))
+	records := make([]librecords.Record, 0, len(ids))
+	updaters := make([]batch.Updater, 0, len(ids))
+	for _, id := range ids {
+		record, err := u.startUpdate(id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		startedIDs = append(startedIDs, id)
+		records = append(records, record)
+		updaters = append(updaters, record.Provider.(batch.Updater)) //nolint:forcetypeassert
+	}
+	if len(updaters) == 0 {
+		return errs
+	}
+
+	client := makeAcceptLanguageClient(u.client, u.acceptLanguage)
+	client = makeMetricsClient(client, u.metrics, records[0].Options.ProviderName, u.timeNow)
+	results := updaters[0].BatchUpdate(ctx, client, ip, updaters)
+	for i, record := range records {
+		err := results[i].Err
+		switch {
+		case err == nil:
+		case errors.Is(err, settingserrors.ErrIPReceivedMismatch) && record.Options.SkipVerify:
+			u.logger.Debug(record.Provider.BuildDomainName() + ": ignoring " + err.Error())
+			err = nil
+		case !errors.Is(err, settingserrors.ErrIPReceivedMismatch) &&
+			isIgnoredError(err, record.Options.IgnoreErrors):
+			u.logger.Debug(record.Provider.BuildDomainName() + ": ignoring configured error " + err.Error())
+			err = nil
+		}
+
+		newIP := ip
+		if err != nil {
+			newIP = netip.Addr{}
+		}
+		err = u.finishUpdate(ctx, startedIDs[i], record, newIP,
+			record.Options.ProviderName, results[i].Created, err)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,74 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/batch"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+)
+
+type batchTestProvider struct {
+	orderTestProvider
+	key         string
+	batches     *[][]string
+	updateCalls *int
+}
+
+func (p *batchTestProvider) BatchKey() string { return p.key }
+
+func (p *batchTestProvider) BatchUpdate(_ context.Context, _ *http.Client, _ netip.Addr,
+	updaters []batch.Updater) (results []batch.Result) {
+	hostnames := make([]string, len(updaters))
+	for i, updater := range updaters {
+		hostnames[i] = updater.(*batchTestProvider).BuildDomainName() //nolint:forcetypeassert
+	}
+	*p.batches = append(*p.batches, hostnames)
+	return make([]batch.Result, len(updaters))
+}
+
+func (p *batchTestProvider) Update(_ context.Context, _ *http.Client, ip netip.Addr) (
+	newIP netip.Addr, err error) {
+	*p.updateCalls++
+	return ip, nil
+}
+
+func Test_Runner_updateNecessary_batch(t *testing.T) {
+	t.Parallel()
+
+	var batches [][]string
+	var updateCalls int
+	newRecord := func(host, key string) records.Record {
+		provider := &batchTestProvider{
+			orderTestProvider: orderTestProvider{domain: "a.com", host: host},
+			key:               key,
+			batches:           &batches,
+			updateCalls:       &updateCalls,
+		}
+		return records.New(provider, records.Options{}, nil)
+	}
+	db := &orderTestDatabase{records: []records.Record{
+		newRecord("x", "zone1"),
+		newRecord("y", "zone2"),
+		newRecord("z", "zone1"),
+	}}
+	updater := NewUpdater(testUpdaterSettings(db))
+	runner := NewRunner(testRunnerSettings(db, updater))
+
+	errs := runner.updateNecessary(context.Background())
+
+	assert.Empty(t, errs)
+	assert.Equal(t, [][]string{{"x.a.com", "z.a.com"}}, batches)
+	assert.Equal(t, 1, updateCalls) // y.a.com is alone in its batch
+	statuses := make([]models.Status, len(db.records))
+	for i, record := range db.records {
+		statuses[i] = record.Status
+	}
+	expectedStatuses := []models.Status{constants.SUCCESS, constants.SUCCESS, constants.SUCCESS}
+	assert.Equal(t, expectedStatuses, statuses)
+}
@@ -0,0 +1,52 @@
+package update
+
+import (
+	"fmt"
+	"net/netip"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+// cgnatPrefix is the carrier-grade NAT shared address space, see RFC 6598.
+var cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10") //nolint:gochecknoglobals
+
+func isCGNAT(ip netip.Addr) bool {
+	return cgnatPrefix.Contains(ip.Unmap())
+}
+
+// checkCGNAT marks the records due in the cycle whose public IP
+// address is in the carrier-grade NAT range, and logs a warning once
+// per record if the warning is enabled. This is purely advisory and
+// does not prevent records from being updated.
+func (r *Runner) checkCGNAT(records []librecords.Record, dueIDs map[uint]struct{},
+	ip, ipv4, ipv6 netip.Addr) (errs []error) {
+	for i, record := range records {
+		id := uint(i)
+		if _, due := dueIDs[id]; !due {
+			continue
+		}
+		publicIP := getIPMatchingVersion(ip, ipv4, ipv6, record.Provider.IPVersion())
+		if !publicIP.IsValid() || record.Options.Failover != nil {
+			continue
+		}
+		behindCGNAT := isCGNAT(publicIP)
+
+		if behindCGNAT != record.BehindCGNAT {
+			record.BehindCGNAT = behindCGNAT
+			err := r.db.Update(id, record)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("updating CGNAT status: %w", err))
+			}
+		}
+
+		_, warned := r.cgnatWarned[id]
+		if !behindCGNAT || !r.cgnatWarning || warned {
+			continue
+		}
+		r.cgnatWarned[id] = struct{}{}
+		r.logger.Warn(fmt.Sprintf("public IP address %s for %s is behind carrier-grade NAT (%s): "+
+			"the record will be updated but inbound connections to it are likely not to work",
+			ipToString(publicIP, r.anonymizeIPs), recordToLogString(record), cgnatPrefix))
+	}
+	return errs
+}
@@ -0,0 +1,81 @@
+package update
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_isCGNAT(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ip    netip.Addr
+		cgnat bool
+	}{
+		"invalid":      {},
+		"public_ipv4":  {ip: netip.MustParseAddr("1.2.3.4")},
+		"below_range":  {ip: netip.MustParseAddr("100.63.255.255")},
+		"range_start":  {ip: netip.MustParseAddr("100.64.0.0"), cgnat: true},
+		"range_end":    {ip: netip.MustParseAddr("100.127.255.255"), cgnat: true},
+		"above_range":  {ip: netip.MustParseAddr("100.128.0.0")},
+		"ipv4_in_ipv6": {ip: netip.MustParseAddr("::ffff:100.64.1.2"), cgnat: true},
+		"ipv6":         {ip: netip.MustParseAddr("2001:db8::1")},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cgnat := isCGNAT(testCase.ip)
+
+			assert.Equal(t, testCase.cgnat, cgnat)
+		})
+	}
+}
+
+type warnRecorder struct {
+	noopLogger
+	warnings []string
+}
+
+func (w *warnRecorder) Warn(s string) {
+	w.warnings = append(w.warnings, s)
+}
+
+func Test_Runner_checkCGNAT(t *testing.T) {
+	t.Parallel()
+
+	db := &orderTestDatabase{records: []records.Record{
+		records.New(&orderTestProvider{domain: "example.com", host: "@"}, records.Options{}, nil),
+	}}
+	logger := &warnRecorder{}
+	runner := &Runner{
+		db:           db,
+		logger:       logger,
+		cgnatWarning: true,
+		cgnatWarned:  make(map[uint]struct{}),
+	}
+
+	cgnatIP := netip.MustParseAddr("100.64.1.2")
+	const cycles = 2
+	for i := 0; i < cycles; i++ {
+		errs := runner.checkCGNAT(db.SelectAll(), map[uint]struct{}{0: {}}, cgnatIP, netip.Addr{}, netip.Addr{})
+		assert.Empty(t, errs)
+		assert.True(t, db.records[0].BehindCGNAT)
+	}
+
+	expectedWarnings := []string{
+		"public IP address 100.64.1.2 for @.example.com (ipv4 or ipv6) is behind " +
+			"carrier-grade NAT (100.64.0.0/10): the record will be updated " +
+			"but inbound connections to it are likely not to work",
+	}
+	assert.Equal(t, expectedWarnings, logger.warnings)
+
+	errs := runner.checkCGNAT(db.SelectAll(), map[uint]struct{}{0: {}}, netip.MustParseAddr("1.2.3.4"), netip.Addr{}, netip.Addr{})
+	assert.Empty(t, errs)
+	assert.False(t, db.records[0].BehindCGNAT)
+}
@@ -0,0 +1,66 @@
+package update
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/failover"
+	"github.com/qdm12/ddns-updater/internal/probe"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/transform"
+)
+
+// checkFailovers health checks the primary IP address of records due in
+// the cycle with failover settings, and returns the IP address to use for each of them,
+// keyed by record id.
+func (r *Runner) checkFailovers(ctx context.Context, records []librecords.Record,
+	dueIDs map[uint]struct{}) (failoverIPs map[uint]netip.Addr) {
+	failoverIPs = make(map[uint]netip.Addr)
+	for i, record := range records {
+		id := uint(i)
+		settings := record.Options.Failover
+		if _, due := dueIDs[id]; !due || settings == nil {
+			continue
+		}
+
+		controller, ok := r.failovers[id]
+		if !ok {
+			controller = failover.New(*settings, probe.New(settings.HealthCheck))
+			r.failovers[id] = controller
+		}
+
+		ip, changed, err := controller.Check(ctx)
+		if err != nil {
+			r.logger.Warn("health check of primary IP address " +
+				ipToString(settings.PrimaryIP, r.anonymizeIPs) + " for record " +
+				record.Provider.String() + " failed: " + err.Error())
+		}
+		switch {
+		case changed && controller.OnBackup():
+			r.logger.Warn("failing over record " + record.Provider.String() +
+				" to backup IP address " + ipToString(ip, r.anonymizeIPs))
+		case changed:
+			r.logger.Info("failing back record " + record.Provider.String() +
+				" to primary IP address " + ipToString(ip, r.anonymizeIPs))
+		}
+		failoverIPs[id] = ip
+	}
+	return failoverIPs
+}
+
+// getRecordUpdateIP returns the IP address to set for the record,
+// which is the failover IP address for records with failover settings,
+// and the public IP address matching the record IP version, with the
+// record transforms applied, otherwise. It returns the zero value if no
+// public IP address matches or if a transform cannot be applied.
+func getRecordUpdateIP(id uint, record librecords.Record,
+	ip, ipv4, ipv6 netip.Addr, failoverIPs map[uint]netip.Addr) netip.Addr {
+	if failoverIP, ok := failoverIPs[id]; ok {
+		return failoverIP
+	}
+	updateIP := getIPMatchingVersion(ip, ipv4, ipv6, record.Provider.IPVersion())
+	if updateIP.Is6() {
+		updateIP = ipv6WithSuffix(updateIP, record.Provider.IPv6Suffix())
+	}
+	return transform.Apply(record.Options.Transforms, updateIP)
+}
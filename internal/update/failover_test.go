@@ -0,0 +1,63 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/failover"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+)
+
+type switchProber struct {
+	err error
+}
+
+func (p *switchProber) Probe(context.Context, netip.Addr) error { return p.err }
+
+func Test_Runner_updateNecessary_failover(t *testing.T) {
+	t.Parallel()
+
+	primary := netip.MustParseAddr("192.0.2.1")
+	backup := netip.MustParseAddr("192.0.2.2")
+	settings := &failover.Settings{
+		PrimaryIP:         primary,
+		BackupIP:          backup,
+		FailureThreshold:  2,
+		RecoveryThreshold: 1,
+	}
+	db := &orderTestDatabase{records: []records.Record{
+		records.New(&orderTestProvider{domain: "a.com", host: "@"},
+			records.Options{Failover: settings}, nil),
+	}}
+	updater := &onceTestUpdater{db: db}
+	runner := NewRunner(testRunnerSettings(db, updater))
+	prober := &switchProber{}
+	runner.failovers[0] = failover.New(*settings, prober)
+
+	errDown := errors.New("connection refused")
+	cycles := []struct {
+		probeErr error
+		recordIP netip.Addr
+	}{
+		{probeErr: nil, recordIP: primary},     // primary used instead of public IP
+		{probeErr: errDown, recordIP: primary}, // below failure threshold
+		{probeErr: errDown, recordIP: backup},  // failover
+		{probeErr: errDown, recordIP: backup},
+		{probeErr: nil, recordIP: primary}, // recovery
+	}
+
+	for i, cycle := range cycles {
+		prober.err = cycle.probeErr
+
+		errs := runner.updateNecessary(context.Background())
+
+		assert.Empty(t, errs, "cycle %d", i)
+		recordIP := db.records[0].History.GetCurrentIP()
+		assert.Equal(t, cycle.recordIP, recordIP, "cycle %d", i)
+	}
+	const expectedUpdates = 3 // primary, backup, primary
+	assert.Len(t, db.records[0].History, expectedUpdates)
+}
@@ -0,0 +1,157 @@
+package update
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// makeExtraHeadersClient returns a client setting the extra headers given
+// on every request it sends. If the client given is a logging client, the
+// values of these extra headers are redacted in its debug logs, since they
+// are typically secrets such as authentication proxy credentials.
+func makeExtraHeadersClient(client *http.Client, extraHeaders map[string]string) (
+	newClient *http.Client) {
+	if len(extraHeaders) == 0 {
+		return client
+	}
+
+	header := make(http.Header, len(extraHeaders))
+	for key, value := range extraHeaders {
+		header.Set(key, value)
+	}
+
+	transport := client.Transport
+	if loggingTransport, ok := transport.(*loggingRoundTripper); ok {
+		redacted := make(map[string]struct{}, len(header))
+		for key := range header {
+			redacted[key] = struct{}{}
+		}
+		transport = &loggingRoundTripper{
+			proxied:         loggingTransport.proxied,
+			logger:          loggingTransport.logger,
+			redactedHeaders: redacted,
+		}
+	} else if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	newClient = &http.Client{
+		Timeout: client.Timeout,
+		Transport: &extraHeadersRoundTripper{
+			proxied: transport,
+			header:  header,
+		},
+	}
+	return newClient
+}
+
+type extraHeadersRoundTripper struct {
+	proxied http.RoundTripper
+	header  http.Header
+}
+
+func (ehrt *extraHeadersRoundTripper) RoundTrip(request *http.Request) (
+	response *http.Response, err error) {
+	// Round trippers must not modify the request given.
+	request = request.Clone(request.Context())
+	for key, values := range ehrt.header {
+		request.Header[key] = values
+	}
+	return ehrt.proxied.RoundTrip(request)
+}
+
+// makeAcceptLanguageClient returns a client setting the Accept-Language
+// header to the language given on every request it sends, unless the
+// request already has this header set. This is so providers return error
+// messages in a consistent language, which can then be matched reliably,
+// for example with ignored errors. If the language is empty, the client
+// given is returned as is.
+func makeAcceptLanguageClient(client *http.Client, language string) (
+	newClient *http.Client) {
+	if language == "" {
+		return client
+	}
+
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &http.Client{
+		Timeout: client.Timeout,
+		Transport: &acceptLanguageRoundTripper{
+			proxied:  transport,
+			language: language,
+		},
+	}
+}
+
+type acceptLanguageRoundTripper struct {
+	proxied  http.RoundTripper
+	language string
+}
+
+func (alrt *acceptLanguageRoundTripper) RoundTrip(request *http.Request) (
+	response *http.Response, err error) {
+	if request.Header.Get("Accept-Language") != "" {
+		return alrt.proxied.RoundTrip(request)
+	}
+	// Round trippers must not modify the request given.
+	request = request.Clone(request.Context())
+	request.Header.Set("Accept-Language", alrt.language)
+	return alrt.proxied.RoundTrip(request)
+}
+
+// makeAPIURLClient returns a client sending every request to the API
+// URL given instead of the provider hardcoded API endpoint, by replacing
+// the request URL scheme and host and prefixing its path with the API URL
+// path. If the API URL is nil, the client given is returned as is.
+// Since requests are modified after providers sign them, the API URL
+// must not be set for providers signing the request host or path,
+// which is enforced when parsing the settings.
+func makeAPIURLClient(client *http.Client, apiURL *url.URL) (
+	newClient *http.Client) {
+	if apiURL == nil {
+		return client
+	}
+
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &http.Client{
+		Timeout: client.Timeout,
+		Transport: &apiURLRoundTripper{
+			proxied: transport,
+			apiURL:  apiURL,
+		},
+	}
+}
+
+type apiURLRoundTripper struct {
+	proxied http.RoundTripper
+	apiURL  *url.URL
+}
+
+func (aurt *apiURLRoundTripper) RoundTrip(request *http.Request) (
+	response *http.Response, err error) {
+	// Round trippers must not modify the request given.
+	request = request.Clone(request.Context())
+	request.URL.Scheme = aurt.apiURL.Scheme
+	request.URL.Host = aurt.apiURL.Host
+	if aurt.apiURL.User != nil {
+		request.URL.User = aurt.apiURL.User
+	}
+	basePath := strings.TrimSuffix(aurt.apiURL.Path, "/")
+	if basePath != "" {
+		if request.URL.RawPath != "" {
+			request.URL.RawPath = strings.TrimSuffix(aurt.apiURL.EscapedPath(), "/") +
+				request.URL.RawPath
+		}
+		request.URL.Path = basePath + request.URL.Path
+	}
+	request.Host = ""
+	return aurt.proxied.RoundTrip(request)
+}
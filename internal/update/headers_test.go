@@ -0,0 +1,230 @@
+package update
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/providers/digitalocean"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Debug(s string) { l.lines = append(l.lines, s) }
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_makeExtraHeadersClient(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "id", request.Header.Get("CF-Access-Client-Id"))
+		assert.Equal(t, "s3cr3t", request.Header.Get("CF-Access-Client-Secret"))
+		assert.Equal(t, "provider", request.Header.Get("User-Agent"))
+		rw.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	logger := &recordingLogger{}
+	client := makeLogClient(&http.Client{}, logger)
+	extraHeaders := map[string]string{
+		"CF-Access-Client-Id":     "id",
+		"CF-Access-Client-Secret": "s3cr3t",
+	}
+	client = makeExtraHeadersClient(client, extraHeaders)
+
+	request, err := http.NewRequestWithContext(context.Background(),
+		http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	request.Header.Set("User-Agent", "provider")
+
+	response, err := client.Do(request)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Empty(t, request.Header.Get("CF-Access-Client-Id"),
+		"original request must not be modified")
+
+	require.NotEmpty(t, logger.lines)
+	requestLine := logger.lines[0]
+	assert.Contains(t, requestLine, "Cf-Access-Client-Id: [redacted]")
+	assert.Contains(t, requestLine, "Cf-Access-Client-Secret: [redacted]")
+	assert.Contains(t, requestLine, "User-Agent: provider")
+	assert.False(t, strings.Contains(requestLine, "s3cr3t"),
+		"secret header value must not be logged")
+}
+
+func Test_makeAcceptLanguageClient(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		language       string
+		requestHeader  string
+		expectedHeader string
+	}{
+		"disabled": {},
+		"language_set": {
+			language:       "en",
+			expectedHeader: "en",
+		},
+		"configured_language": {
+			language:       "fr-CH, fr;q=0.9",
+			expectedHeader: "fr-CH, fr;q=0.9",
+		},
+		"request_header_kept": {
+			language:       "en",
+			requestHeader:  "de",
+			expectedHeader: "de",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					assert.Equal(t, testCase.expectedHeader, r.Header.Get("Accept-Language"))
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       http.NoBody,
+					}, nil
+				}),
+			}
+			client = makeAcceptLanguageClient(client, testCase.language)
+
+			request, err := http.NewRequestWithContext(context.Background(),
+				http.MethodGet, "https://example.com", nil)
+			require.NoError(t, err)
+			if testCase.requestHeader != "" {
+				request.Header.Set("Accept-Language", testCase.requestHeader)
+			}
+
+			response, err := client.Do(request)
+			require.NoError(t, err)
+			_ = response.Body.Close()
+
+			assert.Equal(t, testCase.requestHeader, request.Header.Get("Accept-Language"),
+				"original request must not be modified")
+		})
+	}
+}
+
+func Test_makeAPIURLClient(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		paths = append(paths, request.Method+" "+request.URL.Path)
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		switch request.Method {
+		case http.MethodGet:
+			_, _ = rw.Write([]byte(`{"domain_records":[` +
+				`{"id":5,"type":"A","name":"www","data":"5.6.7.8"}]}`))
+		case http.MethodPut:
+			_, _ = rw.Write([]byte(`{"domain_record":{"data":"1.2.3.4"}}`))
+		}
+	})
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	apiURL, err := url.Parse(server.URL + "/proxy/")
+	require.NoError(t, err)
+	client := makeAPIURLClient(server.Client(), apiURL)
+
+	provider, err := digitalocean.New(json.RawMessage(`{"token":"token"}`),
+		"example.com", "www", ipversion.IP4, netip.Prefix{})
+	require.NoError(t, err)
+
+	newIP, err := provider.Update(context.Background(), client,
+		netip.MustParseAddr("1.2.3.4"))
+
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("1.2.3.4"), newIP)
+	expectedPaths := []string{
+		"GET /proxy/v2/domains/example.com/records",
+		"PUT /proxy/v2/domains/example.com/records/5",
+	}
+	assert.Equal(t, expectedPaths, paths)
+}
+
+func Test_makeAPIURLClient_signedRequest(t *testing.T) {
+	t.Parallel()
+
+	const secret = "secret"
+	sign := func(method, host, path string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write([]byte(method + "\n" + host + "\n" + path))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	testCases := map[string]struct {
+		signHostPath bool
+		validAtAPI   bool
+	}{
+		"signature_without_host_path": {
+			validAtAPI: true,
+		},
+		"signature_with_host_path": {
+			signHostPath: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var validAtAPI bool
+			handler := http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+				host, path := "", ""
+				if testCase.signHostPath {
+					host, path = request.Host, request.URL.Path
+				}
+				expected := sign(request.Method, host, path)
+				validAtAPI = request.Header.Get("X-Signature") == expected
+			})
+			server := httptest.NewServer(handler)
+			t.Cleanup(server.Close)
+
+			apiURL, err := url.Parse(server.URL + "/proxy")
+			require.NoError(t, err)
+			client := makeAPIURLClient(server.Client(), apiURL)
+
+			request, err := http.NewRequestWithContext(context.Background(),
+				http.MethodGet, "https://api.example.com/v1/records", nil)
+			require.NoError(t, err)
+			host, path := "", ""
+			if testCase.signHostPath {
+				host, path = request.URL.Host, request.URL.Path
+			}
+			request.Header.Set("X-Signature", sign(request.Method, host, path))
+
+			response, err := client.Do(request)
+			require.NoError(t, err)
+			_ = response.Body.Close()
+
+			assert.Equal(t, testCase.validAtAPI, validAtAPI)
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package update
 
 import (
+	"errors"
 	"fmt"
 	"net/netip"
+	"strconv"
 	"strings"
 
+	settingserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
 	"github.com/qdm12/ddns-updater/internal/records"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
@@ -16,6 +20,15 @@ func ipVersionToIPKind(version ipversion.IPVersion) (kind string) {
 	return version.String()
 }
 
+// ipToString returns the string representation of the IP address,
+// anonymized if anonymize is true.
+func ipToString(ip netip.Addr, anonymize bool) string {
+	if anonymize {
+		return utils.AnonymizeIP(ip)
+	}
+	return ip.String()
+}
+
 func recordToLogString(record records.Record) string {
 	return fmt.Sprintf("%s (%s)",
 		record.Provider.BuildDomainName(),
@@ -24,22 +37,24 @@ func recordToLogString(record records.Record) string {
 
 func (r *Runner) logDebugNoLookupSkip(hostname, ipKind string, lastIP, ip netip.Addr) {
 	r.logger.Debug(fmt.Sprintf("Last %s address stored for %s is %s and your %s address"+
-		" is %s, skipping update", ipKind, hostname, lastIP, ipKind, ip))
+		" is %s, skipping update", ipKind, hostname,
+		ipToString(lastIP, r.anonymizeIPs), ipKind, ipToString(ip, r.anonymizeIPs)))
 }
 
 func (r *Runner) logInfoNoLookupUpdate(hostname, ipKind string, lastIP, ip netip.Addr) {
 	r.logger.Info(fmt.Sprintf("Last %s address stored for %s is %s and your %s address is %s",
-		ipKind, hostname, lastIP, ipKind, ip))
+		ipKind, hostname, ipToString(lastIP, r.anonymizeIPs), ipKind, ipToString(ip, r.anonymizeIPs)))
 }
 
 func (r *Runner) logDebugLookupSkip(hostname, ipKind string, recordIP, ip netip.Addr) {
 	r.logger.Debug(fmt.Sprintf("%s address of %s is %s and your %s address"+
-		" is %s, skipping update", ipKind, hostname, recordIP, ipKind, ip))
+		" is %s, skipping update", ipKind, hostname,
+		ipToString(recordIP, r.anonymizeIPs), ipKind, ipToString(ip, r.anonymizeIPs)))
 }
 
 func (r *Runner) logInfoLookupUpdate(hostname, ipKind string, recordIP, ip netip.Addr) {
 	r.logger.Info(fmt.Sprintf("%s address of %s is %s and your %s address  is %s",
-		ipKind, hostname, recordIP, ipKind, ip))
+		ipKind, hostname, ipToString(recordIP, r.anonymizeIPs), ipKind, ipToString(ip, r.anonymizeIPs)))
 }
 
 type joinedErrors struct { //nolint:errname
@@ -57,3 +72,30 @@ func (e *joinedErrors) Error() string {
 func (e *joinedErrors) Unwrap() []error {
 	return e.errs
 }
+
+// isIgnoredError returns true if the error matches one of the
+// ignored errors given. An ignored error is either an HTTP status
+// code, matching errors for an invalid HTTP status with this code,
+// or a substring to find in the error message.
+func isIgnoredError(err error, ignoredErrors []string) bool {
+	message := err.Error()
+	for _, ignored := range ignoredErrors {
+		if isHTTPStatusCode(ignored) {
+			var statusErr *settingserrors.HTTPStatusError
+			if errors.As(err, &statusErr) && strconv.Itoa(statusErr.StatusCode) == ignored {
+				return true
+			}
+			continue
+		}
+		if strings.Contains(message, ignored) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHTTPStatusCode(s string) bool {
+	const minCode, maxCode = 100, 599
+	statusCode, err := strconv.Atoi(s)
+	return err == nil && len(s) == 3 && statusCode >= minCode && statusCode <= maxCode
+}
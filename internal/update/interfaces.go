@@ -4,9 +4,16 @@ import (
 	"context"
 	"net"
 	"net/netip"
+	"time"
 
+	"github.com/qdm12/ddns-updater/internal/events"
 	"github.com/qdm12/ddns-updater/internal/healthchecksio"
+	"github.com/qdm12/ddns-updater/internal/heartbeat"
+	"github.com/qdm12/ddns-updater/internal/hook"
+	"github.com/qdm12/ddns-updater/internal/metrics"
+	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/webhook"
 )
 
 type PublicIPFetcher interface {
@@ -19,10 +26,17 @@ type UpdaterInterface interface {
 	Update(ctx context.Context, recordID uint, ip netip.Addr) (err error)
 }
 
+// BatchUpdaterInterface is optionally implemented by updaters
+// able to update several records with a single batch update.
+type BatchUpdaterInterface interface {
+	UpdateBatch(ctx context.Context, recordIDs []uint, ip netip.Addr) (errs []error)
+}
+
 type Database interface {
 	Select(recordID uint) (record records.Record, err error)
 	SelectAll() (records []records.Record)
 	Update(recordID uint, record records.Record) (err error)
+	Replace(records []records.Record)
 }
 
 type LookupIPer interface {
@@ -33,6 +47,25 @@ type ShoutrrrClient interface {
 	Notify(message string)
 }
 
+type EventPublisher interface {
+	Publish(event events.Event)
+}
+
+type Metrics interface {
+	RecordCreated(provider models.Provider)
+	RecordUpdated(provider models.Provider)
+	UpdateFinished(record metrics.Record, success bool, at time.Time)
+	ProviderRequestDone(provider models.Provider, duration time.Duration)
+}
+
+type HookRunner interface {
+	Run(ctx context.Context, event hook.Event)
+}
+
+type WebhookSender interface {
+	Send(event webhook.Event)
+}
+
 type Logger interface {
 	DebugLogger
 	Info(s string)
@@ -40,6 +73,22 @@ type Logger interface {
 	Error(s string)
 }
 
+type Prober interface {
+	Probe(ctx context.Context, ip netip.Addr) (err error)
+}
+
+type NetworkGate interface {
+	Check() (err error)
+}
+
+type KillSwitch interface {
+	Paused(ctx context.Context) (paused bool, err error)
+}
+
 type HealthchecksIOClient interface {
 	Ping(ctx context.Context, state healthchecksio.State) (err error)
 }
+
+type HeartbeatClient interface {
+	Ping(ctx context.Context, event heartbeat.Event) (err error)
+}
@@ -0,0 +1,27 @@
+package update
+
+import "context"
+
+// updatesPaused returns true if the kill switch pauses updates, in
+// which case no record should be updated. If the kill switch cannot be
+// checked, its pause on error policy decides if updates are paused.
+func (r *Runner) updatesPaused(ctx context.Context) bool {
+	paused, err := r.killSwitch.Paused(ctx)
+	switch {
+	case err != nil && paused:
+		r.logger.Warn("checking kill switch, pausing updates: " + err.Error())
+	case err != nil:
+		r.logger.Warn("checking kill switch, updating anyway: " + err.Error())
+	}
+
+	switch {
+	case paused && !r.killSwitchPaused:
+		r.logger.Warn("updates are paused by the kill switch")
+	case paused:
+		r.logger.Info("skipping update cycle: updates are still paused by the kill switch")
+	case r.killSwitchPaused:
+		r.logger.Info("updates are resumed by the kill switch")
+	}
+	r.killSwitchPaused = paused
+	return paused
+}
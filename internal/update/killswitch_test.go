@@ -0,0 +1,49 @@
+package update
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+)
+
+type sequenceKillSwitch struct {
+	paused []bool
+	calls  int
+}
+
+func (k *sequenceKillSwitch) Paused(context.Context) (bool, error) {
+	paused := k.paused[k.calls]
+	k.calls++
+	return paused, nil
+}
+
+func Test_Runner_updateNecessary_killSwitch(t *testing.T) {
+	t.Parallel()
+
+	db := &orderTestDatabase{records: []records.Record{
+		records.New(&orderTestProvider{domain: "a.com", host: "@"}, records.Options{}, nil),
+	}}
+	updater := &orderTestUpdater{db: db}
+	killSwitch := &sequenceKillSwitch{paused: []bool{false, true, true, false}}
+	runnerSettings := testRunnerSettings(db, updater)
+	runnerSettings.KillSwitch = killSwitch
+	runner := NewRunner(runnerSettings)
+
+	expectedDomains := [][]string{
+		{"@.a.com"}, // running
+		nil,         // paused
+		nil,         // still paused
+		{"@.a.com"}, // resumed
+	}
+	for _, expected := range expectedDomains {
+		updater.domains = nil
+
+		errs := runner.updateNecessary(context.Background())
+
+		assert.Empty(t, errs)
+		assert.Equal(t, expected, updater.domains)
+	}
+	assert.False(t, runner.killSwitchPaused)
+}
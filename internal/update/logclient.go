@@ -44,11 +44,14 @@ func makeLogClient(client *http.Client, logger DebugLogger) (newClient *http.Cli
 type loggingRoundTripper struct {
 	proxied http.RoundTripper
 	logger  DebugLogger
+	// redactedHeaders contains the canonical keys of request
+	// headers which values must not be logged.
+	redactedHeaders map[string]struct{}
 }
 
 func (lrt *loggingRoundTripper) RoundTrip(request *http.Request) (
 	response *http.Response, err error) {
-	lrt.logger.Debug(requestToString(request))
+	lrt.logger.Debug(requestToString(request, lrt.redactedHeaders))
 
 	response, err = lrt.proxied.RoundTrip(request)
 	if err != nil {
@@ -60,11 +63,12 @@ func (lrt *loggingRoundTripper) RoundTrip(request *http.Request) (
 	return response, nil
 }
 
-func requestToString(request *http.Request) (s string) {
+func requestToString(request *http.Request,
+	redactedHeaders map[string]struct{}) (s string) {
 	s = request.Method + " " + request.URL.String()
 
 	if request.Header != nil {
-		s += " | headers: " + headerToString(request.Header)
+		s += " | headers: " + headerToString(request.Header, redactedHeaders)
 	}
 
 	if request.Body != nil {
@@ -80,7 +84,7 @@ func responseToString(response *http.Response) (s string) {
 	s = response.Status
 
 	if response.Header != nil {
-		s += " | headers: " + headerToString(response.Header)
+		s += " | headers: " + headerToString(response.Header, nil)
 	}
 
 	if response.Body != nil {
@@ -92,10 +96,15 @@ func responseToString(response *http.Response) (s string) {
 	return s
 }
 
-func headerToString(header http.Header) (s string) {
+func headerToString(header http.Header,
+	redactedHeaders map[string]struct{}) (s string) {
 	headers := make([]string, 0, len(header))
 	for key, values := range header {
-		headerString := key + ": " + strings.Join(values, ",")
+		value := strings.Join(values, ",")
+		if _, redacted := redactedHeaders[key]; redacted {
+			value = "[redacted]"
+		}
+		headerString := key + ": " + value
 		headers = append(headers, headerString)
 	}
 	return strings.Join(headers, "; ")
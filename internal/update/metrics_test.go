@@ -0,0 +1,118 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/events"
+	"github.com/qdm12/ddns-updater/internal/hook"
+	"github.com/qdm12/ddns-updater/internal/metrics"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type metricsTestProvider struct {
+	orderTestProvider
+	create bool
+}
+
+func (p *metricsTestProvider) Update(ctx context.Context, _ *http.Client, ip netip.Addr) (
+	newIP netip.Addr, err error) {
+	if p.create {
+		utils.SignalCreated(ctx)
+	}
+	return ip, nil
+}
+
+type noopShoutrrr struct{}
+
+func (noopShoutrrr) Notify(string) {}
+
+type noopHook struct{}
+
+func (noopHook) Run(context.Context, hook.Event) {}
+
+type noopWebhook struct{}
+
+func (noopWebhook) Send(webhook.Event) {}
+
+type noopEventPublisher struct{}
+
+func (noopEventPublisher) Publish(events.Event) {}
+
+func Test_Updater_Update_metrics(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		create          bool
+		expectedCreated string
+		expectedUpdated string
+	}{
+		"create_increments_created": {
+			create:          true,
+			expectedCreated: `ddns_records_created_total{provider="hetzner"} 1` + "\n",
+		},
+		"update_increments_updated": {
+			expectedUpdated: `ddns_records_updated_total{provider="hetzner"} 1` + "\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &metricsTestProvider{
+				orderTestProvider: orderTestProvider{domain: "example.com", host: "@"},
+				create:            testCase.create,
+			}
+			db := &orderTestDatabase{records: []records.Record{{
+				Provider: provider,
+				Options:  records.Options{ProviderName: constants.Hetzner},
+			}}}
+			metrics := metrics.New()
+			timeNow := func() time.Time { return time.Unix(1700000000, 0) }
+			updaterSettings := testUpdaterSettings(db)
+			updaterSettings.Metrics = metrics
+			updaterSettings.TimeNow = timeNow
+			updater := NewUpdater(updaterSettings)
+
+			err := updater.Update(context.Background(), 0, netip.MustParseAddr("1.2.3.4"))
+			require.NoError(t, err)
+
+			const labels = `record="@.example.com",ip_version="ipv4 or ipv6"`
+			expected := "# HELP ddns_records_created_total Total number of DNS records created because they were missing.\n" +
+				"# TYPE ddns_records_created_total counter\n" +
+				testCase.expectedCreated +
+				"# HELP ddns_records_updated_total Total number of existing DNS records updated.\n" +
+				"# TYPE ddns_records_updated_total counter\n" +
+				testCase.expectedUpdated +
+				"# HELP ddns_record_updates_total Total number of record update attempts, by result.\n" +
+				"# TYPE ddns_record_updates_total counter\n" +
+				`ddns_record_updates_total{provider="hetzner",` + labels + `,result="success"} 1` + "\n" +
+				`ddns_record_updates_total{provider="hetzner",` + labels + `,result="failure"} 0` + "\n" +
+				"# HELP ddns_record_last_update_attempt_timestamp_seconds Unix time of the last record update attempt.\n" +
+				"# TYPE ddns_record_last_update_attempt_timestamp_seconds gauge\n" +
+				`ddns_record_last_update_attempt_timestamp_seconds{provider="hetzner",` + labels + `} 1700000000` + "\n" +
+				"# HELP ddns_record_last_update_success_timestamp_seconds Unix time of the last successful record update.\n" +
+				"# TYPE ddns_record_last_update_success_timestamp_seconds gauge\n" +
+				`ddns_record_last_update_success_timestamp_seconds{provider="hetzner",` + labels + `} 1700000000` + "\n" +
+				"# HELP ddns_public_ip_fetch_failures_total Total number of failed public IP address fetches.\n" +
+				"# TYPE ddns_public_ip_fetch_failures_total counter\n" +
+				"# HELP ddns_provider_http_request_duration_seconds Duration of HTTP requests sent to provider APIs.\n" +
+				"# TYPE ddns_provider_http_request_duration_seconds histogram\n"
+			sb := new(strings.Builder)
+			_, err = metrics.WriteTo(sb)
+			require.NoError(t, err)
+			assert.Equal(t, expected, sb.String())
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package update
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+)
+
+// makeMetricsClient returns a client observing the duration of every
+// request it sends in the request duration metrics of the provider given.
+// The duration is measured until the response headers are received.
+func makeMetricsClient(client *http.Client, metrics Metrics, provider models.Provider,
+	timeNow func() time.Time) (newClient *http.Client) {
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &http.Client{
+		Timeout: client.Timeout,
+		Transport: &metricsRoundTripper{
+			proxied:  transport,
+			metrics:  metrics,
+			provider: provider,
+			timeNow:  timeNow,
+		},
+	}
+}
+
+type metricsRoundTripper struct {
+	proxied  http.RoundTripper
+	metrics  Metrics
+	provider models.Provider
+	timeNow  func() time.Time
+}
+
+func (mrt *metricsRoundTripper) RoundTrip(request *http.Request) (
+	response *http.Response, err error) {
+	start := mrt.timeNow()
+	response, err = mrt.proxied.RoundTrip(request)
+	mrt.metrics.ProviderRequestDone(mrt.provider, mrt.timeNow().Sub(start))
+	return response, err
+}
@@ -0,0 +1,87 @@
+package update
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/mock"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// emptyResolver resolves every hostname to no IP address,
+// such that every record is updated.
+type emptyResolver struct{}
+
+func (emptyResolver) LookupIP(context.Context, string, string) ([]net.IP, error) {
+	return nil, nil
+}
+
+// newMockRunner returns a runner updating the records given to
+// the public IP address 1.2.3.4, with the number of transient
+// retries given.
+func newMockRunner(db *orderTestDatabase, transientRetries uint) *Runner {
+	updater := NewUpdater(testUpdaterSettings(db))
+	runnerSettings := testRunnerSettings(db, updater)
+	runnerSettings.Resolver = emptyResolver{}
+	runnerSettings.TransientRetries = transientRetries
+	runnerSettings.TransientRetryDelay = time.Millisecond
+	return NewRunner(runnerSettings)
+}
+
+func Test_Runner_mockProvider_results(t *testing.T) {
+	t.Parallel()
+
+	errServiceUnavailable := errors.NewHTTPStatusError(http.StatusServiceUnavailable, "service unavailable")
+	provider := mock.NewWithResults("example.com", "@", ipversion.IP4or6, netip.Prefix{},
+		mock.Result{Err: errServiceUnavailable}, // retried within the cycle
+		mock.Result{})
+	db := &orderTestDatabase{records: []records.Record{
+		records.New(provider, records.Options{}, nil),
+	}}
+	runner := newMockRunner(db, 1)
+
+	errs := runner.updateNecessary(context.Background())
+
+	assert.Empty(t, errs)
+	assert.Len(t, provider.Calls(), 2)
+	assert.Equal(t, constants.SUCCESS, db.records[0].Status)
+}
+
+func Test_Runner_mockProvider_stubServer(t *testing.T) {
+	t.Parallel()
+
+	stub := mock.NewServer(
+		mock.Response{Status: http.StatusBadGateway, Body: "bad gateway"},
+		mock.Response{Status: http.StatusUnauthorized, Body: "bad token"},
+	)
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	apiURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	provider := mock.NewWithResults("example.com", "www", ipversion.IP4or6, netip.Prefix{})
+	db := &orderTestDatabase{records: []records.Record{
+		records.New(provider, records.Options{APIURL: apiURL}, nil),
+	}}
+	runner := newMockRunner(db, 2)
+
+	errs := runner.updateNecessary(context.Background())
+
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "HTTP status is not valid: 401: bad token")
+	requests := stub.Requests()
+	require.Len(t, requests, 2) // the permanent error is not retried
+	assert.Equal(t, "www.example.com", requests[0].Get("hostname"))
+	assert.Equal(t, constants.FAIL, db.records[0].Status)
+}
@@ -0,0 +1,24 @@
+package update
+
+import (
+	"errors"
+
+	"github.com/qdm12/ddns-updater/internal/netgate"
+)
+
+// networkAllowed returns false if the network gate does not allow
+// updates on the current network, in which case no record should be
+// updated. Updates are allowed if the network cannot be inspected.
+func (r *Runner) networkAllowed() bool {
+	err := r.networkGate.Check()
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, netgate.ErrNotAllowed):
+		r.logger.Info("skipping update cycle: " + err.Error())
+		return false
+	default:
+		r.logger.Warn("checking network, updating anyway: " + err.Error())
+		return true
+	}
+}
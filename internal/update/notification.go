@@ -0,0 +1,42 @@
+package update
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// makeChangeNotification renders the notification template for a record
+// which IP address changed from oldIP to newIP. If the template is not set
+// or fails to execute, the default message is returned instead, with the
+// template error appended to it.
+func (u *Updater) makeChangeNotification(record records.Record,
+	oldIP, newIP netip.Addr) (message string) {
+	defaultMessage := record.Provider.BuildDomainName() + " changed to " +
+		ipToString(newIP, u.anonymizeIPs)
+	if u.notificationTemplate == nil {
+		return defaultMessage
+	}
+
+	data := models.NotificationData{
+		Host:     record.Provider.Host(),
+		Domain:   record.Provider.Domain(),
+		FQDN:     record.Provider.BuildDomainName(),
+		Provider: string(record.Options.ProviderName),
+		NewIP:    ipToString(newIP, u.anonymizeIPs),
+		Time:     record.Time,
+		Tags:     record.Options.Tags,
+	}
+	if oldIP.IsValid() {
+		data.OldIP = ipToString(oldIP, u.anonymizeIPs)
+	}
+
+	sb := new(strings.Builder)
+	err := u.notificationTemplate.Execute(sb, data)
+	if err != nil {
+		return defaultMessage + " (notification template error: " + err.Error() + ")"
+	}
+	return sb.String()
+}
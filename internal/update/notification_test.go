@@ -0,0 +1,72 @@
+package update
+
+import (
+	"net/netip"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Updater_makeChangeNotification(t *testing.T) {
+	t.Parallel()
+
+	record := records.New(&orderTestProvider{domain: "example.com", host: "sub"},
+		records.Options{ProviderName: "cloudflare", Tags: []string{"home", "nas"}},
+		nil)
+	record.Time = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := map[string]struct {
+		template     string
+		anonymizeIPs bool
+		oldIP        netip.Addr
+		message      string
+	}{
+		"no_template": {
+			message: "sub.example.com changed to 5.6.7.8",
+		},
+		"custom_template": {
+			template: `[{{.Provider}}] {{.Host}} on {{.Domain}}: ` +
+				`{{.OldIP}} -> {{.NewIP}} at {{.Time.Format "2006-01-02"}} ` +
+				`tags={{range $i, $tag := .Tags}}{{if $i}},{{end}}{{$tag}}{{end}}`,
+			oldIP:   netip.MustParseAddr("1.2.3.4"),
+			message: "[cloudflare] sub on example.com: 1.2.3.4 -> 5.6.7.8 at 2024-01-02 tags=home,nas",
+		},
+		"no_old_ip": {
+			template: `{{.FQDN}} {{or .OldIP "none"}} -> {{.NewIP}}`,
+			message:  "sub.example.com none -> 5.6.7.8",
+		},
+		"anonymized": {
+			template:     `{{.OldIP}} -> {{.NewIP}}`,
+			anonymizeIPs: true,
+			oldIP:        netip.MustParseAddr("1.2.3.4"),
+			message:      "1.2.3.0/24 -> 5.6.7.0/24",
+		},
+		"execution_error": {
+			template: `{{index .Tags 5}}`,
+			message: "sub.example.com changed to 5.6.7.8 (notification template error: " +
+				"template: notification:1:2: executing \"notification\" at <index .Tags 5>: " +
+				"error calling index: index out of range: 5)",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			updater := &Updater{anonymizeIPs: testCase.anonymizeIPs}
+			if testCase.template != "" {
+				updater.notificationTemplate = template.Must(
+					template.New("notification").Parse(testCase.template))
+			}
+
+			message := updater.makeChangeNotification(record,
+				testCase.oldIP, netip.MustParseAddr("5.6.7.8"))
+
+			assert.Equal(t, testCase.message, message)
+		})
+	}
+}
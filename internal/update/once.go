@@ -0,0 +1,109 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+)
+
+const (
+	// OutputText is the human readable table output format.
+	OutputText = "text"
+	// OutputJSON is the JSON array output format, for scripting.
+	OutputJSON = "json"
+)
+
+// CycleResult is the result of an update cycle for a record.
+type CycleResult struct {
+	Host     string `json:"host"`
+	Provider string `json:"provider"`
+	OldIP    string `json:"old_ip"`
+	NewIP    string `json:"new_ip"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+var (
+	ErrCycleFailed          = errors.New("update cycle failed")
+	ErrOutputFormatNotValid = errors.New("output format is not valid")
+)
+
+// RunOnce runs a single update cycle and returns the result for each
+// record. In dry-run mode, the old IP address is the last value read
+// from the provider and the new IP address is the detected one. The
+// error returned wraps ErrCycleFailed if any error occurred during
+// the cycle.
+func (r *Runner) RunOnce(ctx context.Context) (results []CycleResult, err error) {
+	recordsBefore := r.db.SelectAll()
+	oldIPs := make([]string, len(recordsBefore))
+	for i, record := range recordsBefore {
+		oldIP := record.History.GetCurrentIP()
+		if oldIP.IsValid() {
+			oldIPs[i] = ipToString(oldIP, r.anonymizeIPs)
+		}
+	}
+
+	errs := r.updateNecessary(ctx)
+
+	recordsAfter := r.db.SelectAll()
+	results = make([]CycleResult, len(recordsAfter))
+	for i, record := range recordsAfter {
+		results[i] = CycleResult{
+			Host:     record.Provider.BuildDomainName(),
+			Provider: string(record.Options.ProviderName),
+			OldIP:    oldIPs[i],
+			Status:   string(record.Status),
+		}
+		newIP := record.History.GetCurrentIP()
+		if r.dryRun {
+			if record.ProviderIP.IsValid() {
+				results[i].OldIP = ipToString(record.ProviderIP, r.anonymizeIPs)
+			}
+			newIP = record.DetectedIP
+		}
+		if newIP.IsValid() {
+			results[i].NewIP = ipToString(newIP, r.anonymizeIPs)
+		}
+		switch record.Status {
+		case constants.FAIL, constants.SKIPPED:
+			results[i].Error = record.Message
+		}
+	}
+
+	if len(errs) > 0 {
+		return results, fmt.Errorf("%w: %d error(s), first error: %w",
+			ErrCycleFailed, len(errs), errs[0])
+	}
+	return results, nil
+}
+
+// WriteResults writes the results to the writer in the format given,
+// which can be OutputText or OutputJSON.
+func WriteResults(writer io.Writer, results []CycleResult, format string) (err error) {
+	switch format {
+	case OutputJSON:
+		if results == nil {
+			results = []CycleResult{} // encode as [] and not null
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	case OutputText:
+		const minWidth, tabWidth, padding = 0, 8, 2
+		tabWriter := tabwriter.NewWriter(writer, minWidth, tabWidth, padding, ' ', 0)
+		_, _ = fmt.Fprintln(tabWriter, "HOST\tPROVIDER\tOLD IP\tNEW IP\tSTATUS\tERROR")
+		for _, result := range results {
+			_, _ = fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				result.Host, result.Provider, result.OldIP, result.NewIP,
+				result.Status, result.Error)
+		}
+		return tabWriter.Flush()
+	default:
+		return fmt.Errorf("%w: %s", ErrOutputFormatNotValid, format)
+	}
+}
@@ -0,0 +1,175 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// onceTestUpdater succeeds updating records except
+// the record with the failDomain domain name.
+type onceTestUpdater struct {
+	db         *orderTestDatabase
+	failDomain string
+}
+
+var errOnceTest = errors.New("bad authentication")
+
+func (u *onceTestUpdater) Update(_ context.Context, id uint, ip netip.Addr) error {
+	record := u.db.records[id]
+	if record.Provider.BuildDomainName() == u.failDomain {
+		record.Status = constants.FAIL
+		record.Message = errOnceTest.Error()
+		u.db.records[id] = record
+		return errOnceTest
+	}
+	record.Status = constants.SUCCESS
+	record.Message = "changed to " + ip.String()
+	record.History = append(record.History, models.HistoryEvent{IP: ip})
+	u.db.records[id] = record
+	return nil
+}
+
+func Test_Runner_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	oldHistory := models.History{{IP: netip.MustParseAddr("5.6.7.8")}}
+	testCases := map[string]struct {
+		failDomain string
+		output     string
+		errWrapped error
+		errMessage string
+	}{
+		"success": {
+			output: `[
+  {
+    "host": "@.a.com",
+    "provider": "cloudflare",
+    "old_ip": "5.6.7.8",
+    "new_ip": "1.2.3.4",
+    "status": "success"
+  },
+  {
+    "host": "@.b.com",
+    "provider": "duckdns",
+    "old_ip": "",
+    "new_ip": "1.2.3.4",
+    "status": "success"
+  }
+]
+`,
+		},
+		"failure": {
+			failDomain: "@.b.com",
+			output: `[
+  {
+    "host": "@.a.com",
+    "provider": "cloudflare",
+    "old_ip": "5.6.7.8",
+    "new_ip": "1.2.3.4",
+    "status": "success"
+  },
+  {
+    "host": "@.b.com",
+    "provider": "duckdns",
+    "old_ip": "",
+    "new_ip": "",
+    "status": "failure",
+    "error": "bad authentication"
+  }
+]
+`,
+			errWrapped: ErrCycleFailed,
+			errMessage: "update cycle failed: 1 error(s), first error: bad authentication",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &orderTestDatabase{records: []records.Record{
+				records.New(&orderTestProvider{domain: "a.com", host: "@"},
+					records.Options{ProviderName: "cloudflare"}, oldHistory),
+				records.New(&orderTestProvider{domain: "b.com", host: "@"},
+					records.Options{ProviderName: "duckdns"}, nil),
+			}}
+			updater := &onceTestUpdater{db: db, failDomain: testCase.failDomain}
+			runner := NewRunner(testRunnerSettings(db, updater))
+
+			results, err := runner.RunOnce(context.Background())
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+
+			buffer := bytes.NewBuffer(nil)
+			err = WriteResults(buffer, results, OutputJSON)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.output, buffer.String())
+		})
+	}
+}
+
+func Test_WriteResults_text(t *testing.T) {
+	t.Parallel()
+
+	results := []CycleResult{
+		{Host: "@.a.com", Provider: "cloudflare", OldIP: "5.6.7.8", NewIP: "1.2.3.4", Status: "success"},
+		{Host: "www.b.com", Provider: "duckdns", Status: "failure", Error: "bad authentication"},
+	}
+	buffer := bytes.NewBuffer(nil)
+
+	err := WriteResults(buffer, results, OutputText)
+
+	require.NoError(t, err)
+	const expected = "HOST       PROVIDER    OLD IP   NEW IP   STATUS   ERROR\n" +
+		"@.a.com    cloudflare  5.6.7.8  1.2.3.4  success  \n" +
+		"www.b.com  duckdns                       failure  bad authentication\n"
+	assert.Equal(t, expected, buffer.String())
+}
+
+func Test_Runner_RunOnce_dryRun(t *testing.T) {
+	t.Parallel()
+
+	oldHistory := models.History{{IP: netip.MustParseAddr("5.6.7.8")}}
+	db := &orderTestDatabase{records: []records.Record{
+		records.New(&orderTestProvider{domain: "a.com", host: "@"},
+			records.Options{ProviderName: "cloudflare"}, oldHistory),
+	}}
+	updater := &onceTestUpdater{db: db}
+	settings := testRunnerSettings(db, updater)
+	settings.DryRun = true
+	logger := &infoRecordingLogger{}
+	settings.Logger = logger
+	runner := NewRunner(settings)
+
+	results, err := runner.RunOnce(context.Background())
+
+	require.NoError(t, err)
+	expected := []CycleResult{{
+		Host:     "@.a.com",
+		Provider: "cloudflare",
+		OldIP:    "5.6.7.8",
+		NewIP:    "1.2.3.4",
+		Status:   "skipped",
+		Error:    "dry run: would update to 1.2.3.4",
+	}}
+	assert.Equal(t, expected, results)
+	assert.Equal(t, oldHistory, db.records[0].History)
+	require.GreaterOrEqual(t, len(logger.infos), 2)
+	assert.Equal(t, []string{
+		"@.a.com (ipv4 or ipv6): dry run: would update to 1.2.3.4",
+		"dry run cycle complete: hosts=1 changed=1 failed=0 unchanged=0 duration=0s ip=1.2.3.4",
+	}, logger.infos[len(logger.infos)-2:])
+}
@@ -0,0 +1,58 @@
+package update
+
+import (
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// PublicIPs contains the public IP addresses last fetched
+// for each IP version, which are invalid if never fetched.
+type PublicIPs struct {
+	// IP is the IP address fetched for records
+	// updated with an IPv4 or IPv6 address.
+	IP   DetectedIP
+	IPv4 DetectedIP
+	IPv6 DetectedIP
+}
+
+// DetectedIP is a public IP address and the time it was fetched at.
+type DetectedIP struct {
+	IP   netip.Addr
+	Time time.Time
+}
+
+type publicIPsStore struct {
+	ips   PublicIPs
+	mutex sync.RWMutex
+}
+
+// set sets the IP addresses given which are valid,
+// keeping the previous IP addresses for the others.
+func (s *publicIPsStore) set(ip, ipv4, ipv6 netip.Addr, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, pair := range [...]struct {
+		ip     netip.Addr
+		target *DetectedIP
+	}{
+		{ip, &s.ips.IP},
+		{ipv4, &s.ips.IPv4},
+		{ipv6, &s.ips.IPv6},
+	} {
+		if pair.ip.IsValid() {
+			*pair.target = DetectedIP{IP: pair.ip, Time: now}
+		}
+	}
+}
+
+func (s *publicIPsStore) get() PublicIPs {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.ips
+}
+
+// PublicIPs returns the public IP addresses last fetched.
+func (r *Runner) PublicIPs() PublicIPs {
+	return r.publicIPs.get()
+}
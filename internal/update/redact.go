@@ -0,0 +1,25 @@
+package update
+
+import "regexp"
+
+// secretRegexes match a secret with the text before it as
+// first group and the text after it as second group.
+var secretRegexes = []*regexp.Regexp{ //nolint:gochecknoglobals
+	// Query parameter values, for example in the URL of an HTTP error
+	regexp.MustCompile(`(?i)([?&](?:[a-z_]*(?:password|passwd|pass|pwd|token|key|` +
+		`secret|auth|signature|sig)[a-z_]*)=)[^&\s"']+()`),
+	// User info password in URLs
+	regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+(@)`),
+	// Authorization header values
+	regexp.MustCompile(`(?i)\b((?:bearer|basic|sso-key|token)\s+)[^\s"',]+()`),
+}
+
+// redactSecrets replaces the credentials commonly found in
+// provider error messages, such as URL query parameters or
+// authorization header values, with [redacted].
+func redactSecrets(s string) string {
+	for _, regex := range secretRegexes {
+		s = regex.ReplaceAllString(s, "${1}[redacted]${2}")
+	}
+	return s
+}
@@ -0,0 +1,60 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/qdm12/ddns-updater/internal/failover"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+// Reload replaces the records with the records given, typically read
+// from a reloaded configuration. Records with the same provider, domain,
+// host and IP version as an existing record keep their state, such as
+// their history and status, and have their settings updated.
+// The reload happens between update cycles, and the function
+// returns once the reload is done or the context is canceled.
+func (r *Runner) Reload(ctx context.Context, records []librecords.Record) (err error) {
+	select {
+	case r.reload <- records:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-r.reloadDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Runner) reloadRecords(newRecords []librecords.Record) {
+	oldRecords := r.db.SelectAll()
+	merged, oldIDs := librecords.Merge(oldRecords, newRecords)
+
+	failovers := make(map[uint]*failover.Controller, len(r.failovers))
+	cgnatWarned := make(map[uint]struct{}, len(r.cgnatWarned))
+	kept := 0
+	for i, oldID := range oldIDs {
+		if oldID == -1 {
+			continue
+		}
+		kept++
+		id, oldUID := uint(i), uint(oldID)
+		if _, warned := r.cgnatWarned[oldUID]; warned {
+			cgnatWarned[id] = struct{}{}
+		}
+		controller, ok := r.failovers[oldUID]
+		if ok && reflect.DeepEqual(oldRecords[oldID].Options.Failover, merged[i].Options.Failover) {
+			failovers[id] = controller
+		}
+	}
+	r.failovers = failovers
+	r.cgnatWarned = cgnatWarned
+	r.db.Replace(merged)
+
+	r.logger.Info(fmt.Sprintf("configuration reloaded: %d records added, %d records removed, %d records kept",
+		len(merged)-kept, len(oldRecords)-kept, kept))
+}
@@ -0,0 +1,56 @@
+package update
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/failover"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Runner_Reload(t *testing.T) {
+	t.Parallel()
+
+	makeRecord := func(domain string, status models.Status) records.Record {
+		record := records.New(&orderTestProvider{domain: domain, host: "@"},
+			records.Options{}, nil)
+		record.Status = status
+		return record
+	}
+
+	db := &orderTestDatabase{records: []records.Record{
+		makeRecord("a.com", constants.SUCCESS),
+		makeRecord("b.com", constants.FAIL),
+	}}
+	runner := NewRunner(testRunnerSettings(db, &orderTestUpdater{db: db}))
+	runner.cgnatWarned[0] = struct{}{}
+	runner.cgnatWarned[1] = struct{}{}
+	runner.failovers[1] = &failover.Controller{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go runner.Run(ctx, done)
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	err := runner.Reload(ctx, []records.Record{
+		makeRecord("c.com", constants.UNSET),
+		makeRecord("b.com", constants.UNSET),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, db.records, 2)
+	assert.Equal(t, "c.com", db.records[0].Provider.Domain())
+	assert.Equal(t, constants.UNSET, db.records[0].Status)
+	assert.Equal(t, "b.com", db.records[1].Provider.Domain())
+	assert.Equal(t, constants.FAIL, db.records[1].Status)
+	assert.Equal(t, map[uint]struct{}{1: {}}, runner.cgnatWarned)
+	assert.Len(t, runner.failovers, 1)
+	assert.Contains(t, runner.failovers, uint(1))
+}
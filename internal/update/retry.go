@@ -0,0 +1,121 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	settingserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+// retryTransient retries the update jobs of a single record which failed
+// with a transient error, up to r.transientRetries times, waiting
+// r.transientRetryDelay before each retry, instead of waiting for the next
+// cycle. Retried jobs go through the same concurrency limits as the first
+// attempt. Batch update jobs are not retried, since the records of a batch
+// can fail for different reasons. It returns the errors of the last attempt
+// of each job, in the order of the jobs given. Jobs failing again are
+// not reported as failed by the updater until their last attempt, and
+// are marked as skipped if the context is canceled before it.
+func (r *Runner) retryTransient(ctx context.Context, records []librecords.Record,
+	jobs []updateJob, jobsErrors [][]error) (errors []error) {
+	for retry := uint(1); ; retry++ {
+		var retryJobs []updateJob
+		var retryErrors [][]error
+		for i, job := range jobs {
+			if retry <= r.transientRetries && len(job.ids) == 1 &&
+				len(jobsErrors[i]) > 0 && allTransient(jobsErrors[i]) {
+				retryJobs = append(retryJobs, job)
+				retryErrors = append(retryErrors, jobsErrors[i])
+				continue
+			}
+			errors = append(errors, jobsErrors[i]...)
+		}
+
+		if len(retryJobs) == 0 {
+			return errors
+		}
+
+		r.logger.Info(fmt.Sprintf("retrying %d record(s) failing with a transient error in %s (retry %d of %d)",
+			len(retryJobs), r.transientRetryDelay, retry, r.transientRetries))
+		timer := time.NewTimer(r.transientRetryDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			for i, job := range retryJobs {
+				errors = append(errors, retryErrors[i]...)
+				errors = append(errors, r.skipTimedOut(job.ids[0], records[job.ids[0]])...)
+			}
+			return errors
+		}
+
+		jobs = retryJobs
+		r.setRetryPending(jobs, retry+1)
+		jobsErrors = r.runUpdateJobs(ctx, records, jobs)
+	}
+}
+
+// setRetryPending sets whether each job given, run for the attempt
+// number given, is retried if it fails with a transient error.
+func (r *Runner) setRetryPending(jobs []updateJob, attempt uint) {
+	for i := range jobs {
+		jobs[i].retryPending = len(jobs[i].ids) == 1 && attempt <= r.transientRetries
+	}
+}
+
+type retryPendingKey struct{}
+
+// contextWithRetryPending returns a context signaling the updater
+// the update is retried if it fails with a transient error, so
+// such a failure must not be reported yet.
+func contextWithRetryPending(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryPendingKey{}, struct{}{})
+}
+
+// retryPending returns true if the context was created with
+// contextWithRetryPending.
+func retryPending(ctx context.Context) bool {
+	return ctx.Value(retryPendingKey{}) != nil
+}
+
+func allTransient(errs []error) bool {
+	for _, err := range errs {
+		if !isTransientError(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// isTransientError returns true if the error is likely to go away
+// on its own shortly, such as a network error, a server side DNS error,
+// or an HTTP status 429 or 5xx. Other errors, such as an authentication
+// error or an HTTP status 4xx, are permanent and are not retried.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, settingserrors.ErrAuth) ||
+		errors.Is(err, settingserrors.ErrBannedAbuse) {
+		return false
+	}
+
+	var statusErr *settingserrors.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests ||
+			statusErr.StatusCode >= http.StatusInternalServerError
+	} else if errors.Is(err, settingserrors.ErrHTTPStatusNotValid) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.Is(err, settingserrors.ErrDNSServerSide) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
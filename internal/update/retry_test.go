@@ -0,0 +1,223 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	settingserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyTestUpdater fails the successive updates
+// with the errors given, and then succeeds.
+type flakyTestUpdater struct {
+	errs     []error
+	attempts int
+}
+
+func (u *flakyTestUpdater) Update(context.Context, uint, netip.Addr) error {
+	u.attempts++
+	if u.attempts <= len(u.errs) {
+		return u.errs[u.attempts-1]
+	}
+	return nil
+}
+
+func Test_Runner_updateNecessary_transientRetry(t *testing.T) {
+	t.Parallel()
+
+	serverErr := settingserrors.NewHTTPStatusError(http.StatusServiceUnavailable, "service unavailable")
+	authErr := settingserrors.NewHTTPStatusError(http.StatusUnauthorized, "invalid token")
+
+	testCases := map[string]struct {
+		retries    uint
+		errs       []error
+		attempts   int
+		errMessage string
+	}{
+		"transient_then_success": {
+			retries:  2,
+			errs:     []error{serverErr},
+			attempts: 2,
+		},
+		"transient_retries_exhausted": {
+			retries:    2,
+			errs:       []error{serverErr, serverErr, serverErr},
+			attempts:   3,
+			errMessage: "HTTP status is not valid: 503: service unavailable",
+		},
+		"retries_disabled": {
+			errs:       []error{serverErr},
+			attempts:   1,
+			errMessage: "HTTP status is not valid: 503: service unavailable",
+		},
+		"permanent_not_retried": {
+			retries:    2,
+			errs:       []error{authErr},
+			attempts:   1,
+			errMessage: "HTTP status is not valid: 401: invalid token",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &orderTestDatabase{records: []records.Record{
+				records.New(&orderTestProvider{domain: "a.com", host: "@"}, records.Options{}, nil),
+			}}
+			updater := &flakyTestUpdater{errs: testCase.errs}
+			runnerSettings := testRunnerSettings(db, updater)
+			runnerSettings.TransientRetries = testCase.retries
+			runnerSettings.TransientRetryDelay = time.Millisecond
+			runner := NewRunner(runnerSettings)
+
+			errs := runner.updateNecessary(context.Background())
+
+			assert.Equal(t, testCase.attempts, updater.attempts)
+			if testCase.errMessage == "" {
+				assert.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1)
+			assert.EqualError(t, errs[0], testCase.errMessage)
+		})
+	}
+}
+
+// flakyTestProvider fails the successive updates
+// with the errors given, and then succeeds.
+type flakyTestProvider struct {
+	orderTestProvider
+	errs  []error
+	calls int
+}
+
+func (p *flakyTestProvider) Update(_ context.Context, _ *http.Client, ip netip.Addr) (
+	newIP netip.Addr, err error) {
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return netip.Addr{}, p.errs[p.calls-1]
+	}
+	return ip, nil
+}
+
+func Test_Runner_updateNecessary_transientRetryReport(t *testing.T) {
+	t.Parallel()
+
+	serverErr := settingserrors.NewHTTPStatusError(http.StatusServiceUnavailable, "service unavailable")
+
+	testCases := map[string]struct {
+		errs     []error
+		statuses []string
+		status   models.Status
+	}{
+		"transient_then_success": {
+			errs:     []error{serverErr},
+			statuses: []string{webhook.StatusSuccess},
+			status:   constants.SUCCESS,
+		},
+		"transient_retries_exhausted": {
+			errs:     []error{serverErr, serverErr, serverErr},
+			statuses: []string{webhook.StatusFailure},
+			status:   constants.FAIL,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &flakyTestProvider{
+				orderTestProvider: orderTestProvider{domain: "a.com", host: "@"},
+				errs:              testCase.errs,
+			}
+			db := &orderTestDatabase{records: []records.Record{
+				records.New(provider, records.Options{}, nil),
+			}}
+			webhookSender := &recordingWebhook{}
+			updaterSettings := testUpdaterSettings(db)
+			updaterSettings.Webhook = webhookSender
+			runnerSettings := testRunnerSettings(db, NewUpdater(updaterSettings))
+			runnerSettings.TransientRetries = 2
+			runnerSettings.TransientRetryDelay = time.Millisecond
+			runner := NewRunner(runnerSettings)
+
+			_ = runner.updateNecessary(context.Background())
+
+			var statuses []string
+			for _, event := range webhookSender.events {
+				statuses = append(statuses, event.Status)
+			}
+			assert.Equal(t, testCase.statuses, statuses)
+			assert.Equal(t, testCase.status, db.records[0].Status)
+		})
+	}
+}
+
+func Test_isTransientError(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err       error
+		transient bool
+	}{
+		"server_error": {
+			err: fmt.Errorf("updating record: %w",
+				settingserrors.NewHTTPStatusError(http.StatusBadGateway, "bad gateway")),
+			transient: true,
+		},
+		"too_many_requests": {
+			err:       settingserrors.NewHTTPStatusError(http.StatusTooManyRequests, ""),
+			transient: true,
+		},
+		"client_error": {
+			err: settingserrors.NewHTTPStatusError(http.StatusNotFound, "not found"),
+		},
+		"status_code_in_message_only": {
+			err: fmt.Errorf("%w: 503: service unavailable", settingserrors.ErrHTTPStatusNotValid),
+		},
+		"auth_error": {
+			err: fmt.Errorf("%w: %w", settingserrors.ErrAuth,
+				settingserrors.NewHTTPStatusError(http.StatusInternalServerError, "")),
+		},
+		"network_error": {
+			err: fmt.Errorf("doing http request: %w",
+				&net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}),
+			transient: true,
+		},
+		"server_side_dns_error": {
+			err:       fmt.Errorf("%w: 911", settingserrors.ErrDNSServerSide),
+			transient: true,
+		},
+		"canceled": {
+			err: fmt.Errorf("doing http request: %w", context.Canceled),
+		},
+		"other_error": {
+			err: settingserrors.ErrBadRequest,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			transient := isTransientError(testCase.err)
+
+			assert.Equal(t, testCase.transient, transient)
+		})
+	}
+}
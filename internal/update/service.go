@@ -4,44 +4,156 @@ import (
 	"context"
 	"fmt"
 	"net/netip"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/failover"
 	"github.com/qdm12/ddns-updater/internal/healthchecksio"
+	"github.com/qdm12/ddns-updater/internal/heartbeat"
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
 	librecords "github.com/qdm12/ddns-updater/internal/records"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
 
 type Runner struct {
-	period      time.Duration
-	db          Database
-	updater     UpdaterInterface
-	force       chan struct{}
+	period  time.Duration
+	db      Database
+	updater UpdaterInterface
+	// force receives the IDs of the records to update right away,
+	// where nil means all the records.
+	force       chan []uint
 	forceResult chan []error
-	cooldown    time.Duration
-	resolver    LookupIPer
-	ipGetter    PublicIPFetcher
-	logger      Logger
-	timeNow     func() time.Time
-	hioClient   HealthchecksIOClient
+	// reload receives the records of a reloaded configuration.
+	reload     chan []librecords.Record
+	reloadDone chan struct{}
+	cooldown   time.Duration
+	// cycleTimeout is the maximum duration of an update cycle.
+	cycleTimeout time.Duration
+	// settleDelay is the duration to wait after startup
+	// before the first update.
+	settleDelay time.Duration
+	// concurrency is the maximum number of records updated at
+	// the same time, and zoneConcurrency is the maximum number
+	// of records of the same zone updated at the same time.
+	concurrency     uint
+	zoneConcurrency uint
+	resolver        LookupIPer
+	ipGetter        PublicIPFetcher
+	// publicIPs holds the public IP addresses last fetched.
+	publicIPs *publicIPsStore
+	logger    Logger
+	timeNow   func() time.Time
+	hioClient HealthchecksIOClient
+	// heartbeat pings URLs at the start and at the end
+	// of each update cycle, for external monitoring.
+	heartbeat HeartbeatClient
+	// prober checks the public IP address is reachable
+	// before updating records with it.
+	prober Prober
+	// networkGate restricts updates to allowed networks.
+	networkGate NetworkGate
+	// killSwitch can pause all updates from a central URL, and
+	// killSwitchPaused is whether updates were paused at the
+	// previous update cycle.
+	killSwitch       KillSwitch
+	killSwitchPaused bool
+	// transientRetries is the maximum number of times a record
+	// failing with a transient error is retried within the cycle,
+	// waiting transientRetryDelay before each retry.
+	transientRetries    uint
+	transientRetryDelay time.Duration
+	// anonymizeIPs is whether to anonymize IP addresses in logs.
+	anonymizeIPs bool
+	// cgnatWarning is whether to log a warning once per record
+	// if its public IP address is in the carrier-grade NAT range.
+	cgnatWarning bool
+	// dryRun is whether to only log the record changes that
+	// would be made, without updating any record.
+	dryRun bool
+	// cgnatWarned contains the IDs of records for which
+	// the carrier-grade NAT warning was already logged.
+	cgnatWarned map[uint]struct{}
+	// failovers contains the failover controllers of records
+	// with failover settings, keyed by record id.
+	failovers map[uint]*failover.Controller
 }
 
-func NewRunner(db Database, updater UpdaterInterface, ipGetter PublicIPFetcher,
-	period time.Duration, cooldown time.Duration, logger Logger, resolver LookupIPer,
-	timeNow func() time.Time, hioClient HealthchecksIOClient) *Runner {
+// RunnerSettings contains the dependencies and settings of a Runner.
+type RunnerSettings struct {
+	DB       Database
+	Updater  UpdaterInterface
+	IPGetter PublicIPFetcher
+	// Period is the global update period.
+	Period   time.Duration
+	Cooldown time.Duration
+	// CycleTimeout is the maximum duration of an update cycle.
+	CycleTimeout time.Duration
+	// SettleDelay is the duration to wait after startup
+	// before the first update.
+	SettleDelay time.Duration
+	// Concurrency is the maximum number of records updated at
+	// the same time, and ZoneConcurrency is the maximum number
+	// of records of the same zone updated at the same time.
+	Concurrency     uint
+	ZoneConcurrency uint
+	Logger          Logger
+	Resolver        LookupIPer
+	TimeNow         func() time.Time
+	HIOClient       HealthchecksIOClient
+	Heartbeat       HeartbeatClient
+	Prober          Prober
+	NetworkGate     NetworkGate
+	KillSwitch      KillSwitch
+	// TransientRetries is the maximum number of times a record
+	// failing with a transient error is retried within the cycle,
+	// waiting TransientRetryDelay before each retry.
+	TransientRetries    uint
+	TransientRetryDelay time.Duration
+	// AnonymizeIPs is whether to anonymize IP addresses in logs.
+	AnonymizeIPs bool
+	// CGNATWarning is whether to log a warning once per record
+	// if its public IP address is in the carrier-grade NAT range.
+	CGNATWarning bool
+	// DryRun is whether to only log the record changes that
+	// would be made, without updating any record.
+	DryRun bool
+}
+
+func NewRunner(settings RunnerSettings) *Runner {
 	return &Runner{
-		period:      period,
-		db:          db,
-		updater:     updater,
-		force:       make(chan struct{}),
-		forceResult: make(chan []error),
-		cooldown:    cooldown,
-		resolver:    resolver,
-		ipGetter:    ipGetter,
-		logger:      logger,
-		timeNow:     timeNow,
-		hioClient:   hioClient,
+		period:              settings.Period,
+		db:                  settings.DB,
+		updater:             settings.Updater,
+		force:               make(chan []uint),
+		forceResult:         make(chan []error),
+		reload:              make(chan []librecords.Record),
+		reloadDone:          make(chan struct{}),
+		cooldown:            settings.Cooldown,
+		cycleTimeout:        settings.CycleTimeout,
+		settleDelay:         settings.SettleDelay,
+		concurrency:         settings.Concurrency,
+		zoneConcurrency:     settings.ZoneConcurrency,
+		resolver:            settings.Resolver,
+		ipGetter:            settings.IPGetter,
+		publicIPs:           &publicIPsStore{},
+		logger:              settings.Logger,
+		timeNow:             settings.TimeNow,
+		hioClient:           settings.HIOClient,
+		heartbeat:           settings.Heartbeat,
+		prober:              settings.Prober,
+		networkGate:         settings.NetworkGate,
+		killSwitch:          settings.KillSwitch,
+		transientRetries:    settings.TransientRetries,
+		transientRetryDelay: settings.TransientRetryDelay,
+		anonymizeIPs:        settings.AnonymizeIPs,
+		cgnatWarning:        settings.CGNATWarning,
+		dryRun:              settings.DryRun,
+		cgnatWarned:         make(map[uint]struct{}),
+		failovers:           make(map[uint]*failover.Controller),
 	}
 }
 
@@ -121,24 +233,40 @@ func (r *Runner) getNewIPs(ctx context.Context, doIP, doIPv4, doIPv6 bool) (
 			errors = append(errors, err)
 		}
 	}
+	r.publicIPs.set(ip, ipv4, ipv6, r.timeNow())
 	return ip, ipv4, ipv6, errors
 }
 
 func (r *Runner) getRecordIDsToUpdate(ctx context.Context, records []librecords.Record,
-	ip, ipv4, ipv6 netip.Addr) (recordIDs map[uint]struct{}) {
+	dueIDs map[uint]struct{}, ip, ipv4, ipv6 netip.Addr, failoverIPs map[uint]netip.Addr) (
+	recordIDs map[uint]struct{}, errs []error) {
 	recordIDs = make(map[uint]struct{})
 	for i, record := range records {
-		shouldUpdate := r.shouldUpdateRecord(ctx, record, ip, ipv4, ipv6)
+		id := uint(i)
+		if _, due := dueIDs[id]; !due {
+			continue
+		}
+		updateIP := getRecordUpdateIP(id, record, ip, ipv4, ipv6, failoverIPs)
+		shouldUpdate, providerIP, publicIP := r.shouldUpdateRecord(ctx, record, updateIP)
 		if shouldUpdate {
-			id := uint(i)
 			recordIDs[id] = struct{}{}
 		}
+		if !publicIP.IsValid() { // record was not checked
+			continue
+		}
+		err := r.setObservedIPs(id, providerIP, publicIP)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("setting observed IP addresses: %w", err))
+		}
 	}
-	return recordIDs
+	return recordIDs, errs
 }
 
+// shouldUpdateRecord returns whether the record should be updated, as well as
+// the IP address observed at the provider and the public IP address to use.
+// The public IP address returned is the zero value if the record was not checked.
 func (r *Runner) shouldUpdateRecord(ctx context.Context, record librecords.Record,
-	ip, ipv4, ipv6 netip.Addr) (update bool) {
+	updateIP netip.Addr) (update bool, providerIP, publicIP netip.Addr) {
 	now := r.timeNow()
 
 	isWithinCooldown := now.Sub(record.History.GetSuccessTime()) < r.cooldown
@@ -146,7 +274,7 @@ func (r *Runner) shouldUpdateRecord(ctx context.Context, record librecords.Recor
 		r.logger.Debug(fmt.Sprintf(
 			"record %s is within cooldown period of %s, skipping update",
 			recordToLogString(record), r.cooldown))
-		return false
+		return false, netip.Addr{}, netip.Addr{}
 	}
 
 	const banPeriod = time.Hour
@@ -155,26 +283,26 @@ func (r *Runner) shouldUpdateRecord(ctx context.Context, record librecords.Recor
 		r.logger.Info(fmt.Sprintf(
 			"record %s is within ban period of %s started at %s, skipping update",
 			recordToLogString(record), banPeriod, *record.LastBan))
-		return false
+		return false, netip.Addr{}, netip.Addr{}
 	}
 
 	hostname := record.Provider.BuildDomainName()
 	ipVersion := record.Provider.IPVersion()
-	publicIP := getIPMatchingVersion(ip, ipv4, ipv6, ipVersion)
+	publicIP = updateIP
 
 	if !publicIP.IsValid() {
 		r.logger.Warn(fmt.Sprintf("Skipping update for %s because %s address was not found",
 			hostname, ipVersionToIPKind(ipVersion)))
-		return false
-	} else if publicIP.Is6() {
-		publicIP = ipv6WithSuffix(publicIP, record.Provider.IPv6Suffix())
+		return false, netip.Addr{}, netip.Addr{}
 	}
 
-	if record.Provider.Proxied() {
+	if provider.SkipLookup(record.Provider) {
 		lastIP := record.History.GetCurrentIP() // can be nil
-		return r.shouldUpdateRecordNoLookup(hostname, ipVersion, lastIP, publicIP)
+		update = r.shouldUpdateRecordNoLookup(hostname, ipVersion, lastIP, publicIP)
+		return update, lastIP, publicIP
 	}
-	return r.shouldUpdateRecordWithLookup(ctx, hostname, ipVersion, publicIP)
+	update, recordIP := r.shouldUpdateRecordWithLookup(ctx, hostname, ipVersion, publicIP)
+	return update, recordIP, publicIP
 }
 
 func (r *Runner) shouldUpdateRecordNoLookup(hostname string, ipVersion ipversion.IPVersion,
@@ -188,22 +316,24 @@ func (r *Runner) shouldUpdateRecordNoLookup(hostname string, ipVersion ipversion
 	return false
 }
 
+// shouldUpdateRecordWithLookup returns whether the record should be updated
+// and the IP address of the record found with a DNS lookup.
 func (r *Runner) shouldUpdateRecordWithLookup(ctx context.Context, hostname string,
-	ipVersion ipversion.IPVersion, publicIP netip.Addr) (update bool) {
+	ipVersion ipversion.IPVersion, publicIP netip.Addr) (update bool, recordIP netip.Addr) {
 	const tries = 5
 	recordIPv4, recordIPv6, err := r.lookupIPsResilient(ctx, hostname, tries)
 	if err != nil {
 		ctxErr := ctx.Err()
 		if ctxErr != nil {
 			r.logger.Warn("DNS resolution of " + hostname + ": " + ctxErr.Error())
-			return false
+			return false, netip.Addr{}
 		}
 		r.logger.Warn("cannot DNS resolve " + hostname + " after " +
 			fmt.Sprint(tries) + " tries: " + err.Error()) // update anyway
 	}
 
 	ipKind := ipVersionToIPKind(ipVersion)
-	recordIP := recordIPv4
+	recordIP = recordIPv4
 	if publicIP.Is6() {
 		recordIP = recordIPv6
 	}
@@ -212,10 +342,25 @@ func (r *Runner) shouldUpdateRecordWithLookup(ctx context.Context, hostname stri
 	if publicIP.IsValid() && publicIP.Compare(recordIP) != 0 {
 		// Note if the recordIP is not valid (not found), we want to update.
 		r.logInfoLookupUpdate(hostname, ipKind, recordIP, publicIP)
-		return true
+		return true, recordIP
 	}
 	r.logDebugLookupSkip(hostname, ipKind, recordIP, publicIP)
-	return false
+	return false, recordIP
+}
+
+// setObservedIPs stores the IP address observed at the provider and the
+// public IP address detected for the record, to preview changes in the UI.
+func (r *Runner) setObservedIPs(id uint, providerIP, detectedIP netip.Addr) (err error) {
+	record, err := r.db.Select(id)
+	if err != nil {
+		return err
+	}
+	if record.ProviderIP == providerIP && record.DetectedIP == detectedIP {
+		return nil
+	}
+	record.ProviderIP = providerIP
+	record.DetectedIP = detectedIP
+	return r.db.Update(id, record)
 }
 
 func getIPMatchingVersion(ip, ipv4, ipv6 netip.Addr, ipVersion ipversion.IPVersion) netip.Addr {
@@ -257,17 +402,108 @@ func setInitialPublicIPFailStatus(db Database, id uint, now time.Time) error {
 	return db.Update(id, record)
 }
 
+// updateNecessary runs an update cycle for all the records,
+// whatever their update period.
 func (r *Runner) updateNecessary(ctx context.Context) (errors []error) {
+	return r.updatePeriod(ctx, 0)
+}
+
+// updatePeriod runs an update cycle for the records with the update
+// period given, or for all the records if the period is zero.
+func (r *Runner) updatePeriod(ctx context.Context, period time.Duration) (errors []error) {
+	return r.runCycle(ctx, func(records []librecords.Record) map[uint]struct{} {
+		return r.dueRecordIDs(records, period)
+	})
+}
+
+// updateRecordIDs runs an update cycle for the records with the IDs given.
+func (r *Runner) updateRecordIDs(ctx context.Context, ids []uint) (errors []error) {
+	return r.runCycle(ctx, func(records []librecords.Record) map[uint]struct{} {
+		dueIDs := make(map[uint]struct{}, len(ids))
+		for _, id := range ids {
+			if id < uint(len(records)) {
+				dueIDs[id] = struct{}{}
+			}
+		}
+		return dueIDs
+	})
+}
+
+// runCycle runs an update cycle for the records selected by selectDue,
+// which returns the IDs of the records due for an update.
+func (r *Runner) runCycle(ctx context.Context,
+	selectDue func(records []librecords.Record) (dueIDs map[uint]struct{})) (errors []error) {
+	// The cycle context bounds the whole update cycle so a hanging
+	// provider cannot block the next cycles. The parent context is
+	// still used to ping healthchecks.io and the heartbeat URLs at
+	// the end of the cycle.
+	err := r.heartbeat.Ping(ctx, heartbeat.Start)
+	if err != nil {
+		r.logger.Error("pinging heartbeat start URL failed: " + err.Error())
+	}
+
+	start := r.timeNow()
+	cycleCtx, cancel := context.WithTimeout(ctx, r.cycleTimeout)
+	defer cancel()
+	summary, errors := r.updateCycle(cycleCtx, selectDue)
+	summary.duration = r.timeNow().Sub(start)
+	r.logger.Info(summary.message(r.anonymizeIPs))
+
+	healthchecksIOState := healthchecksio.Ok
+	heartbeatEvent := heartbeat.Success
+	if len(errors) > 0 {
+		healthchecksIOState = healthchecksio.Fail
+		heartbeatEvent = heartbeat.Failure
+	}
+
+	err = r.hioClient.Ping(ctx, healthchecksIOState)
+	if err != nil {
+		r.logger.Error("pinging healthchecks.io failed: " + err.Error())
+	}
+
+	err = r.heartbeat.Ping(ctx, heartbeatEvent)
+	if err != nil {
+		r.logger.Error("pinging heartbeat " + string(heartbeatEvent) + " URL failed: " + err.Error())
+	}
+
+	return errors
+}
+
+func (r *Runner) updateCycle(ctx context.Context,
+	selectDue func(records []librecords.Record) (dueIDs map[uint]struct{})) (
+	summary cycleSummary, errors []error) {
 	records := r.db.SelectAll()
-	doIP, doIPv4, doIPv6 := doIPVersion(records)
+	dueIDs := selectDue(records)
+	if !r.networkAllowed() || r.updatesPaused(ctx) {
+		return cycleSummary{hosts: len(dueIDs), unchanged: len(dueIDs)}, nil
+	}
+	dueRecords := make([]librecords.Record, 0, len(dueIDs))
+	for i, record := range records {
+		if _, due := dueIDs[uint(i)]; due {
+			dueRecords = append(dueRecords, record)
+		}
+	}
+	doIP, doIPv4, doIPv6 := doIPVersion(dueRecords)
 	r.logger.Debug(fmt.Sprintf("configured to fetch IP: v4 or v6: %t, v4: %t, v6: %t", doIP, doIPv4, doIPv6))
 	ip, ipv4, ipv6, errors := r.getNewIPs(ctx, doIP, doIPv4, doIPv6)
-	r.logger.Debug(fmt.Sprintf("your public IP address are: v4 or v6: %s, v4: %s, v6: %s", ip, ipv4, ipv6))
+	r.logger.Debug(fmt.Sprintf("your public IP address are: v4 or v6: %s, v4: %s, v6: %s",
+		ipToString(ip, r.anonymizeIPs), ipToString(ipv4, r.anonymizeIPs), ipToString(ipv6, r.anonymizeIPs)))
 	for _, err := range errors {
 		r.logger.Error(err.Error())
 	}
 
-	recordIDs := r.getRecordIDsToUpdate(ctx, records, ip, ipv4, ipv6)
+	for _, err := range r.checkCGNAT(records, dueIDs, ip, ipv4, ipv6) {
+		errors = append(errors, err)
+		r.logger.Error(err.Error())
+	}
+
+	failoverIPs := r.checkFailovers(ctx, records, dueIDs)
+
+	recordIDs, errs := r.getRecordIDsToUpdate(ctx, records, dueIDs, ip, ipv4, ipv6, failoverIPs)
+	for _, err := range errs {
+		errors = append(errors, err)
+		r.logger.Error(err.Error())
+	}
 
 	// Current time is used to set initial states for records already
 	// up to date or in the fail state due to the public IP not found.
@@ -277,13 +513,13 @@ func (r *Runner) updateNecessary(ctx context.Context) (errors []error) {
 
 	for i, record := range records {
 		id := uint(i)
+		_, due := dueIDs[id]
 		_, requireUpdate := recordIDs[id]
-		if requireUpdate || record.Status != constants.UNSET {
+		if !due || requireUpdate || record.Status != constants.UNSET {
 			continue
 		}
 
-		ipVersion := record.Provider.IPVersion()
-		updateIP := getIPMatchingVersion(ip, ipv4, ipv6, ipVersion)
+		updateIP := getRecordUpdateIP(id, record, ip, ipv4, ipv6, failoverIPs)
 		if !updateIP.IsValid() {
 			// warning was already logged in getRecordIDsToUpdate
 			err := setInitialPublicIPFailStatus(r.db, id, now)
@@ -293,8 +529,6 @@ func (r *Runner) updateNecessary(ctx context.Context) (errors []error) {
 				r.logger.Error(err.Error())
 			}
 			continue
-		} else if updateIP.Is6() {
-			updateIP = ipv6WithSuffix(updateIP, record.Provider.IPv6Suffix())
 		}
 
 		err := setInitialUpToDateStatus(r.db, id, updateIP, now)
@@ -304,52 +538,420 @@ func (r *Runner) updateNecessary(ctx context.Context) (errors []error) {
 			r.logger.Error(err.Error())
 		}
 	}
-	for id := range recordIDs {
-		record := records[id]
-		updateIP := getIPMatchingVersion(ip, ipv4, ipv6, record.Provider.IPVersion())
-		// Note: each record id has a matching valid public IP address.
-		if updateIP.Is6() {
-			updateIP = ipv6WithSuffix(updateIP, record.Provider.IPv6Suffix())
+	// Records are updated in the database order to have
+	// a deterministic update order and logs.
+	probeErrs := make(map[netip.Addr]error)
+	readyIDs := make([]uint, 0, len(recordIDs))
+	updateIPs := make(map[uint]netip.Addr, len(recordIDs))
+	for i, record := range records {
+		id := uint(i)
+		_, requireUpdate := recordIDs[id]
+		if !requireUpdate {
+			continue
 		}
-		r.logger.Info("Updating record " + record.Provider.String() + " to use " + updateIP.String())
-		err := r.updater.Update(ctx, id, updateIP)
-		if err != nil {
+		if ctx.Err() != nil {
+			errors = append(errors, r.skipTimedOut(id, record)...)
+			continue
+		}
+		// Note: each record id has a matching valid IP address.
+		updateIP := getRecordUpdateIP(id, record, ip, ipv4, ipv6, failoverIPs)
+		_, isFailover := failoverIPs[id]
+		if !isFailover { // failover IP addresses are set explicitly
+			err := checkIPAllowed(updateIP, record.Options.AllowedIPRanges, r.anonymizeIPs)
+			if err != nil {
+				err = fmt.Errorf("skipping update of record %s: %w", record.Provider, err)
+				errors = append(errors, err)
+				r.logger.Error(err.Error())
+				err = r.setSkippedStatus(id, "public IP address not allowed")
+				if err != nil {
+					err = fmt.Errorf("setting skipped status: %w", err)
+					errors = append(errors, err)
+					r.logger.Error(err.Error())
+				}
+				continue
+			}
+		}
+		var probeErr error
+		if !isFailover { // failover records have their own health check
+			var probed bool
+			probeErr, probed = probeErrs[updateIP]
+			if !probed {
+				probeErr = r.prober.Probe(ctx, updateIP)
+				probeErrs[updateIP] = probeErr
+			}
+		}
+		if probeErr != nil {
+			err := fmt.Errorf("skipping update of record %s: %w",
+				record.Provider, probeErr)
 			errors = append(errors, err)
 			r.logger.Error(err.Error())
+			err = r.setSkippedStatus(id, "public IP address not reachable")
+			if err != nil {
+				err = fmt.Errorf("setting skipped status: %w", err)
+				errors = append(errors, err)
+				r.logger.Error(err.Error())
+			}
+			continue
 		}
+		readyIDs = append(readyIDs, id)
+		updateIPs[id] = updateIP
 	}
+	errors = append(errors, r.updateRecords(ctx, records, readyIDs, updateIPs)...)
+	summary = r.summarizeCycle(records, dueIDs, recordIDs, ip, ipv4, ipv6, failoverIPs)
+	return summary, errors
+}
 
-	healthchecksIOState := healthchecksio.Ok
-	if len(errors) > 0 {
-		healthchecksIOState = healthchecksio.Fail
+// updateJob is an update of a single record, or a batch
+// update of several records, to the same IP address.
+type updateJob struct {
+	ids []uint
+	ip  netip.Addr
+	// retryPending is whether the job is retried if it fails
+	// with a transient error, see retryTransient.
+	retryPending bool
+}
+
+// updateRecords updates the records of the IDs given to their IP address.
+// Records which can be updated in a batch with following records, with the
+// same batch key and IP address, are all updated with a single batch update.
+// Records are updated in order, one after the other, unless the concurrency
+// is set above 1. Records failing with a transient error are retried within
+// the cycle, see retryTransient.
+func (r *Runner) updateRecords(ctx context.Context, records []librecords.Record,
+	ids []uint, updateIPs map[uint]netip.Addr) (errors []error) {
+	if r.dryRun {
+		r.previewRecords(records, ids, updateIPs)
+		return nil
 	}
+	jobs := makeUpdateJobs(r.updater, records, ids, updateIPs)
+	r.setRetryPending(jobs, 1)
+	jobsErrors := r.runUpdateJobs(ctx, records, jobs)
+	return r.retryTransient(ctx, records, jobs, jobsErrors)
+}
 
-	err := r.hioClient.Ping(ctx, healthchecksIOState)
+// runUpdateJobs runs the update jobs given and returns the
+// errors of each job, in the order of the jobs given.
+func (r *Runner) runUpdateJobs(ctx context.Context, records []librecords.Record,
+	jobs []updateJob) (jobsErrors [][]error) {
+	if r.concurrency <= 1 {
+		jobsErrors = make([][]error, len(jobs))
+		for i, job := range jobs {
+			jobsErrors[i] = r.runUpdateJob(ctx, records, job)
+		}
+		return jobsErrors
+	}
+	return r.runUpdateJobsConcurrently(ctx, records, jobs)
+}
+
+func makeUpdateJobs(updater UpdaterInterface, records []librecords.Record,
+	ids []uint, updateIPs map[uint]netip.Addr) (jobs []updateJob) {
+	_, canBatch := updater.(BatchUpdaterInterface)
+	jobs = make([]updateJob, 0, len(ids))
+	done := make(map[uint]struct{}, len(ids))
+	for i, id := range ids {
+		if _, ok := done[id]; ok {
+			continue
+		}
+		job := updateJob{ids: []uint{id}, ip: updateIPs[id]}
+		if key := batchKey(records[id]); canBatch && key != "" {
+			for _, otherID := range ids[i+1:] {
+				if updateIPs[otherID] == job.ip && batchKey(records[otherID]) == key {
+					job.ids = append(job.ids, otherID)
+					done[otherID] = struct{}{}
+				}
+			}
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// runUpdateJobsConcurrently runs the update jobs given with at most
+// r.concurrency jobs running at the same time, and at most
+// r.zoneConcurrency jobs running at the same time for the same zone.
+// The errors of each job are returned in the order of the jobs given.
+func (r *Runner) runUpdateJobsConcurrently(ctx context.Context,
+	records []librecords.Record, jobs []updateJob) (jobsErrors [][]error) {
+	pool := make(chan struct{}, r.concurrency)
+	zones := make(map[string]chan struct{})
+	jobsErrors = make([][]error, len(jobs))
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		key := zoneKey(records[job.ids[0]])
+		zone, ok := zones[key]
+		if !ok {
+			zone = make(chan struct{}, r.zoneConcurrency)
+			zones[key] = zone
+		}
+
+		wg.Add(1)
+		go func(i int, job updateJob) {
+			defer wg.Done()
+			// The zone slot is acquired before the pool slot, such that
+			// jobs waiting for their zone do not hold a pool slot.
+			// If the context is canceled, the job is marked as
+			// skipped by runUpdateJob.
+			select {
+			case zone <- struct{}{}:
+				defer func() { <-zone }()
+			case <-ctx.Done():
+			}
+			select {
+			case pool <- struct{}{}:
+				defer func() { <-pool }()
+			case <-ctx.Done():
+			}
+			jobsErrors[i] = r.runUpdateJob(ctx, records, job)
+		}(i, job)
+	}
+	wg.Wait()
+	return jobsErrors
+}
+
+// zoneKey returns a key identifying the zone of the record, as its
+// provider name and domain, to limit concurrent updates of a zone.
+func zoneKey(record librecords.Record) string {
+	return string(record.Options.ProviderName) + " " +
+		utils.NormalizeRecordName(record.Provider.Domain(), false)
+}
+
+// runUpdateJob runs the update job given and returns the errors
+// encountered, or marks its records as skipped if the context is canceled.
+func (r *Runner) runUpdateJob(ctx context.Context, records []librecords.Record,
+	job updateJob) (errors []error) {
+	if ctx.Err() != nil {
+		for _, id := range job.ids {
+			errors = append(errors, r.skipTimedOut(id, records[id])...)
+		}
+		return errors
+	}
+
+	if len(job.ids) > 1 {
+		r.logger.Info(fmt.Sprintf("Updating %d records in a single batch to use %s",
+			len(job.ids), ipToString(job.ip, r.anonymizeIPs)))
+		batchUpdater := r.updater.(BatchUpdaterInterface) //nolint:forcetypeassert
+		for _, err := range batchUpdater.UpdateBatch(ctx, job.ids, job.ip) {
+			errors = append(errors, err)
+			r.logger.Error(err.Error())
+		}
+		return errors
+	}
+
+	id := job.ids[0]
+	record := records[id]
+	r.logger.Info("Updating record " + record.Provider.String() + " to use " + ipToString(job.ip, r.anonymizeIPs))
+	if job.retryPending {
+		ctx = contextWithRetryPending(ctx)
+	}
+	err := r.updater.Update(ctx, id, job.ip)
 	if err != nil {
-		r.logger.Error("pinging healthchecks.io failed: " + err.Error())
+		errors = append(errors, err)
+		r.logger.Error(err.Error())
 	}
+	return errors
+}
 
+// skipTimedOut marks the record as skipped because the update
+// cycle timed out, and returns the errors encountered.
+func (r *Runner) skipTimedOut(id uint, record librecords.Record) (errors []error) {
+	err := fmt.Errorf("skipping update of record %s: cycle timeout of %s exceeded",
+		record.Provider, r.cycleTimeout)
+	errors = append(errors, err)
+	r.logger.Error(err.Error())
+	err = r.setSkippedStatus(id, "cycle timed out")
+	if err != nil {
+		err = fmt.Errorf("setting skipped status: %w", err)
+		errors = append(errors, err)
+		r.logger.Error(err.Error())
+	}
 	return errors
 }
 
+// setSkippedStatus marks the record as skipped for this cycle, for
+// example because the cycle timed out before the record could be updated.
+// previewRecords logs the record changes that would be made in
+// dry-run mode, and marks the records as skipped with the change
+// as message, without calling their provider.
+func (r *Runner) previewRecords(records []librecords.Record,
+	ids []uint, updateIPs map[uint]netip.Addr) {
+	for _, id := range ids {
+		message := "dry run: would update to " + ipToString(updateIPs[id], r.anonymizeIPs)
+		r.logger.Info(recordToLogString(records[id]) + ": " + message)
+		err := r.setSkippedStatus(id, message)
+		if err != nil {
+			r.logger.Error(err.Error())
+		}
+	}
+}
+
+func (r *Runner) setSkippedStatus(id uint, message string) error {
+	record, err := r.db.Select(id)
+	if err != nil {
+		return err
+	}
+	record.Status = constants.SKIPPED
+	record.Message = message
+	record.Time = r.timeNow()
+	return r.db.Update(id, record)
+}
+
+// Run updates records periodically until the context is canceled.
+// Each record is checked at its own update period if it has one, or
+// at the global update period otherwise, and records sharing the same
+// period are checked in the same update cycle.
 func (r *Runner) Run(ctx context.Context, done chan<- struct{}) {
 	defer close(done)
-	ticker := time.NewTicker(r.period)
+	if r.settleDelay > 0 && !r.settle(ctx) {
+		return
+	}
+
+	var wg sync.WaitGroup
+	periodTicks := make(chan time.Duration)
+	stopTicks := r.startTicks(ctx, &wg, periodTicks)
+	defer func() {
+		stopTicks()
+		wg.Wait()
+	}()
+
+	for {
+		select {
+		case period := <-periodTicks:
+			r.updatePeriod(ctx, period)
+		case ids := <-r.force:
+			if ids == nil {
+				r.forceResult <- r.updateNecessary(ctx)
+			} else {
+				r.forceResult <- r.updateRecordIDs(ctx, ids)
+			}
+		case records := <-r.reload:
+			// Restart the tickers since the update periods may have changed.
+			stopTicks()
+			wg.Wait()
+			r.reloadRecords(records)
+			r.reloadDone <- struct{}{}
+			stopTicks = r.startTicks(ctx, &wg, periodTicks)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// startTicks starts a ticker goroutine for each distinct update period
+// of the records, sending to the ticks channel. The goroutines are stopped
+// when calling the stop function returned or when the context is canceled.
+func (r *Runner) startTicks(ctx context.Context, wg *sync.WaitGroup,
+	ticks chan<- time.Duration) (stop context.CancelFunc) {
+	ctx, stop = context.WithCancel(ctx)
+	for _, period := range r.periods() {
+		wg.Add(1)
+		go func(period time.Duration) {
+			defer wg.Done()
+			tick(ctx, period, ticks)
+		}(period)
+	}
+	return stop
+}
+
+// tick sends the period given to the ticks channel at every period,
+// until the context is canceled.
+func tick(ctx context.Context, period time.Duration, ticks chan<- time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
-			r.updateNecessary(ctx)
-		case <-r.force:
-			r.forceResult <- r.updateNecessary(ctx)
 		case <-ctx.Done():
-			ticker.Stop()
 			return
 		}
+		select {
+		case ticks <- period:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// recordPeriod returns the update period of the record, which is the
+// global update period unless the record has its own period set.
+func (r *Runner) recordPeriod(record librecords.Record) time.Duration {
+	if record.Options.Period > 0 {
+		return record.Options.Period
+	}
+	return r.period
+}
+
+// periods returns the distinct update periods of all the records,
+// in ascending order.
+func (r *Runner) periods() (periods []time.Duration) {
+	records := r.db.SelectAll()
+	periods = make([]time.Duration, 0, 1)
+	for _, record := range records {
+		period := r.recordPeriod(record)
+		if !slices.Contains(periods, period) {
+			periods = append(periods, period)
+		}
+	}
+	if len(periods) == 0 { // no record, keep the previous behavior
+		periods = append(periods, r.period)
+	}
+	slices.Sort(periods)
+	return periods
+}
+
+// dueRecordIDs returns the IDs of the records with the update period
+// given, or of all the records if the period is zero.
+func (r *Runner) dueRecordIDs(records []librecords.Record,
+	period time.Duration) (ids map[uint]struct{}) {
+	ids = make(map[uint]struct{}, len(records))
+	for i, record := range records {
+		if period == 0 || r.recordPeriod(record) == period {
+			ids[uint(i)] = struct{}{}
+		}
+	}
+	return ids
+}
+
+// settle waits for the settle delay, since the public IP address
+// can be transient or stale right after a reboot or a network restart.
+// The public IP addresses are fetched and logged, but no record is updated,
+// and forced updates wait for the delay to end. It returns false if the
+// context is canceled during the delay.
+func (r *Runner) settle(ctx context.Context) (ok bool) {
+	timer := time.NewTimer(r.settleDelay)
+	r.logger.Info("waiting " + r.settleDelay.String() +
+		" for the network to settle before the first update")
+
+	doIP, doIPv4, doIPv6 := doIPVersion(r.db.SelectAll())
+	ip, ipv4, ipv6, errors := r.getNewIPs(ctx, doIP, doIPv4, doIPv6)
+	for _, err := range errors {
+		r.logger.Warn(err.Error())
+	}
+	r.logger.Info(fmt.Sprintf("public IP addresses before settling are: v4 or v6: %s, v4: %s, v6: %s"+
+		", not updating records until the settle delay ends",
+		ipToString(ip, r.anonymizeIPs), ipToString(ipv4, r.anonymizeIPs), ipToString(ipv6, r.anonymizeIPs)))
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		timer.Stop()
+		return false
 	}
 }
 
 func (r *Runner) ForceUpdate(ctx context.Context) (errs []error) {
-	r.force <- struct{}{}
+	return r.forceUpdate(ctx, nil)
+}
+
+// ForceUpdateRecord runs an update cycle right away for the record with
+// the id given only, and returns the errors encountered. Like for other
+// cycles, the record is only updated if its IP address needs to change.
+func (r *Runner) ForceUpdateRecord(ctx context.Context, id uint) (errs []error) {
+	return r.forceUpdate(ctx, []uint{id})
+}
+
+func (r *Runner) forceUpdate(ctx context.Context, ids []uint) (errs []error) {
+	r.force <- ids
 
 	select {
 	case errs = <-r.forceResult:
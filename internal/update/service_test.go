@@ -0,0 +1,597 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/healthchecksio"
+	"github.com/qdm12/ddns-updater/internal/heartbeat"
+	"github.com/qdm12/ddns-updater/internal/metrics"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/netgate"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderTestProvider struct {
+	provider.Provider
+	domain string
+	host   string
+}
+
+func (p *orderTestProvider) Domain() string                 { return p.domain }
+func (p *orderTestProvider) Host() string                   { return p.host }
+func (p *orderTestProvider) String() string                 { return p.host + "." + p.domain }
+func (p *orderTestProvider) BuildDomainName() string        { return p.host + "." + p.domain }
+func (p *orderTestProvider) Proxied() bool                  { return true }
+func (p *orderTestProvider) IPVersion() ipversion.IPVersion { return ipversion.IP4or6 }
+
+type orderTestDatabase struct {
+	records []records.Record
+}
+
+func (db *orderTestDatabase) Select(id uint) (records.Record, error) { return db.records[id], nil }
+func (db *orderTestDatabase) SelectAll() []records.Record            { return db.records }
+func (db *orderTestDatabase) Update(id uint, record records.Record) error {
+	db.records[id] = record
+	return nil
+}
+func (db *orderTestDatabase) Replace(records []records.Record) { db.records = records }
+
+type orderTestUpdater struct {
+	db      *orderTestDatabase
+	domains []string
+}
+
+func (u *orderTestUpdater) Update(_ context.Context, id uint, _ netip.Addr) error {
+	record := u.db.records[id]
+	u.domains = append(u.domains, record.Provider.BuildDomainName())
+	return nil
+}
+
+type orderTestIPGetter struct{}
+
+func (orderTestIPGetter) IP(context.Context) (netip.Addr, error) {
+	return netip.MustParseAddr("1.2.3.4"), nil
+}
+func (orderTestIPGetter) IP4(context.Context) (netip.Addr, error) { return netip.Addr{}, nil }
+func (orderTestIPGetter) IP6(context.Context) (netip.Addr, error) { return netip.Addr{}, nil }
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string) {}
+func (noopLogger) Info(string)  {}
+func (noopLogger) Warn(string)  {}
+func (noopLogger) Error(string) {}
+
+type noopHealthchecksIO struct{}
+
+func (noopHealthchecksIO) Ping(context.Context, healthchecksio.State) error { return nil }
+
+type noopHeartbeat struct{}
+
+func (noopHeartbeat) Ping(context.Context, heartbeat.Event) error { return nil }
+
+type noopProber struct{}
+
+func (noopProber) Probe(context.Context, netip.Addr) error { return nil }
+
+type noopNetworkGate struct{}
+
+func (noopNetworkGate) Check() error { return nil }
+
+type noopKillSwitch struct{}
+
+func (noopKillSwitch) Paused(context.Context) (bool, error) { return false, nil }
+
+// testRunnerSettings returns runner settings for the database and
+// updater given, with no-op dependencies and defaults for tests.
+func testRunnerSettings(db Database, updater UpdaterInterface) RunnerSettings {
+	return RunnerSettings{
+		DB:              db,
+		Updater:         updater,
+		IPGetter:        orderTestIPGetter{},
+		Period:          time.Hour,
+		CycleTimeout:    time.Hour,
+		Concurrency:     1,
+		ZoneConcurrency: 1,
+		Logger:          noopLogger{},
+		TimeNow:         func() time.Time { return time.Unix(0, 0) },
+		HIOClient:       noopHealthchecksIO{},
+		Heartbeat:       noopHeartbeat{},
+		Prober:          noopProber{},
+		NetworkGate:     noopNetworkGate{},
+		KillSwitch:      noopKillSwitch{},
+	}
+}
+
+// testUpdaterSettings returns updater settings for the database
+// given, with no-op dependencies and defaults for tests.
+func testUpdaterSettings(db Database) UpdaterSettings {
+	return UpdaterSettings{
+		DB:             db,
+		Client:         http.DefaultClient,
+		ShoutrrrClient: noopShoutrrr{},
+		Events:         noopEventPublisher{},
+		Metrics:        metrics.New(),
+		Hook:           noopHook{},
+		Webhook:        noopWebhook{},
+		Logger:         noopLogger{},
+		TimeNow:        func() time.Time { return time.Unix(0, 0) },
+	}
+}
+
+func Test_Runner_updateNecessary_order(t *testing.T) {
+	t.Parallel()
+
+	makeRecord := func(domain, host string) records.Record {
+		return records.New(&orderTestProvider{domain: domain, host: host},
+			records.Options{}, nil)
+	}
+
+	recordsSlice := []records.Record{
+		makeRecord("b.com", "@"),
+		makeRecord("a.com", "www"),
+		makeRecord("c.com", "@"),
+		makeRecord("a.com", "@"),
+	}
+	records.Sort(recordsSlice)
+
+	db := &orderTestDatabase{records: recordsSlice}
+	updater := &orderTestUpdater{db: db}
+	runner := NewRunner(testRunnerSettings(db, updater))
+
+	const cycles = 3
+	for i := 0; i < cycles; i++ {
+		updater.domains = nil
+
+		errs := runner.updateNecessary(context.Background())
+
+		assert.Empty(t, errs)
+		expectedDomains := []string{"@.a.com", "www.a.com", "@.b.com", "@.c.com"}
+		assert.Equal(t, expectedDomains, updater.domains)
+	}
+}
+
+type testNetworkGate struct {
+	err error
+}
+
+func (g testNetworkGate) Check() error { return g.err }
+
+func Test_Runner_updateNecessary_networkGate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		gateErr error
+		domains []string
+	}{
+		"allowed": {
+			domains: []string{"@.a.com"},
+		},
+		"not_allowed": {
+			gateErr: fmt.Errorf("%w: default route through interface wwan0", netgate.ErrNotAllowed),
+		},
+		"check_failed": {
+			gateErr: os.ErrNotExist,
+			domains: []string{"@.a.com"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &orderTestDatabase{records: []records.Record{
+				records.New(&orderTestProvider{domain: "a.com", host: "@"}, records.Options{}, nil),
+			}}
+			updater := &orderTestUpdater{db: db}
+			runnerSettings := testRunnerSettings(db, updater)
+			runnerSettings.NetworkGate = testNetworkGate{err: testCase.gateErr}
+			runner := NewRunner(runnerSettings)
+
+			errs := runner.updateNecessary(context.Background())
+
+			assert.Empty(t, errs)
+			assert.Equal(t, testCase.domains, updater.domains)
+		})
+	}
+}
+
+type hangingTestUpdater struct {
+	db         *orderTestDatabase
+	hangDomain string
+	domains    []string
+}
+
+func (u *hangingTestUpdater) Update(ctx context.Context, id uint, _ netip.Addr) error {
+	domain := u.db.records[id].Provider.BuildDomainName()
+	if domain == u.hangDomain {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	u.domains = append(u.domains, domain)
+	return nil
+}
+
+func Test_Runner_updateNecessary_cycleTimeout(t *testing.T) {
+	t.Parallel()
+
+	db := &orderTestDatabase{records: []records.Record{
+		records.New(&orderTestProvider{domain: "a.com", host: "@"}, records.Options{}, nil),
+		records.New(&orderTestProvider{domain: "b.com", host: "@"}, records.Options{}, nil),
+	}}
+	updater := &hangingTestUpdater{db: db, hangDomain: "@.a.com"}
+	const cycleTimeout = 50 * time.Millisecond
+	runnerSettings := testRunnerSettings(db, updater)
+	runnerSettings.CycleTimeout = cycleTimeout
+	runner := NewRunner(runnerSettings)
+
+	errsCh := make(chan []error)
+	go func() {
+		errsCh <- runner.updateNecessary(context.Background())
+	}()
+
+	var errs []error
+	select {
+	case errs = <-errsCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("update cycle did not complete")
+	}
+
+	require.Len(t, errs, 2)
+	assert.ErrorIs(t, errs[0], context.DeadlineExceeded)
+	assert.EqualError(t, errs[1], "skipping update of record @.b.com: cycle timeout of 50ms exceeded")
+	assert.Empty(t, updater.domains)
+	assert.Equal(t, constants.SKIPPED, db.records[1].Status)
+	assert.Equal(t, "cycle timed out", db.records[1].Message)
+}
+
+type settleTestIPGetter struct {
+	orderTestIPGetter
+	fetched chan struct{}
+}
+
+func (g settleTestIPGetter) IP(ctx context.Context) (netip.Addr, error) {
+	select {
+	case g.fetched <- struct{}{}:
+	default:
+	}
+	return g.orderTestIPGetter.IP(ctx)
+}
+
+type settleTestUpdater struct {
+	updated chan time.Time
+}
+
+func (u *settleTestUpdater) Update(context.Context, uint, netip.Addr) error {
+	u.updated <- time.Now()
+	return nil
+}
+
+func Test_Runner_Run_settleDelay(t *testing.T) {
+	t.Parallel()
+
+	db := &orderTestDatabase{records: []records.Record{
+		records.New(&orderTestProvider{domain: "a.com", host: "@"}, records.Options{}, nil),
+	}}
+	ipGetter := settleTestIPGetter{fetched: make(chan struct{}, 1)}
+	updater := &settleTestUpdater{updated: make(chan time.Time, 1)}
+	const settleDelay = 100 * time.Millisecond
+	runnerSettings := testRunnerSettings(db, updater)
+	runnerSettings.IPGetter = ipGetter
+	runnerSettings.SettleDelay = settleDelay
+	runner := NewRunner(runnerSettings)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	start := time.Now()
+	go runner.Run(ctx, done)
+
+	// The public IP address is fetched during the settle delay,
+	// and a forced update only occurs once the delay is over.
+	<-ipGetter.fetched
+	errsCh := make(chan []error)
+	go func() {
+		errsCh <- runner.ForceUpdate(ctx)
+	}()
+	updatedAt := <-updater.updated
+
+	assert.GreaterOrEqual(t, updatedAt.Sub(start), settleDelay)
+	assert.Empty(t, <-errsCh)
+
+	cancel()
+	<-done
+}
+
+type stubProber struct {
+	err error
+}
+
+func (p stubProber) Probe(context.Context, netip.Addr) error { return p.err }
+
+func Test_Runner_updateNecessary_probe(t *testing.T) {
+	t.Parallel()
+
+	errUnreachable := errors.New("IP address is not reachable")
+
+	testCases := map[string]struct {
+		prober  Prober
+		domains []string
+		status  models.Status
+		message string
+		errs    []string
+	}{
+		"reachable": {
+			prober:  stubProber{},
+			domains: []string{"@.a.com"},
+			status:  constants.UNSET,
+		},
+		"unreachable": {
+			prober:  stubProber{err: errUnreachable},
+			status:  constants.SKIPPED,
+			message: "public IP address not reachable",
+			errs:    []string{"skipping update of record @.a.com: IP address is not reachable"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &orderTestDatabase{records: []records.Record{
+				records.New(&orderTestProvider{domain: "a.com", host: "@"}, records.Options{}, nil),
+			}}
+			updater := &orderTestUpdater{db: db}
+			runnerSettings := testRunnerSettings(db, updater)
+			runnerSettings.Prober = testCase.prober
+			runner := NewRunner(runnerSettings)
+
+			errs := runner.updateNecessary(context.Background())
+
+			var errMessages []string
+			for _, err := range errs {
+				errMessages = append(errMessages, err.Error())
+			}
+			assert.Equal(t, testCase.errs, errMessages)
+			assert.Equal(t, testCase.domains, updater.domains)
+			assert.Equal(t, testCase.status, db.records[0].Status)
+			assert.Equal(t, testCase.message, db.records[0].Message)
+		})
+	}
+}
+
+func Test_Runner_updateNecessary_allowedIPRanges(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		allowedIPRanges []netip.Prefix
+		domains         []string
+		status          models.Status
+		message         string
+		errs            []string
+	}{
+		"no_allowed_ip_ranges": {
+			domains: []string{"@.a.com"},
+			status:  constants.UNSET,
+		},
+		"in_range": {
+			allowedIPRanges: []netip.Prefix{
+				netip.MustParsePrefix("5.6.0.0/16"),
+				netip.MustParsePrefix("1.2.3.0/24"),
+			},
+			domains: []string{"@.a.com"},
+			status:  constants.UNSET,
+		},
+		"out_of_range": {
+			allowedIPRanges: []netip.Prefix{
+				netip.MustParsePrefix("5.6.0.0/16"),
+				netip.MustParsePrefix("2001:db8::/32"),
+			},
+			status:  constants.SKIPPED,
+			message: "public IP address not allowed",
+			errs: []string{"skipping update of record @.a.com: " +
+				"IP address is not in the allowed IP ranges: 1.2.3.4"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := records.Options{AllowedIPRanges: testCase.allowedIPRanges}
+			db := &orderTestDatabase{records: []records.Record{
+				records.New(&orderTestProvider{domain: "a.com", host: "@"}, options, nil),
+			}}
+			updater := &orderTestUpdater{db: db}
+			runner := NewRunner(testRunnerSettings(db, updater))
+
+			errs := runner.updateNecessary(context.Background())
+
+			var errMessages []string
+			for _, err := range errs {
+				errMessages = append(errMessages, err.Error())
+			}
+			assert.Equal(t, testCase.errs, errMessages)
+			assert.Equal(t, testCase.domains, updater.domains)
+			assert.Equal(t, testCase.status, db.records[0].Status)
+			assert.Equal(t, testCase.message, db.records[0].Message)
+		})
+	}
+}
+
+type recordingHeartbeat struct {
+	events []heartbeat.Event
+}
+
+func (h *recordingHeartbeat) Ping(_ context.Context, event heartbeat.Event) error {
+	h.events = append(h.events, event)
+	return nil
+}
+
+type failingTestUpdater struct{}
+
+func (failingTestUpdater) Update(context.Context, uint, netip.Addr) error {
+	return errors.New("test error")
+}
+
+func Test_Runner_updateNecessary_heartbeat(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		makeUpdater func(db *orderTestDatabase) UpdaterInterface
+		events      []heartbeat.Event
+	}{
+		"successful_cycle": {
+			makeUpdater: func(db *orderTestDatabase) UpdaterInterface {
+				return &orderTestUpdater{db: db}
+			},
+			events: []heartbeat.Event{heartbeat.Start, heartbeat.Success},
+		},
+		"failed_cycle": {
+			makeUpdater: func(*orderTestDatabase) UpdaterInterface {
+				return failingTestUpdater{}
+			},
+			events: []heartbeat.Event{heartbeat.Start, heartbeat.Failure},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &orderTestDatabase{records: []records.Record{
+				records.New(&orderTestProvider{domain: "a.com", host: "@"}, records.Options{}, nil),
+			}}
+			heartbeatClient := &recordingHeartbeat{}
+			runnerSettings := testRunnerSettings(db, testCase.makeUpdater(db))
+			runnerSettings.Heartbeat = heartbeatClient
+			runner := NewRunner(runnerSettings)
+
+			_ = runner.updateNecessary(context.Background())
+
+			assert.Equal(t, testCase.events, heartbeatClient.events)
+		})
+	}
+}
+
+type concurrencyTestUpdater struct {
+	db        *orderTestDatabase
+	mutex     sync.Mutex
+	running   map[string]int
+	total     int
+	maxZone   map[string]int
+	maxTotal  int
+	updateDur time.Duration
+}
+
+func (u *concurrencyTestUpdater) Update(_ context.Context, id uint, _ netip.Addr) error {
+	domain := u.db.records[id].Provider.Domain()
+	u.mutex.Lock()
+	u.running[domain]++
+	u.total++
+	u.maxZone[domain] = max(u.maxZone[domain], u.running[domain])
+	u.maxTotal = max(u.maxTotal, u.total)
+	u.mutex.Unlock()
+
+	time.Sleep(u.updateDur)
+
+	u.mutex.Lock()
+	u.running[domain]--
+	u.total--
+	u.mutex.Unlock()
+	return nil
+}
+
+func Test_Runner_updateNecessary_zoneConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var recordsSlice []records.Record
+	for _, domain := range []string{"a.com", "b.com"} {
+		for _, host := range []string{"@", "www", "mail"} {
+			recordsSlice = append(recordsSlice, records.New(
+				&orderTestProvider{domain: domain, host: host}, records.Options{}, nil))
+		}
+	}
+	db := &orderTestDatabase{records: recordsSlice}
+	updater := &concurrencyTestUpdater{
+		db:        db,
+		running:   make(map[string]int),
+		maxZone:   make(map[string]int),
+		updateDur: 20 * time.Millisecond,
+	}
+	const concurrency, zoneConcurrency = 4, 1
+	runnerSettings := testRunnerSettings(db, updater)
+	runnerSettings.Concurrency = concurrency
+	runnerSettings.ZoneConcurrency = zoneConcurrency
+	runner := NewRunner(runnerSettings)
+
+	errs := runner.updateNecessary(context.Background())
+
+	assert.Empty(t, errs)
+	// Updates of the same zone never overlap.
+	assert.Equal(t, map[string]int{"a.com": 1, "b.com": 1}, updater.maxZone)
+	// Updates of different zones run in parallel.
+	assert.Equal(t, 2, updater.maxTotal)
+}
+
+func Test_Runner_updatePeriod(t *testing.T) {
+	t.Parallel()
+
+	makeRecord := func(domain string, period time.Duration) records.Record {
+		return records.New(&orderTestProvider{domain: domain, host: "@"},
+			records.Options{Period: period}, nil)
+	}
+
+	db := &orderTestDatabase{records: []records.Record{
+		makeRecord("a.com", 0),
+		makeRecord("b.com", 2*time.Minute),
+		makeRecord("c.com", time.Hour),
+		makeRecord("d.com", 2*time.Minute),
+	}}
+	updater := &orderTestUpdater{db: db}
+	runner := NewRunner(testRunnerSettings(db, updater))
+
+	assert.Equal(t, []time.Duration{2 * time.Minute, time.Hour}, runner.periods())
+
+	errs := runner.updatePeriod(context.Background(), 2*time.Minute)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"@.b.com", "@.d.com"}, updater.domains)
+	assert.Equal(t, constants.UNSET, db.records[0].Status)
+
+	updater.domains = nil
+	errs = runner.updatePeriod(context.Background(), time.Hour)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"@.a.com", "@.c.com"}, updater.domains)
+
+	updater.domains = nil
+	errs = runner.updateNecessary(context.Background())
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"@.a.com", "@.b.com", "@.c.com", "@.d.com"}, updater.domains)
+
+	updater.domains = nil
+	const outOfRangeID = 9
+	errs = runner.updateRecordIDs(context.Background(), []uint{2, outOfRangeID})
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"@.c.com"}, updater.domains)
+
+	expectedPublicIPs := PublicIPs{
+		IP: DetectedIP{IP: netip.MustParseAddr("1.2.3.4"), Time: time.Unix(0, 0)},
+	}
+	assert.Equal(t, expectedPublicIPs, runner.PublicIPs())
+}
@@ -0,0 +1,93 @@
+package update
+
+import (
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+// cycleSummary contains the outcome of an update cycle,
+// to log a single line at the end of the cycle.
+type cycleSummary struct {
+	hosts     int
+	changed   int
+	failed    int
+	unchanged int
+	duration  time.Duration
+	ip        netip.Addr
+	ipv4      netip.Addr
+	ipv6      netip.Addr
+	// dryRun is whether the cycle ran in dry-run mode, in which
+	// case changed counts the records that would be changed.
+	dryRun bool
+}
+
+// summarizeCycle counts the records changed, failed and unchanged during
+// the cycle, among the records due in the cycle. Records needing an update are changed if their update
+// succeeded, and failed otherwise, including if they got skipped. In
+// dry-run mode, records needing an update are counted as changed. Other
+// records are failed if no public IP address was found for them, and
+// unchanged otherwise.
+func (r *Runner) summarizeCycle(records []librecords.Record, dueIDs, recordIDs map[uint]struct{},
+	ip, ipv4, ipv6 netip.Addr, failoverIPs map[uint]netip.Addr) (summary cycleSummary) {
+	summary = cycleSummary{
+		hosts:  len(dueIDs),
+		ip:     ip,
+		ipv4:   ipv4,
+		ipv6:   ipv6,
+		dryRun: r.dryRun,
+	}
+	for i, record := range records {
+		id := uint(i)
+		if _, due := dueIDs[id]; !due {
+			continue
+		}
+		if _, requireUpdate := recordIDs[id]; requireUpdate {
+			updatedRecord, err := r.db.Select(id)
+			if r.dryRun || (err == nil && updatedRecord.Status == constants.SUCCESS) {
+				summary.changed++
+			} else {
+				summary.failed++
+			}
+			continue
+		}
+
+		updateIP := getRecordUpdateIP(id, record, ip, ipv4, ipv6, failoverIPs)
+		if updateIP.IsValid() {
+			summary.unchanged++
+		} else {
+			summary.failed++
+		}
+	}
+	return summary
+}
+
+// message returns the summary log line, for example
+// "cycle complete: hosts=3 changed=1 failed=1 unchanged=1
+// duration=1.2s ipv4=1.2.3.4". Public IP addresses not fetched
+// during the cycle are omitted, and the line starts with "dry run"
+// in dry-run mode.
+func (s cycleSummary) message(anonymizeIPs bool) string {
+	message := fmt.Sprintf("cycle complete: hosts=%d changed=%d failed=%d unchanged=%d duration=%s",
+		s.hosts, s.changed, s.failed, s.unchanged, s.duration.Round(time.Millisecond))
+	if s.dryRun {
+		message = "dry run " + message
+	}
+	namedIPs := []struct {
+		name string
+		ip   netip.Addr
+	}{
+		{name: "ip", ip: s.ip},
+		{name: "ipv4", ip: s.ipv4},
+		{name: "ipv6", ip: s.ipv6},
+	}
+	for _, namedIP := range namedIPs {
+		if namedIP.ip.IsValid() {
+			message += " " + namedIP.name + "=" + ipToString(namedIP.ip, anonymizeIPs)
+		}
+	}
+	return message
+}
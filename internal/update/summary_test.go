@@ -0,0 +1,83 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+type ipv6TestProvider struct {
+	orderTestProvider
+}
+
+func (p *ipv6TestProvider) IPVersion() ipversion.IPVersion { return ipversion.IP6 }
+
+type statusTestUpdater struct {
+	db          *orderTestDatabase
+	failDomains map[string]struct{}
+}
+
+func (u *statusTestUpdater) Update(_ context.Context, id uint, _ netip.Addr) error {
+	record := u.db.records[id]
+	if _, fail := u.failDomains[record.Provider.BuildDomainName()]; fail {
+		record.Status = constants.FAIL
+		u.db.records[id] = record
+		return errors.New("test error")
+	}
+	record.Status = constants.SUCCESS
+	u.db.records[id] = record
+	return nil
+}
+
+type infoRecordingLogger struct {
+	noopLogger
+	infos []string
+}
+
+func (l *infoRecordingLogger) Info(s string) { l.infos = append(l.infos, s) }
+
+func Test_Runner_updateNecessary_summary(t *testing.T) {
+	t.Parallel()
+
+	upToDateHistory := models.History{
+		{IP: netip.MustParseAddr("1.2.3.4"), Time: time.Unix(0, 0)},
+	}
+	db := &orderTestDatabase{records: []records.Record{
+		records.New(&orderTestProvider{domain: "a.com", host: "@"}, records.Options{}, nil),
+		records.New(&orderTestProvider{domain: "b.com", host: "@"}, records.Options{}, nil),
+		records.New(&orderTestProvider{domain: "c.com", host: "@"}, records.Options{}, upToDateHistory),
+		records.New(&ipv6TestProvider{orderTestProvider{domain: "d.com", host: "@"}},
+			records.Options{}, nil),
+	}}
+	updater := &statusTestUpdater{
+		db:          db,
+		failDomains: map[string]struct{}{"@.b.com": {}},
+	}
+	now := time.Unix(0, 0)
+	timeNow := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	logger := &infoRecordingLogger{}
+	runnerSettings := testRunnerSettings(db, updater)
+	runnerSettings.Logger = logger
+	runnerSettings.TimeNow = timeNow
+	runner := NewRunner(runnerSettings)
+
+	errs := runner.updateNecessary(context.Background())
+
+	assert.Len(t, errs, 1)
+	if assert.NotEmpty(t, logger.infos) {
+		lastInfo := logger.infos[len(logger.infos)-1]
+		assert.Regexp(t, `^cycle complete: hosts=4 changed=1 failed=2 unchanged=1 `+
+			`duration=[1-9][0-9]*s ip=1\.2\.3\.4$`, lastInfo)
+	}
+}
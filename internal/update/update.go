@@ -6,48 +6,150 @@ import (
 	"fmt"
 	"net/http"
 	"net/netip"
+	"text/template"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/events"
+	"github.com/qdm12/ddns-updater/internal/hook"
+	"github.com/qdm12/ddns-updater/internal/metrics"
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/nscheck"
+	"github.com/qdm12/ddns-updater/internal/provider"
 	settingserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/webhook"
 )
 
 type Updater struct {
-	db             Database
-	client         *http.Client
-	shoutrrrClient ShoutrrrClient
-	logger         DebugLogger
-	timeNow        func() time.Time
+	db                   Database
+	client               *http.Client
+	shoutrrrClient       ShoutrrrClient
+	notificationTemplate *template.Template
+	events               EventPublisher
+	metrics              Metrics
+	hook                 HookRunner
+	webhook              WebhookSender
+	verifyRetries        uint
+	verifyBackoff        time.Duration
+	acceptLanguage       string
+	anonymizeIPs         bool
+	logger               DebugLogger
+	timeNow              func() time.Time
+	newResolver          func(address string) nscheck.LookupIPer
 }
 
-func NewUpdater(db Database, client *http.Client, shoutrrrClient ShoutrrrClient,
-	logger DebugLogger, timeNow func() time.Time) *Updater {
-	client = makeLogClient(client, logger)
+// UpdaterSettings contains the dependencies and settings of an Updater.
+type UpdaterSettings struct {
+	DB                   Database
+	Client               *http.Client
+	ShoutrrrClient       ShoutrrrClient
+	NotificationTemplate *template.Template
+	Events               EventPublisher
+	Metrics              Metrics
+	Hook                 HookRunner
+	Webhook              WebhookSender
+	// VerifyRetries is the number of times the record is read again if
+	// the IP address received does not match the IP address sent, waiting
+	// VerifyBackoff before the first retry and doubling it each time.
+	VerifyRetries  uint
+	VerifyBackoff  time.Duration
+	AcceptLanguage string
+	// AnonymizeIPs is whether to anonymize IP addresses in logs,
+	// notifications and webhooks.
+	AnonymizeIPs bool
+	Logger       DebugLogger
+	TimeNow      func() time.Time
+}
+
+func NewUpdater(settings UpdaterSettings) *Updater {
 	return &Updater{
-		db:             db,
-		client:         client,
-		shoutrrrClient: shoutrrrClient,
-		logger:         logger,
-		timeNow:        timeNow,
+		db:                   settings.DB,
+		client:               makeLogClient(settings.Client, settings.Logger),
+		shoutrrrClient:       settings.ShoutrrrClient,
+		notificationTemplate: settings.NotificationTemplate,
+		events:               settings.Events,
+		metrics:              settings.Metrics,
+		hook:                 settings.Hook,
+		webhook:              settings.Webhook,
+		verifyRetries:        settings.VerifyRetries,
+		verifyBackoff:        settings.VerifyBackoff,
+		acceptLanguage:       settings.AcceptLanguage,
+		anonymizeIPs:         settings.AnonymizeIPs,
+		logger:               settings.Logger,
+		timeNow:              settings.TimeNow,
+		newResolver: func(address string) nscheck.LookupIPer {
+			return nscheck.NewResolver(address)
+		},
 	}
 }
 
 func (u *Updater) Update(ctx context.Context, id uint, ip netip.Addr) (err error) {
-	record, err := u.db.Select(id)
+	record, err := u.startUpdate(id)
 	if err != nil {
 		return err
 	}
+	ctx, created := utils.ContextWithCreatedSignal(ctx)
+	missingRecord := record.Options.MissingRecord
+	if missingRecord == records.MissingRecordError || missingRecord == records.MissingRecordSkip {
+		ctx = utils.ContextWithoutCreation(ctx)
+	}
+	newIP, providerName, err := u.updateWithFallback(ctx, record, ip)
+	if missingRecord == records.MissingRecordSkip && errors.Is(err, settingserrors.ErrRecordNotFound) {
+		return u.skipMissingRecord(id, record, err)
+	} else if err == nil && record.Options.NameserverCheck != nil {
+		err = u.checkNameserver(ctx, record, newIP)
+	}
+	return u.finishUpdate(ctx, id, record, newIP, providerName, created(), err)
+}
+
+// startUpdate sets the record status to updating and returns the record.
+func (u *Updater) startUpdate(id uint) (record records.Record, err error) {
+	record, err = u.db.Select(id)
+	if err != nil {
+		return record, err
+	}
 	record.Time = u.timeNow()
 	record.Status = constants.UPDATING
 	err = u.db.Update(id, record)
 	if err != nil {
-		return err
+		return record, err
 	}
+	return record, nil
+}
+
+// skipMissingRecord sets the record status to skipped, since the record
+// does not exist and its missing record policy is to skip it.
+func (u *Updater) skipMissingRecord(id uint, record records.Record, err error) error {
+	u.logger.Debug(record.Provider.BuildDomainName() + ": skipping missing record: " + err.Error())
+	record.Status = constants.SKIPPED
+	record.Message = "record does not exist"
+	return u.db.Update(id, record)
+}
+
+// finishUpdate sets the record status and message from the update error,
+// and on success records the new IP address set by the provider named
+// providerName, sends a notification and runs the post-update hook.
+// A transient error is returned as is, without being reported, if the
+// update is retried, see contextWithRetryPending.
+func (u *Updater) finishUpdate(ctx context.Context, id uint, record records.Record, newIP netip.Addr,
+	providerName models.Provider, created bool, err error) error {
+	u.metrics.UpdateFinished(metrics.Record{
+		Provider:  record.Options.ProviderName,
+		FQDN:      record.Provider.BuildDomainName(),
+		IPVersion: record.Provider.IPVersion().String(),
+	}, err == nil, u.timeNow())
 	record.Status = constants.FAIL
-	newIP, err := record.Provider.Update(ctx, u.client, ip)
-	if err != nil {
-		record.Message = err.Error()
+	if err != nil && retryPending(ctx) && isTransientError(err) {
+		// The failure is reported after the last retry
+		return err
+	} else if err != nil {
+		record.Message = redactSecrets(err.Error())
+		record.LastError = record.Message
+		if errors.Is(err, settingserrors.ErrIPReceivedMismatch) {
+			u.shoutrrrClient.Notify(record.Provider.BuildDomainName() + ": " + record.Message)
+		}
 		if errors.Is(err, settingserrors.ErrBannedAbuse) {
 			lastBan := time.Unix(u.timeNow().Unix(), 0)
 			record.LastBan = &lastBan
@@ -59,17 +161,200 @@ func (u *Updater) Update(ctx context.Context, id uint, ip netip.Addr) (err error
 		} else {
 			record.LastBan = nil // clear a previous ban
 		}
+		u.publishEvent(record, netip.Addr{})
+		u.sendWebhook(record, providerName, record.History.GetCurrentIP(), netip.Addr{})
 		if updateErr := u.db.Update(id, record); updateErr != nil {
 			return fmt.Errorf("%w (with database update error: %w)", err, updateErr)
 		}
 		return err
 	}
 	record.Status = constants.SUCCESS
-	record.Message = fmt.Sprintf("changed to %s", ip.String())
+	record.LastError = ""
+	if created {
+		u.metrics.RecordCreated(providerName)
+	} else {
+		u.metrics.RecordUpdated(providerName)
+	}
+	// newIP is the IP address set by the provider, which can differ
+	// from the IP address sent if the provider detects it server-side.
+	record.Message = "changed to " + ipToString(newIP, u.anonymizeIPs)
+	if providerName != record.Options.ProviderName {
+		record.Message += " using fallback provider " + string(providerName)
+	}
+	oldIP := record.History.GetCurrentIP()
 	record.History = append(record.History, models.HistoryEvent{
 		IP:   newIP,
 		Time: u.timeNow(),
 	})
-	u.shoutrrrClient.Notify(record.Provider.BuildDomainName() + " " + record.Message)
+	record.ProviderIP = newIP
+	u.shoutrrrClient.Notify(u.makeChangeNotification(record, oldIP, newIP))
+	u.publishEvent(record, newIP)
+	u.hook.Run(ctx, hook.Event{
+		Domain:   record.Provider.Domain(),
+		Host:     record.Provider.Host(),
+		FQDN:     record.Provider.BuildDomainName(),
+		Provider: string(providerName),
+		OldIP:    oldIP,
+		NewIP:    newIP,
+	})
+	u.sendWebhook(record, providerName, oldIP, newIP)
 	return u.db.Update(id, record) // persists some data if needed (i.e new IP)
 }
+
+// updateWithFallback updates the record using its provider and, if this
+// fails, using its fallback provider if one is configured. It returns the
+// name of the provider which updated the record. If both providers fail,
+// the error returned wraps the provider error and mentions the fallback
+// provider error.
+func (u *Updater) updateWithFallback(ctx context.Context, record records.Record,
+	ip netip.Addr) (newIP netip.Addr, providerName models.Provider, err error) {
+	newIP, err = u.updateProvider(ctx, record.Options.ProviderName, record.Provider, record.Options, ip)
+	fallback := record.Options.Fallback
+	if err == nil || fallback == nil {
+		return newIP, record.Options.ProviderName, err
+	}
+
+	u.logger.Debug(fmt.Sprintf("%s: %s, trying fallback provider %s",
+		record.Provider.BuildDomainName(), err, fallback.Name))
+	// The API URL override only applies to the record provider.
+	fallbackOptions := record.Options
+	fallbackOptions.APIURL = nil
+	newIP, fallbackErr := u.updateProvider(ctx, fallback.Name, fallback.Provider, fallbackOptions, ip)
+	if fallbackErr != nil {
+		return netip.Addr{}, "", fmt.Errorf("%w (fallback provider %s: %s)",
+			err, fallback.Name, fallbackErr)
+	}
+	return newIP, fallback.Name, nil
+}
+
+// checkNameserver waits for the nameserver configured for the record
+// to serve the IP address given, returning an error if it does not
+// within the configured timeout.
+func (u *Updater) checkNameserver(ctx context.Context, record records.Record,
+	ip netip.Addr) (err error) {
+	settings := *record.Options.NameserverCheck
+	hostname := record.Provider.BuildDomainName()
+	u.logger.Debug(fmt.Sprintf("%s: waiting for nameserver %s to serve %s",
+		hostname, settings.Address, ipToString(ip, u.anonymizeIPs)))
+	resolver := u.newResolver(settings.Address)
+	return nscheck.Check(ctx, resolver, settings, hostname, ip)
+}
+
+// updateProvider updates the record using its provider, named providerName
+// in the request duration metrics. If the provider accepted the write but
+// returned a mismatching IP address, the update is considered successful
+// if skipVerify is true, and is verified with verifyIP otherwise.
+// Other errors, such as a rejected write, are not retried, and are
+// considered successful only if they match one of the ignored errors
+// configured for the record.
+func (u *Updater) updateProvider(ctx context.Context, providerName models.Provider,
+	provider provider.Provider, options records.Options, ip netip.Addr) (newIP netip.Addr, err error) {
+	client := makeExtraHeadersClient(u.client, options.ExtraHeaders)
+	client = makeAPIURLClient(client, options.APIURL)
+	client = makeAcceptLanguageClient(client, u.acceptLanguage)
+	client = makeMetricsClient(client, u.metrics, providerName, u.timeNow)
+	newIP, err = provider.Update(ctx, client, ip)
+	switch {
+	case err == nil:
+		return newIP, nil
+	case !errors.Is(err, settingserrors.ErrIPReceivedMismatch):
+		if isIgnoredError(err, options.IgnoreErrors) {
+			u.logger.Debug(provider.BuildDomainName() + ": ignoring configured error " + err.Error())
+			return ip, nil
+		}
+		return netip.Addr{}, err
+	case options.SkipVerify:
+		u.logger.Debug(provider.BuildDomainName() + ": ignoring " + err.Error())
+		return ip, nil
+	default:
+		return u.verifyIP(ctx, client, provider, ip, err)
+	}
+}
+
+// verifyIP reads the record IP address again after its provider returned
+// a mismatching IP address for the IP address ip sent, since some APIs
+// return the old value immediately after a write. The record is read up
+// to the configured number of verification retries, with an exponential
+// backoff, and is not written again. The mismatch error given is returned
+// if the provider cannot read the record IP address.
+func (u *Updater) verifyIP(ctx context.Context, client *http.Client,
+	recordProvider provider.Provider, ip netip.Addr, mismatchErr error) (
+	recordIP netip.Addr, err error) {
+	reader, ok := recordProvider.(provider.IPReader)
+	if !ok || u.verifyRetries == 0 {
+		return netip.Addr{}, mismatchErr
+	}
+
+	err = mismatchErr
+	backoff := u.verifyBackoff
+	for try := uint(1); try <= u.verifyRetries; try++ {
+		u.logger.Debug(fmt.Sprintf("%s: %s, reading the record again in %s",
+			recordProvider.BuildDomainName(), err, backoff))
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return netip.Addr{}, fmt.Errorf("%w (verification retries canceled: %w)", err, ctx.Err())
+		}
+		backoff *= 2
+
+		recordIP, err = reader.ReadIP(ctx, client, ip)
+		switch {
+		case err != nil:
+			err = fmt.Errorf("%w (reading record: %w)", mismatchErr, err)
+		case recordIP != ip:
+			err = fmt.Errorf("%w: sent ip %s to update but read %s",
+				settingserrors.ErrIPReceivedMismatch, ip, recordIP)
+		default:
+			if setter, ok := recordProvider.(provider.KnownIPSetter); ok {
+				setter.SetKnownIP(recordIP)
+			}
+			return recordIP, nil
+		}
+	}
+	return netip.Addr{}, fmt.Errorf("%w (after %d verification retries)", err, u.verifyRetries)
+}
+
+// sendWebhook queues the result of the record update to be sent to the
+// configured webhooks. The update failed if newIP is invalid, in which case the
+// error is the record message.
+func (u *Updater) sendWebhook(record records.Record,
+	providerName models.Provider, oldIP, newIP netip.Addr) {
+	if providerName == "" {
+		providerName = record.Options.ProviderName
+	}
+	event := webhook.Event{
+		Record:   record.Provider.BuildDomainName(),
+		Domain:   record.Provider.Domain(),
+		Host:     record.Provider.Host(),
+		Provider: string(providerName),
+		Status:   webhook.StatusFailure,
+		Time:     u.timeNow(),
+	}
+	if oldIP.IsValid() {
+		event.OldIP = ipToString(oldIP, u.anonymizeIPs)
+	}
+	if newIP.IsValid() {
+		event.Status = webhook.StatusSuccess
+		event.NewIP = ipToString(newIP, u.anonymizeIPs)
+	} else {
+		event.Error = record.Message
+	}
+	u.webhook.Send(event)
+}
+
+func (u *Updater) publishEvent(record records.Record, newIP netip.Addr) {
+	event := events.Event{
+		Domain:  record.Provider.Domain(),
+		Host:    record.Provider.Host(),
+		Status:  record.Status,
+		Message: record.Message,
+		Time:    record.Time,
+		CGNAT:   record.BehindCGNAT,
+	}
+	if newIP.IsValid() {
+		event.IP = ipToString(newIP, u.anonymizeIPs)
+	}
+	u.events.Publish(event)
+}
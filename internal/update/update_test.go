@@ -0,0 +1,645 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/hook"
+	"github.com/qdm12/ddns-updater/internal/metrics"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/nscheck"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	providerconstants "github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/update/mock_update"
+	"github.com/qdm12/ddns-updater/internal/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+type testProvider struct {
+	provider.Provider
+	results []error
+	calls   int
+}
+
+func (p *testProvider) BuildDomainName() string {
+	return "example.com"
+}
+
+func (p *testProvider) Update(_ context.Context, _ *http.Client, ip netip.Addr) (
+	newIP netip.Addr, err error) {
+	err = p.results[p.calls]
+	p.calls++
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return ip, nil
+}
+
+// readerTestProvider reads the successive IP addresses
+// given, and then fails with readErr.
+type readerTestProvider struct {
+	*testProvider
+	reads     []netip.Addr
+	readErr   error
+	readCalls int
+}
+
+func (p *readerTestProvider) ReadIP(context.Context, *http.Client, netip.Addr) (
+	recordIP netip.Addr, err error) {
+	p.readCalls++
+	if p.readCalls > len(p.reads) {
+		return netip.Addr{}, p.readErr
+	}
+	return p.reads[p.readCalls-1], nil
+}
+
+func Test_Updater_updateProvider(t *testing.T) {
+	t.Parallel()
+
+	errMismatch := fmt.Errorf("%w: sent ip 1.2.3.4 to update but received 5.6.7.8",
+		errors.ErrIPReceivedMismatch)
+	errConflict := errors.NewHTTPStatusError(http.StatusConflict, "record already up to date")
+
+	testCases := map[string]struct {
+		verifyRetries uint
+		skipVerify    bool
+		ignoreErrors  []string
+		results       []error
+		calls         int
+		reader        bool
+		reads         []netip.Addr
+		readErr       error
+		readCalls     int
+		newIP         netip.Addr
+		errWrapped    error
+		errMessage    string
+	}{
+		"success": {
+			results: []error{nil},
+			calls:   1,
+			newIP:   netip.MustParseAddr("1.2.3.4"),
+		},
+		"mismatch_without_retries": {
+			results:    []error{errMismatch},
+			calls:      1,
+			errWrapped: errors.ErrIPReceivedMismatch,
+			errMessage: "mismatching IP address received: sent ip 1.2.3.4 to update but received 5.6.7.8",
+		},
+		"mismatch_then_read_matches": {
+			verifyRetries: 2,
+			results:       []error{errMismatch},
+			calls:         1,
+			reader:        true,
+			reads:         []netip.Addr{netip.MustParseAddr("5.6.7.8"), netip.MustParseAddr("1.2.3.4")},
+			readCalls:     2,
+			newIP:         netip.MustParseAddr("1.2.3.4"),
+		},
+		"mismatch_retries_exhausted": {
+			verifyRetries: 1,
+			results:       []error{errMismatch},
+			calls:         1,
+			reader:        true,
+			reads:         []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+			readCalls:     1,
+			errWrapped:    errors.ErrIPReceivedMismatch,
+			errMessage: "mismatching IP address received: sent ip 1.2.3.4 to update " +
+				"but read 5.6.7.8 (after 1 verification retries)",
+		},
+		"mismatch_read_failed": {
+			verifyRetries: 1,
+			results:       []error{errMismatch},
+			calls:         1,
+			reader:        true,
+			readErr:       errors.ErrRecordNotFound,
+			readCalls:     1,
+			errWrapped:    errors.ErrIPReceivedMismatch,
+			errMessage: "mismatching IP address received: sent ip 1.2.3.4 to update " +
+				"but received 5.6.7.8 (reading record: record not found) (after 1 verification retries)",
+		},
+		"mismatch_without_reader": {
+			verifyRetries: 2,
+			results:       []error{errMismatch},
+			calls:         1,
+			errWrapped:    errors.ErrIPReceivedMismatch,
+			errMessage:    "mismatching IP address received: sent ip 1.2.3.4 to update but received 5.6.7.8",
+		},
+		"mismatch_skip_verify": {
+			verifyRetries: 2,
+			skipVerify:    true,
+			results:       []error{errMismatch},
+			calls:         1,
+			newIP:         netip.MustParseAddr("1.2.3.4"),
+		},
+		"rejected_skip_verify": {
+			skipVerify: true,
+			results:    []error{errors.ErrAuth},
+			calls:      1,
+			errWrapped: errors.ErrAuth,
+			errMessage: "bad authentication",
+		},
+		"benign_error_substring_ignored": {
+			ignoreErrors: []string{"already up to date"},
+			results:      []error{errConflict},
+			calls:        1,
+			newIP:        netip.MustParseAddr("1.2.3.4"),
+		},
+		"benign_status_code_ignored": {
+			ignoreErrors: []string{"409"},
+			results:      []error{errConflict},
+			calls:        1,
+			newIP:        netip.MustParseAddr("1.2.3.4"),
+		},
+		"other_status_code_not_ignored": {
+			ignoreErrors: []string{"404"},
+			results:      []error{errConflict},
+			calls:        1,
+			errWrapped:   errors.ErrHTTPStatusNotValid,
+			errMessage:   "HTTP status is not valid: 409: record already up to date",
+		},
+		"status_code_only_matches_http_status_errors": {
+			ignoreErrors: []string{"409"},
+			results:      []error{fmt.Errorf("%w: code 409", errors.ErrAuth)},
+			calls:        1,
+			errWrapped:   errors.ErrAuth,
+			errMessage:   "bad authentication: code 409",
+		},
+		"rejected_not_retried": {
+			verifyRetries: 2,
+			results:       []error{errors.ErrAuth},
+			calls:         1,
+			errWrapped:    errors.ErrAuth,
+			errMessage:    "bad authentication",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			logger := mock_update.NewMockDebugLogger(ctrl)
+			logger.EXPECT().Debug(gomock.Any()).AnyTimes()
+
+			updater := &Updater{
+				client:        http.DefaultClient,
+				metrics:       metrics.New(),
+				verifyRetries: testCase.verifyRetries,
+				verifyBackoff: time.Nanosecond,
+				logger:        logger,
+				timeNow:       time.Now,
+			}
+			baseProvider := &testProvider{results: testCase.results}
+			reader := &readerTestProvider{
+				testProvider: baseProvider,
+				reads:        testCase.reads,
+				readErr:      testCase.readErr,
+			}
+			var recordProvider provider.Provider = baseProvider
+			if testCase.reader {
+				recordProvider = reader
+			}
+			options := records.Options{
+				SkipVerify:   testCase.skipVerify,
+				IgnoreErrors: testCase.ignoreErrors,
+			}
+
+			newIP, err := updater.updateProvider(context.Background(),
+				providerconstants.Hetzner, recordProvider, options, netip.MustParseAddr("1.2.3.4"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.newIP, newIP)
+			assert.Equal(t, testCase.calls, baseProvider.calls)
+			assert.Equal(t, testCase.readCalls, reader.readCalls)
+		})
+	}
+}
+
+type fallbackTestProvider struct {
+	orderTestProvider
+	err   error
+	calls int
+}
+
+func (p *fallbackTestProvider) Update(_ context.Context, _ *http.Client, ip netip.Addr) (
+	newIP netip.Addr, err error) {
+	p.calls++
+	if p.err != nil {
+		return netip.Addr{}, p.err
+	}
+	return ip, nil
+}
+
+func Test_Updater_Update_fallback(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		primaryErr    error
+		fallbackErr   error
+		fallbackCalls int
+		status        models.Status
+		message       string
+		errWrapped    error
+		errMessage    string
+	}{
+		"primary_succeeds": {
+			status:  constants.SUCCESS,
+			message: "changed to 1.2.3.4",
+		},
+		"primary_fails_fallback_succeeds": {
+			primaryErr:    errors.ErrAuth,
+			fallbackCalls: 1,
+			status:        constants.SUCCESS,
+			message:       "changed to 1.2.3.4 using fallback provider hetzner",
+		},
+		"both_fail": {
+			primaryErr:    errors.ErrAuth,
+			fallbackErr:   errors.ErrHTTPStatusNotValid,
+			fallbackCalls: 1,
+			status:        constants.FAIL,
+			message:       "bad authentication (fallback provider hetzner: HTTP status is not valid)",
+			errWrapped:    errors.ErrAuth,
+			errMessage:    "bad authentication (fallback provider hetzner: HTTP status is not valid)",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			primary := &fallbackTestProvider{
+				orderTestProvider: orderTestProvider{domain: "example.com", host: "@"},
+				err:               testCase.primaryErr,
+			}
+			fallback := &fallbackTestProvider{
+				orderTestProvider: orderTestProvider{domain: "example.com", host: "@"},
+				err:               testCase.fallbackErr,
+			}
+			db := &orderTestDatabase{records: []records.Record{{
+				Provider: primary,
+				Options: records.Options{
+					ProviderName: providerconstants.Cloudflare,
+					Fallback: &records.Fallback{
+						Name:     providerconstants.Hetzner,
+						Provider: fallback,
+					},
+				},
+			}}}
+			updater := NewUpdater(testUpdaterSettings(db))
+
+			err := updater.Update(context.Background(), 0, netip.MustParseAddr("1.2.3.4"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, 1, primary.calls)
+			assert.Equal(t, testCase.fallbackCalls, fallback.calls)
+			record := db.records[0]
+			assert.Equal(t, testCase.status, record.Status)
+			assert.Equal(t, testCase.message, record.Message)
+		})
+	}
+}
+
+type nameserverTestResolver struct {
+	ips []net.IP
+}
+
+func (r *nameserverTestResolver) LookupIP(context.Context, string, string) ([]net.IP, error) {
+	return r.ips, nil
+}
+
+func Test_Updater_Update_nameserverCheck(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		servedIP   string
+		status     models.Status
+		message    string
+		errWrapped error
+		errMessage string
+	}{
+		"new_ip_served": {
+			servedIP: "1.2.3.4",
+			status:   constants.SUCCESS,
+			message:  "changed to 1.2.3.4",
+		},
+		"old_ip_served": {
+			servedIP: "5.6.7.8",
+			status:   constants.FAIL,
+			message: "nameserver does not serve the IP address expected: " +
+				"1.2.3.4 at ns1.example.com:53 for @.example.com, received [5.6.7.8]",
+			errWrapped: nscheck.ErrIPNotServed,
+			errMessage: "nameserver does not serve the IP address expected: " +
+				"1.2.3.4 at ns1.example.com:53 for @.example.com, received [5.6.7.8]",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &orderTestDatabase{records: []records.Record{{
+				Provider: &fallbackTestProvider{
+					orderTestProvider: orderTestProvider{domain: "example.com", host: "@"},
+				},
+				Options: records.Options{
+					ProviderName: providerconstants.Cloudflare,
+					NameserverCheck: &nscheck.Settings{
+						Address:  "ns1.example.com:53",
+						Timeout:  10 * time.Millisecond,
+						Interval: time.Millisecond,
+					},
+				},
+			}}}
+			updater := NewUpdater(testUpdaterSettings(db))
+			var resolverAddress string
+			updater.newResolver = func(address string) nscheck.LookupIPer {
+				resolverAddress = address
+				return &nameserverTestResolver{ips: []net.IP{net.ParseIP(testCase.servedIP)}}
+			}
+
+			err := updater.Update(context.Background(), 0, netip.MustParseAddr("1.2.3.4"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, "ns1.example.com:53", resolverAddress)
+			record := db.records[0]
+			assert.Equal(t, testCase.status, record.Status)
+			assert.Equal(t, testCase.message, record.Message)
+		})
+	}
+}
+
+type missingTestProvider struct {
+	orderTestProvider
+}
+
+func (p *missingTestProvider) Update(ctx context.Context, _ *http.Client, ip netip.Addr) (
+	newIP netip.Addr, err error) {
+	if !utils.CreationAllowed(ctx) {
+		return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+	}
+	utils.SignalCreated(ctx)
+	return ip, nil
+}
+
+func Test_Updater_Update_missingRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		missingRecord string
+		status        models.Status
+		message       string
+		errWrapped    error
+		errMessage    string
+	}{
+		"default": {
+			status:  constants.SUCCESS,
+			message: "changed to 1.2.3.4",
+		},
+		"create": {
+			missingRecord: records.MissingRecordCreate,
+			status:        constants.SUCCESS,
+			message:       "changed to 1.2.3.4",
+		},
+		"error": {
+			missingRecord: records.MissingRecordError,
+			status:        constants.FAIL,
+			message:       "record not found",
+			errWrapped:    errors.ErrRecordNotFound,
+			errMessage:    "record not found",
+		},
+		"skip": {
+			missingRecord: records.MissingRecordSkip,
+			status:        constants.SKIPPED,
+			message:       "record does not exist",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &orderTestDatabase{records: []records.Record{{
+				Provider: &missingTestProvider{
+					orderTestProvider: orderTestProvider{domain: "example.com", host: "@"},
+				},
+				Options: records.Options{
+					ProviderName:  providerconstants.Cloudflare,
+					MissingRecord: testCase.missingRecord,
+				},
+			}}}
+			updater := NewUpdater(testUpdaterSettings(db))
+
+			err := updater.Update(context.Background(), 0, netip.MustParseAddr("1.2.3.4"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			record := db.records[0]
+			assert.Equal(t, testCase.status, record.Status)
+			assert.Equal(t, testCase.message, record.Message)
+		})
+	}
+}
+
+type recordingHook struct {
+	events []hook.Event
+}
+
+func (h *recordingHook) Run(_ context.Context, event hook.Event) {
+	h.events = append(h.events, event)
+}
+
+func Test_Updater_Update_hook(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		missingRecord string
+		events        []hook.Event
+	}{
+		"change": {
+			events: []hook.Event{{
+				Domain:   "example.com",
+				Host:     "www",
+				FQDN:     "www.example.com",
+				Provider: "cloudflare",
+				OldIP:    netip.MustParseAddr("5.6.7.8"),
+				NewIP:    netip.MustParseAddr("1.2.3.4"),
+			}},
+		},
+		"failure": {
+			missingRecord: records.MissingRecordError,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &orderTestDatabase{records: []records.Record{{
+				Provider: &missingTestProvider{
+					orderTestProvider: orderTestProvider{domain: "example.com", host: "www"},
+				},
+				Options: records.Options{
+					ProviderName:  providerconstants.Cloudflare,
+					MissingRecord: testCase.missingRecord,
+				},
+				History: models.History{{IP: netip.MustParseAddr("5.6.7.8")}},
+			}}}
+			hookRunner := &recordingHook{}
+			updaterSettings := testUpdaterSettings(db)
+			updaterSettings.Hook = hookRunner
+			updater := NewUpdater(updaterSettings)
+
+			_ = updater.Update(context.Background(), 0, netip.MustParseAddr("1.2.3.4"))
+
+			assert.Equal(t, testCase.events, hookRunner.events)
+		})
+	}
+}
+
+type recordingWebhook struct {
+	events []webhook.Event
+}
+
+func (w *recordingWebhook) Send(event webhook.Event) {
+	w.events = append(w.events, event)
+}
+
+func Test_Updater_Update_webhook(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+
+	testCases := map[string]struct {
+		missingRecord string
+		event         webhook.Event
+	}{
+		"success": {
+			event: webhook.Event{
+				Record:   "www.example.com",
+				Domain:   "example.com",
+				Host:     "www",
+				Provider: "cloudflare",
+				Status:   webhook.StatusSuccess,
+				OldIP:    "5.6.7.8",
+				NewIP:    "1.2.3.4",
+				Time:     now,
+			},
+		},
+		"failure": {
+			missingRecord: records.MissingRecordError,
+			event: webhook.Event{
+				Record:   "www.example.com",
+				Domain:   "example.com",
+				Host:     "www",
+				Provider: "cloudflare",
+				Status:   webhook.StatusFailure,
+				OldIP:    "5.6.7.8",
+				Error:    "record not found",
+				Time:     now,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &orderTestDatabase{records: []records.Record{{
+				Provider: &missingTestProvider{
+					orderTestProvider: orderTestProvider{domain: "example.com", host: "www"},
+				},
+				Options: records.Options{
+					ProviderName:  providerconstants.Cloudflare,
+					MissingRecord: testCase.missingRecord,
+				},
+				History: models.History{{IP: netip.MustParseAddr("5.6.7.8")}},
+			}}}
+			webhookSender := &recordingWebhook{}
+			timeNow := func() time.Time { return now }
+			updaterSettings := testUpdaterSettings(db)
+			updaterSettings.Webhook = webhookSender
+			updaterSettings.TimeNow = timeNow
+			updater := NewUpdater(updaterSettings)
+
+			_ = updater.Update(context.Background(), 0, netip.MustParseAddr("1.2.3.4"))
+
+			assert.Equal(t, []webhook.Event{testCase.event}, webhookSender.events)
+		})
+	}
+}
+
+func Test_Updater_Update_hangingWebhook(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	requested := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requested)
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	webhookSender := webhook.New([]string{server.URL}, nil, 0, server.Client(), noopLogger{})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	done := make(chan struct{})
+	go webhookSender.Run(ctx, done)
+
+	db := &orderTestDatabase{records: []records.Record{{
+		Provider: &missingTestProvider{
+			orderTestProvider: orderTestProvider{domain: "example.com", host: "www"},
+		},
+		Options: records.Options{ProviderName: providerconstants.Cloudflare},
+	}}}
+	updaterSettings := testUpdaterSettings(db)
+	updaterSettings.Webhook = webhookSender
+	updater := NewUpdater(updaterSettings)
+
+	updated := make(chan struct{})
+	go func() {
+		_ = updater.Update(context.Background(), 0, netip.MustParseAddr("1.2.3.4"))
+		close(updated)
+	}()
+
+	const timeout = 5 * time.Second
+	select {
+	case <-updated:
+	case <-time.After(timeout):
+		t.Fatal("update is delayed by the hanging webhook")
+	}
+	select {
+	case <-requested:
+	case <-time.After(timeout):
+		t.Fatal("webhook was not sent")
+	}
+	cancel()
+	<-done
+}
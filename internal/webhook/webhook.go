@@ -0,0 +1,235 @@
+// Package webhook sends a JSON payload to user configured URLs when
+// a record update succeeds or fails, for example to alert a chat
+// channel or an automation platform.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Event contains the details of a record update result. It is exported
+// so that the payload template engine can render it.
+type Event struct {
+	// Record is the fully qualified domain name of the record,
+	// for example sub.example.com.
+	Record   string `json:"record"`
+	Domain   string `json:"domain"`
+	Host     string `json:"host"`
+	Provider string `json:"provider"`
+	// Status is "success" or "failure".
+	Status string `json:"status"`
+	// OldIP is the previous IP address of the record,
+	// and is the empty string if there is none.
+	OldIP string `json:"old_ip"`
+	// NewIP is the IP address set, and is the
+	// empty string if the update failed.
+	NewIP string `json:"new_ip"`
+	// Error is the update error message, and is the
+	// empty string if the update succeeded.
+	Error string    `json:"error"`
+	Time  time.Time `json:"time"`
+}
+
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+)
+
+type Logger interface {
+	Debug(s string)
+	Error(s string)
+}
+
+type Sender struct {
+	urls       []string
+	template   *template.Template
+	retries    uint
+	retryDelay time.Duration
+	timeout    time.Duration
+	client     *http.Client
+	logger     Logger
+	queue      chan Event
+}
+
+// New creates a webhook sender posting to each of the URLs given. The
+// payload is the JSON encoded event if payloadTemplate is nil, and the
+// template executed with the event otherwise. Each failed request is
+// retried up to the number of retries given, with an exponential backoff.
+// Events are delivered in the background by the Run method, so a slow or
+// unreachable webhook does not delay record updates.
+// No URL disables the sender.
+func New(urls []string, payloadTemplate *template.Template, retries uint,
+	client *http.Client, logger Logger) *Sender {
+	const (
+		retryDelay = time.Second
+		timeout    = time.Minute
+		queueSize  = 100
+	)
+	return &Sender{
+		urls:       urls,
+		template:   payloadTemplate,
+		retries:    retries,
+		retryDelay: retryDelay,
+		timeout:    timeout,
+		client:     client,
+		logger:     logger,
+		queue:      make(chan Event, queueSize),
+	}
+}
+
+// ParseTemplate parses the payload template given, which can use the
+// json function to JSON encode a value, for example {{json .Error}}.
+func ParseTemplate(text string) (payloadTemplate *template.Template, err error) {
+	return template.New("webhook").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"json": jsonEncode}).
+		Parse(text)
+}
+
+func jsonEncode(value any) (s string, err error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Send queues the event to be sent to each webhook URL by the Run
+// method, and returns without waiting. The event is dropped and an
+// error is logged if the queue is full. Send must not be called
+// after Close.
+// It is a no-op if no URL is configured.
+func (s *Sender) Send(event Event) {
+	if len(s.urls) == 0 {
+		return
+	}
+
+	select {
+	case s.queue <- event:
+	default:
+		s.logger.Error(event.Record + ": webhook queue is full, dropping event")
+	}
+}
+
+// Close stops the sender from accepting events, and makes Run return
+// once all the queued events are delivered.
+func (s *Sender) Close() {
+	close(s.queue)
+}
+
+// Run delivers the queued events one at a time, each with its own
+// timeout, until the context is canceled or the sender is closed.
+// Failures are logged and not returned, so they do not affect the
+// record update.
+func (s *Sender) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-s.queue:
+			if !ok {
+				return
+			}
+			s.deliver(ctx, event)
+		}
+	}
+}
+
+// deliver sends the event to each webhook URL, and waits for the requests
+// to complete, including their retries, or for the timeout to expire.
+func (s *Sender) deliver(ctx context.Context, event Event) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	payload, err := s.makePayload(event)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("%s: making webhook payload: %s", event.Record, err))
+		return
+	}
+
+	for i, address := range s.urls {
+		err = s.post(ctx, address, payload)
+		if err != nil {
+			// Do not log the URL since it often contains a secret token.
+			s.logger.Error(fmt.Sprintf("%s: sending webhook %d of %d: %s",
+				event.Record, i+1, len(s.urls), err))
+		}
+	}
+}
+
+func (s *Sender) makePayload(event Event) (payload []byte, err error) {
+	if s.template == nil {
+		return json.Marshal(event)
+	}
+	buffer := new(bytes.Buffer)
+	err = s.template.Execute(buffer, event)
+	if err != nil {
+		return nil, fmt.Errorf("executing template: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// post posts the payload to the URL, retrying on failure.
+func (s *Sender) post(ctx context.Context, address string, payload []byte) (err error) {
+	delay := s.retryDelay
+	for try := uint(0); ; try++ {
+		err = s.postOnce(ctx, address, payload)
+		if err == nil {
+			return nil
+		} else if try == s.retries {
+			if try > 0 {
+				err = fmt.Errorf("%w (after %d retries)", err, try)
+			}
+			return err
+		}
+
+		s.logger.Debug(fmt.Sprintf("webhook: %s, retrying in %s", err, delay))
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (retries canceled: %w)", err, ctx.Err())
+		}
+		delay *= 2
+	}
+}
+
+var ErrUnsuccessfulResponse = errors.New("unsuccessful response")
+
+func (s *Sender) postOnce(ctx context.Context, address string, payload []byte) (err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		urlErr := new(url.Error)
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err // do not leak the URL in logs
+		}
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		const maxBodyLength = 256
+		body, _ := io.ReadAll(io.LimitReader(response.Body, maxBodyLength))
+		return fmt.Errorf("%w: %d %s: %s", ErrUnsuccessfulResponse, response.StatusCode,
+			http.StatusText(response.StatusCode), strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	mutex  sync.Mutex
+	errors []string
+}
+
+func (l *recordingLogger) Debug(string) {}
+
+func (l *recordingLogger) Error(s string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.errors = append(l.errors, s)
+}
+
+func Test_Sender_Send(t *testing.T) {
+	t.Parallel()
+
+	event := Event{
+		Record:   "www.example.com",
+		Domain:   "example.com",
+		Host:     "www",
+		Provider: "cloudflare",
+		Status:   StatusFailure,
+		OldIP:    "1.2.3.4",
+		Error:    `bad "request"`,
+		Time:     time.Unix(0, 0).UTC(),
+	}
+
+	testCases := map[string]struct {
+		template string
+		retries  uint
+		statuses []int
+		payloads []string
+		errors   []string
+	}{
+		"default_payload": {
+			statuses: []int{http.StatusOK},
+			payloads: []string{`{"record":"www.example.com","domain":"example.com","host":"www",` +
+				`"provider":"cloudflare","status":"failure","old_ip":"1.2.3.4","new_ip":"",` +
+				`"error":"bad \"request\"","time":"1970-01-01T00:00:00Z"}`},
+		},
+		"template_payload": {
+			template: `{"text":{{json (printf "%s: %s" .Record .Error)}}}`,
+			statuses: []int{http.StatusNoContent},
+			payloads: []string{`{"text":"www.example.com: bad \"request\""}`},
+		},
+		"retried": {
+			template: "{{.Status}}",
+			retries:  1,
+			statuses: []int{http.StatusBadGateway, http.StatusOK},
+			payloads: []string{"failure", "failure"},
+		},
+		"failed": {
+			template: "{{.Status}}",
+			retries:  1,
+			statuses: []int{http.StatusInternalServerError, http.StatusUnauthorized},
+			payloads: []string{"failure", "failure"},
+			errors: []string{"www.example.com: sending webhook 1 of 1: " +
+				"unsuccessful response: 401 Unauthorized: invalid token (after 1 retries)"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var payloads []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				status := testCase.statuses[len(payloads)]
+				payloads = append(payloads, string(body))
+				w.WriteHeader(status)
+				if status == http.StatusUnauthorized {
+					_, _ = w.Write([]byte("invalid token\n"))
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			var payloadTemplate *template.Template
+			if testCase.template != "" {
+				var err error
+				payloadTemplate, err = ParseTemplate(testCase.template)
+				require.NoError(t, err)
+			}
+			logger := &recordingLogger{}
+			sender := New([]string{server.URL}, payloadTemplate, testCase.retries, server.Client(), logger)
+			sender.retryDelay = time.Millisecond
+
+			done := make(chan struct{})
+			go sender.Run(context.Background(), done)
+			sender.Send(event)
+			sender.Close()
+			<-done
+
+			assert.Equal(t, testCase.payloads, payloads)
+			assert.Equal(t, testCase.errors, logger.errors)
+		})
+	}
+}
+
+func Test_Sender_Send_noURL(t *testing.T) {
+	t.Parallel()
+
+	logger := &recordingLogger{}
+	sender := New(nil, nil, 0, nil, logger)
+
+	sender.Send(Event{})
+
+	assert.Empty(t, logger.errors)
+}
+
+func Test_Sender_Send_queueFull(t *testing.T) {
+	t.Parallel()
+
+	logger := &recordingLogger{}
+	sender := New([]string{"http://localhost"}, nil, 0, nil, logger)
+	sender.queue = make(chan Event, 1)
+
+	sender.Send(Event{Record: "a.example.com"})
+	sender.Send(Event{Record: "b.example.com"})
+
+	assert.Equal(t, []string{"b.example.com: webhook queue is full, dropping event"}, logger.errors)
+	assert.Equal(t, Event{Record: "a.example.com"}, <-sender.queue)
+}
+
+func Test_Sender_Run_timeout(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	logger := &recordingLogger{}
+	sender := New([]string{server.URL}, nil, 0, server.Client(), logger)
+	sender.timeout = 10 * time.Millisecond
+
+	done := make(chan struct{})
+	go sender.Run(context.Background(), done)
+	sender.Send(Event{Record: "www.example.com"})
+	sender.Close()
+	<-done
+
+	assert.Equal(t, []string{"www.example.com: sending webhook 1 of 1: context deadline exceeded"},
+		logger.errors)
+}
@@ -0,0 +1,139 @@
+// Package file obtains the public IP addresses from a file, for users
+// detecting their public IP address with their own tooling, for example
+// a script or a tunnel, and writing it to a file.
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Fetcher reads the public IP addresses from a file containing an IPv4
+// address, an IPv6 address, or both separated by a space or a new line.
+// Empty or malformed content, typically from a partial write, is ignored
+// and the last valid IP addresses read are used instead.
+type Fetcher struct {
+	path     string
+	readFile func(name string) ([]byte, error)
+	mutex    sync.Mutex
+	// last contains the last valid IP addresses read,
+	// and loaded is true once valid IP addresses were read.
+	last   addresses
+	loaded bool
+}
+
+type addresses struct {
+	ipv4 netip.Addr
+	ipv6 netip.Addr
+}
+
+func New(path string) *Fetcher {
+	return &Fetcher{
+		path:     path,
+		readFile: os.ReadFile,
+	}
+}
+
+var ErrIPVersionNotFound = errors.New("IP address of the IP version requested not found")
+
+// IP returns the IPv4 address of the file,
+// or its IPv6 address if there is no IPv4 address.
+func (f *Fetcher) IP(context.Context) (publicIP netip.Addr, err error) {
+	ips, _, err := f.load()
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	if ips.ipv4.IsValid() {
+		return ips.ipv4, nil
+	}
+	return ips.ipv6, nil
+}
+
+func (f *Fetcher) IP4(context.Context) (publicIP netip.Addr, err error) {
+	ips, _, err := f.load()
+	if err != nil {
+		return netip.Addr{}, err
+	} else if !ips.ipv4.IsValid() {
+		return netip.Addr{}, fmt.Errorf("%w: no IPv4 address in %s", ErrIPVersionNotFound, f.path)
+	}
+	return ips.ipv4, nil
+}
+
+func (f *Fetcher) IP6(context.Context) (publicIP netip.Addr, err error) {
+	ips, _, err := f.load()
+	if err != nil {
+		return netip.Addr{}, err
+	} else if !ips.ipv6.IsValid() {
+		return netip.Addr{}, fmt.Errorf("%w: no IPv6 address in %s", ErrIPVersionNotFound, f.path)
+	}
+	return ips.ipv6, nil
+}
+
+// Changed reads the file and returns true if its IP addresses changed
+// since the file was last read, such that records can be updated right
+// away instead of at the next periodic update.
+func (f *Fetcher) Changed() (changed bool, err error) {
+	_, changed, err = f.load()
+	return changed, err
+}
+
+var (
+	ErrFileEmpty  = errors.New("file is empty")
+	ErrIPNotValid = errors.New("IP address is not valid")
+)
+
+// load reads and parses the file, and returns its IP addresses and
+// whether they changed since the last valid read. If the content is empty
+// or malformed, the last valid IP addresses are returned if any.
+func (f *Fetcher) load() (ips addresses, changed bool, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	data, err := f.readFile(f.path)
+	if err != nil {
+		return addresses{}, false, fmt.Errorf("reading file: %w", err)
+	}
+
+	ips, err = parse(string(data))
+	if err != nil {
+		if f.loaded {
+			return f.last, false, nil
+		}
+		return addresses{}, false, fmt.Errorf("parsing %s: %w", f.path, err)
+	}
+
+	changed = f.loaded && ips != f.last
+	f.last = ips
+	f.loaded = true
+	return ips, changed, nil
+}
+
+func parse(content string) (ips addresses, err error) {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return addresses{}, fmt.Errorf("%w", ErrFileEmpty)
+	}
+
+	for _, field := range fields {
+		ip, err := netip.ParseAddr(field)
+		if err != nil {
+			return addresses{}, fmt.Errorf("%w: %w", ErrIPNotValid, err)
+		}
+		ip = ip.Unmap()
+		switch {
+		case ip.Is4() && !ips.ipv4.IsValid():
+			ips.ipv4 = ip
+		case ip.Is6() && !ips.ipv6.IsValid():
+			ips.ipv6 = ip
+		default:
+			return addresses{}, fmt.Errorf("%w: %s is a second address of the same IP version",
+				ErrIPNotValid, ip)
+		}
+	}
+	return ips, nil
+}
@@ -0,0 +1,129 @@
+package file
+
+import (
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Fetcher(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ip")
+	writeFile := func(content string) {
+		t.Helper()
+		err := os.WriteFile(path, []byte(content), 0o600)
+		require.NoError(t, err)
+	}
+
+	fetcher := New(path)
+
+	_, err := fetcher.IP(ctx)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	writeFile("")
+	_, err = fetcher.IP(ctx)
+	assert.ErrorIs(t, err, ErrFileEmpty)
+	assert.EqualError(t, err, "parsing "+path+": file is empty")
+
+	writeFile("1.2.3.4\n")
+	ip, err := fetcher.IP(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("1.2.3.4"), ip)
+	_, err = fetcher.IP6(ctx)
+	assert.ErrorIs(t, err, ErrIPVersionNotFound)
+	assert.EqualError(t, err, "IP address of the IP version requested not found: no IPv6 address in "+path)
+
+	changed, err := fetcher.Changed()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Partial and empty writes are ignored
+	for _, content := range []string{"", "5.6.", "\n"} {
+		writeFile(content)
+		changed, err = fetcher.Changed()
+		require.NoError(t, err)
+		assert.False(t, changed)
+		ip, err = fetcher.IP4(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, netip.MustParseAddr("1.2.3.4"), ip)
+	}
+
+	writeFile("5.6.7.8 2001:db8::1\n")
+	changed, err = fetcher.Changed()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	ip, err = fetcher.IP4(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("5.6.7.8"), ip)
+	ip, err = fetcher.IP6(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("2001:db8::1"), ip)
+
+	changed, err = fetcher.Changed()
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func Test_parse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content    string
+		ips        addresses
+		errWrapped error
+		errMessage string
+	}{
+		"ipv4": {
+			content: "1.2.3.4",
+			ips:     addresses{ipv4: netip.MustParseAddr("1.2.3.4")},
+		},
+		"ipv4_mapped_ipv6": {
+			content: "::ffff:1.2.3.4\n",
+			ips:     addresses{ipv4: netip.MustParseAddr("1.2.3.4")},
+		},
+		"both_on_lines": {
+			content: "2001:db8::1\n1.2.3.4\n",
+			ips: addresses{
+				ipv4: netip.MustParseAddr("1.2.3.4"),
+				ipv6: netip.MustParseAddr("2001:db8::1"),
+			},
+		},
+		"empty": {
+			content:    " \n",
+			errWrapped: ErrFileEmpty,
+			errMessage: "file is empty",
+		},
+		"malformed": {
+			content:    "1.2.3",
+			errWrapped: ErrIPNotValid,
+			errMessage: `IP address is not valid: ParseAddr("1.2.3"): IPv4 address too short`,
+		},
+		"two_ipv4": {
+			content:    "1.2.3.4 5.6.7.8",
+			errWrapped: ErrIPNotValid,
+			errMessage: "IP address is not valid: 5.6.7.8 is a second address of the same IP version",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ips, err := parse(testCase.content)
+
+			assert.Equal(t, testCase.ips, ips)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Package iface obtains the public IP addresses assigned to a network
+// interface, for hosts with native IPv6 or a public IPv4 address, such
+// that no external service is queried.
+package iface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// Fetcher reads the addresses of a network interface and returns
+// its first global unicast address of the IP version requested.
+type Fetcher struct {
+	name           string
+	interfaceAddrs func(name string) ([]net.Addr, error)
+}
+
+func New(name string) *Fetcher {
+	return &Fetcher{
+		name:           name,
+		interfaceAddrs: interfaceAddrs,
+	}
+}
+
+func interfaceAddrs(name string) (addresses []net.Addr, err error) {
+	netInterface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return netInterface.Addrs()
+}
+
+// IP returns the global IPv4 address of the interface,
+// or its global IPv6 address if it has no global IPv4 address.
+func (f *Fetcher) IP(ctx context.Context) (publicIP netip.Addr, err error) {
+	publicIP, err = f.IP4(ctx)
+	if err == nil {
+		return publicIP, nil
+	}
+
+	publicIP, ipv6Err := f.IP6(ctx)
+	if ipv6Err != nil {
+		return netip.Addr{}, fmt.Errorf("%w; %w", err, ipv6Err)
+	}
+	return publicIP, nil
+}
+
+func (f *Fetcher) IP4(_ context.Context) (publicIP netip.Addr, err error) {
+	return f.ip(netip.Addr.Is4, "IPv4")
+}
+
+func (f *Fetcher) IP6(_ context.Context) (publicIP netip.Addr, err error) {
+	return f.ip(netip.Addr.Is6, "IPv6")
+}
+
+var ErrIPNotFound = errors.New("no global IP address found")
+
+func (f *Fetcher) ip(isVersion func(ip netip.Addr) bool, version string) (
+	publicIP netip.Addr, err error) {
+	addresses, err := f.interfaceAddrs(f.name)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("listing addresses of interface %s: %w", f.name, err)
+	}
+
+	for _, address := range addresses {
+		ipNet, ok := address.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip, ok := netip.AddrFromSlice(ipNet.IP)
+		if !ok {
+			continue
+		}
+		ip = ip.Unmap()
+		// Private addresses include IPv6 unique local addresses.
+		if isVersion(ip) && ip.IsGlobalUnicast() && !ip.IsPrivate() {
+			return ip, nil
+		}
+	}
+	return netip.Addr{}, fmt.Errorf("%w: for %s on interface %s",
+		ErrIPNotFound, version, f.name)
+}
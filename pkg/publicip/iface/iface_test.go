@@ -0,0 +1,112 @@
+package iface
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTestInterface = errors.New("no such network interface")
+
+// newTestFetcher returns a fetcher for the interface eth0 with the
+// addresses given, in CIDR notation, and failing for other interfaces.
+func newTestFetcher(t *testing.T, cidrs []string) *Fetcher {
+	t.Helper()
+	return &Fetcher{
+		name: "eth0",
+		interfaceAddrs: func(name string) ([]net.Addr, error) {
+			if cidrs == nil {
+				return nil, errTestInterface
+			}
+			assert.Equal(t, "eth0", name)
+			addresses := make([]net.Addr, len(cidrs))
+			for i, cidr := range cidrs {
+				prefix := netip.MustParsePrefix(cidr)
+				addresses[i] = &net.IPNet{
+					IP:   prefix.Addr().AsSlice(),
+					Mask: net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen()),
+				}
+			}
+			return addresses, nil
+		},
+	}
+}
+
+func Test_Fetcher_ip(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		cidrs      []string
+		ipVersion  string
+		publicIP   netip.Addr
+		errWrapped error
+		errMessage string
+	}{
+		"ipv6_global": {
+			cidrs:     []string{"192.168.1.10/24", "fe80::5/64", "fd00::5/64", "2001:db8::5/64"},
+			ipVersion: "ipv6",
+			publicIP:  netip.MustParseAddr("2001:db8::5"),
+		},
+		"ipv6_not_found": {
+			cidrs:      []string{"203.0.113.5/24", "fe80::5/64", "fd00::5/64"},
+			ipVersion:  "ipv6",
+			errWrapped: ErrIPNotFound,
+			errMessage: "no global IP address found: for IPv6 on interface eth0",
+		},
+		"ipv4_global": {
+			cidrs:     []string{"2001:db8::5/64", "10.0.0.2/8", "203.0.113.5/24"},
+			ipVersion: "ipv4",
+			publicIP:  netip.MustParseAddr("203.0.113.5"),
+		},
+		"interface_error": {
+			ipVersion:  "ipv4",
+			errWrapped: errTestInterface,
+			errMessage: "listing addresses of interface eth0: no such network interface",
+		},
+		"any_prefers_ipv4": {
+			cidrs:    []string{"2001:db8::5/64", "203.0.113.5/24"},
+			publicIP: netip.MustParseAddr("203.0.113.5"),
+		},
+		"any_falls_back_to_ipv6": {
+			cidrs:    []string{"192.168.1.10/24", "2001:db8::5/64"},
+			publicIP: netip.MustParseAddr("2001:db8::5"),
+		},
+		"any_not_found": {
+			cidrs:      []string{"192.168.1.10/24"},
+			errWrapped: ErrIPNotFound,
+			errMessage: "no global IP address found: for IPv4 on interface eth0; " +
+				"no global IP address found: for IPv6 on interface eth0",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			fetcher := newTestFetcher(t, testCase.cidrs)
+			ctx := context.Background()
+
+			var publicIP netip.Addr
+			var err error
+			switch testCase.ipVersion {
+			case "ipv4":
+				publicIP, err = fetcher.IP4(ctx)
+			case "ipv6":
+				publicIP, err = fetcher.IP6(ctx)
+			default:
+				publicIP, err = fetcher.IP(ctx)
+			}
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.publicIP, publicIP)
+		})
+	}
+}
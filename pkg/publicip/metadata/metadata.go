@@ -0,0 +1,162 @@
+// Package metadata obtains the public IP addresses from the instance
+// metadata service of cloud providers, for hosts running on a cloud
+// instance. This is the most reliable source on such instances since
+// it does not depend on any third party service.
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// baseURL is the link-local address of the instance metadata
+// service, shared by all the providers supported.
+const baseURL = "http://169.254.169.254"
+
+type Fetcher struct {
+	client    *http.Client
+	provider  Provider
+	endpoints endpoints
+	baseURL   string
+}
+
+var ErrProviderNotValid = errors.New("metadata provider is not valid")
+
+func New(client *http.Client, provider Provider) (f *Fetcher, err error) {
+	endpoints, ok := provider.endpoints()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProviderNotValid, provider)
+	}
+	return &Fetcher{
+		client:    client,
+		provider:  provider,
+		endpoints: endpoints,
+		baseURL:   baseURL,
+	}, nil
+}
+
+// IP returns the public IPv4 address of the instance,
+// or its public IPv6 address if it has no public IPv4 address.
+func (f *Fetcher) IP(ctx context.Context) (publicIP netip.Addr, err error) {
+	publicIP, err = f.IP4(ctx)
+	if err == nil {
+		return publicIP, nil
+	}
+
+	publicIP, ipv6Err := f.IP6(ctx)
+	if ipv6Err != nil {
+		return netip.Addr{}, fmt.Errorf("%w; %w", err, ipv6Err)
+	}
+	return publicIP, nil
+}
+
+func (f *Fetcher) IP4(ctx context.Context) (publicIP netip.Addr, err error) {
+	publicIP, err = f.fetch(ctx, f.endpoints.ipv4Path)
+	if err != nil {
+		return netip.Addr{}, err
+	} else if !publicIP.Is4() {
+		return netip.Addr{}, fmt.Errorf("%w: %s is not an IPv4 address",
+			ErrIPVersionMismatch, publicIP)
+	}
+	return publicIP, nil
+}
+
+func (f *Fetcher) IP6(ctx context.Context) (publicIP netip.Addr, err error) {
+	publicIP, err = f.fetch(ctx, f.endpoints.ipv6Path)
+	if err != nil {
+		return netip.Addr{}, err
+	} else if !publicIP.Is6() {
+		return netip.Addr{}, fmt.Errorf("%w: %s is not an IPv6 address",
+			ErrIPVersionMismatch, publicIP)
+	}
+	return publicIP, nil
+}
+
+var (
+	ErrHTTPStatusNotValid = errors.New("HTTP status is not valid")
+	ErrIPNotFound         = errors.New("IP address not found")
+	ErrIPNotValid         = errors.New("IP address is not valid")
+	ErrIPVersionMismatch  = errors.New("IP address is not of the IP version requested")
+	ErrIPNotGlobal        = errors.New("IP address is not a global address")
+)
+
+func (f *Fetcher) fetch(ctx context.Context, path string) (publicIP netip.Addr, err error) {
+	headers := f.endpoints.headers.Clone()
+	if f.endpoints.awsToken {
+		token, err := f.getAWSToken(ctx)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("getting session token: %w", err)
+		}
+		headers = http.Header{"X-Aws-Ec2-Metadata-Token": []string{token}}
+	}
+
+	body, err := f.do(ctx, http.MethodGet, path, headers)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("fetching %s metadata: %w", f.provider, err)
+	}
+
+	// Some endpoints list several addresses, one per line,
+	// in which case the first one is used.
+	fields := strings.Fields(body)
+	if len(fields) == 0 {
+		return netip.Addr{}, fmt.Errorf("%w: in %s metadata", ErrIPNotFound, f.provider)
+	}
+	publicIP, err = netip.ParseAddr(fields[0])
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %w", ErrIPNotValid, err)
+	}
+	publicIP = publicIP.Unmap()
+	if !publicIP.IsGlobalUnicast() || publicIP.IsPrivate() {
+		return netip.Addr{}, fmt.Errorf("%w: %s", ErrIPNotGlobal, publicIP)
+	}
+	return publicIP, nil
+}
+
+// getAWSToken obtains a session token from the AWS instance metadata
+// service version 2, which is required on instances not allowing
+// the version 1.
+func (f *Fetcher) getAWSToken(ctx context.Context) (token string, err error) {
+	const tokenTTLSeconds = "60"
+	headers := http.Header{"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": []string{tokenTTLSeconds}}
+	token, err = f.do(ctx, http.MethodPut, "/latest/api/token", headers)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(token), nil
+}
+
+func (f *Fetcher) do(ctx context.Context, method, path string,
+	headers http.Header) (body string, err error) {
+	request, err := http.NewRequestWithContext(ctx, method, f.baseURL+path, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	for key, values := range headers {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+
+	response, err := f.client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	const maxBodySize = 4096
+	b, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+	if err != nil {
+		return "", fmt.Errorf("reading response body: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: %d: %s", ErrHTTPStatusNotValid,
+			response.StatusCode, strings.TrimSpace(string(b)))
+	}
+	return string(b), nil
+}
@@ -0,0 +1,169 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer returns a metadata server responding with the body given
+// for the path given, if the request has the header given, and failing
+// for other requests. The AWS token flow is also served.
+func newTestServer(t *testing.T, path, headerKey, headerValue, body string) *httptest.Server {
+	t.Helper()
+	const awsToken = "token"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/latest/api/token" {
+			if r.Method != http.MethodPut || r.Header.Get("X-Aws-Ec2-Metadata-Token-Ttl-Seconds") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(awsToken))
+			return
+		}
+
+		if r.URL.RequestURI() != path {
+			http.NotFound(w, r)
+			return
+		}
+		if headerKey == "X-Aws-Ec2-Metadata-Token" {
+			headerValue = awsToken
+		}
+		if headerKey != "" && r.Header.Get(headerKey) != headerValue {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("missing header"))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_Fetcher(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		provider    Provider
+		ipv6        bool
+		path        string
+		headerKey   string
+		headerValue string
+		body        string
+		ip          netip.Addr
+		errWrapped  error
+		errMessage  string
+	}{
+		"aws_ipv4": {
+			provider:  AWS,
+			path:      "/latest/meta-data/public-ipv4",
+			headerKey: "X-Aws-Ec2-Metadata-Token",
+			body:      "1.2.3.4",
+			ip:        netip.MustParseAddr("1.2.3.4"),
+		},
+		"azure_ipv4": {
+			provider: Azure,
+			path: "/metadata/instance/network/interface/0/ipv4/ipAddress/0/publicIpAddress" +
+				"?api-version=2021-02-01&format=text",
+			headerKey:   "Metadata",
+			headerValue: "true",
+			body:        "1.2.3.4",
+			ip:          netip.MustParseAddr("1.2.3.4"),
+		},
+		"digitalocean_ipv4": {
+			provider: DigitalOcean,
+			path:     "/metadata/v1/interfaces/public/0/ipv4/address",
+			body:     "1.2.3.4\n",
+			ip:       netip.MustParseAddr("1.2.3.4"),
+		},
+		"digitalocean_ipv6": {
+			provider: DigitalOcean,
+			ipv6:     true,
+			path:     "/metadata/v1/interfaces/public/0/ipv6/address",
+			body:     "2001:db8::1",
+			ip:       netip.MustParseAddr("2001:db8::1"),
+		},
+		"gcp_ipv6_list": {
+			provider:    GCP,
+			ipv6:        true,
+			path:        "/computeMetadata/v1/instance/network-interfaces/0/ipv6s",
+			headerKey:   "Metadata-Flavor",
+			headerValue: "Google",
+			body:        "2001:db8::1\n2001:db8::2\n",
+			ip:          netip.MustParseAddr("2001:db8::1"),
+		},
+		"vultr_ipv4": {
+			provider: Vultr,
+			path:     "/v1/interfaces/0/ipv4/address",
+			body:     "1.2.3.4",
+			ip:       netip.MustParseAddr("1.2.3.4"),
+		},
+		"no_public_ip": {
+			provider:   DigitalOcean,
+			path:       "/metadata/v1/interfaces/public/0/ipv4/address",
+			body:       "",
+			errWrapped: ErrIPNotFound,
+			errMessage: "IP address not found: in digitalocean metadata",
+		},
+		"private_ip": {
+			provider:   Vultr,
+			path:       "/v1/interfaces/0/ipv4/address",
+			body:       "10.0.0.2",
+			errWrapped: ErrIPNotGlobal,
+			errMessage: "IP address is not a global address: 10.0.0.2",
+		},
+		"ip_version_mismatch": {
+			provider:   Vultr,
+			path:       "/v1/interfaces/0/ipv4/address",
+			body:       "2001:db8::1",
+			errWrapped: ErrIPVersionMismatch,
+			errMessage: "IP address is not of the IP version requested: 2001:db8::1 is not an IPv4 address",
+		},
+		"bad_status": { // expected header value does not match
+			provider:   GCP,
+			path:       "/computeMetadata/v1/instance/network-interfaces/0/access-configs/0/external-ip",
+			headerKey:  "Metadata-Flavor",
+			errWrapped: ErrHTTPStatusNotValid,
+			errMessage: "fetching gcp metadata: HTTP status is not valid: 401: missing header",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newTestServer(t, testCase.path, testCase.headerKey,
+				testCase.headerValue, testCase.body)
+			fetcher, err := New(server.Client(), testCase.provider)
+			require.NoError(t, err)
+			fetcher.baseURL = server.URL
+
+			fetch := fetcher.IP4
+			if testCase.ipv6 {
+				fetch = fetcher.IP6
+			}
+			ip, err := fetch(context.Background())
+
+			assert.Equal(t, testCase.ip, ip)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_New_providerNotValid(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(http.DefaultClient, "linode")
+
+	assert.ErrorIs(t, err, ErrProviderNotValid)
+	assert.EqualError(t, err, "metadata provider is not valid: linode")
+}
@@ -0,0 +1,72 @@
+package metadata
+
+import (
+	"net/http"
+)
+
+type Provider string
+
+const (
+	AWS          Provider = "aws"
+	Azure        Provider = "azure"
+	DigitalOcean Provider = "digitalocean"
+	GCP          Provider = "gcp"
+	Vultr        Provider = "vultr"
+)
+
+func ListProviders() []Provider {
+	return []Provider{
+		AWS,
+		Azure,
+		DigitalOcean,
+		GCP,
+		Vultr,
+	}
+}
+
+// endpoints contains the paths of the public IPv4 and IPv6 addresses
+// on the metadata service, and the headers to set on each request.
+type endpoints struct {
+	ipv4Path string
+	ipv6Path string
+	headers  http.Header
+	// awsToken is true if a session token must be obtained first,
+	// as required by the AWS instance metadata service version 2.
+	awsToken bool
+}
+
+func (p Provider) endpoints() (e endpoints, ok bool) {
+	switch p {
+	case AWS:
+		return endpoints{
+			ipv4Path: "/latest/meta-data/public-ipv4",
+			ipv6Path: "/latest/meta-data/ipv6",
+			awsToken: true,
+		}, true
+	case Azure:
+		const query = "?api-version=2021-02-01&format=text"
+		return endpoints{
+			ipv4Path: "/metadata/instance/network/interface/0/ipv4/ipAddress/0/publicIpAddress" + query,
+			ipv6Path: "/metadata/instance/network/interface/0/ipv6/ipAddress/0/publicIpAddress" + query,
+			headers:  http.Header{"Metadata": []string{"true"}},
+		}, true
+	case DigitalOcean:
+		return endpoints{
+			ipv4Path: "/metadata/v1/interfaces/public/0/ipv4/address",
+			ipv6Path: "/metadata/v1/interfaces/public/0/ipv6/address",
+		}, true
+	case GCP:
+		return endpoints{
+			ipv4Path: "/computeMetadata/v1/instance/network-interfaces/0/access-configs/0/external-ip",
+			ipv6Path: "/computeMetadata/v1/instance/network-interfaces/0/ipv6s",
+			headers:  http.Header{"Metadata-Flavor": []string{"Google"}},
+		}, true
+	case Vultr:
+		return endpoints{
+			ipv4Path: "/v1/interfaces/0/ipv4/address",
+			ipv6Path: "/v1/interfaces/0/ipv6/address",
+		}, true
+	default:
+		return endpoints{}, false
+	}
+}
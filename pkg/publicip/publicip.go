@@ -6,7 +6,13 @@ import (
 	"net/netip"
 
 	"github.com/qdm12/ddns-updater/pkg/publicip/dns"
+	"github.com/qdm12/ddns-updater/pkg/publicip/file"
 	"github.com/qdm12/ddns-updater/pkg/publicip/http"
+	"github.com/qdm12/ddns-updater/pkg/publicip/iface"
+	"github.com/qdm12/ddns-updater/pkg/publicip/metadata"
+	"github.com/qdm12/ddns-updater/pkg/publicip/route"
+	"github.com/qdm12/ddns-updater/pkg/publicip/stun"
+	"github.com/qdm12/ddns-updater/pkg/publicip/upnp"
 )
 
 type ipFetcher interface {
@@ -18,16 +24,27 @@ type ipFetcher interface {
 type Fetcher struct {
 	settings settings
 	fetchers []ipFetcher
+	// file is the file fetcher, and is nil if it is not enabled.
+	file *file.Fetcher
 	// Cycling effect if both are enabled
 	counter *uint32 // 32 bit for 32 bit systems
 }
 
 var ErrNoFetchTypeSpecified = errors.New("at least one fetcher type must be specified")
 
-func NewFetcher(dnsSettings DNSSettings, httpSettings HTTPSettings) (f *Fetcher, err error) {
+func NewFetcher(dnsSettings DNSSettings, httpSettings HTTPSettings,
+	routeSettings RouteSettings, fileSettings FileSettings,
+	metadataSettings MetadataSettings, stunSettings STUNSettings,
+	interfaceSettings InterfaceSettings, upnpSettings UPnPSettings) (f *Fetcher, err error) {
 	settings := settings{
-		dns:  dnsSettings,
-		http: httpSettings,
+		dns:      dnsSettings,
+		http:     httpSettings,
+		route:    routeSettings,
+		file:     fileSettings,
+		metadata: metadataSettings,
+		stun:     stunSettings,
+		iface:    interfaceSettings,
+		upnp:     upnpSettings,
 	}
 
 	fetcher := &Fetcher{
@@ -51,6 +68,39 @@ func NewFetcher(dnsSettings DNSSettings, httpSettings HTTPSettings) (f *Fetcher,
 		fetcher.fetchers = append(fetcher.fetchers, subFetcher)
 	}
 
+	if settings.route.Enabled {
+		fetcher.fetchers = append(fetcher.fetchers, route.New())
+	}
+
+	if settings.file.Enabled {
+		fetcher.file = file.New(settings.file.Path)
+		fetcher.fetchers = append(fetcher.fetchers, fetcher.file)
+	}
+
+	if settings.metadata.Enabled {
+		subFetcher, err := metadata.New(settings.metadata.Client, settings.metadata.Provider)
+		if err != nil {
+			return nil, err
+		}
+		fetcher.fetchers = append(fetcher.fetchers, subFetcher)
+	}
+
+	if settings.stun.Enabled {
+		subFetcher, err := stun.New(settings.stun.Options...)
+		if err != nil {
+			return nil, err
+		}
+		fetcher.fetchers = append(fetcher.fetchers, subFetcher)
+	}
+
+	if settings.iface.Enabled {
+		fetcher.fetchers = append(fetcher.fetchers, iface.New(settings.iface.Name))
+	}
+
+	if settings.upnp.Enabled {
+		fetcher.fetchers = append(fetcher.fetchers, upnp.New(settings.upnp.Client))
+	}
+
 	if len(fetcher.fetchers) == 0 {
 		return nil, ErrNoFetchTypeSpecified
 	}
@@ -69,3 +119,13 @@ func (f *Fetcher) IP4(ctx context.Context) (ipv4 netip.Addr, err error) {
 func (f *Fetcher) IP6(ctx context.Context) (ipv6 netip.Addr, err error) {
 	return f.getSubFetcher().IP6(ctx)
 }
+
+// FileChanged returns true if the public IP addresses of the file
+// changed since it was last read. It returns false if the file
+// fetcher is not enabled.
+func (f *Fetcher) FileChanged() (changed bool, err error) {
+	if f.file == nil {
+		return false, nil
+	}
+	return f.file.Changed()
+}
@@ -0,0 +1,91 @@
+// Package route obtains the public IP address from the local address
+// used by the default route, for hosts having a public IP address
+// assigned directly to one of their network interfaces.
+package route
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// Fetcher obtains the local address the host uses to reach the
+// internet, by opening a UDP socket to a public address and reading
+// its local address. No packet is sent since UDP is connectionless.
+type Fetcher struct {
+	dial func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+func New() *Fetcher {
+	dialer := &net.Dialer{}
+	return &Fetcher{
+		dial: dialer.DialContext,
+	}
+}
+
+const (
+	// ipv4Target and ipv6Target are public addresses used to select
+	// the default route, and are never sent any packet.
+	ipv4Target = "1.1.1.1:53"
+	ipv6Target = "[2606:4700:4700::1111]:53"
+)
+
+// IP returns the IPv4 address of the default route,
+// or its IPv6 address if there is no IPv4 default route.
+func (f *Fetcher) IP(ctx context.Context) (publicIP netip.Addr, err error) {
+	publicIP, err = f.IP4(ctx)
+	if err == nil {
+		return publicIP, nil
+	}
+
+	publicIP, ipv6Err := f.IP6(ctx)
+	if ipv6Err != nil {
+		return netip.Addr{}, fmt.Errorf("%w; %w", err, ipv6Err)
+	}
+	return publicIP, nil
+}
+
+func (f *Fetcher) IP4(ctx context.Context) (publicIP netip.Addr, err error) {
+	return f.ip(ctx, "udp4", ipv4Target)
+}
+
+func (f *Fetcher) IP6(ctx context.Context) (publicIP netip.Addr, err error) {
+	return f.ip(ctx, "udp6", ipv6Target)
+}
+
+var (
+	ErrLocalAddressNotUDP = errors.New("local address is not a UDP address")
+	ErrIPVersionMismatch  = errors.New("IP address is not of the IP version requested")
+	ErrIPNotGlobal        = errors.New("IP address is not a global address")
+)
+
+func (f *Fetcher) ip(ctx context.Context, network, target string) (
+	publicIP netip.Addr, err error) {
+	conn, err := f.dial(ctx, network, target)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("finding default route for %s: %w", network, err)
+	}
+	localAddress := conn.LocalAddr()
+	err = conn.Close()
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("closing connection: %w", err)
+	}
+
+	udpAddress, ok := localAddress.(*net.UDPAddr)
+	if !ok {
+		return netip.Addr{}, fmt.Errorf("%w: %s", ErrLocalAddressNotUDP, localAddress)
+	}
+	publicIP = udpAddress.AddrPort().Addr().Unmap()
+
+	switch {
+	case network == "udp4" && !publicIP.Is4(),
+		network == "udp6" && !publicIP.Is6():
+		return netip.Addr{}, fmt.Errorf("%w: %s for %s",
+			ErrIPVersionMismatch, publicIP, network)
+	case !publicIP.IsGlobalUnicast() || publicIP.IsPrivate():
+		return netip.Addr{}, fmt.Errorf("%w: %s", ErrIPNotGlobal, publicIP)
+	}
+	return publicIP, nil
+}
@@ -0,0 +1,146 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConn struct {
+	net.Conn
+	localAddress net.Addr
+}
+
+func (c *testConn) LocalAddr() net.Addr { return c.localAddress }
+func (c *testConn) Close() error        { return nil }
+
+var errTestDial = errors.New("network is unreachable")
+
+// newTestFetcher returns a fetcher dialing to connections with the
+// local address given for each network, and failing for other networks.
+func newTestFetcher(t *testing.T, localAddresses map[string]string) *Fetcher {
+	t.Helper()
+	return &Fetcher{
+		dial: func(_ context.Context, network, address string) (net.Conn, error) {
+			switch network {
+			case "udp4":
+				assert.Equal(t, ipv4Target, address)
+			case "udp6":
+				assert.Equal(t, ipv6Target, address)
+			}
+			localAddress, ok := localAddresses[network]
+			if !ok {
+				return nil, errTestDial
+			}
+			addrPort := netip.MustParseAddrPort(localAddress)
+			return &testConn{localAddress: net.UDPAddrFromAddrPort(addrPort)}, nil
+		},
+	}
+}
+
+func Test_Fetcher_ip(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		localAddresses map[string]string
+		ipVersion      string
+		publicIP       netip.Addr
+		errWrapped     error
+		errMessage     string
+	}{
+		"ipv4_global": {
+			localAddresses: map[string]string{"udp4": "203.0.113.5:50000"},
+			ipVersion:      "ipv4",
+			publicIP:       netip.MustParseAddr("203.0.113.5"),
+		},
+		"ipv4_mapped_global": {
+			localAddresses: map[string]string{"udp4": "[::ffff:203.0.113.5]:50000"},
+			ipVersion:      "ipv4",
+			publicIP:       netip.MustParseAddr("203.0.113.5"),
+		},
+		"ipv4_private": {
+			localAddresses: map[string]string{"udp4": "192.168.1.10:50000"},
+			ipVersion:      "ipv4",
+			errWrapped:     ErrIPNotGlobal,
+			errMessage:     "IP address is not a global address: 192.168.1.10",
+		},
+		"ipv4_no_route": {
+			ipVersion:  "ipv4",
+			errWrapped: errTestDial,
+			errMessage: "finding default route for udp4: network is unreachable",
+		},
+		"ipv6_global": {
+			localAddresses: map[string]string{"udp6": "[2001:db8::5]:50000"},
+			ipVersion:      "ipv6",
+			publicIP:       netip.MustParseAddr("2001:db8::5"),
+		},
+		"ipv6_unique_local": {
+			localAddresses: map[string]string{"udp6": "[fd00::5]:50000"},
+			ipVersion:      "ipv6",
+			errWrapped:     ErrIPNotGlobal,
+			errMessage:     "IP address is not a global address: fd00::5",
+		},
+		"ipv6_link_local": {
+			localAddresses: map[string]string{"udp6": "[fe80::5]:50000"},
+			ipVersion:      "ipv6",
+			errWrapped:     ErrIPNotGlobal,
+			errMessage:     "IP address is not a global address: fe80::5",
+		},
+		"ipv6_family_mismatch": {
+			localAddresses: map[string]string{"udp6": "203.0.113.5:50000"},
+			ipVersion:      "ipv6",
+			errWrapped:     ErrIPVersionMismatch,
+			errMessage:     "IP address is not of the IP version requested: 203.0.113.5 for udp6",
+		},
+		"any_prefers_ipv4": {
+			localAddresses: map[string]string{
+				"udp4": "203.0.113.5:50000",
+				"udp6": "[2001:db8::5]:50000",
+			},
+			publicIP: netip.MustParseAddr("203.0.113.5"),
+		},
+		"any_falls_back_to_ipv6": {
+			localAddresses: map[string]string{
+				"udp4": "10.0.0.2:50000",
+				"udp6": "[2001:db8::5]:50000",
+			},
+			publicIP: netip.MustParseAddr("2001:db8::5"),
+		},
+		"any_no_route": {
+			errWrapped: errTestDial,
+			errMessage: "finding default route for udp4: network is unreachable; " +
+				"finding default route for udp6: network is unreachable",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			fetcher := newTestFetcher(t, testCase.localAddresses)
+			ctx := context.Background()
+
+			var publicIP netip.Addr
+			var err error
+			switch testCase.ipVersion {
+			case "ipv4":
+				publicIP, err = fetcher.IP4(ctx)
+			case "ipv6":
+				publicIP, err = fetcher.IP6(ctx)
+			default:
+				publicIP, err = fetcher.IP(ctx)
+			}
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.publicIP, publicIP)
+		})
+	}
+}
@@ -5,12 +5,20 @@ import (
 
 	"github.com/qdm12/ddns-updater/pkg/publicip/dns"
 	iphttp "github.com/qdm12/ddns-updater/pkg/publicip/http"
+	"github.com/qdm12/ddns-updater/pkg/publicip/metadata"
+	"github.com/qdm12/ddns-updater/pkg/publicip/stun"
 )
 
 type settings struct {
-	// If both dns and http are enabled it will cycle between both of them.
-	dns  DNSSettings
-	http HTTPSettings
+	// If several fetchers are enabled it will cycle between them.
+	dns      DNSSettings
+	http     HTTPSettings
+	route    RouteSettings
+	file     FileSettings
+	metadata MetadataSettings
+	stun     STUNSettings
+	iface    InterfaceSettings
+	upnp     UPnPSettings
 }
 
 type DNSSettings struct {
@@ -23,3 +31,35 @@ type HTTPSettings struct {
 	Client  *http.Client
 	Options []iphttp.Option
 }
+
+type RouteSettings struct {
+	Enabled bool
+}
+
+type FileSettings struct {
+	Enabled bool
+	// Path is the path of the file containing the public IP addresses.
+	Path string
+}
+
+type MetadataSettings struct {
+	Enabled  bool
+	Client   *http.Client
+	Provider metadata.Provider
+}
+
+type STUNSettings struct {
+	Enabled bool
+	Options []stun.Option
+}
+
+type InterfaceSettings struct {
+	Enabled bool
+	// Name is the name of the network interface, for example eth0.
+	Name string
+}
+
+type UPnPSettings struct {
+	Enabled bool
+	Client  *http.Client
+}
@@ -0,0 +1,118 @@
+package stun
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// IP returns the public IPv4 address,
+// or the public IPv6 address if no IPv4 address is found.
+func (f *Fetcher) IP(ctx context.Context) (publicIP netip.Addr, err error) {
+	publicIP, err = f.IP4(ctx)
+	if err == nil {
+		return publicIP, nil
+	}
+
+	publicIP, ipv6Err := f.IP6(ctx)
+	if ipv6Err != nil {
+		return netip.Addr{}, fmt.Errorf("%w; %w", err, ipv6Err)
+	}
+	return publicIP, nil
+}
+
+func (f *Fetcher) IP4(ctx context.Context) (publicIP netip.Addr, err error) {
+	return f.ip(ctx, "udp4")
+}
+
+func (f *Fetcher) IP6(ctx context.Context) (publicIP netip.Addr, err error) {
+	return f.ip(ctx, "udp6")
+}
+
+var ErrIPVersionMismatch = errors.New("IP address is not of the IP version requested")
+
+func (f *Fetcher) ip(ctx context.Context, network string) (
+	publicIP netip.Addr, err error) {
+	index := int(atomic.AddUint32(f.ring.counter, 1)) % len(f.ring.servers)
+	server := f.ring.servers[index]
+
+	publicIP, err = f.fetch(ctx, network, server)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("STUN server %s: %w", server, err)
+	}
+
+	publicIP = publicIP.Unmap()
+	if (network == "udp4") != publicIP.Is4() {
+		return netip.Addr{}, fmt.Errorf("%w: %s for %s",
+			ErrIPVersionMismatch, publicIP, network)
+	}
+	return publicIP, nil
+}
+
+// fetch sends a binding request to the server and returns the
+// IP address it received the request from. Responses not matching
+// the request are ignored until the timeout expires.
+func (f *Fetcher) fetch(ctx context.Context, network, server string) (
+	publicIP netip.Addr, err error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	conn, err := f.dial(ctx, network, server)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("dialing: %w", err)
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("setting deadline: %w", err)
+	}
+	// Unblock reads if the parent context is canceled before the deadline.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	var id transactionID
+	_, err = rand.Read(id[:])
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("generating transaction ID: %w", err)
+	}
+
+	_, err = conn.Write(newBindingRequest(id))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("sending binding request: %w", err)
+	}
+
+	const maxMessageSize = 1500
+	buffer := make([]byte, maxMessageSize)
+	for {
+		n, err := conn.Read(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			} else if errors.Is(err, os.ErrDeadlineExceeded) {
+				// the read deadline is the context deadline
+				err = context.DeadlineExceeded
+			}
+			return netip.Addr{}, fmt.Errorf("reading binding response: %w", err)
+		}
+
+		publicIP, err = parseBindingResponse(buffer[:n], id)
+		switch {
+		case errors.Is(err, ErrMessageNotSTUN),
+			errors.Is(err, ErrMessageTooShort),
+			errors.Is(err, ErrTransactionIDMismatch):
+			continue // stray datagram
+		case err != nil:
+			return netip.Addr{}, fmt.Errorf("parsing binding response: %w", err)
+		}
+		return publicIP, nil
+	}
+}
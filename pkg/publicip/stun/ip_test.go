@@ -0,0 +1,96 @@
+package stun
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runTestServer runs a STUN server on the loopback interface answering
+// binding requests with the IP address given, after first sending a
+// response with a wrong transaction ID. It returns the server address.
+func runTestServer(t *testing.T, ip netip.Addr) (address string) {
+	t.Helper()
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 1500) //nolint:gomnd
+		for {
+			n, clientAddress, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			var id transactionID
+			copy(id[:], buffer[8:n])
+			stray := makeResponse(bindingSuccess, transactionID{},
+				addressAttribute(netip.MustParseAddr("192.0.2.1"), transactionID{}, true))
+			_, _ = conn.WriteTo(stray, clientAddress)
+			response := makeResponse(bindingSuccess, id, addressAttribute(ip, id, true))
+			_, _ = conn.WriteTo(response, clientAddress)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func Test_Fetcher_IP4(t *testing.T) {
+	t.Parallel()
+
+	publicIP := netip.MustParseAddr("203.0.113.5")
+	address := runTestServer(t, publicIP)
+	fetcher, err := New(SetServers(address), SetTimeout(time.Second))
+	require.NoError(t, err)
+
+	ip, err := fetcher.IP4(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, publicIP, ip)
+}
+
+func Test_Fetcher_IP4_versionMismatch(t *testing.T) {
+	t.Parallel()
+
+	address := runTestServer(t, netip.MustParseAddr("2001:db8::5"))
+	fetcher, err := New(SetServers(address), SetTimeout(time.Second))
+	require.NoError(t, err)
+
+	ip, err := fetcher.IP4(context.Background())
+
+	assert.ErrorIs(t, err, ErrIPVersionMismatch)
+	assert.EqualError(t, err, "IP address is not of the IP version requested: 2001:db8::5 for udp4")
+	assert.Equal(t, netip.Addr{}, ip)
+}
+
+func Test_Fetcher_IP4_timeout(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	address := conn.LocalAddr().String()
+	fetcher, err := New(SetServers(address), SetTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	_, err = fetcher.IP4(context.Background())
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.EqualError(t, err, "STUN server "+address+
+		": reading binding response: context deadline exceeded")
+}
+
+func Test_SetServers(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(SetServers("stun.example.com"))
+
+	assert.ErrorIs(t, err, ErrServerNotValid)
+	assert.EqualError(t, err, "STUN server address is not valid: "+
+		"address stun.example.com: missing port in address")
+}
@@ -0,0 +1,141 @@
+package stun
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+)
+
+const (
+	headerLength = 20
+	magicCookie  = 0x2112A442
+
+	bindingRequest       = 0x0001
+	bindingSuccess       = 0x0101
+	bindingError         = 0x0111
+	attrMappedAddress    = 0x0001
+	attrXORMappedAddress = 0x0020
+
+	familyIPv4 = 0x01
+	familyIPv6 = 0x02
+)
+
+type transactionID [12]byte
+
+// newBindingRequest returns a binding request message without
+// any attribute, with the transaction ID given.
+func newBindingRequest(id transactionID) (message []byte) {
+	message = make([]byte, headerLength)
+	binary.BigEndian.PutUint16(message[0:2], bindingRequest)
+	binary.BigEndian.PutUint16(message[2:4], 0) // attributes length
+	binary.BigEndian.PutUint32(message[4:8], magicCookie)
+	copy(message[8:20], id[:])
+	return message
+}
+
+var (
+	ErrMessageTooShort       = errors.New("message is too short")
+	ErrMessageNotSTUN        = errors.New("message is not a STUN message")
+	ErrTransactionIDMismatch = errors.New("transaction ID does not match")
+	ErrBindingFailed         = errors.New("binding request failed")
+	ErrMessageTypeUnexpected = errors.New("message type is unexpected")
+	ErrAttributeMalformed    = errors.New("attribute is malformed")
+	ErrAddressNotFound       = errors.New("mapped address not found")
+)
+
+// parseBindingResponse parses the binding response given and returns
+// the IP address from its XOR-MAPPED-ADDRESS attribute, or from its
+// MAPPED-ADDRESS attribute for older servers.
+func parseBindingResponse(message []byte, id transactionID) (ip netip.Addr, err error) {
+	if len(message) < headerLength {
+		return netip.Addr{}, fmt.Errorf("%w: %d bytes", ErrMessageTooShort, len(message))
+	}
+
+	messageType := binary.BigEndian.Uint16(message[0:2])
+	length := int(binary.BigEndian.Uint16(message[2:4]))
+	switch {
+	case binary.BigEndian.Uint32(message[4:8]) != magicCookie,
+		length%4 != 0, headerLength+length > len(message):
+		return netip.Addr{}, fmt.Errorf("%w", ErrMessageNotSTUN)
+	case !bytes.Equal(message[8:20], id[:]):
+		return netip.Addr{}, fmt.Errorf("%w", ErrTransactionIDMismatch)
+	case messageType == bindingError:
+		return netip.Addr{}, fmt.Errorf("%w", ErrBindingFailed)
+	case messageType != bindingSuccess:
+		return netip.Addr{}, fmt.Errorf("%w: 0x%04x", ErrMessageTypeUnexpected, messageType)
+	}
+
+	var mappedIP, xorMappedIP netip.Addr
+	attributes := message[headerLength : headerLength+length]
+	for len(attributes) > 0 {
+		const attributeHeaderLength = 4
+		if len(attributes) < attributeHeaderLength {
+			return netip.Addr{}, fmt.Errorf("%w: header is truncated", ErrAttributeMalformed)
+		}
+		attributeType := binary.BigEndian.Uint16(attributes[0:2])
+		attributeLength := int(binary.BigEndian.Uint16(attributes[2:4]))
+		paddedLength := (attributeLength + 3) &^ 3 //nolint:gomnd
+		if attributeHeaderLength+attributeLength > len(attributes) {
+			return netip.Addr{}, fmt.Errorf("%w: value is truncated", ErrAttributeMalformed)
+		}
+		value := attributes[attributeHeaderLength : attributeHeaderLength+attributeLength]
+
+		switch attributeType {
+		case attrMappedAddress:
+			mappedIP, err = parseAddress(value, nil)
+		case attrXORMappedAddress:
+			xorMappedIP, err = parseAddress(value, message[4:20])
+		}
+		if err != nil {
+			return netip.Addr{}, err
+		}
+
+		attributes = attributes[min(attributeHeaderLength+paddedLength, len(attributes)):]
+	}
+
+	switch {
+	case xorMappedIP.IsValid():
+		return xorMappedIP, nil
+	case mappedIP.IsValid():
+		return mappedIP, nil
+	default:
+		return netip.Addr{}, fmt.Errorf("%w", ErrAddressNotFound)
+	}
+}
+
+// parseAddress parses the value of a MAPPED-ADDRESS attribute, or
+// of a XOR-MAPPED-ADDRESS attribute if the mask is not nil, in which
+// case the mask is the magic cookie followed by the transaction ID.
+func parseAddress(value, mask []byte) (ip netip.Addr, err error) {
+	const addressOffset = 4 // reserved byte, family byte and port
+	if len(value) < addressOffset {
+		return netip.Addr{}, fmt.Errorf("%w: address is truncated", ErrAttributeMalformed)
+	}
+
+	var addressLength int
+	switch family := value[1]; family {
+	case familyIPv4:
+		addressLength = 4 //nolint:gomnd
+	case familyIPv6:
+		addressLength = 16 //nolint:gomnd
+	default:
+		return netip.Addr{}, fmt.Errorf("%w: address family 0x%02x is unknown",
+			ErrAttributeMalformed, family)
+	}
+	if len(value) != addressOffset+addressLength {
+		return netip.Addr{}, fmt.Errorf("%w: address has %d bytes instead of %d",
+			ErrAttributeMalformed, len(value)-addressOffset, addressLength)
+	}
+
+	address := make([]byte, addressLength)
+	copy(address, value[addressOffset:])
+	if mask != nil {
+		for i := range address {
+			address[i] ^= mask[i]
+		}
+	}
+	ip, _ = netip.AddrFromSlice(address)
+	return ip, nil
+}
@@ -0,0 +1,142 @@
+package stun
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// makeResponse returns a binding response with the message type,
+// transaction ID and attributes given, each attribute being its type
+// followed by its value.
+func makeResponse(messageType uint16, id transactionID, attributes ...[]byte) []byte {
+	message := make([]byte, headerLength)
+	binary.BigEndian.PutUint16(message[0:2], messageType)
+	binary.BigEndian.PutUint32(message[4:8], magicCookie)
+	copy(message[8:20], id[:])
+	for _, attribute := range attributes {
+		header := make([]byte, 4) //nolint:gomnd
+		copy(header[0:2], attribute[0:2])
+		binary.BigEndian.PutUint16(header[2:4], uint16(len(attribute)-2))
+		message = append(message, header...)
+		message = append(message, attribute[2:]...)
+		for len(message)%4 != 0 {
+			message = append(message, 0)
+		}
+	}
+	binary.BigEndian.PutUint16(message[2:4], uint16(len(message)-headerLength))
+	return message
+}
+
+// addressAttribute returns a MAPPED-ADDRESS attribute, or a
+// XOR-MAPPED-ADDRESS attribute if xor is true, for the IP address
+// and transaction ID given.
+func addressAttribute(ip netip.Addr, id transactionID, xor bool) []byte {
+	attributeType := uint16(attrMappedAddress)
+	family := byte(familyIPv4)
+	if ip.Is6() {
+		family = familyIPv6
+	}
+	address := ip.AsSlice()
+	if xor {
+		attributeType = attrXORMappedAddress
+		mask := binary.BigEndian.AppendUint32(nil, magicCookie)
+		mask = append(mask, id[:]...)
+		for i := range address {
+			address[i] ^= mask[i]
+		}
+	}
+	attribute := binary.BigEndian.AppendUint16(nil, attributeType)
+	attribute = append(attribute, 0, family, 0, 0)
+	return append(attribute, address...)
+}
+
+func Test_parseBindingResponse(t *testing.T) {
+	t.Parallel()
+
+	id := transactionID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	otherID := transactionID{12}
+	ipv4 := netip.MustParseAddr("203.0.113.5")
+	ipv6 := netip.MustParseAddr("2001:db8::5")
+	software := append([]byte{0x80, 0x22}, "server"...)
+
+	testCases := map[string]struct {
+		message    []byte
+		ip         netip.Addr
+		errWrapped error
+		errMessage string
+	}{
+		"too_short": {
+			message:    make([]byte, 19),
+			errWrapped: ErrMessageTooShort,
+			errMessage: "message is too short: 19 bytes",
+		},
+		"not_stun": {
+			message:    make([]byte, headerLength),
+			errWrapped: ErrMessageNotSTUN,
+			errMessage: "message is not a STUN message",
+		},
+		"transaction_id_mismatch": {
+			message:    makeResponse(bindingSuccess, otherID, addressAttribute(ipv4, otherID, true)),
+			errWrapped: ErrTransactionIDMismatch,
+			errMessage: "transaction ID does not match",
+		},
+		"error_response": {
+			message:    makeResponse(bindingError, id),
+			errWrapped: ErrBindingFailed,
+			errMessage: "binding request failed",
+		},
+		"unexpected_type": {
+			message:    makeResponse(bindingRequest, id),
+			errWrapped: ErrMessageTypeUnexpected,
+			errMessage: "message type is unexpected: 0x0001",
+		},
+		"no_address": {
+			message:    makeResponse(bindingSuccess, id, software),
+			errWrapped: ErrAddressNotFound,
+			errMessage: "mapped address not found",
+		},
+		"unknown_family": {
+			message: makeResponse(bindingSuccess, id,
+				[]byte{0x00, 0x20, 0, 3, 0, 0, 1, 2, 3, 4}),
+			errWrapped: ErrAttributeMalformed,
+			errMessage: "attribute is malformed: address family 0x03 is unknown",
+		},
+		"xor_mapped_ipv4": {
+			message: makeResponse(bindingSuccess, id, software,
+				addressAttribute(ipv4, id, true)),
+			ip: ipv4,
+		},
+		"xor_mapped_ipv6": {
+			message: makeResponse(bindingSuccess, id, addressAttribute(ipv6, id, true)),
+			ip:      ipv6,
+		},
+		"mapped_only": {
+			message: makeResponse(bindingSuccess, id, addressAttribute(ipv4, id, false)),
+			ip:      ipv4,
+		},
+		"xor_mapped_preferred": {
+			message: makeResponse(bindingSuccess, id,
+				addressAttribute(netip.MustParseAddr("10.0.0.1"), id, false),
+				addressAttribute(ipv4, id, true)),
+			ip: ipv4,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ip, err := parseBindingResponse(testCase.message, id)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.ip, ip)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package stun
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+type settings struct {
+	servers []string
+	timeout time.Duration
+}
+
+func newDefaultSettings() settings {
+	const defaultTimeout = 3 * time.Second
+	return settings{
+		servers: []string{
+			"stun.l.google.com:19302",
+			"stun.cloudflare.com:3478",
+		},
+		timeout: defaultTimeout,
+	}
+}
+
+type Option func(s *settings) error
+
+var ErrServerNotValid = errors.New("STUN server address is not valid")
+
+// SetServers sets the STUN server addresses to use, each in
+// the form host:port. The servers are used in turn.
+func SetServers(first string, servers ...string) Option {
+	return func(s *settings) (err error) {
+		servers = append([]string{first}, servers...)
+		for _, server := range servers {
+			host, port, err := net.SplitHostPort(server)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrServerNotValid, err)
+			} else if host == "" || port == "" {
+				return fmt.Errorf("%w: %s", ErrServerNotValid, server)
+			}
+		}
+		s.servers = servers
+		return nil
+	}
+}
+
+func SetTimeout(timeout time.Duration) Option {
+	return func(s *settings) (err error) {
+		s.timeout = timeout
+		return nil
+	}
+}
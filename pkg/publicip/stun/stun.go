@@ -0,0 +1,42 @@
+// Package stun obtains the public IP address with STUN binding
+// requests over UDP, as described in RFC 5389. It is faster than
+// HTTP echo services and is not subject to their rate limits.
+package stun
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+type Fetcher struct {
+	ring    ring
+	timeout time.Duration
+	dial    func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+type ring struct {
+	// counter is used to get an index in the servers slice
+	counter *uint32 // uint32 for 32 bit systems atomic operations
+	servers []string
+}
+
+func New(options ...Option) (f *Fetcher, err error) {
+	settings := newDefaultSettings()
+	for _, option := range options {
+		err = option(&settings)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dialer := &net.Dialer{}
+	return &Fetcher{
+		ring: ring{
+			counter: new(uint32),
+			servers: settings.servers,
+		},
+		timeout: settings.timeout,
+		dial:    dialer.DialContext,
+	}, nil
+}
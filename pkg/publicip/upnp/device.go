@@ -0,0 +1,103 @@
+package upnp
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// service is a WAN connection service of an internet gateway device.
+type service struct {
+	serviceType string
+	controlURL  string
+}
+
+type device struct {
+	Services []struct {
+		ServiceType string `xml:"serviceType"`
+		ControlURL  string `xml:"controlURL"`
+	} `xml:"serviceList>service"`
+	Devices []device `xml:"deviceList>device"`
+}
+
+// findWANService returns the first WAN IP or PPP connection
+// service of the device or of its embedded devices.
+func (d device) findWANService() (serviceType, controlURL string, found bool) {
+	for _, service := range d.Services {
+		if strings.HasPrefix(service.ServiceType, "urn:schemas-upnp-org:service:WANIPConnection:") ||
+			strings.HasPrefix(service.ServiceType, "urn:schemas-upnp-org:service:WANPPPConnection:") {
+			return service.ServiceType, service.ControlURL, true
+		}
+	}
+	for _, embedded := range d.Devices {
+		serviceType, controlURL, found = embedded.findWANService()
+		if found {
+			return serviceType, controlURL, true
+		}
+	}
+	return "", "", false
+}
+
+var (
+	ErrHTTPStatusNotValid = errors.New("HTTP status is not valid")
+	ErrServiceNotFound    = errors.New("WAN connection service not found")
+)
+
+// fetchService fetches the device description at the location given
+// and returns its WAN connection service, with an absolute control URL.
+func fetchService(ctx context.Context, client *http.Client, location string) (
+	wanService *service, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d", ErrHTTPStatusNotValid, response.StatusCode)
+	}
+
+	const maxDescriptionSize = 1 << 20
+	var description struct {
+		URLBase string `xml:"URLBase"`
+		Device  device `xml:"device"`
+	}
+	decoder := xml.NewDecoder(io.LimitReader(response.Body, maxDescriptionSize))
+	err = decoder.Decode(&description)
+	if err != nil {
+		return nil, fmt.Errorf("decoding device description: %w", err)
+	}
+
+	serviceType, controlURL, found := description.Device.findWANService()
+	if !found {
+		return nil, fmt.Errorf("%w", ErrServiceNotFound)
+	}
+
+	base := location
+	if description.URLBase != "" {
+		base = description.URLBase
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("parsing base URL: %w", err)
+	}
+	relativeControlURL, err := url.Parse(controlURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing control URL: %w", err)
+	}
+
+	return &service{
+		serviceType: serviceType,
+		controlURL:  baseURL.ResolveReference(relativeControlURL).String(),
+	}, nil
+}
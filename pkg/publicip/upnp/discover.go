@@ -0,0 +1,97 @@
+package upnp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+)
+
+// searchTargets are the SSDP search targets for internet gateway
+// devices, for both versions 1 and 2 of the specification.
+var searchTargets = []string{ //nolint:gochecknoglobals
+	"urn:schemas-upnp-org:device:InternetGatewayDevice:1",
+	"urn:schemas-upnp-org:device:InternetGatewayDevice:2",
+}
+
+// discover sends SSDP search requests on the local network and returns
+// the device description locations of the internet gateway devices
+// which answered within the timeout given.
+func discover(ctx context.Context, timeout time.Duration) (locations []string, err error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, fmt.Errorf("listening: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return nil, fmt.Errorf("setting deadline: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	multicastAddress := &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900} //nolint:gomnd
+	for _, searchTarget := range searchTargets {
+		_, err = conn.WriteTo(makeSearchRequest(searchTarget), multicastAddress)
+		if err != nil {
+			return nil, fmt.Errorf("sending search request: %w", err)
+		}
+	}
+
+	const maxMessageSize = 2048
+	buffer := make([]byte, maxMessageSize)
+	for {
+		n, _, err := conn.ReadFrom(buffer)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return locations, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("reading search response: %w", err)
+		}
+
+		location, ok := parseSearchResponse(buffer[:n])
+		if ok && !slices.Contains(locations, location) {
+			locations = append(locations, location)
+		}
+	}
+}
+
+func makeSearchRequest(searchTarget string) []byte {
+	return []byte("M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: 239.255.255.250:1900\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 1\r\n" +
+		"ST: " + searchTarget + "\r\n" +
+		"\r\n")
+}
+
+// parseSearchResponse returns the device description location from
+// the SSDP search response given, and false if it is not a valid
+// search response.
+func parseSearchResponse(message []byte) (location string, ok bool) {
+	response, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(message)), nil)
+	if err != nil {
+		return "", false
+	}
+	_ = response.Body.Close()
+	location = response.Header.Get("Location")
+	if response.StatusCode != http.StatusOK || location == "" {
+		return "", false
+	}
+	return location, true
+}
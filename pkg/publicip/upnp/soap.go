@@ -0,0 +1,70 @@
+package upnp
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+var (
+	ErrIPNotFound = errors.New("IP address not found")
+	ErrIPNotValid = errors.New("IP address is not valid")
+)
+
+// getExternalIPAddress calls the GetExternalIPAddress
+// action of the WAN connection service given.
+func getExternalIPAddress(ctx context.Context, client *http.Client,
+	wanService service) (ip netip.Addr, err error) {
+	body := `<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
+		`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">` +
+		`<s:Body><u:GetExternalIPAddress xmlns:u="` + wanService.serviceType + `"/></s:Body>` +
+		`</s:Envelope>`
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		wanService.controlURL, strings.NewReader(body))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	request.Header.Set("SOAPAction", `"`+wanService.serviceType+`#GetExternalIPAddress"`)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return netip.Addr{}, fmt.Errorf("%w: %d", ErrHTTPStatusNotValid, response.StatusCode)
+	}
+
+	const maxResponseSize = 1 << 16
+	var envelope struct {
+		Body struct {
+			Response struct {
+				NewExternalIPAddress string `xml:"NewExternalIPAddress"`
+			} `xml:"GetExternalIPAddressResponse"`
+		} `xml:"Body"`
+	}
+	decoder := xml.NewDecoder(io.LimitReader(response.Body, maxResponseSize))
+	err = decoder.Decode(&envelope)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("decoding response: %w", err)
+	}
+
+	s := strings.TrimSpace(envelope.Body.Response.NewExternalIPAddress)
+	if s == "" {
+		// the router has no WAN connection, or is not connected yet
+		return netip.Addr{}, fmt.Errorf("%w", ErrIPNotFound)
+	}
+	ip, err = netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %w", ErrIPNotValid, err)
+	}
+	return ip, nil
+}
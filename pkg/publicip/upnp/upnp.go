@@ -0,0 +1,100 @@
+// Package upnp obtains the public IPv4 address from the local router,
+// using the GetExternalIPAddress action of its UPnP Internet Gateway
+// Device WAN connection service. This requires no remote service and
+// has no rate limit, but only works on networks where the router has
+// UPnP enabled and holds the public IPv4 address.
+package upnp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+type Fetcher struct {
+	client   *http.Client
+	discover func(ctx context.Context) (locations []string, err error)
+	// service is the WAN connection service found, cached
+	// until it fails, and is protected by the mutex.
+	service *service
+	mutex   sync.Mutex
+}
+
+func New(client *http.Client) *Fetcher {
+	const discoveryTimeout = 2 * time.Second
+	return &Fetcher{
+		client: client,
+		discover: func(ctx context.Context) (locations []string, err error) {
+			return discover(ctx, discoveryTimeout)
+		},
+	}
+}
+
+// IP returns the public IPv4 address, since the
+// UPnP Internet Gateway Device only reports IPv4.
+func (f *Fetcher) IP(ctx context.Context) (publicIP netip.Addr, err error) {
+	return f.IP4(ctx)
+}
+
+var ErrIPv6NotSupported = errors.New("IPv6 is not supported by UPnP")
+
+func (f *Fetcher) IP6(context.Context) (publicIP netip.Addr, err error) {
+	return netip.Addr{}, fmt.Errorf("%w", ErrIPv6NotSupported)
+}
+
+var ErrIPNotGlobal = errors.New("IP address is not a global address")
+
+func (f *Fetcher) IP4(ctx context.Context) (publicIP netip.Addr, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.service == nil {
+		f.service, err = f.findService(ctx)
+		if err != nil {
+			return netip.Addr{}, err
+		}
+	}
+
+	publicIP, err = getExternalIPAddress(ctx, f.client, *f.service)
+	if err != nil {
+		// the router may have restarted with a different control URL
+		f.service = nil
+		return netip.Addr{}, fmt.Errorf("getting external IP address: %w", err)
+	}
+
+	publicIP = publicIP.Unmap()
+	if !publicIP.Is4() || !publicIP.IsGlobalUnicast() || publicIP.IsPrivate() {
+		// for example if the router is itself behind another router
+		return netip.Addr{}, fmt.Errorf("%w: %s", ErrIPNotGlobal, publicIP)
+	}
+	return publicIP, nil
+}
+
+var ErrGatewayNotFound = errors.New("no UPnP internet gateway device found")
+
+// findService discovers the internet gateway devices on the local network
+// and returns the first WAN connection service found.
+func (f *Fetcher) findService(ctx context.Context) (wanService *service, err error) {
+	locations, err := f.discover(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("discovering gateway: %w", err)
+	}
+
+	errs := make([]error, 0, len(locations))
+	for _, location := range locations {
+		wanService, err = fetchService(ctx, f.client, location)
+		if err == nil {
+			return wanService, nil
+		}
+		errs = append(errs, fmt.Errorf("device %s: %w", location, err))
+	}
+
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("%w", ErrGatewayNotFound)
+	}
+	return nil, fmt.Errorf("%w: %w", ErrGatewayNotFound, errors.Join(errs...))
+}
@@ -0,0 +1,210 @@
+package upnp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testServiceType = "urn:schemas-upnp-org:service:WANIPConnection:1"
+	// testDescription is an internet gateway device description with
+	// its WAN connection service in an embedded device, as most routers.
+	testDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+    <serviceList>
+      <service>
+        <serviceType>urn:schemas-upnp-org:service:Layer3Forwarding:1</serviceType>
+        <controlURL>/ctl/L3F</controlURL>
+      </service>
+    </serviceList>
+    <deviceList>
+      <device>
+        <deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+        <deviceList>
+          <device>
+            <deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+            <serviceList>
+              <service>
+                <serviceType>` + testServiceType + `</serviceType>
+                <controlURL>ctl/IPConn</controlURL>
+              </service>
+            </serviceList>
+          </device>
+        </deviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+)
+
+// newTestServer returns a router serving the device description given
+// at /rootDesc.xml and responding to GetExternalIPAddress with the IP
+// address given.
+func newTestServer(t *testing.T, description, externalIP string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rootDesc.xml":
+			_, _ = w.Write([]byte(description))
+		case "/ctl/IPConn":
+			body, _ := io.ReadAll(r.Body)
+			if r.Method != http.MethodPost ||
+				r.Header.Get("SOAPAction") != `"`+testServiceType+`#GetExternalIPAddress"` ||
+				!strings.Contains(string(body), `<u:GetExternalIPAddress xmlns:u="`+testServiceType+`"/>`) {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte(`<?xml version="1.0"?>` +
+				`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>` +
+				`<u:GetExternalIPAddressResponse xmlns:u="` + testServiceType + `">` +
+				`<NewExternalIPAddress>` + externalIP + `</NewExternalIPAddress>` +
+				`</u:GetExternalIPAddressResponse></s:Body></s:Envelope>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_Fetcher_IP4(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		description string
+		externalIP  string
+		noGateway   bool
+		publicIP    netip.Addr
+		errWrapped  error
+		errMessage  string
+	}{
+		"success": {
+			description: testDescription,
+			externalIP:  "203.0.113.5",
+			publicIP:    netip.MustParseAddr("203.0.113.5"),
+		},
+		"behind_another_router": {
+			description: testDescription,
+			externalIP:  "192.168.0.2",
+			errWrapped:  ErrIPNotGlobal,
+			errMessage:  "IP address is not a global address: 192.168.0.2",
+		},
+		"not_connected": {
+			description: testDescription,
+			errWrapped:  ErrIPNotFound,
+			errMessage:  "getting external IP address: IP address not found",
+		},
+		"no_wan_service": {
+			description: `<root><device><serviceList></serviceList></device></root>`,
+			errWrapped:  ErrServiceNotFound,
+			errMessage: "no UPnP internet gateway device found: device {url}/rootDesc.xml: " +
+				"WAN connection service not found",
+		},
+		"no_gateway": {
+			noGateway:  true,
+			errWrapped: ErrGatewayNotFound,
+			errMessage: "no UPnP internet gateway device found",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newTestServer(t, testCase.description, testCase.externalIP)
+			fetcher := New(server.Client())
+			fetcher.discover = func(context.Context) (locations []string, err error) {
+				if testCase.noGateway {
+					return nil, nil
+				}
+				return []string{server.URL + "/rootDesc.xml"}, nil
+			}
+
+			publicIP, err := fetcher.IP4(context.Background())
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				errMessage := strings.ReplaceAll(testCase.errMessage, "{url}", server.URL)
+				assert.EqualError(t, err, errMessage)
+			}
+			assert.Equal(t, testCase.publicIP, publicIP)
+		})
+	}
+}
+
+func Test_Fetcher_IP4_serviceCached(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, testDescription, "203.0.113.5")
+	fetcher := New(server.Client())
+	discoveries := 0
+	fetcher.discover = func(context.Context) (locations []string, err error) {
+		discoveries++
+		return []string{server.URL + "/rootDesc.xml"}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		publicIP, err := fetcher.IP4(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, netip.MustParseAddr("203.0.113.5"), publicIP)
+	}
+	assert.Equal(t, 1, discoveries)
+	assert.Equal(t, server.URL+"/ctl/IPConn", fetcher.service.controlURL)
+}
+
+func Test_Fetcher_IP6(t *testing.T) {
+	t.Parallel()
+
+	fetcher := New(http.DefaultClient)
+
+	_, err := fetcher.IP6(context.Background())
+
+	assert.ErrorIs(t, err, ErrIPv6NotSupported)
+}
+
+func Test_parseSearchResponse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		message  string
+		location string
+		ok       bool
+	}{
+		"valid": {
+			message: "HTTP/1.1 200 OK\r\nCACHE-CONTROL: max-age=120\r\n" +
+				"ST: urn:schemas-upnp-org:device:InternetGatewayDevice:1\r\n" +
+				"LOCATION: http://192.168.1.1:5000/rootDesc.xml\r\n\r\n",
+			location: "http://192.168.1.1:5000/rootDesc.xml",
+			ok:       true,
+		},
+		"no_location": {
+			message: "HTTP/1.1 200 OK\r\nST: upnp:rootdevice\r\n\r\n",
+		},
+		"search_request": {
+			message: string(makeSearchRequest(searchTargets[0])),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			location, ok := parseSearchResponse([]byte(testCase.message))
+
+			assert.Equal(t, testCase.location, location)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}